@@ -0,0 +1,141 @@
+package authz
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	RoleAdmin    = "admin"
+	RoleSeller   = "seller"
+	RoleCustomer = "customer"
+	// RoleAnonymous is the caller of a request carrying no identity, including calls from
+	// services that neither forward the identity of a user nor act as the system
+	RoleAnonymous = "anonymous"
+	// RoleSystem is the caller of a call a service makes for itself, see AsSystem
+	RoleSystem = "system"
+)
+
+// Gin context key of the verified caller
+const callerKey = "authz.caller"
+
+// Caller is the authenticated user behind a request
+type Caller struct {
+	UserID int64
+	Role   string
+	Email  string
+}
+
+func (c *Caller) IsAdmin() bool {
+	return c.Role == RoleAdmin
+}
+
+func (c *Caller) IsAnonymous() bool {
+	return c.Role == RoleAnonymous
+}
+
+func (c *Caller) IsSystem() bool {
+	return c.Role == RoleSystem
+}
+
+// Privileged reports whether the caller is let through every rule, as admins and the system are
+func (c *Caller) Privileged() bool {
+	return c.IsAdmin() || c.IsSystem()
+}
+
+// Owns reports whether the caller may act on a resource belonging to ownerID.
+// Admins and the system own everything, anonymous callers nothing.
+func (c *Caller) Owns(ownerID int64) bool {
+	return c.Privileged() || (!c.IsAnonymous() && c.UserID == ownerID)
+}
+
+// Authorize returns the caller Middleware identified. The caller is never nil. A route served
+// without Middleware aborts with 500 and ok is false.
+func Authorize(c *gin.Context) (caller *Caller, ok bool) {
+	if value, exists := c.Get(callerKey); exists {
+		return value.(*Caller), true
+	}
+	log.Printf("No caller for %s %s, authz.Middleware is not installed", c.Request.Method, c.Request.URL.Path)
+	c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Internal Server Error"})
+	return nil, false
+}
+
+// identify verifies the signed identity headers forwarded by the gateway or by another
+// service. Calls between services without identity are made by the system when their service
+// token says so (see AsSystem) and are anonymous otherwise, like requests with neither. Bad
+// identity headers or service tokens abort the request with 401 and ok is false.
+func (k *Keys) identify(c *gin.Context) (caller *Caller, ok bool) {
+	if value, exists := c.Get(callerKey); exists {
+		return value.(*Caller), true
+	}
+
+	caller, err := k.VerifyIdentity(c.Request.Header)
+	if err != nil {
+		log.Printf("Rejected identity headers: %v", err)
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid identity"})
+		return nil, false
+	}
+
+	if caller == nil {
+		caller = &Caller{Role: RoleAnonymous}
+		if token := c.GetHeader(HeaderServiceToken); token != "" {
+			claims, err := k.verifyServiceToken(token)
+			if err != nil {
+				log.Printf("Rejected service token: %v", err)
+				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid service token"})
+				return nil, false
+			}
+			if claims.Subject == RoleSystem {
+				caller = &Caller{Role: RoleSystem}
+			}
+		}
+	}
+	c.Set(callerKey, caller)
+	return caller, true
+}
+
+// RequireOwner aborts with 403 unless the caller owns the resource
+func RequireOwner(c *gin.Context, caller *Caller, ownerID int64) bool {
+	if caller.Owns(ownerID) {
+		return true
+	}
+	if caller.IsAnonymous() {
+		abortAnonymous(c)
+		return false
+	}
+	c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+	return false
+}
+
+// RequireRole aborts with 403 unless the caller has one of the roles. Admins and the system pass.
+func RequireRole(c *gin.Context, caller *Caller, roles ...string) bool {
+	if caller.Privileged() {
+		return true
+	}
+	for _, role := range roles {
+		if caller.Role == role {
+			return true
+		}
+	}
+	if caller.IsAnonymous() {
+		abortAnonymous(c)
+		return false
+	}
+	c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+	return false
+}
+
+// RequireUser aborts with 401 when the caller is anonymous
+func RequireUser(c *gin.Context, caller *Caller) bool {
+	if caller.IsAnonymous() {
+		abortAnonymous(c)
+		return false
+	}
+	return true
+}
+
+func abortAnonymous(c *gin.Context) {
+	c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+}
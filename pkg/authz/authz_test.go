@@ -0,0 +1,193 @@
+package authz
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"th3y3m/e-commerce-microservices/pkg/config"
+	"th3y3m/e-commerce-microservices/pkg/discovery"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+var testKeys = NewKeys(config.Auth{IdentitySecret: "test-secret", ServiceTokenSecret: "test-service-secret"})
+
+func newContext(t *testing.T, role string, userID int64) (*gin.Context, *httptest.ResponseRecorder) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(rec)
+	c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+
+	if role != "" {
+		assert.NoError(t, testKeys.SignIdentity(c.Request.Header, Caller{UserID: userID, Role: role}))
+	}
+	return c, rec
+}
+
+func TestRequireOwner(t *testing.T) {
+	c, rec := newContext(t, RoleCustomer, 7)
+	caller, ok := testKeys.identify(c)
+	assert.True(t, ok)
+	assert.Equal(t, int64(7), caller.UserID)
+
+	assert.True(t, RequireOwner(c, caller, 7))
+	assert.False(t, RequireOwner(c, caller, 8))
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+}
+
+func TestAdminAndSystemCallsPass(t *testing.T) {
+	c, _ := newContext(t, RoleAdmin, 1)
+	admin, _ := testKeys.identify(c)
+	assert.True(t, RequireOwner(c, admin, 8))
+	assert.True(t, RequireRole(c, admin, RoleSeller))
+
+	c, _ = newContext(t, "", 0)
+	token, err := testKeys.NewSystemToken("order")
+	assert.NoError(t, err)
+	c.Request.Header.Set(HeaderServiceToken, token)
+	system, ok := testKeys.identify(c)
+	assert.True(t, ok)
+	assert.True(t, system.IsSystem())
+	assert.True(t, RequireOwner(c, system, 8))
+	assert.True(t, RequireRole(c, system, RoleAdmin))
+}
+
+func TestServiceCallsWithoutIdentityAreAnonymous(t *testing.T) {
+	c, rec := newContext(t, "", 0)
+	token, err := testKeys.NewServiceToken("order")
+	assert.NoError(t, err)
+	c.Request.Header.Set(HeaderServiceToken, token)
+
+	caller, ok := testKeys.identify(c)
+	assert.True(t, ok)
+	assert.True(t, caller.IsAnonymous())
+	assert.False(t, RequireUser(c, caller))
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+func TestServiceCallsForwardIdentity(t *testing.T) {
+	c, rec := newContext(t, RoleCustomer, 7)
+	token, err := testKeys.NewSystemToken("payment")
+	assert.NoError(t, err)
+	c.Request.Header.Set(HeaderServiceToken, token)
+
+	// The user the call is made for wins over the system
+	caller, ok := testKeys.identify(c)
+	assert.True(t, ok)
+	assert.Equal(t, RoleCustomer, caller.Role)
+	assert.False(t, RequireOwner(c, caller, 8))
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+}
+
+func TestServiceClientActsAsSystemOnlyWhenAsked(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	r.Use(testKeys.Middleware())
+	r.GET("/", func(c *gin.Context) {
+		caller, ok := Authorize(c)
+		if ok {
+			c.String(http.StatusOK, caller.Role)
+		}
+	})
+	server := httptest.NewServer(r)
+	defer server.Close()
+
+	send := func(ctx context.Context) string {
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+		resp, err := testKeys.NewServiceClient("momo", discovery.Static{}).Do(req)
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+		role, _ := io.ReadAll(resp.Body)
+		return string(role)
+	}
+
+	assert.Equal(t, RoleAnonymous, send(context.Background()))
+	assert.Equal(t, RoleSystem, send(AsSystem(context.Background())))
+}
+
+func TestAnonymousCallerOwnsNothing(t *testing.T) {
+	c, rec := newContext(t, "", 0)
+	caller, ok := testKeys.identify(c)
+	assert.True(t, ok)
+	assert.True(t, caller.IsAnonymous())
+
+	assert.False(t, RequireOwner(c, caller, 0))
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+func TestInternalOnly(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	r.PUT("/", testKeys.InternalOnly("momo", "vnpay"), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	send := func(client *http.Client) int {
+		server := httptest.NewServer(r)
+		defer server.Close()
+		req, _ := http.NewRequest(http.MethodPut, server.URL, nil)
+		resp, err := client.Do(req)
+		assert.NoError(t, err)
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	assert.Equal(t, http.StatusOK, send(testKeys.NewServiceClient("momo", discovery.Static{})))
+	assert.Equal(t, http.StatusForbidden, send(testKeys.NewServiceClient("cart", discovery.Static{})))
+	assert.Equal(t, http.StatusUnauthorized, send(http.DefaultClient))
+}
+
+func TestTamperedIdentity(t *testing.T) {
+	c, rec := newContext(t, RoleCustomer, 7)
+	c.Request.Header.Set(HeaderUserRole, RoleAdmin)
+
+	_, ok := testKeys.identify(c)
+	assert.False(t, ok)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+func TestExpiredIdentity(t *testing.T) {
+	c, _ := newContext(t, RoleCustomer, 7)
+	stale := strconv.FormatInt(time.Now().Add(-time.Hour).Unix(), 10)
+	secret, _ := testKeys.identitySecret()
+	c.Request.Header.Set(HeaderTimestamp, stale)
+	c.Request.Header.Set(HeaderSignature, sign(secret, "7", RoleCustomer, "", stale))
+
+	_, err := testKeys.VerifyIdentity(c.Request.Header)
+	assert.ErrorIs(t, err, errExpiredIdentity)
+}
+
+func TestMiddlewareRejectsBodyIdentity(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	r.Use(testKeys.Middleware("user_id"))
+	r.POST("/", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	send := func(body string) int {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		assert.NoError(t, testKeys.SignIdentity(req.Header, Caller{UserID: 7, Role: RoleCustomer}))
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	assert.Equal(t, http.StatusOK, send(`{"user_id": 7, "product_id": 3}`))
+	assert.Equal(t, http.StatusOK, send(`{"product_id": 3}`))
+	assert.Equal(t, http.StatusForbidden, send(`{"user_id": 8, "product_id": 3}`))
+	assert.Equal(t, http.StatusForbidden, send(`{"user_id": "8"}`))
+}
+
+func TestAuthorizeNeedsTheMiddleware(t *testing.T) {
+	c, rec := newContext(t, RoleCustomer, 7)
+
+	_, ok := Authorize(c)
+	assert.False(t, ok)
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+}
@@ -0,0 +1,18 @@
+package authz
+
+import "th3y3m/e-commerce-microservices/pkg/config"
+
+// Keys holds the secrets identity headers and service tokens are signed and verified with.
+// Services build them once from their auth block and share them with their clients.
+type Keys struct {
+	identity []byte
+	service  []byte
+}
+
+// NewKeys returns the keys of the auth block
+func NewKeys(cfg config.Auth) *Keys {
+	return &Keys{
+		identity: []byte(cfg.IdentitySecret),
+		service:  []byte(cfg.ServiceTokenSecret),
+	}
+}
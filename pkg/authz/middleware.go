@@ -0,0 +1,70 @@
+package authz
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Middleware verifies the identity headers of every request. For callers other than admins and
+// the system it also rejects JSON bodies whose identityFields (e.g. "user_id") name a different
+// user, so a client cannot act for someone else by choosing the ID in the body.
+func (k *Keys) Middleware(identityFields ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		caller, ok := k.identify(c)
+		if !ok {
+			return
+		}
+		if caller.Privileged() || len(identityFields) == 0 || c.Request.Body == nil {
+			c.Next()
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Bad Request"})
+			return
+		}
+		// Handlers bind the body again
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		if !bodyMatchesCaller(body, caller, identityFields) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Identity in the request does not match the caller"})
+			return
+		}
+		c.Next()
+	}
+}
+
+// bodyMatchesCaller reports false when one of the fields holds another user's ID.
+// Bodies that are not JSON objects are left to the handler to reject.
+func bodyMatchesCaller(body []byte, caller *Caller, fields []string) bool {
+	var values map[string]json.RawMessage
+	if err := json.Unmarshal(body, &values); err != nil {
+		return true
+	}
+
+	for _, field := range fields {
+		raw, ok := values[field]
+		if !ok || string(raw) == "null" {
+			continue
+		}
+
+		var id json.Number
+		if err := json.Unmarshal(raw, &id); err != nil {
+			var s string
+			if err := json.Unmarshal(raw, &s); err != nil {
+				return false
+			}
+			id = json.Number(s)
+		}
+		if userID, err := strconv.ParseInt(id.String(), 10, 64); err != nil || userID != caller.UserID {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,131 @@
+package broker
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"th3y3m/e-commerce-microservices/pkg/config"
+	"th3y3m/e-commerce-microservices/pkg/events"
+	"time"
+)
+
+// Handler handles the body of one message. Returning nil acknowledges the message; an error
+// rejects it, so it is delivered again after a delay, and dead-lettered after the last retry.
+type Handler func(ctx context.Context, body []byte) error
+
+// Broker carries events between services. Events are published to a topic exchange with their
+// type as routing key, and every subscription has its own queue bound to the types it receives.
+type Broker interface {
+	// Publish sends the event and returns once the broker has taken responsibility for it.
+	// Events no queue is bound for are dropped, see Declare.
+	Publish(ctx context.Context, env *events.Envelope) error
+	// Declare declares the queues of the subscriptions and their bindings without consuming,
+	// so events published before a subscriber first runs wait in its queue. Exclusive
+	// subscriptions are skipped, their queues only live while their subscriber does.
+	Declare(subs ...Subscription) error
+	// Subscribe declares the queue of the subscription and its bindings, then calls handler for
+	// every message of the queue until ctx is canceled. It returns once the messages in hand
+	// are handled.
+	Subscribe(ctx context.Context, sub Subscription, handler Handler) error
+}
+
+// Subscription is a queue of a service and the events it receives. Every service binds its own
+// queues, so one event reaches every service subscribed to it, and the instances of a service
+// share the messages of its queues.
+type Subscription struct {
+	Queue string
+	// Routing keys bound to the queue, event types such as order.placed or patterns such as
+	// order.* and #
+	Events []string
+	// Exclusive gives the subscriber a queue of its own, deleted once it stops consuming, so
+	// every instance receives every event instead of sharing them. Queue must then be unique to
+	// the instance. Messages of an exclusive queue that fail are dropped, not retried.
+	Exclusive bool
+}
+
+// PublishEvent sends event in an envelope, correlated with the request of ctx
+func PublishEvent(ctx context.Context, b Broker, event events.Event) error {
+	env, err := events.New(ctx, event)
+	if err != nil {
+		return err
+	}
+	return b.Publish(ctx, env)
+}
+
+// Consume calls handler with every event of type E in the queue of the subscription, as
+// Subscribe does. Events of older versions are upgraded; messages that are not an E are
+// dead-lettered.
+func Consume[E events.Event](ctx context.Context, b Broker, sub Subscription, handler func(context.Context, *events.Envelope, E) error) error {
+	return b.Subscribe(ctx, sub, func(ctx context.Context, body []byte) error {
+		env, event, err := events.Decode[E](body)
+		if err != nil {
+			return Permanent(err)
+		}
+		return handler(ctx, env, event)
+	})
+}
+
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+
+func (e *permanentError) Unwrap() error { return e.err }
+
+// IsPermanent reports whether err was marked with Permanent
+func IsPermanent(err error) bool {
+	var permanent *permanentError
+	return errors.As(err, &permanent)
+}
+
+// Permanent marks a handler error that retrying cannot fix, such as a malformed message,
+// so the message is dead-lettered right away
+func Permanent(err error) error {
+	return &permanentError{err: err}
+}
+
+// ConsumerConfig tunes how the messages of a queue are handled
+type ConsumerConfig struct {
+	// Messages delivered to the consumer and not acknowledged yet
+	Prefetch int
+	// Messages handled at the same time
+	Concurrency int
+	// Failed deliveries are retried this many times before the message is dead-lettered
+	MaxRetries int
+	// Delay before the first retry, doubled for every following one
+	RetryDelay time.Duration
+}
+
+// NewConsumerConfig returns the consumer settings of the RabbitMQ block
+func NewConsumerConfig(c config.RabbitMQ) ConsumerConfig {
+	return ConsumerConfig{
+		// Workers beyond the prefetch count would never get a message
+		Prefetch:    max(c.Prefetch, c.Concurrency, 1),
+		Concurrency: max(c.Concurrency, 1),
+		MaxRetries:  max(c.MaxRetries, 0),
+		RetryDelay:  c.RetryDelay,
+	}
+}
+
+// Backoff is the time a message waits before its attempt-th retry (1-based)
+func (cfg ConsumerConfig) Backoff(attempt int) time.Duration {
+	return cfg.RetryDelay << (attempt - 1)
+}
+
+var (
+	memoryOnce    sync.Once
+	memoryDefault *Memory
+)
+
+// FromConfig returns the in-process broker shared by the whole process when cfg selects it,
+// and the broker amqp builds from cfg otherwise
+func FromConfig(cfg config.RabbitMQ, amqp func(config.RabbitMQ) Broker) Broker {
+	if !cfg.InMemory() {
+		return amqp(cfg)
+	}
+	memoryOnce.Do(func() {
+		memoryDefault = NewMemory(NewConsumerConfig(cfg))
+	})
+	return memoryDefault
+}
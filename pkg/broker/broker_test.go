@@ -0,0 +1,29 @@
+package broker
+
+import (
+	"errors"
+	"testing"
+	"th3y3m/e-commerce-microservices/pkg/config"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewConsumerConfig(t *testing.T) {
+	cfg := NewConsumerConfig(config.RabbitMQ{Prefetch: 10, Concurrency: 1, MaxRetries: 3, RetryDelay: time.Second})
+	assert.Equal(t, ConsumerConfig{Prefetch: 10, Concurrency: 1, MaxRetries: 3, RetryDelay: time.Second}, cfg)
+
+	cfg = NewConsumerConfig(config.RabbitMQ{Prefetch: 2, Concurrency: 4, MaxRetries: 0, RetryDelay: 500 * time.Millisecond})
+	assert.Equal(t, ConsumerConfig{Prefetch: 4, Concurrency: 4, MaxRetries: 0, RetryDelay: 500 * time.Millisecond}, cfg)
+	assert.Equal(t, 2*time.Second, cfg.Backoff(3))
+}
+
+func TestPermanent(t *testing.T) {
+	cause := errors.New("bad id")
+	err := Permanent(cause)
+
+	assert.True(t, IsPermanent(err))
+	assert.False(t, IsPermanent(cause))
+	assert.ErrorIs(t, err, cause)
+	assert.Equal(t, "bad id", err.Error())
+}
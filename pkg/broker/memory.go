@@ -0,0 +1,241 @@
+package broker
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"strings"
+	"sync"
+	"th3y3m/e-commerce-microservices/pkg/events"
+	"th3y3m/e-commerce-microservices/pkg/logging"
+	"th3y3m/e-commerce-microservices/pkg/metrics"
+	"time"
+)
+
+// Memory is a broker inside the process with the semantics of the RabbitMQ one: topic routing,
+// queues shared by their subscribers, delayed retries and a dead-letter list per queue. Messages
+// are lost when the process stops.
+type Memory struct {
+	cfg ConsumerConfig
+
+	mu     sync.Mutex
+	queues map[string]*memoryQueue
+}
+
+// DeadLetter is a message that failed every retry
+type DeadLetter struct {
+	Body    []byte
+	Error   string
+	Retries int
+}
+
+type memoryQueue struct {
+	keys    []string
+	pending []memoryMessage
+	// Closed and replaced when a message is added, to wake up waiting subscribers
+	ready chan struct{}
+	dead  []DeadLetter
+}
+
+type memoryMessage struct {
+	body      []byte
+	requestID string
+	retries   int
+}
+
+func NewMemory(cfg ConsumerConfig) *Memory {
+	return &Memory{
+		cfg:    cfg,
+		queues: make(map[string]*memoryQueue),
+	}
+}
+
+func (m *Memory) Publish(ctx context.Context, env *events.Envelope) (err error) {
+	defer func() { metrics.MessagePublished(env.Type, err) }()
+
+	body, err := json.Marshal(env)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, q := range m.queues {
+		if q.binds(env.Type) {
+			m.enqueue(q, memoryMessage{body: body, requestID: env.CorrelationID})
+		}
+	}
+	return nil
+}
+
+func (m *Memory) Subscribe(ctx context.Context, sub Subscription, handler Handler) error {
+	q := m.declare(sub)
+	if sub.Exclusive {
+		defer m.remove(sub.Queue)
+	}
+
+	// Messages already handed to the handler are finished on shutdown
+	handlerCtx := context.WithoutCancel(ctx)
+
+	var wg sync.WaitGroup
+	for i := 0; i < max(m.cfg.Concurrency, 1); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				msg, ok := m.next(ctx, q)
+				if !ok {
+					return
+				}
+				m.handle(handlerCtx, sub.Queue, sub.Exclusive, q, msg, handler)
+			}
+		}()
+	}
+	wg.Wait()
+	return nil
+}
+
+func (m *Memory) Declare(subs ...Subscription) error {
+	for _, sub := range subs {
+		if !sub.Exclusive {
+			m.declare(sub)
+		}
+	}
+	return nil
+}
+
+// DeadLetters returns the messages of queue that failed every retry, oldest first
+func (m *Memory) DeadLetters(queue string) []DeadLetter {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	q, ok := m.queues[queue]
+	if !ok {
+		return nil
+	}
+	return append([]DeadLetter(nil), q.dead...)
+}
+
+// declare creates the queue of the subscription unless it exists and binds it to its events.
+// Like a durable queue, it keeps receiving messages when nobody is subscribed, unless the
+// subscription is exclusive.
+func (m *Memory) declare(sub Subscription) *memoryQueue {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	q, ok := m.queues[sub.Queue]
+	if !ok {
+		q = &memoryQueue{ready: make(chan struct{})}
+		m.queues[sub.Queue] = q
+	}
+	for _, key := range sub.Events {
+		if !slices.Contains(q.keys, key) {
+			q.keys = append(q.keys, key)
+		}
+	}
+	return q
+}
+
+// remove deletes the queue with its messages
+func (m *Memory) remove(queue string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.queues, queue)
+}
+
+// enqueue adds msg to q, the caller holds m.mu
+func (m *Memory) enqueue(q *memoryQueue, msg memoryMessage) {
+	q.pending = append(q.pending, msg)
+	close(q.ready)
+	q.ready = make(chan struct{})
+}
+
+// next takes the oldest message of q, waiting for one until ctx is canceled
+func (m *Memory) next(ctx context.Context, q *memoryQueue) (memoryMessage, bool) {
+	for {
+		m.mu.Lock()
+		if len(q.pending) > 0 {
+			msg := q.pending[0]
+			q.pending = q.pending[1:]
+			m.mu.Unlock()
+			return msg, true
+		}
+		ready := q.ready
+		m.mu.Unlock()
+
+		select {
+		case <-ready:
+		case <-ctx.Done():
+			return memoryMessage{}, false
+		}
+	}
+}
+
+func (m *Memory) handle(ctx context.Context, queue string, exclusive bool, q *memoryQueue, msg memoryMessage, handler Handler) {
+	ctx = logging.WithRequestInfo(ctx, &logging.RequestInfo{
+		RequestID: logging.RequestID(msg.requestID),
+		Route:     queue,
+	})
+
+	err := handler(ctx, msg.body)
+	metrics.MessageConsumed(queue, err)
+	if err == nil {
+		return
+	}
+
+	logger := logging.Logger().WithContext(ctx)
+	if exclusive {
+		logger.Errorf("Message of %s dropped: %v", queue, err)
+		return
+	}
+
+	attempt := msg.retries + 1
+	if attempt > m.cfg.MaxRetries || IsPermanent(err) {
+		m.mu.Lock()
+		q.dead = append(q.dead, DeadLetter{Body: msg.body, Error: err.Error(), Retries: attempt})
+		m.mu.Unlock()
+		metrics.MessageDeadLettered(queue)
+		logger.Errorf("Message dead-lettered after %d attempts: %v", attempt, err)
+		return
+	}
+
+	metrics.MessageRetried(queue)
+	logger.Warnf("Message will be retried in %s: %v", m.cfg.Backoff(attempt), err)
+	msg.retries = attempt
+	time.AfterFunc(m.cfg.Backoff(attempt), func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		m.enqueue(q, msg)
+	})
+}
+
+// binds reports whether q receives the messages published with the routing key
+func (q *memoryQueue) binds(key string) bool {
+	for _, pattern := range q.keys {
+		if matchTopic(strings.Split(pattern, "."), strings.Split(key, ".")) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchTopic matches the words of a routing key against the words of a binding pattern, where *
+// stands for exactly one word and # for zero or more words
+func matchTopic(pattern, key []string) bool {
+	if len(pattern) == 0 {
+		return len(key) == 0
+	}
+	switch pattern[0] {
+	case "#":
+		for i := 0; i <= len(key); i++ {
+			if matchTopic(pattern[1:], key[i:]) {
+				return true
+			}
+		}
+		return false
+	case "*":
+		return len(key) > 0 && matchTopic(pattern[1:], key[1:])
+	default:
+		return len(key) > 0 && pattern[0] == key[0] && matchTopic(pattern[1:], key[1:])
+	}
+}
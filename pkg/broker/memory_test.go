@@ -0,0 +1,187 @@
+package broker
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"th3y3m/e-commerce-microservices/pkg/events"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatchTopic(t *testing.T) {
+	cases := []struct {
+		pattern, key string
+		match        bool
+	}{
+		{"order.placed", "order.placed", true},
+		{"order.placed", "order.cancelled", false},
+		{"order.*", "order.placed", true},
+		{"order.*", "order.placed.v2", false},
+		{"#", "order.placed", true},
+		{"order.#", "order", true},
+		{"#.placed", "order.placed", true},
+		{"*.placed", "placed", false},
+	}
+	for _, c := range cases {
+		got := matchTopic(strings.Split(c.pattern, "."), strings.Split(c.key, "."))
+		assert.Equal(t, c.match, got, "%s ~ %s", c.pattern, c.key)
+	}
+}
+
+// subscribe runs Consume in the background until the test ends. The queue is declared first,
+// so events published right after are not dropped.
+func subscribe[E events.Event](t *testing.T, b *Memory, sub Subscription, handler func(context.Context, *events.Envelope, E) error) {
+	b.declare(sub)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		_ = Consume(ctx, b, sub, handler)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		wg.Wait()
+	})
+}
+
+func TestMemoryFanOut(t *testing.T) {
+	b := NewMemory(ConsumerConfig{Concurrency: 1, MaxRetries: 3, RetryDelay: time.Millisecond})
+
+	received := make(chan string, 2)
+	for _, queue := range []string{"product", "mail"} {
+		queue := queue
+		subscribe(t, b, Subscription{Queue: queue, Events: []string{events.TypeOrderPlaced}},
+			func(ctx context.Context, env *events.Envelope, event events.OrderPlaced) error {
+				received <- queue
+				return nil
+			})
+	}
+
+	require.NoError(t, PublishEvent(context.Background(), b, events.OrderPlaced{OrderID: 1}))
+	// Nobody is bound to payments
+	require.NoError(t, PublishEvent(context.Background(), b, events.PaymentCompleted{OrderID: 1}))
+
+	got := []string{<-received, <-received}
+	assert.ElementsMatch(t, []string{"product", "mail"}, got)
+}
+
+func TestMemoryKeepsEventsOfDeclaredQueues(t *testing.T) {
+	b := NewMemory(ConsumerConfig{Concurrency: 1})
+	sub := Subscription{Queue: "mail", Events: []string{events.TypeOrderPlaced}}
+	require.NoError(t, b.Declare(sub, Subscription{Queue: "gateway.1", Events: []string{"#"}, Exclusive: true}))
+
+	// Published before anyone subscribed
+	require.NoError(t, PublishEvent(context.Background(), b, events.OrderPlaced{OrderID: 7}))
+
+	received := make(chan int64, 1)
+	subscribe(t, b, sub, func(ctx context.Context, env *events.Envelope, event events.OrderPlaced) error {
+		received <- event.OrderID
+		return nil
+	})
+	select {
+	case orderID := <-received:
+		assert.Equal(t, int64(7), orderID)
+	case <-time.After(time.Second):
+		t.Fatal("event published before the subscription was lost")
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	assert.NotContains(t, b.queues, "gateway.1")
+}
+
+func TestMemoryRetriesAndDeadLetters(t *testing.T) {
+	b := NewMemory(ConsumerConfig{Concurrency: 2, MaxRetries: 2, RetryDelay: time.Millisecond})
+	sub := Subscription{Queue: "mail", Events: []string{"order.*"}}
+
+	var mu sync.Mutex
+	attempts := map[int64]int{}
+	subscribe(t, b, sub, func(ctx context.Context, env *events.Envelope, event events.OrderPlaced) error {
+		mu.Lock()
+		defer mu.Unlock()
+		attempts[event.OrderID]++
+		switch {
+		case event.OrderID == 1 && attempts[1] < 2:
+			return errors.New("smtp down")
+		case event.OrderID == 2:
+			return errors.New("smtp down")
+		case event.OrderID == 3:
+			return Permanent(errors.New("no customer"))
+		}
+		return nil
+	})
+
+	for id := int64(1); id <= 3; id++ {
+		require.NoError(t, PublishEvent(context.Background(), b, events.OrderPlaced{OrderID: id}))
+	}
+
+	require.Eventually(t, func() bool { return len(b.DeadLetters("mail")) == 2 }, time.Second, time.Millisecond)
+	mu.Lock()
+	defer mu.Unlock()
+	// Redelivered once, retried until the last retry, and dead-lettered right away
+	assert.Equal(t, map[int64]int{1: 2, 2: 3, 3: 1}, attempts)
+	for _, letter := range b.DeadLetters("mail") {
+		assert.NotEmpty(t, letter.Error)
+	}
+}
+
+func TestMemoryExclusiveQueues(t *testing.T) {
+	b := NewMemory(ConsumerConfig{Concurrency: 1, MaxRetries: 3, RetryDelay: time.Millisecond})
+
+	var mu sync.Mutex
+	attempts := map[string]int{}
+	for _, queue := range []string{"gateway.a", "gateway.b"} {
+		queue := queue
+		subscribe(t, b, Subscription{Queue: queue, Events: []string{events.TypeCacheInvalidated}, Exclusive: true},
+			func(ctx context.Context, env *events.Envelope, event events.CacheInvalidated) error {
+				mu.Lock()
+				defer mu.Unlock()
+				attempts[queue]++
+				return errors.New("cache down")
+			})
+	}
+
+	require.NoError(t, PublishEvent(context.Background(), b, events.CacheInvalidated{Path: "/api/products"}))
+
+	// Every replica gets the event, and a failure is dropped rather than retried
+	require.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return attempts["gateway.a"] == 1 && attempts["gateway.b"] == 1
+	}, time.Second, time.Millisecond)
+	time.Sleep(20 * time.Millisecond)
+	mu.Lock()
+	assert.Equal(t, map[string]int{"gateway.a": 1, "gateway.b": 1}, attempts)
+	mu.Unlock()
+	assert.Empty(t, b.DeadLetters("gateway.a"))
+}
+
+func TestMemoryExclusiveQueueIsDeletedWithItsSubscriber(t *testing.T) {
+	b := NewMemory(ConsumerConfig{Concurrency: 1})
+	sub := Subscription{Queue: "gateway.a", Events: []string{events.TypeCacheInvalidated}, Exclusive: true}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = b.Subscribe(ctx, sub, func(context.Context, []byte) error { return nil })
+	}()
+	require.Eventually(t, func() bool {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		return b.queues["gateway.a"] != nil
+	}, time.Second, time.Millisecond)
+
+	cancel()
+	<-done
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	assert.NotContains(t, b.queues, "gateway.a")
+}
@@ -0,0 +1,21 @@
+package broker
+
+import "th3y3m/e-commerce-microservices/pkg/events"
+
+// Subscriptions that must receive every event, even one published before their service first
+// subscribed. Publishers declare them, see Durable.
+var (
+	// Every placed order gets a mail with its payment link
+	OrderNotifications = Subscription{
+		Queue:  "order_notification_queue",
+		Events: []string{events.TypeOrderPlaced},
+	}
+	// The stock of every placed order is taken from the products of its cart
+	InventoryUpdates = Subscription{
+		Queue:  "inventory_update_queue",
+		Events: []string{events.TypeOrderPlaced},
+	}
+)
+
+// Durable lists the subscriptions publishers declare before their first publish
+var Durable = []Subscription{OrderNotifications, InventoryUpdates}
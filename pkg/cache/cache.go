@@ -0,0 +1,216 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"reflect"
+	"slices"
+	"sync"
+	"th3y3m/e-commerce-microservices/pkg/config"
+	"th3y3m/e-commerce-microservices/pkg/logging"
+	"th3y3m/e-commerce-microservices/pkg/metrics"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"golang.org/x/sync/singleflight"
+)
+
+// Store keeps encoded values under keys, each with a TTL and the tags it can be invalidated by
+type Store interface {
+	// Get returns the value of key and whether it was found
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration, tags ...string) error
+	Delete(ctx context.Context, keys ...string) error
+	// InvalidateTags deletes every key stored with one of the tags
+	InvalidateTags(ctx context.Context, tags ...string) error
+}
+
+const defaultMemorySize = 10000
+
+// Config tunes how long the entries of a cache are kept
+type Config struct {
+	TTL time.Duration
+	// Fraction of the TTL added at random, so entries written together do not expire together
+	Jitter float64
+	// How long a missing record is remembered, 0 disables negative caching
+	NegativeTTL time.Duration
+}
+
+// NewConfig returns the settings of the cache block, ignoring negative jitters and TTLs
+func NewConfig(c config.Cache) Config {
+	return Config{
+		TTL:         c.TTL,
+		Jitter:      max(c.Jitter, 0),
+		NegativeTTL: max(c.NegativeTTL, 0),
+	}
+}
+
+// ttl is the TTL of one entry, with jitter
+func (cfg Config) ttl() time.Duration {
+	if cfg.Jitter <= 0 {
+		return cfg.TTL
+	}
+	return cfg.TTL + time.Duration(rand.Float64()*cfg.Jitter*float64(cfg.TTL))
+}
+
+var (
+	memoryOnce    sync.Once
+	memoryDefault *Memory
+)
+
+// NewStore returns a Redis store on client, or the in-memory store shared by the whole process
+// when client is nil because Redis is not available
+func NewStore(client *redis.Client) Store {
+	if client != nil {
+		return NewRedis(client)
+	}
+	memoryOnce.Do(func() {
+		memoryDefault = NewMemory(defaultMemorySize)
+	})
+	return memoryDefault
+}
+
+// Loads of the same key and value type are shared by the callers of the whole process, as
+// repositories and their caches are built per request
+var loads singleflight.Group
+
+// A missing record is stored as an empty value, which no JSON encoding of a value can be
+var missing = []byte{}
+
+// Cache is a cache-aside view of a Store for values of type V. Its keys are prefixed with its
+// namespace, which is also a tag of every entry, so the namespace can be invalidated at once.
+type Cache[V any] struct {
+	store     Store
+	namespace string
+	cfg       Config
+	notFound  error
+	tags      func(V) []string
+}
+
+// New returns a cache of values of type V in namespace, such as "product"
+func New[V any](store Store, namespace string, cfg Config) *Cache[V] {
+	return &Cache[V]{store: store, namespace: namespace, cfg: cfg}
+}
+
+// CacheNotFound makes the cache remember, for NegativeTTL, that loading a key failed with err,
+// and return err again without loading until then
+func (c *Cache[V]) CacheNotFound(err error) *Cache[V] {
+	c.notFound = err
+	return c
+}
+
+// Tagged makes the cache store every value with the tags returned by tags, such as the tag of
+// the record it was built from, so all the keys of a record can be invalidated at once
+func (c *Cache[V]) Tagged(tags func(V) []string) *Cache[V] {
+	c.tags = tags
+	return c
+}
+
+func (c *Cache[V]) key(key string) string {
+	return c.namespace + ":" + key
+}
+
+// GetOrLoad returns the cached value of key, or calls load and caches its result.
+// Concurrent misses of a key share one call of load. Errors of the store are logged and the
+// value is loaded as if it was missing.
+func (c *Cache[V]) GetOrLoad(ctx context.Context, key string, load func(ctx context.Context) (V, error)) (V, error) {
+	var value V
+	fullKey := c.key(key)
+
+	raw, found, err := c.store.Get(ctx, fullKey)
+	if err != nil {
+		logging.Logger().WithContext(ctx).Warnf("Failed to get %s from the cache: %v", fullKey, err)
+	}
+	if found {
+		if len(raw) == 0 && c.notFound != nil {
+			metrics.CacheHit(c.namespace)
+			return value, c.notFound
+		}
+		if err := json.Unmarshal(raw, &value); err == nil {
+			metrics.CacheHit(c.namespace)
+			return value, nil
+		}
+		logging.Logger().WithContext(ctx).Warnf("Failed to decode %s from the cache: %v", fullKey, err)
+	}
+	metrics.CacheMiss(c.namespace)
+
+	// The load outlives a caller that gives up, the others may still wait for it
+	// The value type is part of the key, so caches of different types sharing a namespace do
+	// not receive each other's values
+	ch := loads.DoChan(reflect.TypeFor[V]().String()+" "+fullKey, func() (any, error) {
+		loadCtx := context.WithoutCancel(ctx)
+		value, err := load(loadCtx)
+		switch {
+		case err == nil:
+			c.Set(loadCtx, key, value)
+		case c.notFound != nil && c.cfg.NegativeTTL > 0 && errors.Is(err, c.notFound):
+			c.set(loadCtx, fullKey, missing, c.cfg.NegativeTTL, nil)
+		}
+		return value, err
+	})
+
+	select {
+	case <-ctx.Done():
+		return value, ctx.Err()
+	case res := <-ch:
+		if res.Err != nil {
+			return value, res.Err
+		}
+		value, ok := res.Val.(V)
+		if !ok {
+			return value, fmt.Errorf("cache: load of %s returned %T instead of %s", fullKey, res.Val, reflect.TypeFor[V]())
+		}
+		return value, nil
+	}
+}
+
+// Set caches value under key, such as after the record was written
+func (c *Cache[V]) Set(ctx context.Context, key string, value V) {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		logging.Logger().WithContext(ctx).Warnf("Failed to encode %s for the cache: %v", c.key(key), err)
+		return
+	}
+	var tags []string
+	if c.tags != nil {
+		tags = c.tags(value)
+	}
+	c.set(ctx, c.key(key), raw, c.cfg.ttl(), tags)
+}
+
+func (c *Cache[V]) set(ctx context.Context, fullKey string, raw []byte, ttl time.Duration, tags []string) {
+	if err := c.store.Set(ctx, fullKey, raw, ttl, append(slices.Clip(tags), c.namespace)...); err != nil {
+		logging.Logger().WithContext(ctx).Warnf("Failed to save %s to the cache: %v", fullKey, err)
+	}
+}
+
+// Delete removes keys from the cache
+func (c *Cache[V]) Delete(ctx context.Context, keys ...string) {
+	fullKeys := make([]string, len(keys))
+	for i, key := range keys {
+		fullKeys[i] = c.key(key)
+	}
+	if err := c.store.Delete(ctx, fullKeys...); err != nil {
+		logging.Logger().WithContext(ctx).Warnf("Failed to delete %v from the cache: %v", fullKeys, err)
+	}
+}
+
+// Invalidate removes the entries stored with one of tags, in any namespace
+func (c *Cache[V]) Invalidate(ctx context.Context, tags ...string) {
+	if err := c.store.InvalidateTags(ctx, tags...); err != nil {
+		logging.Logger().WithContext(ctx).Warnf("Failed to invalidate cache tags %v: %v", tags, err)
+	}
+}
+
+// InvalidateAll removes every entry of the namespace
+func (c *Cache[V]) InvalidateAll(ctx context.Context) {
+	c.Invalidate(ctx, c.namespace)
+}
+
+// Tag builds the tag of a record, such as product:42, to invalidate every entry built from it
+func Tag(kind string, id any) string {
+	return fmt.Sprintf("%s:%v", kind, id)
+}
@@ -0,0 +1,167 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errNotFound = errors.New("record not found")
+
+type item struct {
+	ID   int64
+	Name string
+}
+
+func newTestCache() *Cache[item] {
+	return New[item](NewMemory(100), "item", Config{TTL: time.Minute, NegativeTTL: time.Minute})
+}
+
+func TestGetOrLoadCachesValue(t *testing.T) {
+	c := newTestCache()
+	ctx := context.Background()
+
+	var calls int
+	load := func(context.Context) (item, error) {
+		calls++
+		return item{ID: 1, Name: "phone"}, nil
+	}
+
+	for i := 0; i < 2; i++ {
+		got, err := c.GetOrLoad(ctx, "1", load)
+		require.NoError(t, err)
+		assert.Equal(t, item{ID: 1, Name: "phone"}, got)
+	}
+	assert.Equal(t, 1, calls)
+
+	c.Delete(ctx, "1")
+	_, err := c.GetOrLoad(ctx, "1", load)
+	require.NoError(t, err)
+	assert.Equal(t, 2, calls)
+}
+
+func TestGetOrLoadSharesConcurrentLoads(t *testing.T) {
+	c := newTestCache()
+	release := make(chan struct{})
+	var calls atomic.Int32
+	load := func(context.Context) (item, error) {
+		calls.Add(1)
+		<-release
+		return item{ID: 2}, nil
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			got, err := c.GetOrLoad(context.Background(), "2", load)
+			assert.NoError(t, err)
+			assert.Equal(t, int64(2), got.ID)
+		}()
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestGetOrLoadKeepsTypesApart(t *testing.T) {
+	items := New[item](NewMemory(100), "shared", Config{TTL: time.Minute})
+	names := New[string](NewMemory(100), "shared", Config{TTL: time.Minute})
+	started := make(chan struct{}, 2)
+	release := make(chan struct{})
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		got, err := items.GetOrLoad(context.Background(), "3", func(context.Context) (item, error) {
+			started <- struct{}{}
+			<-release
+			return item{ID: 3}, nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), got.ID)
+	}()
+	go func() {
+		defer wg.Done()
+		got, err := names.GetOrLoad(context.Background(), "3", func(context.Context) (string, error) {
+			started <- struct{}{}
+			<-release
+			return "three", nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, "three", got)
+	}()
+
+	// Both loads run, neither waits for the other
+	<-started
+	<-started
+	close(release)
+	wg.Wait()
+}
+
+func TestGetOrLoadCachesNotFound(t *testing.T) {
+	c := newTestCache().CacheNotFound(errNotFound)
+	ctx := context.Background()
+
+	var calls int
+	load := func(context.Context) (item, error) {
+		calls++
+		return item{}, errNotFound
+	}
+
+	for i := 0; i < 2; i++ {
+		_, err := c.GetOrLoad(ctx, "3", load)
+		assert.ErrorIs(t, err, errNotFound)
+	}
+	assert.Equal(t, 1, calls)
+
+	// Other errors are not remembered
+	failing := func(context.Context) (item, error) {
+		calls++
+		return item{}, errors.New("connection refused")
+	}
+	for i := 0; i < 2; i++ {
+		_, err := c.GetOrLoad(ctx, "4", failing)
+		assert.Error(t, err)
+	}
+	assert.Equal(t, 3, calls)
+}
+
+func TestInvalidateTags(t *testing.T) {
+	store := NewMemory(100)
+	items := New[item](store, "item", Config{TTL: time.Minute}).Tagged(func(i item) []string {
+		return []string{Tag("item", i.ID)}
+	})
+	lists := New[[]item](store, "items", Config{TTL: time.Minute})
+	ctx := context.Background()
+
+	items.Set(ctx, "1", item{ID: 1})
+	items.Set(ctx, "name:phone", item{ID: 1})
+	items.Set(ctx, "2", item{ID: 2})
+	lists.Set(ctx, "all", []item{{ID: 1}, {ID: 2}})
+
+	items.Invalidate(ctx, Tag("item", 1))
+	assert.Equal(t, []string{"item:2", "items:all"}, keys(store))
+
+	lists.InvalidateAll(ctx)
+	assert.Equal(t, []string{"item:2"}, keys(store))
+}
+
+func TestConfigTTLJitter(t *testing.T) {
+	cfg := Config{TTL: time.Minute, Jitter: 0.5}
+	for i := 0; i < 100; i++ {
+		ttl := cfg.ttl()
+		assert.GreaterOrEqual(t, ttl, time.Minute)
+		assert.LessOrEqual(t, ttl, 90*time.Second)
+	}
+}
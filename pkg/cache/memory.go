@@ -0,0 +1,120 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type memoryEntry struct {
+	value   []byte
+	expires time.Time
+	tags    []string
+}
+
+// Memory caches values in the process, for replicas without Redis. It holds up to a number of
+// entries: when it is full, expired entries are dropped first, then arbitrary ones.
+type Memory struct {
+	mu         sync.Mutex
+	maxEntries int
+	entries    map[string]memoryEntry
+	// Keys stored with each tag
+	tags map[string]map[string]struct{}
+	now  func() time.Time
+}
+
+func NewMemory(maxEntries int) *Memory {
+	return &Memory{
+		maxEntries: maxEntries,
+		entries:    map[string]memoryEntry{},
+		tags:       map[string]map[string]struct{}{},
+		now:        time.Now,
+	}
+}
+
+func (s *Memory) Get(_ context.Context, key string) ([]byte, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.entries[key]
+	if !ok {
+		return nil, false, nil
+	}
+	if !s.now().Before(entry.expires) {
+		s.remove(key)
+		return nil, false, nil
+	}
+	return entry.value, true, nil
+}
+
+func (s *Memory) Set(_ context.Context, key string, value []byte, ttl time.Duration, tags ...string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.remove(key)
+	if len(s.entries) >= s.maxEntries {
+		s.evict()
+	}
+
+	s.entries[key] = memoryEntry{value: value, expires: s.now().Add(ttl), tags: tags}
+	for _, tag := range tags {
+		if s.tags[tag] == nil {
+			s.tags[tag] = map[string]struct{}{}
+		}
+		s.tags[tag][key] = struct{}{}
+	}
+	return nil
+}
+
+func (s *Memory) Delete(_ context.Context, keys ...string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, key := range keys {
+		s.remove(key)
+	}
+	return nil
+}
+
+func (s *Memory) InvalidateTags(_ context.Context, tags ...string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, tag := range tags {
+		for key := range s.tags[tag] {
+			s.remove(key)
+		}
+	}
+	return nil
+}
+
+// remove deletes key and its place in the tags. The caller holds the lock.
+func (s *Memory) remove(key string) {
+	entry, ok := s.entries[key]
+	if !ok {
+		return
+	}
+	delete(s.entries, key)
+	for _, tag := range entry.tags {
+		delete(s.tags[tag], key)
+		if len(s.tags[tag]) == 0 {
+			delete(s.tags, tag)
+		}
+	}
+}
+
+// evict makes room for an entry. The caller holds the lock.
+func (s *Memory) evict() {
+	now := s.now()
+	for key, entry := range s.entries {
+		if !now.Before(entry.expires) {
+			s.remove(key)
+		}
+	}
+	for key := range s.entries {
+		if len(s.entries) < s.maxEntries {
+			return
+		}
+		s.remove(key)
+	}
+}
@@ -0,0 +1,61 @@
+package cache
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func keys(s *Memory) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	keys := make([]string, 0, len(s.entries))
+	for key := range s.entries {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+func TestMemoryExpires(t *testing.T) {
+	s := NewMemory(10)
+	now := time.Now()
+	s.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	require.NoError(t, s.Set(ctx, "a", []byte("1"), time.Minute, "tag"))
+
+	value, found, err := s.Get(ctx, "a")
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, []byte("1"), value)
+
+	now = now.Add(time.Minute)
+	_, found, err = s.Get(ctx, "a")
+	require.NoError(t, err)
+	assert.False(t, found)
+	assert.Empty(t, s.tags)
+}
+
+func TestMemoryEvictsExpiredFirst(t *testing.T) {
+	s := NewMemory(2)
+	now := time.Now()
+	s.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	require.NoError(t, s.Set(ctx, "short", []byte("1"), time.Second))
+	require.NoError(t, s.Set(ctx, "long", []byte("2"), time.Hour))
+	now = now.Add(2 * time.Second)
+	require.NoError(t, s.Set(ctx, "new", []byte("3"), time.Hour))
+
+	assert.Equal(t, []string{"long", "new"}, keys(s))
+
+	require.NoError(t, s.Set(ctx, "newer", []byte("4"), time.Hour))
+	assert.Len(t, keys(s), 2)
+	assert.Contains(t, keys(s), "newer")
+}
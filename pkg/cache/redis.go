@@ -0,0 +1,80 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	keyPrefix = "cache:"
+	tagPrefix = "cache-tag:"
+)
+
+// Redis shares cached values between the replicas of a service. Every tag is a set of the keys
+// stored with it, which expires with the last of them.
+type Redis struct {
+	client *redis.Client
+}
+
+func NewRedis(client *redis.Client) *Redis {
+	return &Redis{client: client}
+}
+
+func (s *Redis) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	value, err := s.client.Get(ctx, keyPrefix+key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to read cache entry: %w", err)
+	}
+	return value, true, nil
+}
+
+func (s *Redis) Set(ctx context.Context, key string, value []byte, ttl time.Duration, tags ...string) error {
+	_, err := s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, keyPrefix+key, value, ttl)
+		for _, tag := range tags {
+			pipe.SAdd(ctx, tagPrefix+tag, keyPrefix+key)
+			// A new set gets the TTL of the entry, an existing one is only extended
+			pipe.ExpireNX(ctx, tagPrefix+tag, ttl)
+			pipe.ExpireGT(ctx, tagPrefix+tag, ttl)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to write cache entry: %w", err)
+	}
+	return nil
+}
+
+func (s *Redis) Delete(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	prefixed := make([]string, len(keys))
+	for i, key := range keys {
+		prefixed[i] = keyPrefix + key
+	}
+	if err := s.client.Del(ctx, prefixed...).Err(); err != nil {
+		return fmt.Errorf("failed to delete cache entries: %w", err)
+	}
+	return nil
+}
+
+func (s *Redis) InvalidateTags(ctx context.Context, tags ...string) error {
+	for _, tag := range tags {
+		keys, err := s.client.SMembers(ctx, tagPrefix+tag).Result()
+		if err != nil {
+			return fmt.Errorf("failed to read cache tag %s: %w", tag, err)
+		}
+		if err := s.client.Del(ctx, append(keys, tagPrefix+tag)...).Err(); err != nil {
+			return fmt.Errorf("failed to invalidate cache tag %s: %w", tag, err)
+		}
+	}
+	return nil
+}
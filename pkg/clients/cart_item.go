@@ -0,0 +1,47 @@
+package clients
+
+import (
+	"context"
+	"net/http"
+	"th3y3m/e-commerce-microservices/pkg/constant"
+)
+
+type CartItem struct {
+	CartID    int64 `json:"cart_id"`
+	ProductID int64 `json:"product_id"`
+	Quantity  int   `json:"quantity"`
+}
+
+// CartItemFilter selects cart items, by the fields that are set
+type CartItemFilter struct {
+	CartID    *int64
+	ProductID *int64
+}
+
+type CartItemClient struct {
+	c *client
+}
+
+// NewCartItemClient returns a client of the cart item service, called with httpClient, the service
+// client of the calling service, and opts
+func NewCartItemClient(httpClient *http.Client, opts Options) *CartItemClient {
+	return &CartItemClient{c: newClient(httpClient, opts, "cart item", constant.CART_ITEM_SERVICE)}
+}
+
+func (c *CartItemClient) List(ctx context.Context, filter CartItemFilter) ([]CartItem, error) {
+	var items []CartItem
+	query := idQuery(map[string]*int64{"cart_id": filter.CartID, "product_id": filter.ProductID})
+	if err := c.c.do(ctx, http.MethodGet, "", query, nil, &items); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// Save sets the quantity of a product in a cart, adding the product when it is not in it
+func (c *CartItemClient) Save(ctx context.Context, item CartItem) error {
+	return c.c.do(ctx, http.MethodPut, "/UpdateOrCreateCartItem", nil, item, nil)
+}
+
+func (c *CartItemClient) Delete(ctx context.Context, cartID, productID int64) error {
+	return c.c.do(ctx, http.MethodDelete, "", nil, CartItem{CartID: cartID, ProductID: productID}, nil)
+}
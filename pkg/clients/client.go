@@ -0,0 +1,220 @@
+package clients
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"th3y3m/e-commerce-microservices/pkg/authz"
+	"th3y3m/e-commerce-microservices/pkg/config"
+	"time"
+)
+
+const (
+	defaultTimeout  = 5 * time.Second
+	defaultAttempts = 3
+	defaultBackoff  = 100 * time.Millisecond
+	// Responses are read whole, so the connection can be reused, up to this size
+	maxResponseSize = 4 << 20
+)
+
+// Options tunes the calls of the clients
+type Options struct {
+	// Deadline of each attempt, unless the context of the call ends sooner
+	Timeout time.Duration
+	// Attempts of idempotent calls that failed to connect or got a 502, 503 or 504
+	Attempts int
+	// Wait before the second attempt, doubled for each one after it
+	Backoff time.Duration
+}
+
+// NewOptions returns the options of the clients block, with the defaults for those not set
+func NewOptions(c config.Clients) Options {
+	opts := Options{
+		Timeout:  c.Timeout,
+		Attempts: c.Attempts,
+		Backoff:  c.Backoff,
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = defaultTimeout
+	}
+	if opts.Attempts <= 0 {
+		opts.Attempts = defaultAttempts
+	}
+	if opts.Backoff <= 0 {
+		opts.Backoff = defaultBackoff
+	}
+	return opts
+}
+
+// Error is the response of a service to a call that did not succeed
+type Error struct {
+	Service    string
+	Method     string
+	Path       string
+	StatusCode int
+	// The error the service gave in its body, if any
+	Message string
+}
+
+func (e *Error) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("%s service: %s %s returned %d", e.Service, e.Method, e.Path, e.StatusCode)
+	}
+	return fmt.Sprintf("%s service: %s %s returned %d: %s", e.Service, e.Method, e.Path, e.StatusCode, e.Message)
+}
+
+// IsNotFound reports whether err is a 404 from another service
+func IsNotFound(err error) bool {
+	var e *Error
+	return errors.As(err, &e) && e.StatusCode == http.StatusNotFound
+}
+
+// RequestOption changes a request before it is sent
+type RequestOption func(req *http.Request)
+
+// OnBehalfOf forwards the identity of the user of an incoming request, so the called service
+// applies its rules for that user instead of trusting the calling service
+func OnBehalfOf(incoming http.Header) RequestOption {
+	return func(req *http.Request) {
+		authz.CopyIdentity(req.Header, incoming)
+	}
+}
+
+// client sends the calls of one service to another: JSON in and out, a deadline per attempt,
+// retries of idempotent calls and errors decoded into *Error. Requests are signed as the
+// calling service, traced and resolved by pkg/discovery through the authz service client
+// it is given.
+type client struct {
+	http *http.Client
+	// Name of the called service, for errors
+	service string
+	// Logical URL of the API of the called service, from pkg/constant
+	base string
+	opts Options
+}
+
+func newClient(httpClient *http.Client, opts Options, service, base string) *client {
+	return &client{http: httpClient, service: service, base: base, opts: opts}
+}
+
+// idempotent methods may be sent again when an attempt failed
+func idempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+func retryable(status int) bool {
+	return status == http.StatusBadGateway || status == http.StatusServiceUnavailable || status == http.StatusGatewayTimeout
+}
+
+// do sends in, if not nil, as the JSON body of method path?query and decodes the response into
+// out, if not nil
+func (c *client) do(ctx context.Context, method, path string, query url.Values, in, out any, opts ...RequestOption) error {
+	var body []byte
+	if in != nil {
+		var err error
+		if body, err = json.Marshal(in); err != nil {
+			return fmt.Errorf("%s service: encoding %s %s: %w", c.service, method, path, err)
+		}
+	}
+
+	target := c.base + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+
+	attempts := 1
+	if idempotent(method) {
+		attempts = c.opts.Attempts
+	}
+	backoff := c.opts.Backoff
+
+	var err error
+	for attempt := 1; ; attempt++ {
+		var retry bool
+		retry, err = c.attempt(ctx, c.opts.Timeout, method, path, target, body, out, opts)
+		if !retry || attempt >= attempts {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// attempt sends the request once and reports whether a failure may be retried
+func (c *client) attempt(ctx context.Context, timeout time.Duration, method, path, target string, body []byte, out any, opts []RequestOption) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, method, target, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	// Errors name the path on the service, such as /api/cartItems
+	path = req.URL.Path
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", "application/json")
+	for _, opt := range opts {
+		opt(req)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		// Canceled by the caller, rather than timed out by this attempt
+		if errors.Is(ctx.Err(), context.Canceled) {
+			return false, err
+		}
+		return true, fmt.Errorf("%s service: %s %s: %w", c.service, method, path, err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return true, fmt.Errorf("%s service: reading %s %s: %w", c.service, method, path, err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		apiErr := &Error{Service: c.service, Method: method, Path: path, StatusCode: resp.StatusCode}
+		var payload struct {
+			Error string `json:"error"`
+		}
+		if json.Unmarshal(data, &payload) == nil {
+			apiErr.Message = payload.Error
+		}
+		return retryable(resp.StatusCode), apiErr
+	}
+
+	if out == nil {
+		return false, nil
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return false, fmt.Errorf("%s service: decoding %s %s: %w", c.service, method, path, err)
+	}
+	return false, nil
+}
+
+// idQuery returns the query with the given parameters that are set
+func idQuery(params map[string]*int64) url.Values {
+	query := url.Values{}
+	for key, value := range params {
+		if value != nil {
+			query.Set(key, fmt.Sprint(*value))
+		}
+	}
+	return query
+}
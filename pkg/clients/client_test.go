@@ -0,0 +1,130 @@
+package clients
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"th3y3m/e-commerce-microservices/pkg/config"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testClient(srv *httptest.Server, opts Options) *client {
+	return &client{http: srv.Client(), service: "test", base: srv.URL + "/api/test", opts: opts}
+}
+
+func TestNewOptions(t *testing.T) {
+	opts := NewOptions(config.Clients{Timeout: time.Second, Attempts: 5, Backoff: time.Millisecond})
+	assert.Equal(t, Options{Timeout: time.Second, Attempts: 5, Backoff: time.Millisecond}, opts)
+
+	opts = NewOptions(config.Clients{})
+	assert.Equal(t, Options{Timeout: defaultTimeout, Attempts: defaultAttempts, Backoff: defaultBackoff}, opts)
+}
+
+func TestGetIsRetriedWhenUnavailable(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(`{"product_id": 7, "price": 12.5}`))
+	}))
+	defer srv.Close()
+
+	products := &ProductClient{c: testClient(srv, Options{Timeout: time.Second, Attempts: 3, Backoff: time.Millisecond})}
+	product, err := products.Get(context.Background(), 7)
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), product.ProductID)
+	assert.Equal(t, 12.5, product.Price)
+	assert.Equal(t, int32(3), calls.Load())
+}
+
+func TestPostIsNotRetried(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	payments := &PaymentClient{c: testClient(srv, Options{Timeout: time.Second, Attempts: 3, Backoff: time.Millisecond})}
+	err := payments.Create(context.Background(), CreatePayment{OrderID: 1})
+
+	var apiErr *Error
+	require.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, http.StatusServiceUnavailable, apiErr.StatusCode)
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestErrorsAreDecoded(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"error": "Product discount not found"}`))
+	}))
+	defer srv.Close()
+
+	discounts := &ProductDiscountClient{c: testClient(srv, Options{Timeout: time.Second, Attempts: 3, Backoff: time.Millisecond})}
+	_, err := discounts.List(context.Background(), ProductDiscountFilter{})
+
+	assert.True(t, IsNotFound(err))
+	assert.EqualError(t, err, "test service: GET /api/test returned 404: Product discount not found")
+}
+
+func TestListSendsTheFilterAsQuery(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Equal(t, "/api/test", r.URL.Path)
+		assert.Equal(t, "cart_id=3", r.URL.RawQuery)
+		assert.Equal(t, http.NoBody, r.Body)
+		_, _ = w.Write([]byte(`[{"cart_id": 3, "product_id": 9, "quantity": 2}]`))
+	}))
+	defer srv.Close()
+
+	items := &CartItemClient{c: testClient(srv, Options{Timeout: time.Second, Attempts: 1})}
+	cartID := int64(3)
+	list, err := items.List(context.Background(), CartItemFilter{CartID: &cartID})
+	require.NoError(t, err)
+	assert.Equal(t, []CartItem{{CartID: 3, ProductID: 9, Quantity: 2}}, list)
+}
+
+func TestEachAttemptHasADeadline(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			// Hangs until the client gives up on the attempt
+			<-r.Context().Done()
+			return
+		}
+		_, _ = w.Write([]byte(`{"voucher_id": 5}`))
+	}))
+	defer srv.Close()
+
+	vouchers := &VoucherClient{c: testClient(srv, Options{Timeout: 50 * time.Millisecond, Attempts: 2, Backoff: time.Millisecond})}
+	voucher, err := vouchers.Get(context.Background(), 5)
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), voucher.VoucherID)
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func TestCanceledCallsAreNotRetried(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		<-r.Context().Done()
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+
+	orders := &OrderClient{c: testClient(srv, Options{Timeout: time.Second, Attempts: 3, Backoff: time.Millisecond})}
+	_, err := orders.Get(ctx, 1)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, int32(1), calls.Load())
+}
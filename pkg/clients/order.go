@@ -0,0 +1,176 @@
+package clients
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"th3y3m/e-commerce-microservices/pkg/constant"
+	"th3y3m/e-commerce-microservices/pkg/util"
+	"time"
+)
+
+type Order struct {
+	OrderID               int64   `json:"order_id"`
+	CustomerID            int64   `json:"customer_id"`
+	OrderDate             string  `json:"order_date"`
+	TotalAmount           float64 `json:"total_amount"`
+	OrderStatus           string  `json:"order_status"`
+	ShippingAddress       string  `json:"shipping_address"`
+	CourierID             int64   `json:"courier_id"`
+	FreightPrice          float64 `json:"freight_price"`
+	EstimatedDeliveryDate string  `json:"estimated_delivery_date"`
+	ActualDeliveryDate    string  `json:"actual_delivery_date"`
+	VoucherID             int64   `json:"voucher_id"`
+	IsDeleted             bool    `json:"is_deleted"`
+	CreatedAt             string  `json:"created_at"`
+	UpdatedAt             string  `json:"updated_at"`
+}
+
+type UpdateOrder struct {
+	OrderID               int64     `json:"order_id"`
+	CustomerID            int64     `json:"customer_id"`
+	OrderDate             time.Time `json:"order_date"`
+	TotalAmount           float64   `json:"total_amount"`
+	OrderStatus           string    `json:"order_status"`
+	ShippingAddress       string    `json:"shipping_address"`
+	CourierID             int64     `json:"courier_id"`
+	FreightPrice          float64   `json:"freight_price"`
+	EstimatedDeliveryDate time.Time `json:"estimated_delivery_date"`
+	ActualDeliveryDate    time.Time `json:"actual_delivery_date"`
+	VoucherID             int64     `json:"voucher_id"`
+	IsDeleted             bool      `json:"is_deleted"`
+}
+
+type OrderClient struct {
+	c *client
+}
+
+// NewOrderClient returns a client of the order service, called with httpClient, the service client
+// of the calling service, and opts
+func NewOrderClient(httpClient *http.Client, opts Options) *OrderClient {
+	return &OrderClient{c: newClient(httpClient, opts, "order", constant.ORDER_SERVICE)}
+}
+
+func (c *OrderClient) Get(ctx context.Context, orderID int64, opts ...RequestOption) (*Order, error) {
+	var order Order
+	if err := c.c.do(ctx, http.MethodGet, fmt.Sprintf("/%d", orderID), nil, nil, &order, opts...); err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+func (c *OrderClient) Update(ctx context.Context, order UpdateOrder) error {
+	return c.c.do(ctx, http.MethodPut, "", nil, order, nil)
+}
+
+// UpdateStatus saves the order, as read from the order service, with the given status
+func (c *OrderClient) UpdateStatus(ctx context.Context, order *Order, status string) error {
+	return c.Update(ctx, UpdateOrder{
+		OrderID:               order.OrderID,
+		CustomerID:            order.CustomerID,
+		OrderDate:             util.ParseTime(order.OrderDate),
+		TotalAmount:           order.TotalAmount,
+		OrderStatus:           status,
+		ShippingAddress:       order.ShippingAddress,
+		CourierID:             order.CourierID,
+		FreightPrice:          order.FreightPrice,
+		EstimatedDeliveryDate: util.ParseTime(order.EstimatedDeliveryDate),
+		ActualDeliveryDate:    util.ParseTime(order.ActualDeliveryDate),
+		VoucherID:             order.VoucherID,
+		IsDeleted:             order.IsDeleted,
+	})
+}
+
+type OrderDetail struct {
+	OrderID   int64   `json:"order_id"`
+	ProductID int64   `json:"product_id"`
+	Quantity  int     `json:"quantity"`
+	UnitPrice float64 `json:"unit_price"`
+}
+
+// OrderDetailFilter selects order details, by the fields that are set
+type OrderDetailFilter struct {
+	OrderID   *int64
+	ProductID *int64
+}
+
+type OrderDetailClient struct {
+	c *client
+}
+
+// NewOrderDetailClient returns a client of the order detail service, called with httpClient, the
+// service client of the calling service, and opts
+func NewOrderDetailClient(httpClient *http.Client, opts Options) *OrderDetailClient {
+	return &OrderDetailClient{c: newClient(httpClient, opts, "order detail", constant.ORDER_DETAILS_SERVICE)}
+}
+
+func (c *OrderDetailClient) List(ctx context.Context, filter OrderDetailFilter) ([]OrderDetail, error) {
+	var details []OrderDetail
+	query := idQuery(map[string]*int64{"order_id": filter.OrderID, "product_id": filter.ProductID})
+	if err := c.c.do(ctx, http.MethodGet, "", query, nil, &details); err != nil {
+		return nil, err
+	}
+	return details, nil
+}
+
+func (c *OrderDetailClient) Create(ctx context.Context, detail OrderDetail) error {
+	return c.c.do(ctx, http.MethodPost, "", nil, detail, nil)
+}
+
+type CreatePayment struct {
+	OrderID          int64   `json:"order_id"`
+	PaymentAmount    float64 `json:"payment_amount"`
+	PaymentMethod    string  `json:"payment_method"`
+	PaymentStatus    string  `json:"payment_status"`
+	PaymentSignature string  `json:"payment_signature"`
+	// Transaction ID of the payment provider, for the PaymentCompleted event
+	TransactionID string `json:"transaction_id,omitempty"`
+}
+
+type PaymentClient struct {
+	c *client
+}
+
+// NewPaymentClient returns a client of the payment service, called with httpClient, the service
+// client of the calling service, and opts
+func NewPaymentClient(httpClient *http.Client, opts Options) *PaymentClient {
+	return &PaymentClient{c: newClient(httpClient, opts, "payment", constant.PAYMENT_SERVICE)}
+}
+
+func (c *PaymentClient) Create(ctx context.Context, payment CreatePayment) error {
+	return c.c.do(ctx, http.MethodPost, "", nil, payment, nil)
+}
+
+// GatewayClient asks a payment gateway service, MoMo or VNPay, for the URL the customer pays
+// an order at
+type GatewayClient struct {
+	c *client
+}
+
+// NewMoMoClient returns a client of the MoMo service, called with httpClient, the service client of
+// the calling service, and opts
+func NewMoMoClient(httpClient *http.Client, opts Options) *GatewayClient {
+	return &GatewayClient{c: newClient(httpClient, opts, "momo", constant.MOMO_SERVICE)}
+}
+
+// NewVnPayClient returns a client of the VNPay service, called with httpClient, the service client
+// of the calling service, and opts
+func NewVnPayClient(httpClient *http.Client, opts Options) *GatewayClient {
+	return &GatewayClient{c: newClient(httpClient, opts, "vnpay", constant.VNPAY_SERVICE)}
+}
+
+func (c *GatewayClient) PaymentURL(ctx context.Context, orderID int64, amount float64) (string, error) {
+	query := url.Values{
+		"amount":  {strconv.FormatFloat(amount, 'f', 2, 64)},
+		"orderID": {strconv.FormatInt(orderID, 10)},
+	}
+	var out struct {
+		PaymentURL string `json:"payment_url"`
+	}
+	if err := c.c.do(ctx, http.MethodPost, "", query, nil, &out); err != nil {
+		return "", err
+	}
+	return out.PaymentURL, nil
+}
@@ -0,0 +1,110 @@
+package clients
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"th3y3m/e-commerce-microservices/pkg/constant"
+)
+
+type Product struct {
+	ProductID   int64   `json:"product_id"`
+	SellerID    int64   `json:"seller_id"`
+	ProductName string  `json:"product_name"`
+	Description string  `json:"description"`
+	Price       float64 `json:"price"`
+	Quantity    int     `json:"quantity"`
+	CategoryID  int64   `json:"category_id"`
+	ImageURL    string  `json:"image_url"`
+	CreatedAt   string  `json:"created_at"`
+	UpdatedAt   string  `json:"updated_at"`
+	IsDeleted   bool    `json:"is_deleted"`
+}
+
+type ProductClient struct {
+	c *client
+}
+
+// NewProductClient returns a client of the product service, called with httpClient, the service
+// client of the calling service, and opts
+func NewProductClient(httpClient *http.Client, opts Options) *ProductClient {
+	return &ProductClient{c: newClient(httpClient, opts, "product", constant.PRODUCT_SERVICE)}
+}
+
+func (c *ProductClient) Get(ctx context.Context, productID int64) (*Product, error) {
+	var product Product
+	if err := c.c.do(ctx, http.MethodGet, fmt.Sprintf("/%d", productID), nil, nil, &product); err != nil {
+		return nil, err
+	}
+	return &product, nil
+}
+
+// PriceAfterDiscount returns the price of the product with its current discounts applied
+func (c *ProductClient) PriceAfterDiscount(ctx context.Context, productID int64) (float64, error) {
+	var price float64
+	if err := c.c.do(ctx, http.MethodGet, fmt.Sprintf("/discount-price/%d", productID), nil, nil, &price); err != nil {
+		return 0, err
+	}
+	return price, nil
+}
+
+type ProductDiscount struct {
+	ProductID  int64 `json:"product_id"`
+	DiscountID int64 `json:"discount_id"`
+}
+
+// ProductDiscountFilter selects the discounts of products, by the fields that are set
+type ProductDiscountFilter struct {
+	ProductID  *int64
+	DiscountID *int64
+}
+
+type ProductDiscountClient struct {
+	c *client
+}
+
+// NewProductDiscountClient returns a client of the product discount service, called with
+// httpClient, the service client of the calling service, and opts
+func NewProductDiscountClient(httpClient *http.Client, opts Options) *ProductDiscountClient {
+	return &ProductDiscountClient{c: newClient(httpClient, opts, "product discount", constant.PRODUCT_DISCOUNT_SERVICE)}
+}
+
+// List returns the matching product discounts, and an error for which IsNotFound holds when
+// there are none
+func (c *ProductDiscountClient) List(ctx context.Context, filter ProductDiscountFilter) ([]ProductDiscount, error) {
+	var discounts []ProductDiscount
+	query := idQuery(map[string]*int64{"product_id": filter.ProductID, "discount_id": filter.DiscountID})
+	if err := c.c.do(ctx, http.MethodGet, "", query, nil, &discounts); err != nil {
+		return nil, err
+	}
+	return discounts, nil
+}
+
+type Discount struct {
+	DiscountID    int64   `json:"discount_id"`
+	DiscountType  string  `json:"discount_type"`
+	DiscountValue float64 `json:"discount_value"`
+	StartDate     string  `json:"start_date"`
+	EndDate       string  `json:"end_date"`
+	IsDeleted     bool    `json:"is_deleted"`
+	CreatedAt     string  `json:"created_at"`
+	UpdatedAt     string  `json:"updated_at"`
+}
+
+type DiscountClient struct {
+	c *client
+}
+
+// NewDiscountClient returns a client of the discount service, called with httpClient, the service
+// client of the calling service, and opts
+func NewDiscountClient(httpClient *http.Client, opts Options) *DiscountClient {
+	return &DiscountClient{c: newClient(httpClient, opts, "discount", constant.DISCOUNT_SERVICE)}
+}
+
+func (c *DiscountClient) Get(ctx context.Context, discountID int64) (*Discount, error) {
+	var discount Discount
+	if err := c.c.do(ctx, http.MethodGet, fmt.Sprintf("/%d", discountID), nil, nil, &discount); err != nil {
+		return nil, err
+	}
+	return &discount, nil
+}
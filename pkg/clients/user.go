@@ -0,0 +1,110 @@
+package clients
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"th3y3m/e-commerce-microservices/pkg/constant"
+	"time"
+)
+
+type User struct {
+	UserID       int64  `json:"user_id"`
+	Email        string `json:"email"`
+	PasswordHash string `json:"password_hash"`
+	FullName     string `json:"full_name"`
+	PhoneNumber  string `json:"phone_number"`
+	Address      string `json:"address"`
+	Role         string `json:"role"`
+	ImageURL     string `json:"image_url"`
+	Provider     string `json:"provider"`
+	CreatedAt    string `json:"created_at"`
+	UpdatedAt    string `json:"updated_at"`
+	Token        string `json:"token"`
+	TokenExpires string `json:"token_expires"`
+	IsVerified   bool   `json:"is_verified"`
+	IsDeleted    bool   `json:"is_deleted"`
+}
+
+type CreateUser struct {
+	Email      string `json:"email"`
+	Password   string `json:"password"`
+	Role       string `json:"role"`
+	ImageURL   string `json:"image_url"`
+	Provider   string `json:"provider"`
+	IsVerified *bool  `json:"is_verified"`
+}
+
+type UpdateUser struct {
+	UserID       int64     `json:"user_id"`
+	Email        string    `json:"email"`
+	PasswordHash string    `json:"password_hash"`
+	FullName     string    `json:"full_name"`
+	PhoneNumber  string    `json:"phone_number"`
+	Address      string    `json:"address"`
+	Role         string    `json:"role"`
+	ImageURL     string    `json:"image_url"`
+	Token        string    `json:"token"`
+	TokenExpires time.Time `json:"token_expires"`
+	IsVerified   bool      `json:"is_verified"`
+	IsDeleted    bool      `json:"is_deleted"`
+}
+
+type UserClient struct {
+	c *client
+}
+
+// NewUserClient returns a client of the user service, called with httpClient, the service client of
+// the calling service, and opts
+func NewUserClient(httpClient *http.Client, opts Options) *UserClient {
+	return &UserClient{c: newClient(httpClient, opts, "user", constant.USER_SERVICE)}
+}
+
+// Get returns the user with the given ID. The user service answers with an empty user, with a
+// zero UserID, when there is none.
+func (c *UserClient) Get(ctx context.Context, userID int64) (*User, error) {
+	return c.get(ctx, url.Values{"user_id": {strconv.FormatInt(userID, 10)}})
+}
+
+// GetByEmail returns the user with the given email, or an empty user when there is none
+func (c *UserClient) GetByEmail(ctx context.Context, email string) (*User, error) {
+	return c.get(ctx, url.Values{"email": {email}})
+}
+
+func (c *UserClient) get(ctx context.Context, query url.Values) (*User, error) {
+	var user User
+	if err := c.c.do(ctx, http.MethodGet, "/get-user", query, nil, &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (c *UserClient) Create(ctx context.Context, user CreateUser) (*User, error) {
+	var created User
+	if err := c.c.do(ctx, http.MethodPost, "", nil, user, &created); err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+func (c *UserClient) Update(ctx context.Context, user UpdateUser) (*User, error) {
+	var updated User
+	if err := c.c.do(ctx, http.MethodPut, "", nil, user, &updated); err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+// VerifyToken checks the email verification token of the user, and marks the user verified
+// when it is valid
+func (c *UserClient) VerifyToken(ctx context.Context, userID int64, token string) (bool, error) {
+	query := url.Values{"token": {token}, "user_id": {strconv.FormatInt(userID, 10)}}
+	var out struct {
+		IsValid bool `json:"is_valid"`
+	}
+	if err := c.c.do(ctx, http.MethodPost, "/verify", query, nil, &out); err != nil {
+		return false, err
+	}
+	return out.IsValid, nil
+}
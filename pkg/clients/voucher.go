@@ -0,0 +1,67 @@
+package clients
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"th3y3m/e-commerce-microservices/pkg/constant"
+)
+
+type Voucher struct {
+	VoucherID          int64   `json:"voucher_id"`
+	VoucherCode        string  `json:"voucher_code"`
+	DiscountType       string  `json:"discount_type"`
+	DiscountValue      float64 `json:"discount_value"`
+	MinimumOrderAmount float64 `json:"minimum_order_amount"`
+	MaxDiscountAmount  float64 `json:"max_discount_amount"`
+	StartDate          string  `json:"start_date"`
+	EndDate            string  `json:"end_date"`
+	UsageLimit         int     `json:"usage_limit"`
+	UsageCount         int     `json:"usage_count"`
+	IsDeleted          bool    `json:"is_deleted"`
+	CreatedAt          string  `json:"created_at"`
+	UpdatedAt          string  `json:"updated_at"`
+}
+
+// VoucherOrder is the order, not placed yet, a voucher is checked against
+type VoucherOrder struct {
+	CustomerID      int64   `json:"customer_id"`
+	TotalAmount     float64 `json:"total_amount"`
+	ShippingAddress string  `json:"shipping_address"`
+	CourierID       int64   `json:"courier_id"`
+	FreightPrice    float64 `json:"freight_price"`
+	VoucherID       int64   `json:"voucher_id"`
+}
+
+type VoucherClient struct {
+	c *client
+}
+
+// NewVoucherClient returns a client of the voucher service, called with httpClient, the service
+// client of the calling service, and opts
+func NewVoucherClient(httpClient *http.Client, opts Options) *VoucherClient {
+	return &VoucherClient{c: newClient(httpClient, opts, "voucher", constant.VOUCHER_SERVICE)}
+}
+
+func (c *VoucherClient) Get(ctx context.Context, voucherID int64) (*Voucher, error) {
+	var voucher Voucher
+	if err := c.c.do(ctx, http.MethodGet, fmt.Sprintf("/%d", voucherID), nil, nil, &voucher); err != nil {
+		return nil, err
+	}
+	return &voucher, nil
+}
+
+// CheckUsage reports whether the voucher may be applied to the order
+func (c *VoucherClient) CheckUsage(ctx context.Context, voucherID int64, order VoucherOrder) (bool, error) {
+	in := struct {
+		VoucherID int64        `json:"voucher_id"`
+		Order     VoucherOrder `json:"order"`
+	}{VoucherID: voucherID, Order: order}
+	var out struct {
+		Valid bool `json:"valid"`
+	}
+	if err := c.c.do(ctx, http.MethodPost, "/check-usage", nil, in, &out); err != nil {
+		return false, err
+	}
+	return out.Valid, nil
+}
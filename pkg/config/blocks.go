@@ -0,0 +1,135 @@
+package config
+
+import "time"
+
+// Auth holds the secrets the calls to and between services are verified with
+type Auth struct {
+	// Signs the identity headers the gateway forwards to the services
+	IdentitySecret string `env:"IDENTITY_SECRET" required:"true" secret:"true"`
+	// Signs the short-lived tokens services attach to their calls to each other
+	ServiceTokenSecret string `env:"SERVICE_TOKEN_SECRET" required:"true" secret:"true"`
+}
+
+// JWT holds the secret of the tokens users log in with, for the services that issue or verify them
+type JWT struct {
+	JWTSecret string `env:"JWT_SECRET" required:"true" secret:"true"`
+}
+
+// Tracing selects where spans are exported: none, stdout or otlp
+type Tracing struct {
+	Exporter     string `env:"OTEL_TRACES_EXPORTER" default:"none"`
+	OTLPEndpoint string `env:"OTEL_EXPORTER_OTLP_ENDPOINT"`
+}
+
+// Server tunes the HTTP server and the workers next to it
+type Server struct {
+	// How long a stopping service keeps serving while it reports not ready, so load balancers
+	// and the gateway's health checks stop sending it requests before the listener closes
+	ShutdownDelay time.Duration `env:"SHUTDOWN_DELAY" default:"2s"`
+	// How long a stopping service then waits for in-flight requests and workers. Together with
+	// the delay it stays below the 10 seconds Docker waits after SIGTERM before it kills the container.
+	ShutdownTimeout time.Duration `env:"SHUTDOWN_TIMEOUT" default:"7s"`
+}
+
+// Discovery selects how services find each other: static, dns or redis
+type Discovery struct {
+	Backend string `env:"SERVICE_DISCOVERY" default:"static"`
+	// Instances of the static backend, as product=http://host:8081|http://other:8081,user=...
+	// The dns backend uses them instead of the Docker hostname of the services listed.
+	URLs string `env:"SERVICE_URLS"`
+	// Where the redis backend announces this instance, defaults to its hostname
+	AdvertiseURL      string        `env:"SERVICE_ADVERTISE_URL"`
+	HeartbeatInterval time.Duration `env:"SERVICE_HEARTBEAT_INTERVAL" default:"10s"`
+	// How long the redis backend reuses the instances it resolved before asking Redis again
+	CacheTTL time.Duration `env:"SERVICE_DISCOVERY_CACHE_TTL" default:"2s"`
+}
+
+// Clients tunes the calls services make to each other through pkg/clients
+type Clients struct {
+	// Deadline of each attempt of a call
+	Timeout time.Duration `env:"CLIENT_TIMEOUT" default:"5s"`
+	// Attempts of reads and other idempotent calls, when an instance is down or overloaded
+	Attempts int           `env:"CLIENT_ATTEMPTS" default:"3"`
+	Backoff  time.Duration `env:"CLIENT_BACKOFF" default:"100ms"`
+}
+
+// Base is the configuration every service shares
+type Base struct {
+	Auth
+	Tracing
+	Server
+	Discovery
+	Clients
+	Redis
+}
+
+// Cache tunes how long repositories keep the records they read
+type Cache struct {
+	TTL time.Duration `env:"CACHE_TTL" default:"10m"`
+	// Fraction of the TTL added at random, so entries written together do not expire together
+	Jitter float64 `env:"CACHE_JITTER" default:"0.1"`
+	// How long a missing record is remembered, 0 disables negative caching
+	NegativeTTL time.Duration `env:"CACHE_NEGATIVE_TTL" default:"30s"`
+}
+
+type Postgres struct {
+	ConnectionString string `env:"CONNECTION_STRING" required:"true" secret:"true"`
+}
+
+// Redis is optional, services fall back to per-replica caches without it. The redis discovery
+// backend keeps the instances of the services in it.
+type Redis struct {
+	URI      string `env:"REDIS_URI"`
+	Password string `env:"REDIS_PASSWORD" secret:"true"`
+	DB       string `env:"REDIS_DB"`
+}
+
+type RabbitMQ struct {
+	// Holds the credentials of the broker
+	URI string `env:"RABBITMQ_URI" secret:"true"`
+	// memory passes events in process instead of through RabbitMQ
+	Broker string `env:"BROKER"`
+	// Messages delivered to a consumer and not acknowledged yet
+	Prefetch int `env:"RABBITMQ_PREFETCH" default:"10"`
+	// Messages a consumer handles at the same time
+	Concurrency int `env:"RABBITMQ_CONCURRENCY" default:"1"`
+	// Failed deliveries are retried this many times before the message is dead-lettered
+	MaxRetries int `env:"RABBITMQ_MAX_RETRIES" default:"3"`
+	// Delay before the first retry, doubled for every following one
+	RetryDelay time.Duration `env:"RABBITMQ_RETRY_DELAY" default:"1s"`
+}
+
+// InMemory reports whether the in-process broker replaces RabbitMQ, for tests and local runs
+func (r RabbitMQ) InMemory() bool {
+	return r.Broker == "memory"
+}
+
+// Outbox tunes the relay publishing the events services write to their outbox
+type Outbox struct {
+	// Time between two polls of the outbox when it was found empty
+	PollInterval time.Duration `env:"OUTBOX_POLL_INTERVAL" default:"1s"`
+	// Messages published per transaction
+	BatchSize int `env:"OUTBOX_BATCH_SIZE" default:"100"`
+	// Failed publishes of a message before it is parked and the messages after it go on
+	MaxAttempts int `env:"OUTBOX_MAX_ATTEMPTS" default:"10"`
+	// Sent messages are deleted after this long
+	Retention time.Duration `env:"OUTBOX_RETENTION" default:"24h"`
+}
+
+// Inbox tunes how consumers remember the events they processed
+type Inbox struct {
+	// Far longer than a message spends in retries
+	TTL time.Duration `env:"INBOX_TTL" default:"168h"`
+}
+
+// Elasticsearch is optional, searches fall back to the database without it
+type Elasticsearch struct {
+	URL string `env:"ELASTICSEARCH_URL"`
+}
+
+// Service is the configuration of a service with a database and a cache
+type Service struct {
+	Base
+	Postgres
+	Cache
+}
@@ -0,0 +1,251 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// The .env file at the root of the repository, as services are started from their directory
+const defaultFile = "../../.env"
+
+// Shown instead of the value of secret settings
+const redacted = "[REDACTED]"
+
+// Settings are the fields of a config struct bound with tags:
+//
+//	env:"JWT_SECRET"   name of the variable in the environment and the config file
+//	default:"10s"      value used when the setting is not set anywhere
+//	required:"true"    loading fails when the setting is empty
+//	secret:"true"      the value is redacted when the config is printed
+//
+// Every setting can also be given as a flag, --jwt-secret for JWT_SECRET. Nested and embedded
+// structs without an env tag are walked, so configs are built from the blocks of this package.
+type setting struct {
+	env      string
+	def      string
+	required bool
+	secret   bool
+	value    reflect.Value
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+func settings(cfg any) ([]setting, error) {
+	v := reflect.ValueOf(cfg)
+	if v.Kind() != reflect.Pointer || v.Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf("config: %T is not a pointer to a struct", cfg)
+	}
+	var all []setting
+	walk(v.Elem(), &all)
+	return all, nil
+}
+
+func walk(v reflect.Value, all *[]setting) {
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		env, ok := field.Tag.Lookup("env")
+		if !ok {
+			if field.Type.Kind() == reflect.Struct {
+				walk(v.Field(i), all)
+			}
+			continue
+		}
+		*all = append(*all, setting{
+			env:      env,
+			def:      field.Tag.Get("default"),
+			required: field.Tag.Get("required") == "true",
+			secret:   field.Tag.Get("secret") == "true",
+			value:    v.Field(i),
+		})
+	}
+}
+
+// flagName turns JWT_SECRET into jwt-secret
+func flagName(env string) string {
+	return strings.ReplaceAll(strings.ToLower(env), "_", "-")
+}
+
+// Load fills cfg, a pointer to a config struct, from the command-line args, the environment and
+// the config file, in that order of precedence. The config file is --config, CONFIG_FILE or the
+// .env of the repository; only a file that was asked for has to exist. printConfig reports whether
+// --print-config was given.
+//
+// Loaded settings, and the other variables of the config file, are kept in viper and exported to
+// the environment, for the packages and libraries that read them there.
+func Load(cfg any, args []string) (printConfig bool, err error) {
+	all, err := settings(cfg)
+	if err != nil {
+		return false, err
+	}
+
+	fs := flag.NewFlagSet("service", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	file := fs.String("config", "", "config file, defaults to CONFIG_FILE or "+defaultFile)
+	fs.BoolVar(&printConfig, "print-config", false, "print the configuration with secrets redacted, then exit")
+	// Flags by name, with the setting they set
+	envs := map[string]string{}
+	for _, s := range all {
+		fs.String(flagName(s.env), "", "sets "+s.env)
+		envs[flagName(s.env)] = s.env
+	}
+	if err := fs.Parse(args); err != nil {
+		return false, fmt.Errorf("config: %w", err)
+	}
+
+	viper.AutomaticEnv()
+	if err := readFile(*file); err != nil {
+		return printConfig, err
+	}
+	fs.Visit(func(f *flag.Flag) {
+		if env, ok := envs[f.Name]; ok {
+			viper.Set(env, f.Value.String())
+		}
+	})
+
+	var missing []string
+	var errs []error
+	for _, s := range all {
+		raw := viper.GetString(s.env)
+		if raw == "" && s.def != "" {
+			raw = s.def
+			viper.SetDefault(s.env, s.def)
+		}
+		if raw == "" {
+			if s.required {
+				missing = append(missing, s.env)
+			}
+			continue
+		}
+		if err := set(s.value, raw); err != nil {
+			errs = append(errs, fmt.Errorf("invalid %s %q: %w", s.env, raw, err))
+		}
+	}
+	if len(missing) > 0 {
+		errs = append(errs, fmt.Errorf("missing required settings: %s", strings.Join(missing, ", ")))
+	}
+	if len(errs) > 0 {
+		return printConfig, fmt.Errorf("config: %w", errors.Join(errs...))
+	}
+
+	export()
+	return printConfig, nil
+}
+
+func readFile(file string) error {
+	explicit := file != ""
+	if !explicit {
+		file = viper.GetString("CONFIG_FILE")
+		explicit = file != ""
+	}
+	if !explicit {
+		file = defaultFile
+	}
+
+	viper.SetConfigFile(file)
+	if err := viper.ReadInConfig(); err != nil {
+		if explicit {
+			return fmt.Errorf("config: reading %s: %w", file, err)
+		}
+		log.Printf("No config file at %s, using the environment: %v", file, err)
+	}
+	return nil
+}
+
+// export copies the settings viper knows, such as those of the config file, to the environment
+// where they are not set yet
+func export() {
+	for _, key := range viper.AllKeys() {
+		env := strings.ToUpper(key)
+		if _, ok := os.LookupEnv(env); ok {
+			continue
+		}
+		if value := viper.GetString(key); value != "" {
+			_ = os.Setenv(env, value)
+		}
+	}
+}
+
+func set(v reflect.Value, raw string) error {
+	switch {
+	case v.Type() == durationType:
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(d))
+	case v.Kind() == reflect.String:
+		v.SetString(raw)
+	case v.Kind() == reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case v.CanInt():
+		n, err := strconv.ParseInt(raw, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case v.CanFloat():
+		f, err := strconv.ParseFloat(raw, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+	return nil
+}
+
+// Print writes the settings of cfg as KEY=value lines, with the values of secrets redacted
+func Print(w io.Writer, cfg any) error {
+	all, err := settings(cfg)
+	if err != nil {
+		return err
+	}
+	for _, s := range all {
+		value := fmt.Sprint(s.value.Interface())
+		if s.secret && !s.value.IsZero() {
+			value = redacted
+		}
+		if _, err := fmt.Fprintf(w, "%s=%s\n", s.env, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// MustLoad loads cfg from the command line, the environment and the config file, and stops the
+// service when it is invalid. With --print-config it prints the configuration and exits instead.
+func MustLoad(service string, cfg any) {
+	printConfig, err := Load(cfg, os.Args[1:])
+	if printConfig {
+		if printErr := Print(os.Stdout, cfg); printErr != nil {
+			log.Fatalf("Failed to print the %s configuration: %v", service, printErr)
+		}
+		// Printed even when invalid, to show what is missing
+		if err != nil {
+			log.Fatalf("Invalid %s configuration: %v", service, err)
+		}
+		os.Exit(0)
+	}
+	if err != nil {
+		log.Fatalf("Invalid %s configuration: %v", service, err)
+	}
+	log.Println("Config loaded successfully")
+}
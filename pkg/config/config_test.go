@@ -0,0 +1,100 @@
+package config
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testConfig struct {
+	JWT
+	Redis
+	Server
+	Endpoint string        `env:"TEST_ENDPOINT" required:"true"`
+	Retries  int           `env:"TEST_RETRIES" default:"3"`
+	Timeout  time.Duration `env:"TEST_TIMEOUT"`
+	Enabled  bool          `env:"TEST_ENABLED"`
+}
+
+func writeFile(t *testing.T, content string) string {
+	t.Helper()
+	file := filepath.Join(t.TempDir(), ".env")
+	require.NoError(t, os.WriteFile(file, []byte(content), 0o600))
+	return file
+}
+
+func setup(t *testing.T) {
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+	// Unset by the test, so values exported by Load do not leak into the next one
+	for _, env := range []string{"JWT_SECRET", "REDIS_PASSWORD", "TEST_ENDPOINT", "TEST_RETRIES", "TEST_TIMEOUT", "TEST_ENABLED", "SHUTDOWN_TIMEOUT"} {
+		t.Setenv(env, "")
+		os.Unsetenv(env)
+	}
+}
+
+func TestLoadPrecedence(t *testing.T) {
+	setup(t)
+	file := writeFile(t, "JWT_SECRET=from-file\nTEST_ENDPOINT=file\nTEST_TIMEOUT=5s\nTEST_ENABLED=true\n")
+	t.Setenv("TEST_ENDPOINT", "env")
+
+	var cfg testConfig
+	printConfig, err := Load(&cfg, []string{"--config", file, "--test-timeout", "2s"})
+	require.NoError(t, err)
+	assert.False(t, printConfig)
+
+	assert.Equal(t, "from-file", cfg.JWTSecret)
+	assert.Equal(t, "env", cfg.Endpoint)
+	assert.Equal(t, 2*time.Second, cfg.Timeout)
+	assert.True(t, cfg.Enabled)
+	assert.Equal(t, 3, cfg.Retries)
+	assert.Equal(t, 2*time.Second, cfg.ShutdownDelay)
+	assert.Equal(t, 7*time.Second, cfg.ShutdownTimeout)
+
+	// Packages reading viper see the same values
+	assert.Equal(t, 2*time.Second, viper.GetDuration("TEST_TIMEOUT"))
+	assert.Equal(t, 3, viper.GetInt("TEST_RETRIES"))
+}
+
+func TestLoadFailsOnMissingAndInvalidSettings(t *testing.T) {
+	setup(t)
+	file := writeFile(t, "TEST_RETRIES=many\n")
+
+	var cfg testConfig
+	_, err := Load(&cfg, []string{"--config", file})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "missing required settings: JWT_SECRET, TEST_ENDPOINT")
+	assert.Contains(t, err.Error(), `invalid TEST_RETRIES "many"`)
+}
+
+func TestLoadFailsOnMissingRequestedFile(t *testing.T) {
+	setup(t)
+
+	var cfg testConfig
+	_, err := Load(&cfg, []string{"--config", filepath.Join(t.TempDir(), "missing.env")})
+	assert.Error(t, err)
+}
+
+func TestPrintRedactsSecrets(t *testing.T) {
+	setup(t)
+
+	file := writeFile(t, "")
+
+	var cfg testConfig
+	printConfig, err := Load(&cfg, []string{"--config", file, "--print-config", "--jwt-secret", "s3cret", "--test-endpoint", "http://payments"})
+	require.NoError(t, err)
+	assert.True(t, printConfig)
+
+	var out bytes.Buffer
+	require.NoError(t, Print(&out, &cfg))
+	assert.Contains(t, out.String(), "JWT_SECRET=[REDACTED]\n")
+	assert.Contains(t, out.String(), "REDIS_PASSWORD=\n")
+	assert.Contains(t, out.String(), "TEST_ENDPOINT=http://payments\n")
+	assert.NotContains(t, out.String(), "s3cret")
+}
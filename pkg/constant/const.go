@@ -2,51 +2,29 @@ package constant
 
 import "errors"
 
-// const API_GATEWAY = "http://api_gateway_service:9000"
-
-// const OAUTH_SERVICE = "http://oauth_service:8080/auth"
-// const PRODUCT_SERVICE = "http://product_service:8081/api/products"
-// const USER_SERVICE = "http://user_service:8082/api/users"
-// const NEWS_SERVICE = "http://news_service:8083/api/news"
-// const CART_ITEM_SERVICE = "http://cart_items_service:8084/api/cartItems"
-// const CART_SERVICE = "http://cart_service:8085/api/carts"
-// const CATEGORY_SERVICE = "http://category_service:8086/api/categories"
-// const COURIER_SERVICE = "http://courier_service:8087/api/couriers"
-// const DISCOUNT_SERVICE = "http://discount_service:8088/api/discounts"
-// const FREIGHT_RATE_SERVICE = "http://freight_rate_service:8089/api/freightRates"
-// const ORDER_SERVICE = "http://order_service:8090/api/orders"
-// const ORDER_DETAILS_SERVICE = "http://order_detail_service:8091/api/orderDetails"
-// const PRODUCT_DISCOUNT_SERVICE = "http://product_discount_service:8092/api/productDiscounts"
-// const REVIEW_SERVICE = "http://review_service:8093/api/reviews"
-// const PAYMENT_SERVICE = "http://payment_service:8094/api/payments"
-// const VOUCHER_SERVICE = "http://voucher_service:8095/api/vouchers"
-// const MAIL_SERVICE = "http://mail_service:8096/api/mail"
-// const MOMO_SERVICE = "http://momo_service:8097/api/momo"
-// const VNPAY_SERVICE = "http://vnpay_service:8098/api/vnpay"
-// const AUTH_SERVICE = "http://auth_service:8099/api/authentication"
-
-const API_GATEWAY = "http://localhost:9000"
-
-const OAUTH_SERVICE = "http://localhost:8080/auth"
-const PRODUCT_SERVICE = "http://localhost:8081/api/products"
-const USER_SERVICE = "http://localhost:8082/api/users"
-const NEWS_SERVICE = "http://localhost:8083/api/news"
-const CART_ITEM_SERVICE = "http://localhost:8084/api/cartItems"
-const CART_SERVICE = "http://localhost:8085/api/carts"
-const CATEGORY_SERVICE = "http://localhost:8086/api/categories"
-const COURIER_SERVICE = "http://localhost:8087/api/couriers"
-const DISCOUNT_SERVICE = "http://localhost:8088/api/discounts"
-const FREIGHT_RATE_SERVICE = "http://localhost:8089/api/freightRates"
-const ORDER_SERVICE = "http://localhost:8090/api/orders"
-const ORDER_DETAILS_SERVICE = "http://localhost:8091/api/orderDetails"
-const PRODUCT_DISCOUNT_SERVICE = "http://localhost:8092/api/productDiscounts"
-const REVIEW_SERVICE = "http://localhost:8093/api/reviews"
-const PAYMENT_SERVICE = "http://localhost:8094/api/payments"
-const VOUCHER_SERVICE = "http://localhost:8095/api/vouchers"
-const MAIL_SERVICE = "http://localhost:8096/api/mail"
-const MOMO_SERVICE = "http://localhost:8097/api/momo"
-const VNPAY_SERVICE = "http://localhost:8098/api/vnpay"
-const AUTH_SERVICE = "http://localhost:8099/api/authentication"
+// Services are addressed by name, pkg/discovery resolves the host of each call to an instance
+const API_GATEWAY = "http://api_gateway"
+
+const OAUTH_SERVICE = "http://oauth/auth"
+const PRODUCT_SERVICE = "http://product/api/products"
+const USER_SERVICE = "http://user/api/users"
+const NEWS_SERVICE = "http://news/api/news"
+const CART_ITEM_SERVICE = "http://cart_item/api/cartItems"
+const CART_SERVICE = "http://cart/api/carts"
+const CATEGORY_SERVICE = "http://category/api/categories"
+const COURIER_SERVICE = "http://courier/api/couriers"
+const DISCOUNT_SERVICE = "http://discount/api/discounts"
+const FREIGHT_RATE_SERVICE = "http://freight_rate/api/freightRates"
+const ORDER_SERVICE = "http://order/api/orders"
+const ORDER_DETAILS_SERVICE = "http://order_detail/api/orderDetails"
+const PRODUCT_DISCOUNT_SERVICE = "http://product_discount/api/productDiscounts"
+const REVIEW_SERVICE = "http://review/api/reviews"
+const PAYMENT_SERVICE = "http://payment/api/payments"
+const VOUCHER_SERVICE = "http://voucher/api/vouchers"
+const MAIL_SERVICE = "http://mail/api/mail"
+const MOMO_SERVICE = "http://momo/api/momo"
+const VNPAY_SERVICE = "http://vnpay/api/vnpay"
+const AUTH_SERVICE = "http://authentication/api/authentication"
 
 const PAYMENT_RESPONSE_REJECT_URL = "http://localhost:3000/reject"
 const PAYMENT_RESPONSE_CONFIRM_URL = "http://localhost:3000/confirm"
@@ -56,6 +34,7 @@ const PAYMENT_STATUS_COMPLETED = "Completed"
 const PAYMENT_STATUS_FAILED = "Failed"
 
 const ORDER_STATUS_PENDING = "Pending"
+const ORDER_STATUS_PLACED = "Placed"
 const ORDER_STATUS_COMPLETED = "Completed"
 const ORDER_STATUS_FAILED = "Failed"
 const ORDER_STATUS_CANCELLED = "Cancelled"
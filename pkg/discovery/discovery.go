@@ -0,0 +1,125 @@
+package discovery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"th3y3m/e-commerce-microservices/pkg/config"
+	redis_client "th3y3m/e-commerce-microservices/pkg/redis"
+	"time"
+)
+
+// Service is a service the others call, addressed by its name
+type Service struct {
+	Name string
+	// The port it listens on, locally as in Docker
+	Port int
+}
+
+// Host is the name of the service on the Docker network of compose.yml
+func (s Service) Host() string {
+	return s.Name + "_service"
+}
+
+var (
+	APIGateway      = Service{Name: "api_gateway", Port: 9000}
+	OAuth           = Service{Name: "oauth", Port: 8080}
+	Product         = Service{Name: "product", Port: 8081}
+	User            = Service{Name: "user", Port: 8082}
+	News            = Service{Name: "news", Port: 8083}
+	CartItem        = Service{Name: "cart_item", Port: 8084}
+	Cart            = Service{Name: "cart", Port: 8085}
+	Category        = Service{Name: "category", Port: 8086}
+	Courier         = Service{Name: "courier", Port: 8087}
+	Discount        = Service{Name: "discount", Port: 8088}
+	FreightRate     = Service{Name: "freight_rate", Port: 8089}
+	Order           = Service{Name: "order", Port: 8090}
+	OrderDetail     = Service{Name: "order_detail", Port: 8091}
+	ProductDiscount = Service{Name: "product_discount", Port: 8092}
+	Review          = Service{Name: "review", Port: 8093}
+	Payment         = Service{Name: "payment", Port: 8094}
+	Voucher         = Service{Name: "voucher", Port: 8095}
+	Mail            = Service{Name: "mail", Port: 8096}
+	MoMo            = Service{Name: "momo", Port: 8097}
+	VNPay           = Service{Name: "vnpay", Port: 8098}
+	Authentication  = Service{Name: "authentication", Port: 8099}
+)
+
+var services = map[string]Service{}
+
+func init() {
+	for _, s := range []Service{
+		APIGateway, OAuth, Product, User, News, CartItem, Cart, Category, Courier, Discount, FreightRate,
+		Order, OrderDetail, ProductDiscount, Review, Payment, Voucher, Mail, MoMo, VNPay, Authentication,
+	} {
+		services[s.Name] = s
+	}
+}
+
+// Lookup returns the service with the given name
+func Lookup(name string) (Service, bool) {
+	s, ok := services[name]
+	return s, ok
+}
+
+// ErrNoInstances is returned when no instance of a service is running
+var ErrNoInstances = errors.New("no instances")
+
+// Resolver finds the base URLs, such as http://10.0.0.5:8081, of the instances of a service
+type Resolver interface {
+	Resolve(ctx context.Context, service Service) ([]string, error)
+}
+
+// Registrar is implemented by the resolvers instances register with. They must renew their
+// registration before ttl runs out, or they are no longer resolved.
+type Registrar interface {
+	Register(ctx context.Context, service Service, url string, ttl time.Duration) error
+	Deregister(ctx context.Context, service Service, url string) error
+}
+
+// Calls made by the process to each service, to take its instances in turn
+var turns sync.Map
+
+// Pick returns the base URL of one instance of service, the next one on each call
+func Pick(ctx context.Context, r Resolver, service Service) (string, error) {
+	urls, err := r.Resolve(ctx, service)
+	if err != nil {
+		return "", fmt.Errorf("discovery: resolving %s: %w", service.Name, err)
+	}
+	if len(urls) == 0 {
+		return "", fmt.Errorf("discovery: %s: %w", service.Name, ErrNoInstances)
+	}
+	turn, _ := turns.LoadOrStore(service.Name, new(atomic.Uint64))
+	return urls[(turn.(*atomic.Uint64).Add(1)-1)%uint64(len(urls))], nil
+}
+
+// New builds the resolver selected by the discovery block:
+//
+//	static  the URLs of each service, localhost on its port for the others (default)
+//	dns     the URLs of each service when set, the Docker hostname of the service otherwise
+//	redis   the instances registered in Redis with Heartbeat
+//
+// Services build it once and share it between their clients and their heartbeat.
+func New(cfg config.Discovery, redis config.Redis) (Resolver, error) {
+	switch backend := strings.ToLower(cfg.Backend); backend {
+	case "", "static":
+		return ParseStatic(cfg.URLs)
+	case "dns":
+		urls, err := ParseStatic(cfg.URLs)
+		if err != nil {
+			return nil, err
+		}
+		return NewDNS(urls), nil
+	case "redis":
+		client, err := redis_client.NewClient(redis)
+		if err != nil {
+			return nil, fmt.Errorf("discovery: %w", err)
+		}
+		return NewRedis(client, cfg.CacheTTL), nil
+	default:
+		return nil, fmt.Errorf("discovery: unknown SERVICE_DISCOVERY %q", backend)
+	}
+}
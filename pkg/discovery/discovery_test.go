@@ -0,0 +1,118 @@
+package discovery
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseStatic(t *testing.T) {
+	static, err := ParseStatic("product=http://10.0.0.5:8081/ignored|http://10.0.0.6:8081, user=http://user:8082")
+	require.NoError(t, err)
+
+	urls, err := static.Resolve(context.Background(), Product)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"http://10.0.0.5:8081", "http://10.0.0.6:8081"}, urls)
+
+	// Services without URLs run on localhost
+	urls, err = static.Resolve(context.Background(), Order)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"http://localhost:8090"}, urls)
+
+	_, err = ParseStatic("shipping=http://10.0.0.5:8081")
+	assert.Error(t, err)
+	_, err = ParseStatic("product=10.0.0.5")
+	assert.Error(t, err)
+}
+
+func TestPickTakesInstancesInTurn(t *testing.T) {
+	static := Static{"review": {"http://a", "http://b"}}
+
+	var picked []string
+	for i := 0; i < 4; i++ {
+		url, err := Pick(context.Background(), static, Review)
+		require.NoError(t, err)
+		picked = append(picked, url)
+	}
+	assert.Equal(t, []string{"http://a", "http://b", "http://a", "http://b"}, picked)
+
+	_, err := Pick(context.Background(), Static{"review": {}}, Review)
+	assert.NoError(t, err, "an empty list falls back to localhost")
+}
+
+func TestDNS(t *testing.T) {
+	dns := &DNS{lookup: func(_ context.Context, host string) ([]string, error) {
+		if host != "cart_item_service" {
+			return nil, errors.New("no such host")
+		}
+		return []string{"172.18.0.5", "172.18.0.6"}, nil
+	}}
+
+	urls, err := dns.Resolve(context.Background(), CartItem)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"http://172.18.0.5:8084", "http://172.18.0.6:8084"}, urls)
+
+	dns.urls = Static{"cart_item": {"http://cart-items.internal:80"}}
+	urls, err = dns.Resolve(context.Background(), CartItem)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"http://cart-items.internal:80"}, urls)
+
+	_, err = dns.Resolve(context.Background(), Voucher)
+	assert.Error(t, err)
+}
+
+func TestTransportResolvesServiceNames(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, r.URL.String())
+	}))
+	defer upstream.Close()
+
+	resolver := Static{"voucher": {upstream.URL}}
+	client := &http.Client{Transport: Transport(resolver, http.DefaultTransport)}
+
+	for _, url := range []string{"http://voucher/api/vouchers/7?x=1", upstream.URL + "/api/vouchers/7?x=1"} {
+		resp, err := client.Get(url)
+		require.NoError(t, err)
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		require.NoError(t, err)
+		assert.Equal(t, "/api/vouchers/7?x=1", string(body))
+	}
+}
+
+func TestRedisReusesResolvedInstances(t *testing.T) {
+	srv := miniredis.RunT(t)
+	r := NewRedis(redis.NewClient(&redis.Options{Addr: srv.Addr()}), time.Second)
+	ctx := context.Background()
+
+	require.NoError(t, r.Register(ctx, Order, "http://order-1:8090", time.Minute))
+	urls, err := r.Resolve(ctx, Order)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"http://order-1:8090"}, urls)
+
+	// Instances registered by other processes show once the cache expires
+	srv.ZAdd(registryKey(Order), float64(time.Now().Add(time.Minute).UnixMilli()), "http://order-2:8090")
+	urls, err = r.Resolve(ctx, Order)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"http://order-1:8090"}, urls)
+
+	r.now = func() time.Time { return time.Now().Add(2 * time.Second) }
+	urls, err = r.Resolve(ctx, Order)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"http://order-1:8090", "http://order-2:8090"}, urls)
+
+	// Those of this process show at once
+	require.NoError(t, r.Deregister(ctx, Order, "http://order-1:8090"))
+	urls, err = r.Resolve(ctx, Order)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"http://order-2:8090"}, urls)
+}
@@ -0,0 +1,34 @@
+package discovery
+
+import (
+	"context"
+	"net"
+	"strconv"
+)
+
+// DNS resolves services to the addresses of their Docker hostname, one per replica, unless
+// urls lists theirs
+type DNS struct {
+	urls   Static
+	lookup func(ctx context.Context, host string) ([]string, error)
+}
+
+func NewDNS(urls Static) *DNS {
+	return &DNS{urls: urls, lookup: net.DefaultResolver.LookupHost}
+}
+
+func (d *DNS) Resolve(ctx context.Context, service Service) ([]string, error) {
+	if urls := d.urls[service.Name]; len(urls) > 0 {
+		return urls, nil
+	}
+
+	addrs, err := d.lookup(ctx, service.Host())
+	if err != nil {
+		return nil, err
+	}
+	urls := make([]string, 0, len(addrs))
+	for _, addr := range addrs {
+		urls = append(urls, "http://"+net.JoinHostPort(addr, strconv.Itoa(service.Port)))
+	}
+	return urls, nil
+}
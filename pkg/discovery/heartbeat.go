@@ -0,0 +1,71 @@
+package discovery
+
+import (
+	"context"
+	"log"
+	"os"
+	"strconv"
+	"th3y3m/e-commerce-microservices/pkg/config"
+	"time"
+)
+
+const (
+	defaultHeartbeatInterval = 10 * time.Second
+	deregisterTimeout        = 2 * time.Second
+)
+
+// Heartbeat registers this instance of service with resolver and renews the registration
+// every heartbeat interval of cfg until ctx is canceled, then deregisters it. It is meant to
+// run as a server.Worker, and returns at once with resolvers instances do not register with.
+//
+// The instance is announced at the advertise URL of cfg, or at its hostname on the service port.
+func Heartbeat(service Service, resolver Resolver, cfg config.Discovery) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		registrar, ok := resolver.(Registrar)
+		if !ok {
+			return nil
+		}
+
+		url := advertiseURL(service, cfg.AdvertiseURL)
+		interval := cfg.HeartbeatInterval
+		if interval <= 0 {
+			interval = defaultHeartbeatInterval
+		}
+		// Missing two heartbeats in a row takes the instance out of rotation
+		ttl := 3 * interval
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		registered := false
+		for {
+			if err := registrar.Register(ctx, service, url, ttl); err != nil {
+				log.Printf("Failed to register %s at %s: %v", service.Name, url, err)
+			} else if !registered {
+				registered = true
+				log.Printf("Registered %s at %s", service.Name, url)
+			}
+
+			select {
+			case <-ctx.Done():
+				deregisterCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), deregisterTimeout)
+				defer cancel()
+				if err := registrar.Deregister(deregisterCtx, service, url); err != nil {
+					log.Printf("Failed to deregister %s at %s: %v", service.Name, url, err)
+				}
+				return nil
+			case <-ticker.C:
+			}
+		}
+	}
+}
+
+func advertiseURL(service Service, url string) string {
+	if url != "" {
+		return url
+	}
+	host, err := os.Hostname()
+	if err != nil {
+		host = "localhost"
+	}
+	return "http://" + host + ":" + strconv.Itoa(service.Port)
+}
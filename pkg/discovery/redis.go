@@ -0,0 +1,89 @@
+package discovery
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Redis keeps the instances of each service in a sorted set, scored by the time their
+// registration expires, so instances that stop renewing it are no longer resolved. The
+// instances resolved are reused for cacheTTL, so calls do not each cost a round trip to Redis.
+type Redis struct {
+	client   *redis.Client
+	now      func() time.Time
+	cacheTTL time.Duration
+
+	mu       sync.Mutex
+	resolved map[string]resolved
+}
+
+type resolved struct {
+	urls    []string
+	expires time.Time
+}
+
+// NewRedis returns the resolver of the instances registered in client. A cacheTTL of 0 asks
+// Redis on every call.
+func NewRedis(client *redis.Client, cacheTTL time.Duration) *Redis {
+	return &Redis{client: client, now: time.Now, cacheTTL: cacheTTL, resolved: make(map[string]resolved)}
+}
+
+func registryKey(service Service) string {
+	return "discovery:" + service.Name
+}
+
+func (r *Redis) Resolve(ctx context.Context, service Service) ([]string, error) {
+	now := r.now()
+	r.mu.Lock()
+	cached, ok := r.resolved[service.Name]
+	r.mu.Unlock()
+	if ok && now.Before(cached.expires) {
+		return cached.urls, nil
+	}
+
+	urls, err := r.client.ZRangeByScore(ctx, registryKey(service), &redis.ZRangeBy{
+		Min: strconv.FormatInt(now.UnixMilli(), 10),
+		Max: "+inf",
+	}).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	if r.cacheTTL > 0 {
+		r.mu.Lock()
+		r.resolved[service.Name] = resolved{urls: urls, expires: now.Add(r.cacheTTL)}
+		r.mu.Unlock()
+	}
+	return urls, nil
+}
+
+// forget drops the cached instances of service, after this process changed them
+func (r *Redis) forget(service Service) {
+	r.mu.Lock()
+	delete(r.resolved, service.Name)
+	r.mu.Unlock()
+}
+
+func (r *Redis) Register(ctx context.Context, service Service, url string, ttl time.Duration) error {
+	defer r.forget(service)
+	key := registryKey(service)
+	now := r.now()
+	_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZAdd(ctx, key, redis.Z{Score: float64(now.Add(ttl).UnixMilli()), Member: url})
+		// Instances that stopped without deregistering are dropped by the next heartbeat
+		pipe.ZRemRangeByScore(ctx, key, "-inf", "("+strconv.FormatInt(now.UnixMilli(), 10))
+		// The set goes away with the last instance
+		pipe.Expire(ctx, key, ttl)
+		return nil
+	})
+	return err
+}
+
+func (r *Redis) Deregister(ctx context.Context, service Service, url string) error {
+	defer r.forget(service)
+	return r.client.ZRem(ctx, registryKey(service), url).Err()
+}
@@ -0,0 +1,56 @@
+package discovery
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Static resolves services to fixed base URLs by name. Services without URLs run on localhost.
+type Static map[string][]string
+
+// ParseStatic reads the URLs of services written as
+//
+//	product=http://10.0.0.5:8081|http://10.0.0.6:8081,user=http://10.0.0.7:8082
+func ParseStatic(raw string) (Static, error) {
+	static := Static{}
+	for _, entry := range strings.Split(raw, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		name, urls, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("discovery: invalid SERVICE_URLS entry %q, want name=url", entry)
+		}
+		name = strings.TrimSpace(name)
+		if _, ok := Lookup(name); !ok {
+			return nil, fmt.Errorf("discovery: unknown service %q in SERVICE_URLS", name)
+		}
+		for _, raw := range strings.Split(urls, "|") {
+			base, err := parseBaseURL(raw)
+			if err != nil {
+				return nil, err
+			}
+			static[name] = append(static[name], base)
+		}
+	}
+	return static, nil
+}
+
+func parseBaseURL(raw string) (string, error) {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return "", fmt.Errorf("discovery: invalid service URL %q", raw)
+	}
+	return u.Scheme + "://" + u.Host, nil
+}
+
+func (s Static) Resolve(_ context.Context, service Service) ([]string, error) {
+	if urls := s[service.Name]; len(urls) > 0 {
+		return urls, nil
+	}
+	return []string{"http://localhost:" + strconv.Itoa(service.Port)}, nil
+}
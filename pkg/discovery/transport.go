@@ -0,0 +1,40 @@
+package discovery
+
+import (
+	"net/http"
+	"net/url"
+)
+
+// Transport sends requests to http://<service name>/..., such as http://product/api/products/1,
+// to an instance of the service found by resolver. Other requests are sent as is.
+func Transport(resolver Resolver, next http.RoundTripper) http.RoundTripper {
+	return &transport{resolver: resolver, next: next}
+}
+
+type transport struct {
+	resolver Resolver
+	next     http.RoundTripper
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	service, ok := Lookup(req.URL.Host)
+	if !ok {
+		return t.next.RoundTrip(req)
+	}
+
+	base, err := Pick(req.Context(), t.resolver, service)
+	if err != nil {
+		return nil, err
+	}
+	target, err := url.Parse(base)
+	if err != nil {
+		return nil, err
+	}
+
+	// A RoundTripper must not modify the caller's request
+	req = req.Clone(req.Context())
+	req.URL.Scheme = target.Scheme
+	req.URL.Host = target.Host
+	req.Host = ""
+	return t.next.RoundTrip(req)
+}
@@ -7,20 +7,21 @@ import (
 	"fmt"
 	"io"
 	"log"
+	"net/http"
+	"th3y3m/e-commerce-microservices/pkg/config"
+	"th3y3m/e-commerce-microservices/pkg/tracing"
 	"time"
 
 	"github.com/elastic/go-elasticsearch/v7"
-	"github.com/spf13/viper"
 )
 
-func ConnectToElasticsearch() (*elasticsearch.Client, error) {
-	cfg := elasticsearch.Config{
-		Addresses: []string{
-			viper.GetString("ELASTICSEARCH_URL"), // Update with your Elasticsearch server URL
-		},
+func ConnectToElasticsearch(cfg config.Elasticsearch) (*elasticsearch.Client, error) {
+	esCfg := elasticsearch.Config{
+		Addresses: []string{cfg.URL},
+		Transport: tracing.Transport(http.DefaultTransport),
 	}
 
-	es, err := elasticsearch.NewClient(cfg)
+	es, err := elasticsearch.NewClient(esCfg)
 	if err != nil {
 		return nil, fmt.Errorf("error creating Elasticsearch client: %w", err)
 	}
@@ -0,0 +1,29 @@
+package events
+
+import "encoding/json"
+
+const TypeCacheInvalidated = "cache.invalidated"
+
+// CacheInvalidated tells the gateway that the responses under Path are stale
+type CacheInvalidated struct {
+	Path string `json:"path"`
+	// What changed, such as product_updated
+	Reason string `json:"reason"`
+}
+
+func (CacheInvalidated) EventType() string { return TypeCacheInvalidated }
+
+func (CacheInvalidated) EventVersion() int { return 1 }
+
+func init() {
+	registerUpgrade(TypeCacheInvalidated, 0, upgradeCacheInvalidatedV0)
+}
+
+// upgradeCacheInvalidatedV0 reads the {"event", "path"} maps of the product service
+func upgradeCacheInvalidatedV0(data json.RawMessage) (json.RawMessage, error) {
+	fields, err := legacyFields(data)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(CacheInvalidated{Path: fields["path"], Reason: fields["event"]})
+}
@@ -0,0 +1,128 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"th3y3m/e-commerce-microservices/pkg/logging"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrUnexpectedType     = errors.New("events: unexpected event type")
+	ErrUnsupportedVersion = errors.New("events: unsupported event version")
+)
+
+// Event is a domain event published to other services. Its type and version are part of the
+// contract: a change that older consumers cannot read needs a new version and an upgrade from
+// the previous one.
+type Event interface {
+	EventType() string
+	EventVersion() int
+}
+
+// Envelope carries an event with the metadata every consumer needs
+type Envelope struct {
+	ID      string `json:"id"`
+	Type    string `json:"type"`
+	Version int    `json:"version"`
+	// When the producer created the event
+	OccurredAt time.Time `json:"occurred_at"`
+	// Request ID of the request that caused the event
+	CorrelationID string          `json:"correlation_id,omitempty"`
+	Data          json.RawMessage `json:"data"`
+}
+
+// New wraps event in an envelope, correlated with the request of ctx
+func New(ctx context.Context, event Event) (*Envelope, error) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return nil, err
+	}
+	return &Envelope{
+		ID:            uuid.NewString(),
+		Type:          event.EventType(),
+		Version:       event.EventVersion(),
+		OccurredAt:    time.Now().UTC(),
+		CorrelationID: logging.RequestIDFromContext(ctx),
+		Data:          data,
+	}, nil
+}
+
+// upgrade turns the data of an event at some version into the data of the next version
+type upgrade func(data json.RawMessage) (json.RawMessage, error)
+
+// upgrades by event type and by the version they upgrade from
+var upgrades = map[string]map[int]upgrade{}
+
+func registerUpgrade(eventType string, from int, fn upgrade) {
+	if upgrades[eventType] == nil {
+		upgrades[eventType] = map[int]upgrade{}
+	}
+	upgrades[eventType][from] = fn
+}
+
+// Decode reads an event of type E from body, upgrading the data of older versions.
+// Bodies without an envelope are version 0, the untyped maps published before envelopes.
+func Decode[E Event](body []byte) (*Envelope, E, error) {
+	var event E
+
+	var env Envelope
+	if err := json.Unmarshal(body, &env); err != nil {
+		return nil, event, fmt.Errorf("events: malformed message: %w", err)
+	}
+	if env.Type == "" {
+		env = Envelope{Type: event.EventType(), Version: 0, Data: body}
+	}
+
+	if env.Type != event.EventType() {
+		return nil, event, fmt.Errorf("%w: got %s, want %s", ErrUnexpectedType, env.Type, event.EventType())
+	}
+	if env.Version > event.EventVersion() {
+		return nil, event, fmt.Errorf("%w: %s version %d is newer than %d", ErrUnsupportedVersion, env.Type, env.Version, event.EventVersion())
+	}
+
+	data := env.Data
+	for version := env.Version; version < event.EventVersion(); version++ {
+		fn, ok := upgrades[env.Type][version]
+		if !ok {
+			return nil, event, fmt.Errorf("%w: %s version %d cannot be upgraded", ErrUnsupportedVersion, env.Type, version)
+		}
+		var err error
+		if data, err = fn(data); err != nil {
+			return nil, event, fmt.Errorf("events: upgrading %s version %d: %w", env.Type, version, err)
+		}
+	}
+
+	if err := json.Unmarshal(data, &event); err != nil {
+		return nil, event, fmt.Errorf("events: malformed %s: %w", env.Type, err)
+	}
+	env.Version = event.EventVersion()
+	env.Data = data
+	return &env, event, nil
+}
+
+// legacyFields reads the map[string]string body of a version 0 event
+func legacyFields(data json.RawMessage) (map[string]string, error) {
+	var fields map[string]string
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+// legacyID parses an ID of a version 0 event, where missing IDs were left out
+func legacyID(fields map[string]string, key string) (int64, error) {
+	value, ok := fields[key]
+	if !ok || value == "" {
+		return 0, nil
+	}
+	var id int64
+	if _, err := fmt.Sscan(value, &id); err != nil {
+		return 0, fmt.Errorf("invalid %s %q", key, value)
+	}
+	return id, nil
+}
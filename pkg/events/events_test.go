@@ -0,0 +1,65 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"th3y3m/e-commerce-microservices/pkg/logging"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoundTrip(t *testing.T) {
+	ctx := logging.WithRequestInfo(context.Background(), &logging.RequestInfo{RequestID: "req-1"})
+	placed := OrderPlaced{OrderID: 7, CustomerID: 3, CartID: 5, TotalAmount: 120.5, PaymentMethod: "MoMo", PaymentURL: "https://pay"}
+
+	env, err := New(ctx, placed)
+	require.NoError(t, err)
+	assert.NotEmpty(t, env.ID)
+	assert.Equal(t, TypeOrderPlaced, env.Type)
+	assert.Equal(t, 1, env.Version)
+	assert.Equal(t, "req-1", env.CorrelationID)
+
+	body, err := json.Marshal(env)
+	require.NoError(t, err)
+
+	decoded, event, err := Decode[OrderPlaced](body)
+	require.NoError(t, err)
+	assert.Equal(t, placed, event)
+	assert.Equal(t, env.ID, decoded.ID)
+	assert.Equal(t, "req-1", decoded.CorrelationID)
+}
+
+func TestDecodeLegacy(t *testing.T) {
+	env, event, err := Decode[OrderPlaced]([]byte(`{"userId":"3","cartId":"5"}`))
+	require.NoError(t, err)
+	assert.Equal(t, OrderPlaced{CustomerID: 3, CartID: 5}, event)
+	assert.Equal(t, 1, env.Version)
+
+	_, event, err = Decode[OrderPlaced]([]byte(`{"orderId":"7","url":"https://pay"}`))
+	require.NoError(t, err)
+	assert.Equal(t, OrderPlaced{OrderID: 7, PaymentURL: "https://pay"}, event)
+
+	_, invalidated, err := Decode[CacheInvalidated]([]byte(`{"event":"product_updated","path":"/api/products/1"}`))
+	require.NoError(t, err)
+	assert.Equal(t, CacheInvalidated{Path: "/api/products/1", Reason: "product_updated"}, invalidated)
+
+	_, _, err = Decode[OrderPlaced]([]byte(`{"userId":"x"}`))
+	assert.Error(t, err)
+}
+
+func TestDecodeRejects(t *testing.T) {
+	_, _, err := Decode[OrderPlaced]([]byte(`{"type":"payment.completed","version":1,"data":{}}`))
+	assert.ErrorIs(t, err, ErrUnexpectedType)
+
+	_, _, err = Decode[OrderPlaced]([]byte(`{"type":"order.placed","version":2,"data":{}}`))
+	assert.ErrorIs(t, err, ErrUnsupportedVersion)
+
+	// Payments have no version 0 to upgrade from
+	_, _, err = Decode[PaymentCompleted]([]byte(`{"orderId":"7"}`))
+	assert.ErrorIs(t, err, ErrUnsupportedVersion)
+
+	_, _, err = Decode[OrderPlaced]([]byte(`not json`))
+	assert.Error(t, err)
+}
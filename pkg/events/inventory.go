@@ -0,0 +1,15 @@
+package events
+
+const TypeInventoryReserved = "inventory.reserved"
+
+// InventoryReserved is published by the product service once the stock of every item of the
+// cart of an order was taken
+type InventoryReserved struct {
+	OrderID    int64 `json:"order_id"`
+	CustomerID int64 `json:"customer_id"`
+	CartID     int64 `json:"cart_id"`
+}
+
+func (InventoryReserved) EventType() string { return TypeInventoryReserved }
+
+func (InventoryReserved) EventVersion() int { return 1 }
@@ -0,0 +1,47 @@
+package events
+
+import "encoding/json"
+
+const TypeOrderPlaced = "order.placed"
+
+// OrderPlaced is published by the order service once an order is saved and its payment started
+type OrderPlaced struct {
+	OrderID       int64   `json:"order_id"`
+	CustomerID    int64   `json:"customer_id"`
+	CartID        int64   `json:"cart_id"`
+	VoucherID     int64   `json:"voucher_id,omitempty"`
+	TotalAmount   float64 `json:"total_amount"`
+	PaymentMethod string  `json:"payment_method"`
+	// Where the customer pays for the order
+	PaymentURL string `json:"payment_url"`
+}
+
+func (OrderPlaced) EventType() string { return TypeOrderPlaced }
+
+func (OrderPlaced) EventVersion() int { return 1 }
+
+func init() {
+	registerUpgrade(TypeOrderPlaced, 0, upgradeOrderPlacedV0)
+}
+
+// upgradeOrderPlacedV0 reads the maps the order service published to the inventory queue
+// ({"userId", "cartId"}) and to the notification queue ({"orderId", "url"})
+func upgradeOrderPlacedV0(data json.RawMessage) (json.RawMessage, error) {
+	fields, err := legacyFields(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var event OrderPlaced
+	if event.OrderID, err = legacyID(fields, "orderId"); err != nil {
+		return nil, err
+	}
+	if event.CustomerID, err = legacyID(fields, "userId"); err != nil {
+		return nil, err
+	}
+	if event.CartID, err = legacyID(fields, "cartId"); err != nil {
+		return nil, err
+	}
+	event.PaymentURL = fields["url"]
+	return json.Marshal(event)
+}
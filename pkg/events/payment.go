@@ -0,0 +1,16 @@
+package events
+
+const TypePaymentCompleted = "payment.completed"
+
+// PaymentCompleted is published by the payment service once the payment of an order succeeded
+type PaymentCompleted struct {
+	OrderID       int64   `json:"order_id"`
+	Amount        float64 `json:"amount"`
+	PaymentMethod string  `json:"payment_method"`
+	// Transaction ID of the payment provider
+	TransactionID string `json:"transaction_id,omitempty"`
+}
+
+func (PaymentCompleted) EventType() string { return TypePaymentCompleted }
+
+func (PaymentCompleted) EventVersion() int { return 1 }
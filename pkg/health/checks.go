@@ -0,0 +1,104 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"th3y3m/e-commerce-microservices/pkg/config"
+	"th3y3m/e-commerce-microservices/pkg/postgresql"
+	"th3y3m/e-commerce-microservices/pkg/rabbitmq"
+	redis_client "th3y3m/e-commerce-microservices/pkg/redis"
+
+	"github.com/elastic/go-elasticsearch/v7"
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+)
+
+// Postgres pings the database of cfg. The connection is opened on the first check and kept,
+// so a database that comes up after the service is picked up.
+func Postgres(cfg config.Postgres) Check {
+	var mu sync.Mutex
+	var db *gorm.DB
+
+	return Check{Name: "postgres", Func: func(ctx context.Context) error {
+		mu.Lock()
+		if db == nil {
+			var err error
+			if db, err = postgresql.NewGormDB(cfg); err != nil {
+				mu.Unlock()
+				return err
+			}
+		}
+		mu.Unlock()
+
+		sqlDB, err := db.DB()
+		if err != nil {
+			return err
+		}
+		return sqlDB.PingContext(ctx)
+	}}
+}
+
+// Redis pings the server of cfg. Repositories fall back to the database without it, so it
+// does not make the service unready.
+func Redis(cfg config.Redis) Check {
+	var mu sync.Mutex
+	var client *redis.Client
+
+	return Check{Name: "redis", Optional: true, Func: func(ctx context.Context) error {
+		mu.Lock()
+		if client == nil {
+			var err error
+			if client, err = redis_client.NewClient(cfg); err != nil {
+				mu.Unlock()
+				return err
+			}
+		}
+		mu.Unlock()
+
+		return client.Ping(ctx).Err()
+	}}
+}
+
+// RabbitMQ checks the connection shared by the publishers and consumers, connecting
+// again if it was lost. It passes when the in-process broker replaces RabbitMQ.
+func RabbitMQ(cfg config.RabbitMQ) Check {
+	return Check{Name: "rabbitmq", Func: func(ctx context.Context) error {
+		if cfg.InMemory() {
+			return nil
+		}
+		return rabbitmq.Default(cfg).Ping()
+	}}
+}
+
+// Elasticsearch pings the cluster of cfg
+func Elasticsearch(cfg config.Elasticsearch) Check {
+	var mu sync.Mutex
+	var client *elasticsearch.Client
+
+	return Check{Name: "elasticsearch", Func: func(ctx context.Context) error {
+		mu.Lock()
+		if client == nil {
+			var err error
+			client, err = elasticsearch.NewClient(elasticsearch.Config{
+				Addresses: []string{cfg.URL},
+			})
+			if err != nil {
+				mu.Unlock()
+				return err
+			}
+		}
+		mu.Unlock()
+
+		res, err := client.Ping(client.Ping.WithContext(ctx))
+		if err != nil {
+			return err
+		}
+		defer res.Body.Close()
+		if res.StatusCode != http.StatusOK {
+			return fmt.Errorf("elasticsearch returned %s", res.Status())
+		}
+		return nil
+	}}
+}
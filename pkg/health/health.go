@@ -0,0 +1,102 @@
+package health
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	LivePath  = "/healthz"
+	ReadyPath = "/readyz"
+
+	// Each check gets this long before the dependency is reported as down
+	checkTimeout = 2 * time.Second
+)
+
+// Check reports whether a dependency of the service can be used
+type Check struct {
+	Name string
+	// Optional dependencies are reported but do not make the service unready,
+	// because the service keeps working without them
+	Optional bool
+	Func     func(ctx context.Context) error
+}
+
+// Checker serves the liveness and readiness endpoints of a service
+type Checker struct {
+	checks       []Check
+	shuttingDown atomic.Bool
+}
+
+func NewChecker(checks ...Check) *Checker {
+	return &Checker{checks: checks}
+}
+
+// Register mounts LivePath and ReadyPath on r
+func (h *Checker) Register(r gin.IRoutes) {
+	r.GET(LivePath, h.Live)
+	r.GET(ReadyPath, h.Ready)
+}
+
+// ShuttingDown makes the service report not ready so that no new traffic is sent to it
+// while in-flight requests are drained
+func (h *Checker) ShuttingDown() {
+	h.shuttingDown.Store(true)
+}
+
+// Live answers as long as the process serves HTTP, dependencies are not checked
+func (h *Checker) Live(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+// Ready runs every check and answers 503 when a required dependency is down
+func (h *Checker) Ready(c *gin.Context) {
+	if h.shuttingDown.Load() {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": "shutting down"})
+		return
+	}
+
+	results, ready := h.run(c.Request.Context())
+	if !ready {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": "unavailable", "checks": results})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "ok", "checks": results})
+}
+
+func (h *Checker) run(ctx context.Context) (map[string]string, bool) {
+	results := make(map[string]string, len(h.checks))
+	ready := true
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, check := range h.checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			checkCtx, cancel := context.WithTimeout(ctx, checkTimeout)
+			defer cancel()
+			err := check.Func(checkCtx)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err == nil {
+				results[check.Name] = "ok"
+				return
+			}
+			results[check.Name] = err.Error()
+			if !check.Optional {
+				ready = false
+			}
+		}()
+	}
+	wg.Wait()
+
+	return results, ready
+}
@@ -0,0 +1,51 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func serve(checker *Checker, path string) (int, map[string]interface{}) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	checker.Register(r)
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+
+	var body map[string]interface{}
+	json.Unmarshal(rec.Body.Bytes(), &body)
+	return rec.Code, body
+}
+
+func TestReady(t *testing.T) {
+	up := Check{Name: "postgres", Func: func(context.Context) error { return nil }}
+	down := Check{Name: "redis", Optional: true, Func: func(context.Context) error { return errors.New("connection refused") }}
+
+	code, body := serve(NewChecker(up, down), ReadyPath)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, map[string]interface{}{"postgres": "ok", "redis": "connection refused"}, body["checks"])
+
+	down.Optional = false
+	code, body = serve(NewChecker(up, down), ReadyPath)
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, "unavailable", body["status"])
+}
+
+func TestShuttingDown(t *testing.T) {
+	checker := NewChecker()
+	checker.ShuttingDown()
+
+	code, _ := serve(checker, ReadyPath)
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+
+	code, _ = serve(checker, LivePath)
+	assert.Equal(t, http.StatusOK, code)
+}
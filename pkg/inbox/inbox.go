@@ -0,0 +1,42 @@
+package inbox
+
+import (
+	"context"
+	"errors"
+	"th3y3m/e-commerce-microservices/pkg/events"
+	"th3y3m/e-commerce-microservices/pkg/logging"
+	"th3y3m/e-commerce-microservices/pkg/metrics"
+)
+
+// ErrInProgress means another delivery of the event is being processed, the message should be
+// tried again later
+var ErrInProgress = errors.New("inbox: event is being processed")
+
+// Store remembers the events each consumer processed
+type Store interface {
+	// Process calls fn unless consumer already processed the event, and reports whether fn ran.
+	// The event is only remembered when fn succeeds.
+	Process(ctx context.Context, consumer, eventID string, fn func(ctx context.Context) error) (bool, error)
+}
+
+// Once wraps the handler of a consumer so each event is handled at most once by it, however often
+// the broker delivers it. Events published before envelopes carry no ID and are always handled.
+func Once[E events.Event](store Store, consumer string, handler func(context.Context, *events.Envelope, E) error) func(context.Context, *events.Envelope, E) error {
+	return func(ctx context.Context, env *events.Envelope, event E) error {
+		if env.ID == "" {
+			return handler(ctx, env, event)
+		}
+
+		processed, err := store.Process(ctx, consumer, env.ID, func(ctx context.Context) error {
+			return handler(ctx, env, event)
+		})
+		if err != nil {
+			return err
+		}
+		if !processed {
+			metrics.DuplicateSkipped(consumer)
+			logging.Logger().WithContext(ctx).Infof("Skipped %s %s, %s already processed it", env.Type, env.ID, consumer)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,82 @@
+package inbox
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"th3y3m/e-commerce-microservices/pkg/config"
+	"th3y3m/e-commerce-microservices/pkg/events"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// memoryStore remembers processed events in a map
+type memoryStore struct {
+	processed map[string]bool
+}
+
+func (s *memoryStore) Process(ctx context.Context, consumer, eventID string, fn func(ctx context.Context) error) (bool, error) {
+	key := consumer + "/" + eventID
+	if s.processed[key] {
+		return false, nil
+	}
+	if err := fn(ctx); err != nil {
+		return true, err
+	}
+	s.processed[key] = true
+	return true, nil
+}
+
+func TestOnce(t *testing.T) {
+	store := &memoryStore{processed: map[string]bool{}}
+	calls := 0
+	fail := true
+	handler := Once(store, "mail", func(ctx context.Context, env *events.Envelope, event events.OrderPlaced) error {
+		calls++
+		if fail {
+			return errors.New("smtp down")
+		}
+		return nil
+	})
+
+	env := &events.Envelope{ID: "event-1", Type: events.TypeOrderPlaced}
+
+	// A failed event is processed again on redelivery
+	assert.Error(t, handler(context.Background(), env, events.OrderPlaced{}))
+	fail = false
+	assert.NoError(t, handler(context.Background(), env, events.OrderPlaced{}))
+	assert.Equal(t, 2, calls)
+
+	// A processed event is skipped
+	assert.NoError(t, handler(context.Background(), env, events.OrderPlaced{}))
+	assert.Equal(t, 2, calls)
+
+	// Another consumer processes it too
+	other := Once(store, "product", func(ctx context.Context, env *events.Envelope, event events.OrderPlaced) error {
+		calls++
+		return nil
+	})
+	assert.NoError(t, other(context.Background(), env, events.OrderPlaced{}))
+	assert.Equal(t, 3, calls)
+
+	// Legacy events have no ID to deduplicate on
+	legacy := &events.Envelope{Type: events.TypeOrderPlaced}
+	assert.NoError(t, handler(context.Background(), legacy, events.OrderPlaced{}))
+	assert.NoError(t, handler(context.Background(), legacy, events.OrderPlaced{}))
+	assert.Equal(t, 5, calls)
+}
+
+func TestRedisStoreWithoutClient(t *testing.T) {
+	store := NewRedisStore(nil, config.Inbox{TTL: time.Hour})
+	calls := 0
+	for i := 0; i < 2; i++ {
+		processed, err := store.Process(context.Background(), "mail", "event-1", func(ctx context.Context) error {
+			calls++
+			return nil
+		})
+		assert.NoError(t, err)
+		assert.True(t, processed)
+	}
+	assert.Equal(t, 2, calls)
+}
@@ -0,0 +1,117 @@
+package inbox
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"th3y3m/e-commerce-microservices/pkg/config"
+	"th3y3m/e-commerce-microservices/pkg/postgresql"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Expired events are deleted at most this often
+const cleanupInterval = time.Hour
+
+// Message is an event a consumer processed
+type Message struct {
+	Consumer    string    `gorm:"primaryKey;column:consumer"`
+	EventID     string    `gorm:"primaryKey;column:event_id"`
+	ProcessedAt time.Time `gorm:"column:processed_at;index"`
+}
+
+func (Message) TableName() string {
+	return "inbox_messages"
+}
+
+// PostgresStore remembers processed events in the inbox table, in the transaction of the handler:
+// the handler gets a context carrying the transaction, so repositories using postgresql.DB commit
+// their writes together with the event, or not at all. A second delivery processed at the same
+// time waits for the first one to commit and is then skipped.
+type PostgresStore struct {
+	pg  config.Postgres
+	ttl time.Duration
+
+	mu          sync.Mutex
+	db          *gorm.DB
+	nextCleanup time.Time
+}
+
+// NewPostgresStore returns a store in the database of pg, which is opened on first use
+func NewPostgresStore(pg config.Postgres, cfg config.Inbox) *PostgresStore {
+	return &PostgresStore{pg: pg, ttl: cfg.TTL}
+}
+
+func (s *PostgresStore) Process(ctx context.Context, consumer, eventID string, fn func(ctx context.Context) error) (bool, error) {
+	db, err := s.connect()
+	if err != nil {
+		return false, err
+	}
+
+	processed := false
+	err = db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		claim := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&Message{
+			Consumer:    consumer,
+			EventID:     eventID,
+			ProcessedAt: time.Now(),
+		})
+		if claim.Error != nil {
+			return claim.Error
+		}
+		if claim.RowsAffected == 0 {
+			return nil
+		}
+
+		processed = true
+		return fn(postgresql.WithTx(ctx, tx))
+	})
+	if err != nil {
+		return false, err
+	}
+
+	s.cleanup(db)
+	return processed, nil
+}
+
+// connect opens the database on first use and creates the inbox table
+func (s *PostgresStore) connect() (*gorm.DB, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.db != nil {
+		return s.db, nil
+	}
+
+	db, err := postgresql.NewGormDB(s.pg)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.AutoMigrate(&Message{}); err != nil {
+		return nil, fmt.Errorf("failed to create the inbox table: %w", err)
+	}
+	s.db = db
+	return db, nil
+}
+
+// cleanup deletes the expired events in the background, once per cleanupInterval
+func (s *PostgresStore) cleanup(db *gorm.DB) {
+	s.mu.Lock()
+	due := time.Now().After(s.nextCleanup)
+	if due {
+		s.nextCleanup = time.Now().Add(cleanupInterval)
+	}
+	s.mu.Unlock()
+	if !due {
+		return
+	}
+
+	go func() {
+		err := db.Where("processed_at < ?", time.Now().Add(-s.ttl)).Delete(&Message{}).Error
+		if err != nil {
+			log.Printf("Failed to delete expired inbox messages: %v", err)
+		}
+	}()
+}
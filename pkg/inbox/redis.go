@@ -0,0 +1,75 @@
+package inbox
+
+import (
+	"context"
+	"fmt"
+	"th3y3m/e-commerce-microservices/pkg/config"
+	"th3y3m/e-commerce-microservices/pkg/logging"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// An event claimed by a consumer that died while processing it is free again after this long
+const processingTTL = 5 * time.Minute
+
+const (
+	stateProcessing = "processing"
+	stateDone       = "done"
+)
+
+// RedisStore remembers processed events in Redis keys expiring after the TTL of the inbox. It suits handlers
+// without database writes, such as sending a mail: the event is claimed before the handler runs
+// and marked done after it succeeded, so a crash in between can repeat the handler once the
+// claim expires.
+type RedisStore struct {
+	client *redis.Client
+	ttl    time.Duration
+}
+
+// NewRedisStore returns a store on client, which may be nil when Redis is not configured:
+// events are then processed without deduplication
+func NewRedisStore(client *redis.Client, cfg config.Inbox) *RedisStore {
+	return &RedisStore{client: client, ttl: cfg.TTL}
+}
+
+func (s *RedisStore) Process(ctx context.Context, consumer, eventID string, fn func(ctx context.Context) error) (bool, error) {
+	if s.client == nil {
+		logging.Logger().WithContext(ctx).Warn("Redis client is not initialized, processing event without deduplication")
+		return true, fn(ctx)
+	}
+
+	key := fmt.Sprintf("inbox:%s:%s", consumer, eventID)
+	claimed, err := s.client.SetNX(ctx, key, stateProcessing, processingTTL).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to claim event %s: %w", eventID, err)
+	}
+	if !claimed {
+		state, err := s.client.Get(ctx, key).Result()
+		switch {
+		case err == redis.Nil:
+			// The claim expired in between
+			return false, ErrInProgress
+		case err != nil:
+			return false, fmt.Errorf("failed to read the state of event %s: %w", eventID, err)
+		case state == stateDone:
+			return false, nil
+		default:
+			return false, ErrInProgress
+		}
+	}
+
+	if err := fn(ctx); err != nil {
+		// Released so the retry of the message processes it
+		if delErr := s.client.Del(ctx, key).Err(); delErr != nil {
+			logging.Logger().WithContext(ctx).Warnf("Failed to release event %s: %v", eventID, delErr)
+		}
+		return true, err
+	}
+
+	if err := s.client.Set(ctx, key, stateDone, s.ttl).Err(); err != nil {
+		// The claim still blocks duplicates until it expires
+		logging.Logger().WithContext(ctx).Warnf("Failed to mark event %s processed: %v", eventID, err)
+	}
+	return true, nil
+}
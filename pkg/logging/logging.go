@@ -0,0 +1,112 @@
+package logging
+
+import (
+	"context"
+	"log"
+	"regexp"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// HeaderRequestID correlates the log lines of every service involved in one gateway request
+const HeaderRequestID = "X-Request-ID"
+
+// Client supplied IDs are kept when they are reasonably short and safe to log
+var requestIDPattern = regexp.MustCompile(`^[A-Za-z0-9._-]{1,128}$`)
+
+type requestInfoKey struct{}
+
+// RequestInfo describes the request being served. Fields are filled in as the request goes
+// through the middlewares and handlers, and every log line written with the context gets them.
+type RequestInfo struct {
+	RequestID       string
+	Route           string
+	UserID          string
+	Upstream        string
+	UpstreamLatency time.Duration
+}
+
+func WithRequestInfo(ctx context.Context, info *RequestInfo) context.Context {
+	return context.WithValue(ctx, requestInfoKey{}, info)
+}
+
+// RequestInfoFromContext returns the request of ctx, or nil outside of a request
+func RequestInfoFromContext(ctx context.Context) *RequestInfo {
+	if c, ok := ctx.(*gin.Context); ok {
+		if c.Request == nil {
+			return nil
+		}
+		ctx = c.Request.Context()
+	}
+	info, _ := ctx.Value(requestInfoKey{}).(*RequestInfo)
+	return info
+}
+
+// RequestIDFromContext returns the request ID of ctx, or an empty string
+func RequestIDFromContext(ctx context.Context) string {
+	if info := RequestInfoFromContext(ctx); info != nil {
+		return info.RequestID
+	}
+	return ""
+}
+
+// RequestID keeps a valid incoming ID and generates one otherwise
+func RequestID(incoming string) string {
+	if requestIDPattern.MatchString(incoming) {
+		return incoming
+	}
+	return uuid.NewString()
+}
+
+// Setup makes the standard logrus and log output JSON, tagged with the service name
+// and with the fields of the request when a line is written with its context.
+func Setup(service string) {
+	logger := logrus.StandardLogger()
+	logger.SetFormatter(&logrus.JSONFormatter{TimestampFormat: time.RFC3339Nano})
+	logger.AddHook(&contextHook{service: service})
+
+	log.SetFlags(0)
+	log.SetOutput(logger.WriterLevel(logrus.InfoLevel))
+}
+
+// Logger returns the logger shared by the whole service
+func Logger() *logrus.Logger {
+	return logrus.StandardLogger()
+}
+
+type contextHook struct {
+	service string
+}
+
+func (h *contextHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (h *contextHook) Fire(entry *logrus.Entry) error {
+	entry.Data["service"] = h.service
+	if entry.Context == nil {
+		return nil
+	}
+
+	if span := trace.SpanContextFromContext(entry.Context); span.IsValid() {
+		entry.Data["trace_id"] = span.TraceID().String()
+		entry.Data["span_id"] = span.SpanID().String()
+	}
+
+	info := RequestInfoFromContext(entry.Context)
+	if info == nil {
+		return nil
+	}
+	entry.Data["request_id"] = info.RequestID
+	if info.Route != "" {
+		entry.Data["route"] = info.Route
+	}
+	if info.UserID != "" {
+		entry.Data["user_id"] = info.UserID
+	}
+	return nil
+}
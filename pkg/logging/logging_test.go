@@ -0,0 +1,51 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestID(t *testing.T) {
+	assert.Equal(t, "abc-123", RequestID("abc-123"))
+	assert.NotEqual(t, "bad id\n", RequestID("bad id\n"))
+	assert.NotEmpty(t, RequestID(""))
+}
+
+func TestMiddlewareTagsLogLines(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var out bytes.Buffer
+	logger := logrus.New()
+	logger.SetOutput(&out)
+	logger.SetFormatter(&logrus.JSONFormatter{})
+	logger.AddHook(&contextHook{service: "test"})
+
+	r := gin.New()
+	r.Use(Middleware())
+	r.GET("/items/:id", func(c *gin.Context) {
+		logger.WithContext(c).Info("handled")
+		c.Status(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/items/1", nil)
+	req.Header.Set(HeaderRequestID, "req-1")
+	req.Header.Set("X-User-ID", "7")
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	assert.Equal(t, "req-1", rec.Header().Get(HeaderRequestID))
+
+	var line map[string]interface{}
+	assert.NoError(t, json.Unmarshal(out.Bytes(), &line))
+	assert.Equal(t, "req-1", line["request_id"])
+	assert.Equal(t, "/items/:id", line["route"])
+	assert.Equal(t, "7", line["user_id"])
+	assert.Equal(t, "test", line["service"])
+}
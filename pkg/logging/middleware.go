@@ -0,0 +1,39 @@
+package logging
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// Middleware accepts the request ID forwarded by the gateway, or starts a new one, and writes
+// one access log line per request.
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		info := &RequestInfo{
+			RequestID: RequestID(c.GetHeader(HeaderRequestID)),
+			Route:     c.FullPath(),
+			// Verified by the authz middleware, a forged value is rejected before any handler runs
+			UserID: c.GetHeader("X-User-ID"),
+		}
+		c.Request = c.Request.WithContext(WithRequestInfo(c.Request.Context(), info))
+		c.Header(HeaderRequestID, info.RequestID)
+
+		c.Next()
+
+		entry := Logger().WithContext(c.Request.Context()).WithFields(logrus.Fields{
+			"method":     c.Request.Method,
+			"path":       c.Request.URL.Path,
+			"status":     c.Writer.Status(),
+			"latency_ms": time.Since(start).Milliseconds(),
+			"client_ip":  c.ClientIP(),
+		})
+		if len(c.Errors) > 0 {
+			entry = entry.WithField("errors", c.Errors.String())
+		}
+		entry.Info("request")
+	}
+}
@@ -0,0 +1,193 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"th3y3m/e-commerce-microservices/pkg/constant"
+	"th3y3m/e-commerce-microservices/pkg/logging"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Path scraped by Prometheus on every service and the gateway
+const Path = "/metrics"
+
+var (
+	httpRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "Duration of the HTTP requests served, by route and status.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	upstreamRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "upstream_request_duration_seconds",
+		Help:    "Duration of the requests the gateway forwarded, by upstream and status.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"upstream", "status"})
+
+	upstreamErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "upstream_errors_total",
+		Help: "Forwarded requests that failed or got a server error, by upstream and reason.",
+	}, []string{"upstream", "reason"})
+
+	cacheRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "cache_requests_total",
+		Help: "Cache lookups of the repositories, in Redis or in memory, by cache and result.",
+	}, []string{"cache", "result"})
+
+	messagesPublished = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "rabbitmq_messages_published_total",
+		Help: "Messages published to RabbitMQ, by queue and result.",
+	}, []string{"queue", "result"})
+
+	messagesConsumed = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "rabbitmq_messages_consumed_total",
+		Help: "Messages consumed from RabbitMQ, by queue and result.",
+	}, []string{"queue", "result"})
+
+	messagesRetried = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "rabbitmq_messages_retried_total",
+		Help: "Failed messages scheduled for another delivery, by queue.",
+	}, []string{"queue"})
+
+	messagesDeadLettered = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "rabbitmq_messages_dead_lettered_total",
+		Help: "Messages moved to the dead-letter queue after their last retry, by queue.",
+	}, []string{"queue"})
+
+	duplicatesSkipped = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "events_duplicates_skipped_total",
+		Help: "Events delivered again to a consumer that already processed them, by consumer.",
+	}, []string{"consumer"})
+
+	ordersPlaced = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "orders_placed_total",
+		Help: "Orders placed, by payment method.",
+	}, []string{"payment_method"})
+
+	paymentsCompleted = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "payments_completed_total",
+		Help: "Payments completed, by payment method.",
+	}, []string{"payment_method"})
+
+	vouchersRedeemed = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "vouchers_redeemed_total",
+		Help: "Vouchers applied to placed orders.",
+	})
+)
+
+// Handler serves the metrics of the process in the Prometheus text format
+func Handler() gin.HandlerFunc {
+	return gin.WrapH(promhttp.Handler())
+}
+
+// Middleware records the duration and status of every request. Requests are labeled with
+// their route template, or with the route prefix chosen by the gateway, so that IDs in the
+// path do not create new series.
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			if info := logging.RequestInfoFromContext(c); info != nil {
+				route = info.Route
+			}
+		}
+		if route == "" {
+			route = "unmatched"
+		}
+		httpRequestDuration.WithLabelValues(c.Request.Method, route, strconv.Itoa(c.Writer.Status())).
+			Observe(time.Since(start).Seconds())
+	}
+}
+
+// ObserveUpstream records one attempt of the gateway to forward a request to upstream.
+// statusCode is ignored when err is set.
+func ObserveUpstream(upstream string, statusCode int, err error, duration time.Duration) {
+	status := strconv.Itoa(statusCode)
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		status = "error"
+		upstreamErrors.WithLabelValues(upstream, "timeout").Inc()
+	case errors.Is(err, context.Canceled):
+		status = "error"
+		upstreamErrors.WithLabelValues(upstream, "canceled").Inc()
+	case err != nil:
+		status = "error"
+		upstreamErrors.WithLabelValues(upstream, "connection").Inc()
+	case statusCode >= 500:
+		upstreamErrors.WithLabelValues(upstream, "server_error").Inc()
+	}
+	upstreamRequestDuration.WithLabelValues(upstream, status).Observe(duration.Seconds())
+}
+
+// CacheHit records a lookup of cache that was served from the cache
+func CacheHit(cache string) {
+	cacheRequests.WithLabelValues(cache, "hit").Inc()
+}
+
+// CacheMiss records a lookup of cache that had to go to the database
+func CacheMiss(cache string) {
+	cacheRequests.WithLabelValues(cache, "miss").Inc()
+}
+
+// MessagePublished records the outcome of publishing a message to queue
+func MessagePublished(queue string, err error) {
+	messagesPublished.WithLabelValues(queue, result(err)).Inc()
+}
+
+// MessageConsumed records the outcome of handling a message from queue
+func MessageConsumed(queue string, err error) {
+	messagesConsumed.WithLabelValues(queue, result(err)).Inc()
+}
+
+// MessageRetried records a failed message of queue that will be delivered again
+func MessageRetried(queue string) {
+	messagesRetried.WithLabelValues(queue).Inc()
+}
+
+// MessageDeadLettered records a message of queue moved to its dead-letter queue
+func MessageDeadLettered(queue string) {
+	messagesDeadLettered.WithLabelValues(queue).Inc()
+}
+
+// DuplicateSkipped records an event consumer already processed and did not process again
+func DuplicateSkipped(consumer string) {
+	duplicatesSkipped.WithLabelValues(consumer).Inc()
+}
+
+func OrderPlaced(paymentMethod string) {
+	ordersPlaced.WithLabelValues(paymentMethodLabel(paymentMethod)).Inc()
+}
+
+func PaymentCompleted(paymentMethod string) {
+	paymentsCompleted.WithLabelValues(paymentMethodLabel(paymentMethod)).Inc()
+}
+
+func VoucherRedeemed() {
+	vouchersRedeemed.Inc()
+}
+
+// The payment method comes from the client, unknown values share one series
+func paymentMethodLabel(paymentMethod string) string {
+	switch paymentMethod {
+	case constant.PAYMENT_METHOD_MOMO, constant.PAYMENT_METHOD_VNPAY:
+		return paymentMethod
+	}
+	return "other"
+}
+
+func result(err error) string {
+	if err != nil {
+		return "error"
+	}
+	return "success"
+}
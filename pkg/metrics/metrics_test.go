@@ -0,0 +1,51 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMiddlewareLabelsRouteTemplate(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	r.Use(Middleware())
+	r.GET(Path, Handler())
+	r.GET("/items/:id", func(c *gin.Context) {
+		c.Status(http.StatusNotFound)
+	})
+
+	for _, path := range []string{"/items/1", "/items/2"} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, Path, nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `http_request_duration_seconds_count{method="GET",route="/items/:id",status="404"} 2`)
+	assert.NotContains(t, rec.Body.String(), `route="/items/1"`)
+}
+
+func TestCacheAndPaymentCounters(t *testing.T) {
+	CacheHit("testRepository.Get")
+	CacheMiss("testRepository.Get")
+	CacheMiss("testRepository.Get")
+	assert.Equal(t, 1.0, testutil.ToFloat64(cacheRequests.WithLabelValues("testRepository.Get", "hit")))
+	assert.Equal(t, 2.0, testutil.ToFloat64(cacheRequests.WithLabelValues("testRepository.Get", "miss")))
+
+	OrderPlaced("MoMo")
+	OrderPlaced("made up")
+	expected := `
+# HELP orders_placed_total Orders placed, by payment method.
+# TYPE orders_placed_total counter
+orders_placed_total{payment_method="MoMo"} 1
+orders_placed_total{payment_method="other"} 1
+`
+	assert.NoError(t, testutil.CollectAndCompare(ordersPlaced, strings.NewReader(expected)))
+}
@@ -0,0 +1,69 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"th3y3m/e-commerce-microservices/pkg/events"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Message is an event waiting in the outbox table until the relay has published it
+type Message struct {
+	ID        int64      `gorm:"primaryKey;column:id;autoIncrement"`
+	EventID   string     `gorm:"column:event_id;uniqueIndex"`
+	EventType string     `gorm:"column:event_type"`
+	Envelope  []byte     `gorm:"column:envelope;type:jsonb"`
+	CreatedAt time.Time  `gorm:"column:created_at;autoCreateTime"`
+	SentAt    *time.Time `gorm:"column:sent_at;index"`
+	// Failed publishes, the message is tried again on the next poll
+	Attempts  int    `gorm:"column:attempts;default:0"`
+	LastError string `gorm:"column:last_error"`
+	// Set when the relay gave up on the message, which is kept for inspection and not retried
+	FailedAt *time.Time `gorm:"column:failed_at"`
+}
+
+func (Message) TableName() string {
+	return "outbox_messages"
+}
+
+// Migrate creates the outbox table
+func Migrate(db *gorm.DB) error {
+	return db.AutoMigrate(&Message{})
+}
+
+// Add writes the events to the outbox with tx, so they are published if and only if the
+// transaction that changed the state they describe commits
+func Add(ctx context.Context, tx *gorm.DB, evs ...events.Event) error {
+	if len(evs) == 0 {
+		return nil
+	}
+
+	messages := make([]*Message, 0, len(evs))
+	for _, event := range evs {
+		message, err := newMessage(ctx, event)
+		if err != nil {
+			return err
+		}
+		messages = append(messages, message)
+	}
+	return tx.WithContext(ctx).Create(messages).Error
+}
+
+func newMessage(ctx context.Context, event events.Event) (*Message, error) {
+	env, err := events.New(ctx, event)
+	if err != nil {
+		return nil, err
+	}
+	body, err := json.Marshal(env)
+	if err != nil {
+		return nil, err
+	}
+	return &Message{
+		EventID:   env.ID,
+		EventType: env.Type,
+		Envelope:  body,
+		CreatedAt: env.OccurredAt,
+	}, nil
+}
@@ -0,0 +1,58 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"th3y3m/e-commerce-microservices/pkg/broker"
+	"th3y3m/e-commerce-microservices/pkg/config"
+	"th3y3m/e-commerce-microservices/pkg/events"
+	"th3y3m/e-commerce-microservices/pkg/logging"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewMessage(t *testing.T) {
+	ctx := logging.WithRequestInfo(context.Background(), &logging.RequestInfo{RequestID: "req-1"})
+	completed := events.PaymentCompleted{OrderID: 7, Amount: 120, PaymentMethod: "MoMo", TransactionID: "2890631"}
+
+	message, err := newMessage(ctx, completed)
+	require.NoError(t, err)
+	assert.Equal(t, events.TypePaymentCompleted, message.EventType)
+	assert.Nil(t, message.SentAt)
+
+	// The relay publishes the stored envelope as it is
+	var env events.Envelope
+	require.NoError(t, json.Unmarshal(message.Envelope, &env))
+	assert.Equal(t, message.EventID, env.ID)
+	assert.Equal(t, "req-1", env.CorrelationID)
+
+	body, err := json.Marshal(env)
+	require.NoError(t, err)
+	_, event, err := events.Decode[events.PaymentCompleted](body)
+	require.NoError(t, err)
+	assert.Equal(t, completed, event)
+}
+
+func TestNewRelayConfig(t *testing.T) {
+	cfg := NewRelayConfig(config.Outbox{PollInterval: 200 * time.Millisecond, BatchSize: 10, Retention: time.Hour, MaxAttempts: 5})
+	assert.Equal(t, RelayConfig{PollInterval: 200 * time.Millisecond, BatchSize: 10, Retention: time.Hour, MaxAttempts: 5}, cfg)
+
+	cfg = NewRelayConfig(config.Outbox{PollInterval: time.Second})
+	assert.Equal(t, 1, cfg.BatchSize)
+	assert.Equal(t, 1, cfg.MaxAttempts)
+}
+
+func TestRelayParksMessages(t *testing.T) {
+	cfg := RelayConfig{MaxAttempts: 3}
+	failed := errors.New("broker unreachable")
+
+	assert.False(t, cfg.parks(1, failed))
+	assert.False(t, cfg.parks(2, failed))
+	assert.True(t, cfg.parks(3, failed))
+	// Retrying cannot fix a message that can never be published
+	assert.True(t, cfg.parks(1, broker.Permanent(failed)))
+}
@@ -0,0 +1,203 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"th3y3m/e-commerce-microservices/pkg/broker"
+	"th3y3m/e-commerce-microservices/pkg/config"
+	"th3y3m/e-commerce-microservices/pkg/events"
+	"th3y3m/e-commerce-microservices/pkg/logging"
+	"th3y3m/e-commerce-microservices/pkg/postgresql"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// RelayConfig tunes how often and how much the relay publishes
+type RelayConfig struct {
+	// Time between two polls of the outbox when it was found empty
+	PollInterval time.Duration
+	// Messages published per transaction
+	BatchSize int
+	// Sent messages are deleted after this long
+	Retention time.Duration
+	// Failed publishes of a message before it is parked
+	MaxAttempts int
+}
+
+// NewRelayConfig returns the settings of the outbox block, a batch holds at least one message
+// and a message is tried at least once
+func NewRelayConfig(c config.Outbox) RelayConfig {
+	return RelayConfig{
+		PollInterval: c.PollInterval,
+		BatchSize:    max(c.BatchSize, 1),
+		Retention:    c.Retention,
+		MaxAttempts:  max(c.MaxAttempts, 1),
+	}
+}
+
+// parks reports whether a message that failed its attempts-th publish with err is given up on:
+// after the last attempt, or right away when retrying cannot help
+func (cfg RelayConfig) parks(attempts int, err error) bool {
+	return attempts >= cfg.MaxAttempts || broker.IsPermanent(err)
+}
+
+// Relay publishes the messages of the outbox in the order they were added. A message is marked
+// sent in the transaction that locked it, after the broker confirmed it, so it is published at
+// least once: again when the process dies between the confirmation and the commit. Relays of
+// several instances of a service skip the messages locked by each other.
+//
+// A message that fails MaxAttempts publishes, or can never be published, is marked failed and
+// kept, and the messages after it are published. It has to be looked at and fixed by hand.
+//
+// Before its first publish the relay declares the queues of broker.Durable, so their events are
+// kept until the subscribers run. An event no queue is bound for is dropped by the broker and
+// still marked sent.
+type Relay struct {
+	cfg      RelayConfig
+	pg       config.Postgres
+	db       *gorm.DB
+	broker   broker.Broker
+	declared bool
+}
+
+// NewRelay returns a relay publishing the outbox in the database of pg to b
+func NewRelay(pg config.Postgres, cfg RelayConfig, b broker.Broker) *Relay {
+	return &Relay{
+		cfg:    cfg,
+		pg:     pg,
+		broker: b,
+	}
+}
+
+// Run publishes the outbox until ctx is canceled, it is meant to run as a server.Worker
+func (r *Relay) Run(ctx context.Context) error {
+	cleanup := time.NewTicker(time.Hour)
+	defer cleanup.Stop()
+
+	for {
+		published, err := r.relayBatch(ctx)
+		if err != nil {
+			log.Printf("Outbox relay failed: %v", err)
+		}
+
+		// A full batch means more messages are waiting
+		wait := r.cfg.PollInterval
+		if err == nil && published == r.cfg.BatchSize {
+			wait = 0
+		}
+
+		select {
+		case <-ctx.Done():
+			log.Println("Stopped relaying the outbox")
+			return nil
+		case <-cleanup.C:
+			if err := r.deleteSent(ctx); err != nil {
+				log.Printf("Failed to delete sent outbox messages: %v", err)
+			}
+		case <-time.After(wait):
+		}
+	}
+}
+
+// connect opens the database on first use and creates the outbox table
+func (r *Relay) connect() (*gorm.DB, error) {
+	if r.db != nil {
+		return r.db, nil
+	}
+
+	db, err := postgresql.NewGormDB(r.pg)
+	if err != nil {
+		return nil, err
+	}
+	if err := Migrate(db); err != nil {
+		return nil, fmt.Errorf("failed to create the outbox table: %w", err)
+	}
+	r.db = db
+	return db, nil
+}
+
+// relayBatch publishes the oldest pending messages and returns how many were published.
+// It stops at the first failure so later events are not published before earlier ones, unless
+// the failed message is parked.
+func (r *Relay) relayBatch(ctx context.Context) (int, error) {
+	db, err := r.connect()
+	if err != nil {
+		return 0, err
+	}
+	if !r.declared {
+		if err := r.broker.Declare(broker.Durable...); err != nil {
+			return 0, fmt.Errorf("failed to declare the subscriber queues: %w", err)
+		}
+		r.declared = true
+	}
+
+	published := 0
+	err = db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var messages []*Message
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("sent_at IS NULL AND failed_at IS NULL").
+			Order("id").
+			Limit(r.cfg.BatchSize).
+			Find(&messages).Error
+		if err != nil {
+			return err
+		}
+
+		for _, message := range messages {
+			if err := r.publishMessage(ctx, message); err != nil {
+				attempts := message.Attempts + 1
+				updates := map[string]interface{}{
+					"attempts":   attempts,
+					"last_error": err.Error(),
+				}
+				if !r.cfg.parks(attempts, err) {
+					// Messages published so far are committed, this one is tried again on the next poll
+					return tx.Model(message).Updates(updates).Error
+				}
+
+				log.Printf("Outbox message %d (%s) parked after %d attempts: %v", message.ID, message.EventType, attempts, err)
+				updates["failed_at"] = time.Now()
+				if err := tx.Model(message).Updates(updates).Error; err != nil {
+					return err
+				}
+				continue
+			}
+
+			if err := tx.Model(message).Update("sent_at", time.Now()).Error; err != nil {
+				return err
+			}
+			published++
+		}
+		return nil
+	})
+	return published, err
+}
+
+func (r *Relay) publishMessage(ctx context.Context, message *Message) error {
+	var env events.Envelope
+	if err := json.Unmarshal(message.Envelope, &env); err != nil {
+		return broker.Permanent(fmt.Errorf("malformed envelope of outbox message %d: %w", message.ID, err))
+	}
+
+	// Log lines of the publish tie back to the request that added the event
+	ctx = logging.WithRequestInfo(ctx, &logging.RequestInfo{RequestID: env.CorrelationID, Route: "outbox"})
+	if err := r.broker.Publish(ctx, &env); err != nil {
+		logging.Logger().WithContext(ctx).Warnf("Failed to publish %s %s from the outbox: %v", env.Type, env.ID, err)
+		return err
+	}
+	return nil
+}
+
+func (r *Relay) deleteSent(ctx context.Context) error {
+	db, err := r.connect()
+	if err != nil {
+		return err
+	}
+	return db.WithContext(ctx).
+		Where("sent_at < ?", time.Now().Add(-r.cfg.Retention)).
+		Delete(&Message{}).Error
+}
@@ -2,14 +2,15 @@ package postgresql
 
 import (
 	"fmt"
+	"th3y3m/e-commerce-microservices/pkg/config"
 
-	"github.com/spf13/viper"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
+	gormtracing "gorm.io/plugin/opentelemetry/tracing"
 )
 
-func NewGormDB() (*gorm.DB, error) {
-	databaseURL := viper.GetString("CONNECTION_STRING")
+func NewGormDB(cfg config.Postgres) (*gorm.DB, error) {
+	databaseURL := cfg.ConnectionString
 
 	// Use GORM to open a PostgreSQL connection
 	db, err := gorm.Open(postgres.Open(databaseURL), &gorm.Config{})
@@ -17,6 +18,11 @@ func NewGormDB() (*gorm.DB, error) {
 		return nil, err
 	}
 
+	// Queries made with WithContext(ctx) become spans of the request
+	if err := db.Use(gormtracing.NewPlugin(gormtracing.WithoutMetrics())); err != nil {
+		return nil, err
+	}
+
 	fmt.Println("Connected to PostgreSQL using GORM!")
 	return db, nil
 }
@@ -0,0 +1,66 @@
+package rabbitmq
+
+import (
+	"context"
+	"encoding/json"
+	"th3y3m/e-commerce-microservices/pkg/broker"
+	"th3y3m/e-commerce-microservices/pkg/config"
+	"th3y3m/e-commerce-microservices/pkg/events"
+	"th3y3m/e-commerce-microservices/pkg/logging"
+	"th3y3m/e-commerce-microservices/pkg/metrics"
+	"th3y3m/e-commerce-microservices/pkg/tracing"
+	"time"
+
+	"github.com/streadway/amqp"
+)
+
+// How long a publish waits for the broker to confirm the message
+const publishTimeout = 5 * time.Second
+
+// Broker is the broker.Broker of a RabbitMQ connection
+type Broker struct {
+	conn *Connection
+	cfg  broker.ConsumerConfig
+}
+
+var _ broker.Broker = (*Broker)(nil)
+
+// NewBroker returns the broker of conn, its subscriptions consume as cfg says
+func NewBroker(conn *Connection, cfg broker.ConsumerConfig) *Broker {
+	return &Broker{conn: conn, cfg: cfg}
+}
+
+// DefaultBroker is the broker of the connection of cfg shared by the whole process
+func DefaultBroker(cfg config.RabbitMQ) broker.Broker {
+	return NewBroker(Default(cfg), broker.NewConsumerConfig(cfg))
+}
+
+// Publish sends an event in its envelope to Exchange, with its type as routing key. The
+// correlation ID and the trace context of ctx go in the message headers. It returns once the
+// broker has confirmed the message, which is persistent.
+func (b *Broker) Publish(ctx context.Context, env *events.Envelope) (err error) {
+	headers := amqp.Table{logging.HeaderRequestID: logging.RequestID(env.CorrelationID)}
+	_, span := tracing.StartPublish(ctx, env.Type, headers)
+	defer func() {
+		metrics.MessagePublished(env.Type, err)
+		tracing.End(span, err)
+	}()
+
+	body, err := json.Marshal(env)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, publishTimeout)
+	defer cancel()
+
+	return b.conn.PublishTopic(ctx, Exchange, env.Type, amqp.Publishing{
+		ContentType:  "application/json",
+		DeliveryMode: amqp.Persistent,
+		MessageId:    env.ID,
+		Type:         env.Type,
+		Timestamp:    env.OccurredAt,
+		Headers:      headers,
+		Body:         body,
+	})
+}
@@ -0,0 +1,375 @@
+package rabbitmq
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"th3y3m/e-commerce-microservices/pkg/config"
+	"time"
+
+	"github.com/streadway/amqp"
+)
+
+const (
+	dialTimeout = 5 * time.Second
+	// Channels kept open for publishing, more are opened under load and closed when returned
+	maxIdleChannels = 8
+)
+
+var ErrClosed = errors.New("rabbitmq: connection is closed")
+
+// Connection shares one AMQP connection between the publishers and consumers of a process.
+// The connection is dialed on first use and again after the broker drops it, and the exchanges,
+// queues and bindings declared through it are declared again on the new connection.
+type Connection struct {
+	uri string
+
+	mu        sync.Mutex
+	conn      *amqp.Connection
+	exchanges map[string]struct{}
+	queues    map[string]amqp.Table
+	bindings  map[binding]struct{}
+	closed    bool
+
+	idle chan *publisher
+}
+
+// binding routes the messages of exchange with a routing key matching key to queue
+type binding struct {
+	queue    string
+	exchange string
+	key      string
+}
+
+// publisher is a channel in confirm mode, used by one publish at a time
+type publisher struct {
+	conn     *amqp.Connection
+	ch       *amqp.Channel
+	confirms chan amqp.Confirmation
+	closed   chan *amqp.Error
+}
+
+// open reports whether neither the broker nor a failed publish closed the channel
+func (p *publisher) open() bool {
+	select {
+	case <-p.closed:
+		return false
+	default:
+		return true
+	}
+}
+
+func NewConnection(uri string) *Connection {
+	return &Connection{
+		uri:       uri,
+		exchanges: make(map[string]struct{}),
+		queues:    make(map[string]amqp.Table),
+		bindings:  make(map[binding]struct{}),
+		idle:      make(chan *publisher, maxIdleChannels),
+	}
+}
+
+var (
+	sharedMu    sync.Mutex
+	connections = make(map[string]*Connection)
+)
+
+// Default returns the connection to the broker of cfg, shared by the whole process
+func Default(cfg config.RabbitMQ) *Connection {
+	sharedMu.Lock()
+	defer sharedMu.Unlock()
+
+	conn, ok := connections[cfg.URI]
+	if !ok {
+		conn = NewConnection(cfg.URI)
+		connections[cfg.URI] = conn
+	}
+	return conn
+}
+
+// Ping connects to the broker if the connection is down
+func (c *Connection) Ping() error {
+	_, err := c.connection()
+	return err
+}
+
+// Close closes the connection, publishing and consuming fail afterwards
+func (c *Connection) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.closed = true
+	if c.conn == nil || c.conn.IsClosed() {
+		return nil
+	}
+	return c.conn.Close()
+}
+
+// connection returns the open AMQP connection, dialing again and recovering the topology
+// when the previous one was lost
+func (c *Connection) connection() (*amqp.Connection, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return nil, ErrClosed
+	}
+	if c.conn != nil && !c.conn.IsClosed() {
+		return c.conn, nil
+	}
+
+	conn, err := amqp.DialConfig(c.uri, amqp.Config{Dial: amqp.DefaultDial(dialTimeout)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to RabbitMQ: %w", err)
+	}
+	if err := c.recoverTopology(conn); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	if c.conn != nil {
+		log.Println("Reconnected to RabbitMQ")
+	}
+	c.conn = conn
+	return conn, nil
+}
+
+func (c *Connection) recoverTopology(conn *amqp.Connection) error {
+	if len(c.exchanges) == 0 && len(c.queues) == 0 {
+		return nil
+	}
+
+	ch, err := conn.Channel()
+	if err != nil {
+		return fmt.Errorf("failed to open a channel: %w", err)
+	}
+	defer ch.Close()
+
+	for exchange := range c.exchanges {
+		if err := declareExchange(ch, exchange); err != nil {
+			return err
+		}
+	}
+	for queue, args := range c.queues {
+		if err := declareQueue(ch, queue, args); err != nil {
+			return err
+		}
+	}
+	for b := range c.bindings {
+		if err := bindQueue(ch, b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// channel opens a channel on the shared connection
+func (c *Connection) channel() (*amqp.Channel, error) {
+	conn, err := c.connection()
+	if err != nil {
+		return nil, err
+	}
+	ch, err := conn.Channel()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open a channel: %w", err)
+	}
+	return ch, nil
+}
+
+// declare declares queue with args on ch unless it already exists on the current connection
+func (c *Connection) declare(ch *amqp.Channel, queue string, args amqp.Table) error {
+	c.mu.Lock()
+	_, ok := c.queues[queue]
+	c.mu.Unlock()
+	if ok {
+		return nil
+	}
+
+	if err := declareQueue(ch, queue, args); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	c.queues[queue] = args
+	c.mu.Unlock()
+	return nil
+}
+
+func declareQueue(ch *amqp.Channel, queue string, args amqp.Table) error {
+	_, err := ch.QueueDeclare(
+		queue,
+		true,  // durable
+		false, // auto-delete
+		false, // exclusive
+		false, // no-wait
+		args,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to declare queue %s: %w", queue, err)
+	}
+	return nil
+}
+
+// declareTopic declares the topic exchange on ch unless it already exists on the current connection
+func (c *Connection) declareTopic(ch *amqp.Channel, exchange string) error {
+	c.mu.Lock()
+	_, ok := c.exchanges[exchange]
+	c.mu.Unlock()
+	if ok {
+		return nil
+	}
+
+	if err := declareExchange(ch, exchange); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	c.exchanges[exchange] = struct{}{}
+	c.mu.Unlock()
+	return nil
+}
+
+func declareExchange(ch *amqp.Channel, exchange string) error {
+	err := ch.ExchangeDeclare(
+		exchange,
+		amqp.ExchangeTopic,
+		true,  // durable
+		false, // auto-delete
+		false, // internal
+		false, // no-wait
+		nil,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to declare exchange %s: %w", exchange, err)
+	}
+	return nil
+}
+
+// bind binds the queue to the exchange on ch unless it is already bound on the current connection
+func (c *Connection) bind(ch *amqp.Channel, b binding) error {
+	c.mu.Lock()
+	_, ok := c.bindings[b]
+	c.mu.Unlock()
+	if ok {
+		return nil
+	}
+
+	if err := bindQueue(ch, b); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	c.bindings[b] = struct{}{}
+	c.mu.Unlock()
+	return nil
+}
+
+func bindQueue(ch *amqp.Channel, b binding) error {
+	if err := ch.QueueBind(b.queue, b.key, b.exchange, false, nil); err != nil {
+		return fmt.Errorf("failed to bind %s to %s with %s: %w", b.queue, b.exchange, b.key, err)
+	}
+	return nil
+}
+
+// Publish sends msg to queue and waits until the broker confirms it has taken responsibility
+// for it, which for a persistent message on a durable queue means it was written to disk.
+// It is safe to call from concurrent goroutines.
+func (c *Connection) Publish(ctx context.Context, queue string, msg amqp.Publishing) error {
+	p, err := c.publisher()
+	if err != nil {
+		return err
+	}
+
+	if err := c.declare(p.ch, queue, nil); err != nil {
+		p.ch.Close()
+		return err
+	}
+	return c.publish(ctx, p, "", queue, queue, msg)
+}
+
+// PublishTopic sends msg to the topic exchange with the routing key and waits until the broker
+// confirms it. The message is dropped by the broker when no queue is bound for the key.
+func (c *Connection) PublishTopic(ctx context.Context, exchange, key string, msg amqp.Publishing) error {
+	p, err := c.publisher()
+	if err != nil {
+		return err
+	}
+
+	if err := c.declareTopic(p.ch, exchange); err != nil {
+		p.ch.Close()
+		return err
+	}
+	return c.publish(ctx, p, exchange, key, exchange+"/"+key, msg)
+}
+
+// publish sends msg on p and waits for its confirmation, target names the destination in errors
+func (c *Connection) publish(ctx context.Context, p *publisher, exchange, key, target string, msg amqp.Publishing) error {
+	if err := p.ch.Publish(exchange, key, false, false, msg); err != nil {
+		p.ch.Close()
+		return fmt.Errorf("failed to publish to %s: %w", target, err)
+	}
+
+	select {
+	case confirm, ok := <-p.confirms:
+		if !ok {
+			return fmt.Errorf("channel closed before the broker confirmed the message to %s", target)
+		}
+		c.release(p)
+		if !confirm.Ack {
+			return fmt.Errorf("broker did not accept the message to %s", target)
+		}
+		return nil
+	case <-ctx.Done():
+		// A late confirmation would be read by the next publish, so the channel is dropped
+		p.ch.Close()
+		return fmt.Errorf("no confirmation for the message to %s: %w", target, ctx.Err())
+	}
+}
+
+// publisher takes an idle channel of the current connection or opens a new one
+func (c *Connection) publisher() (*publisher, error) {
+	conn, err := c.connection()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		select {
+		case p := <-c.idle:
+			if p.conn == conn && p.open() {
+				return p, nil
+			}
+			// Left over from a lost connection, or closed by a channel error
+			p.ch.Close()
+		default:
+			return openPublisher(conn)
+		}
+	}
+}
+
+func openPublisher(conn *amqp.Connection) (*publisher, error) {
+	ch, err := conn.Channel()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open a channel: %w", err)
+	}
+	if err := ch.Confirm(false); err != nil {
+		ch.Close()
+		return nil, fmt.Errorf("failed to put the channel in confirm mode: %w", err)
+	}
+	return &publisher{
+		conn:     conn,
+		ch:       ch,
+		confirms: ch.NotifyPublish(make(chan amqp.Confirmation, 1)),
+		closed:   ch.NotifyClose(make(chan *amqp.Error, 1)),
+	}, nil
+}
+
+func (c *Connection) release(p *publisher) {
+	select {
+	case c.idle <- p:
+	default:
+		p.ch.Close()
+	}
+}
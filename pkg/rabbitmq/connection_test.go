@@ -0,0 +1,417 @@
+package rabbitmq
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"testing"
+	"th3y3m/e-commerce-microservices/pkg/broker"
+	"time"
+
+	"github.com/streadway/amqp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPublishIsConfirmed(t *testing.T) {
+	server := newFakeBroker(t)
+	conn := NewConnection(server.uri())
+	defer conn.Close()
+
+	err := conn.Publish(context.Background(), "orders", amqp.Publishing{Body: []byte("placed")})
+	require.NoError(t, err)
+	err = conn.PublishTopic(context.Background(), Exchange, "order.placed", amqp.Publishing{Body: []byte("event")})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"/orders:placed", Exchange + "/order.placed:event"}, server.messages())
+	assert.Equal(t, 1, server.declarations("queue orders"))
+	assert.Equal(t, 1, server.declarations("exchange "+Exchange))
+}
+
+func TestPublishFailsWhenTheBrokerRejects(t *testing.T) {
+	server := newFakeBroker(t)
+	conn := NewConnection(server.uri())
+	defer conn.Close()
+
+	server.setReply(replyNack)
+	err := conn.Publish(context.Background(), "orders", amqp.Publishing{Body: []byte("placed")})
+	assert.EqualError(t, err, "broker did not accept the message to orders")
+
+	// Without a confirmation the channel is dropped, the next publish does not read a stale one
+	server.setReply(replyNone)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	err = conn.Publish(ctx, "orders", amqp.Publishing{Body: []byte("placed")})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	server.setReply(replyAck)
+	assert.NoError(t, conn.Publish(context.Background(), "orders", amqp.Publishing{Body: []byte("placed")}))
+}
+
+func TestPublishResumesAfterTheConnectionDrops(t *testing.T) {
+	server := newFakeBroker(t)
+	conn := NewConnection(server.uri())
+	defer conn.Close()
+
+	require.NoError(t, conn.Publish(context.Background(), "orders", amqp.Publishing{Body: []byte("first")}))
+	require.NoError(t, conn.PublishTopic(context.Background(), Exchange, "order.placed", amqp.Publishing{Body: []byte("event")}))
+
+	server.drop()
+	require.Eventually(t, func() bool {
+		conn.mu.Lock()
+		defer conn.mu.Unlock()
+		return conn.conn.IsClosed()
+	}, time.Second, 5*time.Millisecond)
+
+	require.NoError(t, conn.Publish(context.Background(), "orders", amqp.Publishing{Body: []byte("second")}))
+
+	assert.Equal(t, 2, server.connections())
+	assert.Equal(t, []string{"/orders:first", Exchange + "/order.placed:event", "/orders:second"}, server.messages())
+	// The topology is declared again on the new connection
+	assert.Equal(t, 2, server.declarations("queue orders"))
+	assert.Equal(t, 2, server.declarations("exchange "+Exchange))
+}
+
+func TestConcurrentPublishes(t *testing.T) {
+	server := newFakeBroker(t)
+	conn := NewConnection(server.uri())
+	defer conn.Close()
+
+	const publishes = 50
+	var wg sync.WaitGroup
+	errs := make(chan error, publishes)
+	for i := 0; i < publishes; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs <- conn.Publish(context.Background(), "orders", amqp.Publishing{Body: []byte(fmt.Sprint(i))})
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		assert.NoError(t, err)
+	}
+
+	want := make([]string, 0, publishes)
+	for i := 0; i < publishes; i++ {
+		want = append(want, fmt.Sprintf("/orders:%d", i))
+	}
+	assert.ElementsMatch(t, want, server.messages())
+	assert.Equal(t, 1, server.connections())
+}
+
+func TestDeclareSubscriptions(t *testing.T) {
+	server := newFakeBroker(t)
+	b := NewBroker(NewConnection(server.uri()), broker.ConsumerConfig{})
+	defer b.conn.Close()
+
+	err := b.Declare(
+		broker.Subscription{Queue: "mail", Events: []string{"order.placed", "payment.*"}},
+		broker.Subscription{Queue: "gateway.1", Events: []string{"cache.invalidated"}, Exclusive: true},
+	)
+	require.NoError(t, err)
+	// Declared once per connection
+	require.NoError(t, b.Declare(broker.Subscription{Queue: "mail", Events: []string{"order.placed"}}))
+
+	assert.Equal(t, 1, server.declarations("queue mail"))
+	assert.Equal(t, 1, server.declarations("exchange "+Exchange))
+	assert.Equal(t, 1, server.declarations("binding mail "+Exchange+"/order.placed"))
+	assert.Equal(t, 1, server.declarations("binding mail "+Exchange+"/payment.*"))
+	assert.Equal(t, 0, server.declarations("queue gateway.1"))
+}
+
+func TestClosedConnection(t *testing.T) {
+	server := newFakeBroker(t)
+	conn := NewConnection(server.uri())
+	require.NoError(t, conn.Ping())
+	require.NoError(t, conn.Close())
+
+	err := conn.Publish(context.Background(), "orders", amqp.Publishing{})
+	assert.ErrorIs(t, err, ErrClosed)
+}
+
+// How the fake broker answers published messages
+const (
+	replyAck = iota
+	replyNack
+	replyNone
+)
+
+// fakeBroker speaks just enough AMQP 0-9-1 for the publishing side of Connection: the
+// handshake, channels, confirm mode, declarations, bindings and confirmed publishes.
+type fakeBroker struct {
+	ln net.Listener
+
+	mu       sync.Mutex
+	conns    []net.Conn
+	accepted int
+	declared map[string]int
+	received []string
+	reply    int
+}
+
+func newFakeBroker(t *testing.T) *fakeBroker {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	b := &fakeBroker{ln: ln, declared: make(map[string]int)}
+	go b.accept()
+	t.Cleanup(func() {
+		ln.Close()
+		b.drop()
+	})
+	return b
+}
+
+func (b *fakeBroker) uri() string {
+	return "amqp://guest:guest@" + b.ln.Addr().String() + "/"
+}
+
+func (b *fakeBroker) setReply(reply int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.reply = reply
+}
+
+// drop closes every client connection, as a broker restart would
+func (b *fakeBroker) drop() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, conn := range b.conns {
+		conn.Close()
+	}
+	b.conns = nil
+}
+
+func (b *fakeBroker) connections() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.accepted
+}
+
+func (b *fakeBroker) declarations(name string) int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.declared[name]
+}
+
+// messages returns the confirmed messages as exchange/key:body
+func (b *fakeBroker) messages() []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]string(nil), b.received...)
+}
+
+func (b *fakeBroker) accept() {
+	for {
+		conn, err := b.ln.Accept()
+		if err != nil {
+			return
+		}
+		b.mu.Lock()
+		b.conns = append(b.conns, conn)
+		b.accepted++
+		b.mu.Unlock()
+		go b.serve(conn)
+	}
+}
+
+// pending is a message whose content frames are still being read
+type pending struct {
+	target string
+	size   uint64
+	body   []byte
+}
+
+// fakeChannel is the state of one channel of a client connection
+type fakeChannel struct {
+	tag     uint64
+	publish *pending
+}
+
+func (b *fakeBroker) serve(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+
+	header := make([]byte, 8)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return
+	}
+	writeMethod(conn, 0, 10, 10, (&amqpArgs{}).octet(0).octet(9).table().longstr("PLAIN").longstr("en_US"))
+
+	channels := make(map[uint16]*fakeChannel)
+	for {
+		kind, channel, payload, err := readFrame(r)
+		if err != nil {
+			return
+		}
+
+		ch := channels[channel]
+		switch kind {
+		case 2: // content header
+			ch.publish.size = binary.BigEndian.Uint64(payload[4:12])
+			if ch.publish.size == 0 {
+				b.confirm(conn, channel, ch)
+			}
+			continue
+		case 3: // content body
+			ch.publish.body = append(ch.publish.body, payload...)
+			if uint64(len(ch.publish.body)) >= ch.publish.size {
+				b.confirm(conn, channel, ch)
+			}
+			continue
+		case 1:
+		default:
+			continue
+		}
+
+		class, method := binary.BigEndian.Uint16(payload), binary.BigEndian.Uint16(payload[2:])
+		args := bytes.NewReader(payload[4:])
+		switch {
+		case class == 10 && method == 11: // connection.start-ok
+			writeMethod(conn, 0, 10, 30, (&amqpArgs{}).short(0).long(131072).short(0))
+		case class == 10 && method == 40: // connection.open
+			writeMethod(conn, 0, 10, 41, (&amqpArgs{}).shortstr(""))
+		case class == 10 && method == 50: // connection.close
+			writeMethod(conn, 0, 10, 51, &amqpArgs{})
+			return
+		case class == 20 && method == 10: // channel.open
+			channels[channel] = &fakeChannel{}
+			writeMethod(conn, channel, 20, 11, (&amqpArgs{}).long(0))
+		case class == 20 && method == 40: // channel.close
+			delete(channels, channel)
+			writeMethod(conn, channel, 20, 41, &amqpArgs{})
+		case class == 85 && method == 10: // confirm.select
+			writeMethod(conn, channel, 85, 11, &amqpArgs{})
+		case class == 40 && method == 10: // exchange.declare
+			args.Seek(2, io.SeekCurrent)
+			b.declare("exchange " + readShortstr(args))
+			writeMethod(conn, channel, 40, 11, &amqpArgs{})
+		case class == 50 && method == 10: // queue.declare
+			args.Seek(2, io.SeekCurrent)
+			queue := readShortstr(args)
+			b.declare("queue " + queue)
+			writeMethod(conn, channel, 50, 11, (&amqpArgs{}).shortstr(queue).long(0).long(0))
+		case class == 50 && method == 20: // queue.bind
+			args.Seek(2, io.SeekCurrent)
+			queue := readShortstr(args)
+			exchange := readShortstr(args)
+			b.declare("binding " + queue + " " + exchange + "/" + readShortstr(args))
+			writeMethod(conn, channel, 50, 21, &amqpArgs{})
+		case class == 60 && method == 40: // basic.publish
+			args.Seek(2, io.SeekCurrent)
+			exchange := readShortstr(args)
+			key := readShortstr(args)
+			ch.publish = &pending{target: exchange + "/" + key}
+		}
+	}
+}
+
+func (b *fakeBroker) declare(name string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.declared[name]++
+}
+
+// confirm records the message of the channel and answers it the way the broker is set to
+func (b *fakeBroker) confirm(conn net.Conn, channel uint16, ch *fakeChannel) {
+	ch.tag++
+	msg := ch.publish.target + ":" + string(ch.publish.body)
+	ch.publish = nil
+
+	b.mu.Lock()
+	reply := b.reply
+	if reply == replyAck {
+		b.received = append(b.received, msg)
+	}
+	b.mu.Unlock()
+
+	switch reply {
+	case replyAck:
+		writeMethod(conn, channel, 60, 80, (&amqpArgs{}).longlong(ch.tag).octet(0))
+	case replyNack:
+		writeMethod(conn, channel, 60, 120, (&amqpArgs{}).longlong(ch.tag).octet(0))
+	}
+}
+
+func readFrame(r *bufio.Reader) (kind byte, channel uint16, payload []byte, err error) {
+	header := make([]byte, 7)
+	if _, err = io.ReadFull(r, header); err != nil {
+		return
+	}
+	kind = header[0]
+	channel = binary.BigEndian.Uint16(header[1:3])
+	payload = make([]byte, binary.BigEndian.Uint32(header[3:7])+1)
+	if _, err = io.ReadFull(r, payload); err != nil {
+		return
+	}
+	// Drop the frame end
+	payload = payload[:len(payload)-1]
+	return
+}
+
+func readShortstr(r *bytes.Reader) string {
+	n, _ := r.ReadByte()
+	s := make([]byte, n)
+	io.ReadFull(r, s)
+	return string(s)
+}
+
+func writeMethod(w io.Writer, channel uint16, class, method uint16, args *amqpArgs) {
+	payload := (&amqpArgs{}).short(class).short(method)
+	payload.Write(args.Bytes())
+
+	frame := (&amqpArgs{}).octet(1).short(channel).long(uint32(payload.Len()))
+	frame.Write(payload.Bytes())
+	frame.WriteByte(0xCE)
+	w.Write(frame.Bytes())
+}
+
+// amqpArgs encodes the arguments of a method
+type amqpArgs struct {
+	bytes.Buffer
+}
+
+func (a *amqpArgs) octet(v byte) *amqpArgs {
+	a.WriteByte(v)
+	return a
+}
+
+func (a *amqpArgs) short(v uint16) *amqpArgs {
+	binary.Write(a, binary.BigEndian, v)
+	return a
+}
+
+func (a *amqpArgs) long(v uint32) *amqpArgs {
+	binary.Write(a, binary.BigEndian, v)
+	return a
+}
+
+func (a *amqpArgs) longlong(v uint64) *amqpArgs {
+	binary.Write(a, binary.BigEndian, v)
+	return a
+}
+
+func (a *amqpArgs) shortstr(s string) *amqpArgs {
+	a.WriteByte(byte(len(s)))
+	a.WriteString(s)
+	return a
+}
+
+func (a *amqpArgs) longstr(s string) *amqpArgs {
+	a.long(uint32(len(s)))
+	a.WriteString(s)
+	return a
+}
+
+// table writes an empty field table
+func (a *amqpArgs) table() *amqpArgs {
+	return a.long(0)
+}
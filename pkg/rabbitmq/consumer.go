@@ -2,75 +2,310 @@ package rabbitmq
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"log"
+	"sync"
+	"th3y3m/e-commerce-microservices/pkg/broker"
+	"th3y3m/e-commerce-microservices/pkg/logging"
+	"th3y3m/e-commerce-microservices/pkg/metrics"
+	"th3y3m/e-commerce-microservices/pkg/tracing"
+	"time"
 
-	"github.com/spf13/viper"
 	"github.com/streadway/amqp"
 )
 
-func ConsumeMessages(ctx context.Context, queueName string, handler func(map[string]string) error) error {
-	rabbitMQURI := viper.GetString("RABBITMQ_URI")
-	log.Println("RABBITMQ_URI: ", rabbitMQURI)
-	conn, err := amqp.Dial(rabbitMQURI)
-	if err != nil {
-		return fmt.Errorf("failed to connect to RabbitMQ: %w", err)
+// Delays between attempts to consume again after the connection was lost
+const (
+	minReconnectDelay = time.Second
+	maxReconnectDelay = 30 * time.Second
+)
+
+// Headers added to a message that failed, kept when it is dead-lettered
+const (
+	HeaderRetryCount = "x-retry-count"
+	HeaderError      = "x-error"
+)
+
+// DeadLetterQueue is the queue holding the messages of queue that failed every retry
+func DeadLetterQueue(queue string) string {
+	return queue + ".dead-letter"
+}
+
+// retryQueue holds the messages of queue waiting delay before they are delivered again.
+// The delay is part of the name because the TTL of an existing queue cannot be changed.
+func retryQueue(queue string, delay time.Duration) string {
+	return fmt.Sprintf("%s.retry.%s", queue, delay)
+}
+
+// Subscribe declares the queue of the subscription and its bindings to Exchange, then calls
+// handler for every message of the queue until ctx is canceled, and returns
+// once the messages in hand are handled. The handler context carries the request ID of the message,
+// so its log lines tie back to the original request.
+//
+// A message is acknowledged once handled. When the handler fails the message is retried after a
+// growing delay, and moved to DeadLetterQueue(sub.Queue) after the last retry. When the broker
+// cannot be reached or drops the connection, consuming resumes after a backoff.
+func (b *Broker) Subscribe(ctx context.Context, sub broker.Subscription, handler broker.Handler) error {
+	c := &consumer{
+		conn:      b.conn,
+		queue:     sub.Queue,
+		bindings:  bindings(sub),
+		exclusive: sub.Exclusive,
+		cfg:       b.cfg,
+		handler:   handler,
 	}
-	defer conn.Close()
 
-	ch, err := conn.Channel()
-	if err != nil {
-		return fmt.Errorf("failed to open a channel: %w", err)
+	delay := minReconnectDelay
+	for {
+		started, err := c.run(ctx)
+		if ctx.Err() != nil {
+			log.Printf("Stopped consuming %s", sub.Queue)
+			return nil
+		}
+		if started {
+			delay = minReconnectDelay
+		}
+
+		log.Printf("Consuming %s failed, retrying in %s: %v", sub.Queue, delay, err)
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			log.Printf("Stopped consuming %s", sub.Queue)
+			return nil
+		}
+		delay = min(delay*2, maxReconnectDelay)
 	}
-	defer ch.Close()
+}
+
+type consumer struct {
+	conn      *Connection
+	queue     string
+	bindings  []binding
+	exclusive bool
+	cfg       broker.ConsumerConfig
+	handler   broker.Handler
+}
 
-	q, err := ch.QueueDeclare(
-		queueName,
-		true,
-		false,
-		false,
-		false,
+// declareTopology declares the queue and its bindings, its retry queues and its dead-letter queue.
+// A retry queue has no consumer: messages expire after its TTL and are dead-lettered back to the
+// queue through the default exchange, so only the failed subscriber sees them again.
+func (c *consumer) declareTopology(ch *amqp.Channel) error {
+	if c.exclusive {
+		return c.declareExclusive(ch)
+	}
+	if err := c.conn.declareDurable(ch, c.queue, c.bindings); err != nil {
+		return err
+	}
+	if err := c.conn.declare(ch, DeadLetterQueue(c.queue), nil); err != nil {
+		return err
+	}
+	for attempt := 1; attempt <= c.cfg.MaxRetries; attempt++ {
+		delay := c.cfg.Backoff(attempt)
+		err := c.conn.declare(ch, retryQueue(c.queue, delay), amqp.Table{
+			"x-message-ttl":             delay.Milliseconds(),
+			"x-dead-letter-exchange":    "",
+			"x-dead-letter-routing-key": c.queue,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// declareExclusive declares the queue, for this connection alone, and its bindings. The queue
+// is deleted with its bindings when the consumer stops, so they are declared again on every
+// run rather than remembered by the connection.
+func (c *consumer) declareExclusive(ch *amqp.Channel) error {
+	_, err := ch.QueueDeclare(
+		c.queue,
+		false, // durable
+		true,  // auto-delete
+		true,  // exclusive
+		false, // no-wait
 		nil,
 	)
 	if err != nil {
-		return fmt.Errorf("failed to declare a queue: %w", err)
+		return fmt.Errorf("failed to declare queue %s: %w", c.queue, err)
+	}
+	if len(c.bindings) > 0 {
+		if err := c.conn.declareTopic(ch, Exchange); err != nil {
+			return err
+		}
+	}
+	for _, b := range c.bindings {
+		if err := bindQueue(ch, b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// run handles messages on a channel of the shared connection until ctx is canceled or the
+// channel is closed. started reports whether the consumer was registered.
+func (c *consumer) run(ctx context.Context) (started bool, err error) {
+	ch, err := c.conn.channel()
+	if err != nil {
+		return false, err
+	}
+	// Messages prefetched but not handled yet go back to the queue
+	defer ch.Close()
+
+	if err := c.declareTopology(ch); err != nil {
+		return false, err
+	}
+	if err := ch.Qos(c.cfg.Prefetch, 0, false); err != nil {
+		return false, fmt.Errorf("failed to set the prefetch count: %w", err)
 	}
 
 	msgs, err := ch.Consume(
-		q.Name,
+		c.queue,
 		"",
-		true,  // auto-ack
+		false, // auto-ack
 		false, // exclusive
 		false, // no-local
 		false, // no-wait
 		nil,
 	)
 	if err != nil {
-		return fmt.Errorf("failed to register a consumer: %w", err)
+		return false, fmt.Errorf("failed to register a consumer: %w", err)
 	}
 
-	go func() {
-		for {
-			select {
-			case d := <-msgs:
-				var message map[string]string
-				if err := json.Unmarshal(d.Body, &message); err != nil {
-					fmt.Printf("Failed to unmarshal message: %v\n", err)
-					continue
-				}
+	// Messages already handed to the handler are finished on shutdown, so they are handled
+	// with a context that is not canceled with ctx
+	handlerCtx := context.WithoutCancel(ctx)
 
-				if err := handler(message); err != nil {
-					fmt.Printf("Handler error: %v\n", err)
+	closed := make(chan struct{})
+	var once sync.Once
+	var wg sync.WaitGroup
+	for i := 0; i < c.cfg.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case d, ok := <-msgs:
+					if !ok {
+						once.Do(func() { close(closed) })
+						return
+					}
+					c.handle(handlerCtx, d)
+				case <-ctx.Done():
+					return
 				}
-
-			case <-ctx.Done():
-				fmt.Println("Context canceled, stopping consumer.")
-				return
 			}
+		}()
+	}
+	wg.Wait()
+
+	select {
+	case <-closed:
+		return true, fmt.Errorf("consumer of %s was closed by the server", c.queue)
+	default:
+		return true, nil
+	}
+}
+
+func (c *consumer) handle(ctx context.Context, d amqp.Delivery) {
+	requestID, _ := d.Headers[logging.HeaderRequestID].(string)
+	ctx = logging.WithRequestInfo(ctx, &logging.RequestInfo{
+		RequestID: logging.RequestID(requestID),
+		Route:     c.queue,
+	})
+
+	ctx, span := tracing.StartConsume(ctx, c.queue, d.Headers)
+
+	err := c.handler(ctx, d.Body)
+	metrics.MessageConsumed(c.queue, err)
+	tracing.End(span, err)
+
+	if err == nil {
+		if ackErr := d.Ack(false); ackErr != nil {
+			logging.Logger().WithContext(ctx).Warnf("Failed to acknowledge message, it will be delivered again: %v", ackErr)
 		}
-	}()
+		return
+	}
+	c.fail(ctx, d, err)
+}
 
-	<-ctx.Done()
-	return nil
+// fail moves a failed message to the next retry queue, or to the dead-letter queue after the last
+// retry, and acknowledges it. If the message cannot be moved it is requeued as it is. Failed
+// messages of exclusive queues are dropped.
+func (c *consumer) fail(ctx context.Context, d amqp.Delivery, err error) {
+	logger := logging.Logger().WithContext(ctx)
+
+	// Exclusive queues have no retry or dead-letter queues
+	if c.exclusive {
+		logger.Errorf("Message of %s dropped: %v", c.queue, err)
+		if nackErr := d.Nack(false, false); nackErr != nil {
+			logger.Warnf("Failed to drop message: %v", nackErr)
+		}
+		return
+	}
+
+	attempt := RetryCount(d.Headers) + 1
+	target := DeadLetterQueue(c.queue)
+	if attempt <= c.cfg.MaxRetries && !broker.IsPermanent(err) {
+		target = retryQueue(c.queue, c.cfg.Backoff(attempt))
+	}
+
+	headers := amqp.Table{}
+	for key, value := range d.Headers {
+		headers[key] = value
+	}
+	headers[HeaderRetryCount] = int32(attempt)
+	headers[HeaderError] = err.Error()
+
+	publishCtx, cancel := context.WithTimeout(ctx, publishTimeout)
+	defer cancel()
+	pubErr := c.conn.Publish(publishCtx, target, republishing(d, headers))
+	if pubErr != nil {
+		logger.Errorf("Failed to move message to %s, requeueing it: %v", target, pubErr)
+		if nackErr := d.Nack(false, true); nackErr != nil {
+			logger.Warnf("Failed to requeue message, it will be delivered again: %v", nackErr)
+		}
+		return
+	}
+
+	if target == DeadLetterQueue(c.queue) {
+		metrics.MessageDeadLettered(c.queue)
+		logger.Errorf("Message dead-lettered to %s after %d attempts: %v", target, attempt, err)
+	} else {
+		metrics.MessageRetried(c.queue)
+		logger.Warnf("Message will be retried in %s: %v", c.cfg.Backoff(attempt), err)
+	}
+	if ackErr := d.Ack(false); ackErr != nil {
+		logger.Warnf("Failed to acknowledge message, it will be delivered again: %v", ackErr)
+	}
+}
+
+// republishing copies d, with the given headers, to be published again on a retry or replay
+func republishing(d amqp.Delivery, headers amqp.Table) amqp.Publishing {
+	return amqp.Publishing{
+		ContentType:     d.ContentType,
+		ContentEncoding: d.ContentEncoding,
+		DeliveryMode:    amqp.Persistent,
+		Priority:        d.Priority,
+		CorrelationId:   d.CorrelationId,
+		ReplyTo:         d.ReplyTo,
+		MessageId:       d.MessageId,
+		Type:            d.Type,
+		AppId:           d.AppId,
+		Timestamp:       time.Now(),
+		Headers:         headers,
+		Body:            d.Body,
+	}
+}
+
+// RetryCount returns how many times a message was retried
+func RetryCount(headers amqp.Table) int {
+	switch count := headers[HeaderRetryCount].(type) {
+	case int32:
+		return int(count)
+	case int64:
+		return int(count)
+	case int:
+		return count
+	}
+	return 0
 }
@@ -0,0 +1,54 @@
+package rabbitmq
+
+import (
+	"testing"
+	"th3y3m/e-commerce-microservices/pkg/broker"
+	"time"
+
+	"github.com/streadway/amqp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryQueues(t *testing.T) {
+	cfg := broker.ConsumerConfig{RetryDelay: time.Second}
+	assert.Equal(t, "orders.retry.1s", retryQueue("orders", cfg.Backoff(1)))
+	assert.Equal(t, "orders.retry.4s", retryQueue("orders", cfg.Backoff(3)))
+	assert.Equal(t, "orders.dead-letter", DeadLetterQueue("orders"))
+}
+
+func TestRetryCount(t *testing.T) {
+	assert.Equal(t, 0, RetryCount(nil))
+	assert.Equal(t, 2, RetryCount(amqp.Table{HeaderRetryCount: int32(2)}))
+	assert.Equal(t, 3, RetryCount(amqp.Table{HeaderRetryCount: int64(3)}))
+}
+
+func TestSubscriptionBindings(t *testing.T) {
+	sub := broker.Subscription{Queue: "mail", Events: []string{"order.placed", "payment.*"}}
+	assert.Equal(t, []binding{
+		{queue: "mail", exchange: Exchange, key: "order.placed"},
+		{queue: "mail", exchange: Exchange, key: "payment.*"},
+	}, bindings(sub))
+}
+
+func TestRepublishingKeepsTheMessageProperties(t *testing.T) {
+	d := amqp.Delivery{
+		ContentType:   "application/json",
+		CorrelationId: "req-1",
+		MessageId:     "msg-1",
+		Type:          "order.placed",
+		AppId:         "order",
+		Redelivered:   true,
+		Body:          []byte(`{}`),
+	}
+	headers := amqp.Table{HeaderRetryCount: int32(1)}
+
+	msg := republishing(d, headers)
+	assert.Equal(t, "application/json", msg.ContentType)
+	assert.Equal(t, "req-1", msg.CorrelationId)
+	assert.Equal(t, "msg-1", msg.MessageId)
+	assert.Equal(t, "order.placed", msg.Type)
+	assert.Equal(t, "order", msg.AppId)
+	assert.Equal(t, amqp.Persistent, msg.DeliveryMode)
+	assert.Equal(t, headers, msg.Headers)
+	assert.Equal(t, d.Body, msg.Body)
+}
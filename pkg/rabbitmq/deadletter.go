@@ -0,0 +1,124 @@
+package rabbitmq
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"th3y3m/e-commerce-microservices/pkg/logging"
+	"time"
+
+	"github.com/streadway/amqp"
+)
+
+var ErrNoDeadLetterQueue = errors.New("rabbitmq: queue has no dead-letter queue")
+
+// DeadLetter is a message that failed every retry
+type DeadLetter struct {
+	MessageID      string    `json:"message_id,omitempty"`
+	Type           string    `json:"type,omitempty"`
+	RequestID      string    `json:"request_id,omitempty"`
+	Error          string    `json:"error"`
+	Retries        int       `json:"retries"`
+	DeadLetteredAt time.Time `json:"dead_lettered_at"`
+	Body           string    `json:"body"`
+}
+
+// DeadLetters lists up to limit messages of the dead-letter queue of queue, oldest first,
+// and the number of messages it holds. The messages stay in the queue, but since they are
+// fetched and returned unacknowledged RabbitMQ flags them as redelivered.
+func (c *Connection) DeadLetters(queue string, limit int) ([]DeadLetter, int, error) {
+	ch, err := c.channel()
+	if err != nil {
+		return nil, 0, err
+	}
+	// Messages fetched without acknowledgement return to the queue when the channel closes
+	defer ch.Close()
+
+	total, err := inspectDeadLetterQueue(ch, queue)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	letters := make([]DeadLetter, 0, min(limit, total))
+	for len(letters) < limit {
+		d, ok, err := ch.Get(DeadLetterQueue(queue), false)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to read %s: %w", DeadLetterQueue(queue), err)
+		}
+		if !ok {
+			break
+		}
+
+		requestID, _ := d.Headers[logging.HeaderRequestID].(string)
+		reason, _ := d.Headers[HeaderError].(string)
+		letters = append(letters, DeadLetter{
+			MessageID:      d.MessageId,
+			Type:           d.Type,
+			RequestID:      requestID,
+			Error:          reason,
+			Retries:        RetryCount(d.Headers),
+			DeadLetteredAt: d.Timestamp,
+			Body:           string(d.Body),
+		})
+	}
+	return letters, total, nil
+}
+
+// ReplayDeadLetters moves up to limit messages of the dead-letter queue of queue back to queue,
+// oldest first, with their retries reset. It returns how many messages were moved.
+func (c *Connection) ReplayDeadLetters(ctx context.Context, queue string, limit int) (int, error) {
+	ch, err := c.channel()
+	if err != nil {
+		return 0, err
+	}
+	defer ch.Close()
+
+	if _, err := inspectDeadLetterQueue(ch, queue); err != nil {
+		return 0, err
+	}
+
+	replayed := 0
+	for replayed < limit {
+		d, ok, err := ch.Get(DeadLetterQueue(queue), false)
+		if err != nil {
+			return replayed, fmt.Errorf("failed to read %s: %w", DeadLetterQueue(queue), err)
+		}
+		if !ok {
+			break
+		}
+
+		headers := amqp.Table{}
+		for key, value := range d.Headers {
+			headers[key] = value
+		}
+		delete(headers, HeaderRetryCount)
+		delete(headers, HeaderError)
+
+		publishCtx, cancel := context.WithTimeout(ctx, publishTimeout)
+		err = c.Publish(publishCtx, queue, republishing(d, headers))
+		cancel()
+		if err != nil {
+			// The message stays in the dead-letter queue
+			return replayed, err
+		}
+
+		if err := d.Ack(false); err != nil {
+			return replayed, fmt.Errorf("replayed message was not removed from %s: %w", DeadLetterQueue(queue), err)
+		}
+		replayed++
+	}
+	return replayed, nil
+}
+
+// inspectDeadLetterQueue returns the number of messages in the dead-letter queue of queue
+func inspectDeadLetterQueue(ch *amqp.Channel, queue string) (int, error) {
+	q, err := ch.QueueInspect(DeadLetterQueue(queue))
+	if err != nil {
+		var amqpErr *amqp.Error
+		if errors.As(err, &amqpErr) && amqpErr.Code == amqp.NotFound {
+			return 0, ErrNoDeadLetterQueue
+		}
+		return 0, fmt.Errorf("failed to inspect %s: %w", DeadLetterQueue(queue), err)
+	}
+	return q.Messages, nil
+}
@@ -0,0 +1,59 @@
+package rabbitmq
+
+import (
+	"th3y3m/e-commerce-microservices/pkg/broker"
+
+	"github.com/streadway/amqp"
+)
+
+// Exchange is the topic exchange domain events are published to, with their event type
+// (such as order.placed) as routing key
+const Exchange = "events"
+
+// bindings of the queue of the subscription to Exchange
+func bindings(sub broker.Subscription) []binding {
+	bindings := make([]binding, 0, len(sub.Events))
+	for _, key := range sub.Events {
+		bindings = append(bindings, binding{queue: sub.Queue, exchange: Exchange, key: key})
+	}
+	return bindings
+}
+
+// Declare declares the durable queue of every subscription that is not exclusive and binds it
+// to Exchange. Queues and bindings are declared again after a reconnect.
+func (b *Broker) Declare(subs ...broker.Subscription) error {
+	ch, err := b.conn.channel()
+	if err != nil {
+		return err
+	}
+	defer ch.Close()
+
+	for _, sub := range subs {
+		if sub.Exclusive {
+			continue
+		}
+		if err := b.conn.declareDurable(ch, sub.Queue, bindings(sub)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// declareDurable declares the durable queue and its bindings to Exchange on ch, unless they
+// already exist on the current connection
+func (c *Connection) declareDurable(ch *amqp.Channel, queue string, bindings []binding) error {
+	if err := c.declare(ch, queue, nil); err != nil {
+		return err
+	}
+	if len(bindings) > 0 {
+		if err := c.declareTopic(ch, Exchange); err != nil {
+			return err
+		}
+	}
+	for _, b := range bindings {
+		if err := c.bind(ch, b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -5,27 +5,42 @@ import (
 	"fmt"
 	"log"
 	"strconv"
+	"th3y3m/e-commerce-microservices/pkg/config"
 	"time"
 
+	"github.com/redis/go-redis/extra/redisotel/v9"
 	"github.com/redis/go-redis/v9"
-	"github.com/spf13/viper"
 )
 
-func ConnectToRedis() (*redis.Client, error) {
-	// Get Redis connection parameters from environment variables
-	add, pass, dbStr := viper.GetString("REDIS_URI"), viper.GetString("REDIS_PASSWORD"), viper.GetString("REDIS_DB")
+// NewClient builds a Redis client from cfg without waiting for the server.
+// The client connects lazily, so callers that can work without Redis may start before it is up.
+func NewClient(cfg config.Redis) (*redis.Client, error) {
+	add, pass, dbStr := cfg.URI, cfg.Password, cfg.DB
 
 	db, err := strconv.Atoi(dbStr)
 	if err != nil {
 		return nil, fmt.Errorf("invalid Redis DB number: %w", err)
 	}
 
-	redisClient := redis.NewClient(&redis.Options{
+	client := redis.NewClient(&redis.Options{
 		Addr:     add,  // Redis address
 		Password: pass, // Redis password
 		DB:       db,   // Redis DB number
 	})
 
+	// Commands become spans of the request that issued them
+	if err := redisotel.InstrumentTracing(client); err != nil {
+		return nil, fmt.Errorf("failed to instrument Redis: %w", err)
+	}
+	return client, nil
+}
+
+func ConnectToRedis(cfg config.Redis) (*redis.Client, error) {
+	redisClient, err := NewClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+
 	// Retry logic for Redis connection
 	for i := 0; i < 5; i++ { // Retry up to 5 times
 		_, err = redisClient.Ping(context.Background()).Result()
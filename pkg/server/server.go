@@ -0,0 +1,85 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"os/signal"
+	"sync"
+	"syscall"
+	"th3y3m/e-commerce-microservices/pkg/config"
+	"th3y3m/e-commerce-microservices/pkg/health"
+	"time"
+)
+
+// Worker runs in the background next to the HTTP server, such as a RabbitMQ consumer.
+// It must return once ctx is canceled, after finishing the work in hand.
+type Worker func(ctx context.Context) error
+
+// Run serves handler on addr and starts the workers. On SIGINT or SIGTERM, or when a worker
+// fails, the service reports not ready and keeps serving for the shutdown delay of cfg. It then
+// stops accepting connections and waits for in-flight requests and workers, for at most the
+// shutdown timeout of cfg.
+func Run(addr string, cfg config.Server, handler http.Handler, checker *health.Checker, workers ...Worker) error {
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	srv := &http.Server{Addr: addr, Handler: handler}
+	serveErr := make(chan error, 1)
+	go func() {
+		if err := srv.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+			serveErr <- err
+		}
+	}()
+
+	workerCtx, cancelWorkers := context.WithCancel(context.Background())
+	defer cancelWorkers()
+	workerErr := make(chan error, len(workers))
+	var wg sync.WaitGroup
+	for _, worker := range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := worker(workerCtx); err != nil {
+				workerErr <- err
+			}
+		}()
+	}
+
+	var err error
+	select {
+	case <-ctx.Done():
+		log.Println("Shutting down")
+	case err = <-serveErr:
+		log.Printf("Server failed, shutting down: %v", err)
+	case err = <-workerErr:
+		log.Printf("Worker failed, shutting down: %v", err)
+	}
+	checker.ShuttingDown()
+	if cfg.ShutdownDelay > 0 {
+		// Keep serving while /readyz fails, so traffic moves elsewhere before connections are refused
+		time.Sleep(cfg.ShutdownDelay)
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+
+	if shutdownErr := srv.Shutdown(shutdownCtx); shutdownErr != nil {
+		log.Printf("HTTP connections were not drained: %v", shutdownErr)
+	}
+
+	cancelWorkers()
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-shutdownCtx.Done():
+		log.Println("Workers did not stop before the shutdown deadline")
+	}
+
+	return err
+}
@@ -0,0 +1,64 @@
+package tracing
+
+import (
+	"context"
+
+	"github.com/streadway/amqp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// headerCarrier lets the propagator read and write AMQP message headers
+type headerCarrier amqp.Table
+
+var _ propagation.TextMapCarrier = headerCarrier(nil)
+
+func (c headerCarrier) Get(key string) string {
+	value, _ := c[key].(string)
+	return value
+}
+
+func (c headerCarrier) Set(key, value string) {
+	c[key] = value
+}
+
+func (c headerCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for key := range c {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// StartPublish starts a producer span for a message to queue and writes its context to headers
+func StartPublish(ctx context.Context, queue string, headers amqp.Table) (context.Context, trace.Span) {
+	ctx, span := Tracer().Start(ctx, "publish "+queue,
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(messagingAttributes(queue, "publish")...),
+	)
+	otel.GetTextMapPropagator().Inject(ctx, headerCarrier(headers))
+	return ctx, span
+}
+
+// StartConsume continues the trace of a received message with a consumer span
+func StartConsume(ctx context.Context, queue string, headers amqp.Table) (context.Context, trace.Span) {
+	if headers == nil {
+		headers = amqp.Table{}
+	}
+	ctx = otel.GetTextMapPropagator().Extract(ctx, headerCarrier(headers))
+	return Tracer().Start(ctx, "process "+queue,
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(messagingAttributes(queue, "process")...),
+	)
+}
+
+func messagingAttributes(queue, operation string) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		semconv.MessagingSystemRabbitmq,
+		semconv.MessagingDestinationName(queue),
+		semconv.MessagingOperationName(operation),
+	}
+}
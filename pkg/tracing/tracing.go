@@ -0,0 +1,109 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"th3y3m/e-commerce-microservices/pkg/config"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Exporters selected by the tracing block. The OTLP exporter sends to its endpoint, or to the one
+// of the standard OTEL_EXPORTER_OTLP_* variables when it has none.
+const (
+	ExporterNone   = "none"
+	ExporterOTLP   = "otlp"
+	ExporterStdout = "stdout"
+)
+
+// Name of the tracer used by the instrumentation in this repository
+const instrumentationName = "th3y3m/e-commerce-microservices"
+
+// NewExporter builds the span exporter cfg selects. ExporterNone returns a nil exporter.
+func NewExporter(ctx context.Context, cfg config.Tracing) (sdktrace.SpanExporter, error) {
+	switch name := strings.ToLower(cfg.Exporter); name {
+	case "", ExporterNone:
+		return nil, nil
+	case ExporterOTLP:
+		var opts []otlptracehttp.Option
+		if cfg.OTLPEndpoint != "" {
+			opts = append(opts, otlptracehttp.WithEndpointURL(cfg.OTLPEndpoint))
+		}
+		return otlptracehttp.New(ctx, opts...)
+	case ExporterStdout, "console":
+		return stdouttrace.New(stdouttrace.WithPrettyPrint())
+	default:
+		return nil, fmt.Errorf("unknown trace exporter %q", name)
+	}
+}
+
+// NewInMemoryExporter keeps finished spans in memory, for tests
+func NewInMemoryExporter() *tracetest.InMemoryExporter {
+	return tracetest.NewInMemoryExporter()
+}
+
+// Install makes spans of the service go to exporter and sets the W3C trace context propagator.
+// Without an exporter only the propagation is set up, so traces still flow through the service.
+// The returned function flushes and stops the provider.
+func Install(service string, exporter sdktrace.SpanExporter) func(context.Context) error {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	if exporter == nil {
+		return func(context.Context) error { return nil }
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewSchemaless(semconv.ServiceName(service))),
+	)
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown
+}
+
+// Setup installs the exporter cfg selects. A bad configuration is logged
+// and leaves tracing disabled rather than stopping the service.
+func Setup(service string, cfg config.Tracing) func(context.Context) error {
+	exporter, err := NewExporter(context.Background(), cfg)
+	if err != nil {
+		log.Printf("Tracing is disabled: %v", err)
+	}
+	return Install(service, exporter)
+}
+
+// Tracer returns the tracer for spans created by hand
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
+// Transport wraps next so every outbound request gets a client span and the trace headers
+func Transport(next http.RoundTripper) http.RoundTripper {
+	return otelhttp.NewTransport(next)
+}
+
+// End records err on the span, if any, and ends it
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// Middleware starts a server span for every request, continuing the trace of the caller
+func Middleware(service string) gin.HandlerFunc {
+	return otelgin.Middleware(service)
+}
@@ -0,0 +1,68 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/streadway/amqp"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// keepSpans flushes like the in-memory exporter but keeps the spans on shutdown
+type keepSpans struct {
+	*tracetest.InMemoryExporter
+}
+
+func (keepSpans) Shutdown(context.Context) error { return nil }
+
+func TestMessageContinuesTrace(t *testing.T) {
+	exporter := NewInMemoryExporter()
+	shutdown := Install("test", keepSpans{exporter})
+
+	headers := amqp.Table{}
+	ctx, publish := StartPublish(context.Background(), "orders", headers)
+	publish.End()
+	assert.NotEmpty(t, headers["traceparent"])
+
+	_, consume := StartConsume(context.Background(), "orders", headers)
+	consume.End()
+
+	assert.NoError(t, shutdown(context.Background()))
+	spans := exporter.GetSpans()
+	assert.Len(t, spans, 2)
+	assert.Equal(t, trace.SpanContextFromContext(ctx).TraceID(), spans[1].SpanContext.TraceID())
+	assert.Equal(t, spans[0].SpanContext.SpanID(), spans[1].Parent.SpanID())
+}
+
+func TestTransportPropagatesToService(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	exporter := NewInMemoryExporter()
+	shutdown := Install("test", keepSpans{exporter})
+
+	var received trace.SpanContext
+	r := gin.New()
+	r.ContextWithFallback = true
+	r.Use(Middleware("test"))
+	r.GET("/ping", func(c *gin.Context) {
+		received = trace.SpanContextFromContext(c)
+		c.Status(http.StatusOK)
+	})
+	server := httptest.NewServer(r)
+	defer server.Close()
+
+	ctx, span := Tracer().Start(context.Background(), "caller")
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/ping", nil)
+	resp, err := (&http.Client{Transport: Transport(http.DefaultTransport)}).Do(req)
+	assert.NoError(t, err)
+	resp.Body.Close()
+	span.End()
+
+	assert.NoError(t, shutdown(context.Background()))
+	assert.Equal(t, span.SpanContext().TraceID(), received.TraceID())
+}
@@ -5,18 +5,15 @@ import (
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
-	"github.com/spf13/viper"
 )
 
-// GenerateJWT generates a JWT token for a given user ID, role, and email.
-func GenerateJWT(userID int64, role, email string) (string, error) {
-	// Get environment variables
-	key := viper.GetString("JWT_SECRET")
-	if key == "" {
+// GenerateJWT generates a JWT token for a given user ID, role, and email, signed with secret.
+func GenerateJWT(secret string, userID int64, role, email string) (string, error) {
+	if secret == "" {
 		return "", errors.New("JWT_SECRET is not set")
 	}
 
-	var jwtSecret = []byte(key) // Replace with your actual secret key
+	var jwtSecret = []byte(secret)
 
 	// Create the JWT claims, including user ID and expiration time
 	claims := jwt.MapClaims{
@@ -38,10 +35,9 @@ func GenerateJWT(userID int64, role, email string) (string, error) {
 	return tokenString, nil
 }
 
-// DecodeJWT decodes a JWT token and returns the user ID.
-func DecodeJWT(tokenString string) (int64, error) {
-	// Get the JWT secret from the environment variables
-	jwtSecret := []byte(viper.GetString("JWT_SECRET"))
+// DecodeJWT decodes a JWT token signed with secret and returns the user ID.
+func DecodeJWT(secret string, tokenString string) (int64, error) {
+	jwtSecret := []byte(secret)
 	if len(jwtSecret) == 0 {
 		return 0, errors.New("JWT_SECRET is not set")
 	}
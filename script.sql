@@ -187,6 +187,34 @@ CREATE TABLE IF NOT EXISTS public.vouchers
     CONSTRAINT uni_vouchers_voucher_code UNIQUE (voucher_code)
 );
 
+-- Events written with the state they describe, published to RabbitMQ by the outbox relay
+CREATE TABLE IF NOT EXISTS public.outbox_messages
+(
+    id bigserial NOT NULL,
+    event_id text COLLATE pg_catalog."default",
+    event_type text COLLATE pg_catalog."default",
+    envelope jsonb,
+    created_at timestamp with time zone,
+    sent_at timestamp with time zone,
+    attempts bigint DEFAULT 0,
+    last_error text COLLATE pg_catalog."default",
+    CONSTRAINT outbox_messages_pkey PRIMARY KEY (id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_outbox_messages_event_id ON public.outbox_messages (event_id);
+CREATE INDEX IF NOT EXISTS idx_outbox_messages_sent_at ON public.outbox_messages (sent_at);
+
+-- Events each consumer processed, so redelivered events are skipped
+CREATE TABLE IF NOT EXISTS public.inbox_messages
+(
+    consumer text COLLATE pg_catalog."default" NOT NULL,
+    event_id text COLLATE pg_catalog."default" NOT NULL,
+    processed_at timestamp with time zone,
+    CONSTRAINT inbox_messages_pkey PRIMARY KEY (consumer, event_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_inbox_messages_processed_at ON public.inbox_messages (processed_at);
+
 -- Insert rows for the `carts` table
 INSERT INTO public.carts (user_id, is_deleted, created_at) VALUES
 (1, false, CURRENT_TIMESTAMP),
@@ -0,0 +1,93 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+type lruItem struct {
+	route string
+	key   string
+	entry *Entry
+	size  int64
+}
+
+// LRU is an in-process store bounded by the total size of the cached responses.
+// The least recently used entries are evicted first.
+type LRU struct {
+	maxBytes int64
+
+	mu    sync.Mutex
+	bytes int64
+	order *list.List
+	items map[string]*list.Element
+}
+
+func NewLRU(maxBytes int64) *LRU {
+	return &LRU{
+		maxBytes: maxBytes,
+		order:    list.New(),
+		items:    make(map[string]*list.Element),
+	}
+}
+
+func (c *LRU) Get(_ context.Context, route, key string) (*Entry, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.items[route+"\x00"+key]
+	if !ok {
+		return nil, nil
+	}
+	item := element.Value.(*lruItem)
+	if !item.entry.Fresh(time.Now()) {
+		c.remove(element)
+		return nil, nil
+	}
+	c.order.MoveToFront(element)
+	return item.entry, nil
+}
+
+func (c *LRU) Set(_ context.Context, route, key string, entry *Entry) error {
+	size := entry.size()
+	if size > c.maxBytes {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	id := route + "\x00" + key
+	if element, ok := c.items[id]; ok {
+		c.remove(element)
+	}
+	c.items[id] = c.order.PushFront(&lruItem{route: route, key: key, entry: entry, size: size})
+	c.bytes += size
+
+	for c.bytes > c.maxBytes {
+		c.remove(c.order.Back())
+	}
+	return nil
+}
+
+func (c *LRU) Purge(_ context.Context, route string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for element := c.order.Front(); element != nil; {
+		next := element.Next()
+		if element.Value.(*lruItem).route == route {
+			c.remove(element)
+		}
+		element = next
+	}
+	return nil
+}
+
+func (c *LRU) remove(element *list.Element) {
+	item := c.order.Remove(element).(*lruItem)
+	delete(c.items, item.route+"\x00"+item.key)
+	c.bytes -= item.size
+}
@@ -0,0 +1,49 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newEntry(body string, ttl time.Duration) *Entry {
+	return &Entry{Status: 200, Body: []byte(body), Expires: time.Now().Add(ttl)}
+}
+
+func TestLRUEvictsLeastRecentlyUsed(t *testing.T) {
+	ctx := context.Background()
+	lru := NewLRU(10)
+
+	lru.Set(ctx, "/api/products", "a", newEntry("aaaa", time.Minute))
+	lru.Set(ctx, "/api/products", "b", newEntry("bbbb", time.Minute))
+	// Touch a so that b is the one evicted
+	lru.Get(ctx, "/api/products", "a")
+	lru.Set(ctx, "/api/products", "c", newEntry("cccc", time.Minute))
+
+	a, _ := lru.Get(ctx, "/api/products", "a")
+	b, _ := lru.Get(ctx, "/api/products", "b")
+	c, _ := lru.Get(ctx, "/api/products", "c")
+	assert.NotNil(t, a)
+	assert.Nil(t, b)
+	assert.NotNil(t, c)
+}
+
+func TestLRUExpiryAndPurge(t *testing.T) {
+	ctx := context.Background()
+	lru := NewLRU(1 << 10)
+
+	lru.Set(ctx, "/api/products", "expired", newEntry("x", -time.Second))
+	lru.Set(ctx, "/api/products", "fresh", newEntry("x", time.Minute))
+	lru.Set(ctx, "/api/news", "fresh", newEntry("x", time.Minute))
+
+	expired, _ := lru.Get(ctx, "/api/products", "expired")
+	assert.Nil(t, expired)
+
+	lru.Purge(ctx, "/api/products")
+	products, _ := lru.Get(ctx, "/api/products", "fresh")
+	news, _ := lru.Get(ctx, "/api/news", "fresh")
+	assert.Nil(t, products)
+	assert.NotNil(t, news)
+}
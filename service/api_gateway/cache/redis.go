@@ -0,0 +1,96 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Redis shares cached responses between gateway replicas. Every route has a generation
+// number that is part of its keys, so a purge only has to bump it and the old entries
+// are left to expire.
+type Redis struct {
+	client  *redis.Client
+	timeout time.Duration
+}
+
+func NewRedis(client *redis.Client, timeout time.Duration) *Redis {
+	return &Redis{client: client, timeout: timeout}
+}
+
+func (c *Redis) Get(ctx context.Context, route, key string) (*Entry, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	entryKey, err := c.entryKey(ctx, route, key)
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := c.client.Get(ctx, entryKey).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cache entry: %w", err)
+	}
+
+	var entry Entry
+	if err := json.Unmarshal(raw, &entry); err != nil {
+		return nil, fmt.Errorf("failed to decode cache entry: %w", err)
+	}
+	if !entry.Fresh(time.Now()) {
+		return nil, nil
+	}
+	return &entry, nil
+}
+
+func (c *Redis) Set(ctx context.Context, route, key string, entry *Entry) error {
+	ttl := time.Until(entry.Expires)
+	if ttl <= 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	entryKey, err := c.entryKey(ctx, route, key)
+	if err != nil {
+		return err
+	}
+
+	raw, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to encode cache entry: %w", err)
+	}
+	if err := c.client.Set(ctx, entryKey, raw, ttl).Err(); err != nil {
+		return fmt.Errorf("failed to write cache entry: %w", err)
+	}
+	return nil
+}
+
+func (c *Redis) Purge(ctx context.Context, route string) error {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	if err := c.client.Incr(ctx, generationKey(route)).Err(); err != nil {
+		return fmt.Errorf("failed to purge route %s: %w", route, err)
+	}
+	return nil
+}
+
+func (c *Redis) entryKey(ctx context.Context, route, key string) (string, error) {
+	generation, err := c.client.Get(ctx, generationKey(route)).Int64()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return "", fmt.Errorf("failed to read cache generation: %w", err)
+	}
+	return fmt.Sprintf("gwcache:entry:%s:%d:%s", route, generation, key), nil
+}
+
+func generationKey(route string) string {
+	return "gwcache:generation:" + route
+}
@@ -0,0 +1,42 @@
+package cache
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// Entry is a stored upstream response
+type Entry struct {
+	Path     string      `json:"path"`
+	Status   int         `json:"status"`
+	Header   http.Header `json:"header"`
+	Body     []byte      `json:"body"`
+	ETag     string      `json:"etag"`
+	StoredAt time.Time   `json:"stored_at"`
+	Expires  time.Time   `json:"expires"`
+}
+
+// Fresh reports whether the entry may still be served without asking the upstream
+func (e *Entry) Fresh(now time.Time) bool {
+	return now.Before(e.Expires)
+}
+
+func (e *Entry) size() int64 {
+	size := int64(len(e.Body) + len(e.Path) + len(e.ETag))
+	for name, values := range e.Header {
+		size += int64(len(name))
+		for _, value := range values {
+			size += int64(len(value))
+		}
+	}
+	return size
+}
+
+// Store keeps entries grouped by route so that a route can be purged at once.
+// Get returns nil without an error on a miss.
+type Store interface {
+	Get(ctx context.Context, route, key string) (*Entry, error)
+	Set(ctx context.Context, route, key string, entry *Entry) error
+	Purge(ctx context.Context, route string) error
+}
@@ -0,0 +1,131 @@
+package circuitbreaker
+
+import (
+	"sync"
+	"time"
+)
+
+type State string
+
+const (
+	StateClosed   State = "closed"
+	StateOpen     State = "open"
+	StateHalfOpen State = "half_open"
+)
+
+// Breaker is a consecutive-failure circuit breaker for a single upstream.
+// After FailureThreshold failures it opens and rejects calls for OpenTimeout,
+// then lets HalfOpenRequests trial calls through to decide whether to close again.
+type Breaker struct {
+	failureThreshold int
+	openTimeout      time.Duration
+	halfOpenRequests int
+
+	mu        sync.Mutex
+	state     State
+	failures  int
+	openedAt  time.Time
+	inFlight  int
+	lastError string
+}
+
+// Snapshot is the externally visible state of a breaker
+type Snapshot struct {
+	State      State     `json:"state"`
+	Failures   int       `json:"failures"`
+	OpenedAt   time.Time `json:"opened_at,omitempty"`
+	RetryAfter string    `json:"retry_after,omitempty"`
+	LastError  string    `json:"last_error,omitempty"`
+}
+
+func NewBreaker(failureThreshold int, openTimeout time.Duration, halfOpenRequests int) *Breaker {
+	return &Breaker{
+		failureThreshold: failureThreshold,
+		openTimeout:      openTimeout,
+		halfOpenRequests: halfOpenRequests,
+		state:            StateClosed,
+	}
+}
+
+// Allow reports whether a call may go through. When it may not, it also returns
+// how long the caller should wait before trying again.
+func (b *Breaker) Allow() (bool, time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case StateOpen:
+		remaining := b.openTimeout - time.Since(b.openedAt)
+		if remaining > 0 {
+			return false, remaining
+		}
+		b.state = StateHalfOpen
+		b.inFlight = 0
+		fallthrough
+	case StateHalfOpen:
+		if b.inFlight >= b.halfOpenRequests {
+			return false, b.openTimeout
+		}
+		b.inFlight++
+		return true, 0
+	default:
+		return true, 0
+	}
+}
+
+// Success records a successful call and closes the breaker
+func (b *Breaker) Success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.state = StateClosed
+	b.failures = 0
+	b.inFlight = 0
+	b.lastError = ""
+}
+
+// Failure records a failed call and opens the breaker once the threshold is reached.
+// A failure while half-open reopens it immediately.
+func (b *Breaker) Failure(reason string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures++
+	b.lastError = reason
+	if b.state == StateHalfOpen || b.failures >= b.failureThreshold {
+		b.state = StateOpen
+		b.openedAt = time.Now()
+		b.inFlight = 0
+	}
+}
+
+// Release gives back the trial slot of a call that ended without an outcome, such as one the
+// client canceled, leaving the state as it is
+func (b *Breaker) Release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == StateHalfOpen && b.inFlight > 0 {
+		b.inFlight--
+	}
+}
+
+func (b *Breaker) Snapshot() Snapshot {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	snapshot := Snapshot{
+		State:     b.state,
+		Failures:  b.failures,
+		LastError: b.lastError,
+	}
+	if b.state != StateClosed {
+		snapshot.OpenedAt = b.openedAt
+	}
+	if b.state == StateOpen {
+		if remaining := b.openTimeout - time.Since(b.openedAt); remaining > 0 {
+			snapshot.RetryAfter = remaining.Round(time.Second).String()
+		}
+	}
+	return snapshot
+}
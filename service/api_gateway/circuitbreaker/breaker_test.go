@@ -0,0 +1,42 @@
+package circuitbreaker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBreakerOpensAfterThreshold(t *testing.T) {
+	b := NewBreaker(2, time.Minute, 1)
+
+	b.Failure("boom")
+	allowed, _ := b.Allow()
+	assert.True(t, allowed)
+
+	b.Failure("boom")
+	allowed, wait := b.Allow()
+	assert.False(t, allowed)
+	assert.Greater(t, wait, time.Duration(0))
+	assert.Equal(t, StateOpen, b.Snapshot().State)
+}
+
+func TestBreakerHalfOpen(t *testing.T) {
+	b := NewBreaker(1, time.Millisecond, 1)
+	b.Failure("boom")
+	time.Sleep(2 * time.Millisecond)
+
+	allowed, _ := b.Allow()
+	assert.True(t, allowed)
+	allowed, _ = b.Allow()
+	assert.False(t, allowed, "only one trial call while half-open")
+
+	b.Success()
+	assert.Equal(t, StateClosed, b.Snapshot().State)
+
+	b.Failure("boom")
+	time.Sleep(2 * time.Millisecond)
+	b.Allow()
+	b.Failure("still down")
+	assert.Equal(t, StateOpen, b.Snapshot().State)
+}
@@ -0,0 +1,15 @@
+package config
+
+import pkgconfig "th3y3m/e-commerce-microservices/pkg/config"
+
+// Config of the gateway. Every dependency of the gateway is optional and has a fallback.
+type Config struct {
+	pkgconfig.Base
+	pkgconfig.JWT
+	pkgconfig.RabbitMQ
+	// Policies are kept in Postgres when set, and read from the CSV file otherwise
+	ConnectionString string `env:"CONNECTION_STRING" secret:"true"`
+	RoutesFile       string `env:"GATEWAY_ROUTES_FILE" default:"config/routes.yaml"`
+	// Route table as JSON, used instead of the routes file when set
+	Routes string `env:"GATEWAY_ROUTES"`
+}
@@ -0,0 +1,120 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	defaultRateLimitRequests = 10
+	defaultRateLimitPer      = time.Second
+)
+
+// Limit is a token bucket refilled with Requests tokens every Per and holding at most Burst tokens
+type Limit struct {
+	Requests int           `mapstructure:"requests" json:"requests"`
+	Per      time.Duration `mapstructure:"per" json:"per"`
+	Burst    int           `mapstructure:"burst" json:"burst"`
+}
+
+// Enabled reports whether the limit has been configured
+func (l Limit) Enabled() bool {
+	return l.Requests > 0
+}
+
+// Rate returns the refill rate in tokens per second
+func (l Limit) Rate() float64 {
+	return float64(l.Requests) / l.Per.Seconds()
+}
+
+func (l *Limit) validate() error {
+	if l.Requests < 0 || l.Per < 0 || l.Burst < 0 {
+		return errors.New("rate limit values must not be negative")
+	}
+	if !l.Enabled() {
+		return nil
+	}
+	if l.Per == 0 {
+		l.Per = defaultRateLimitPer
+	}
+	if l.Burst == 0 {
+		l.Burst = l.Requests
+	}
+	return nil
+}
+
+// APIKey grants a client its own limit. Only the SHA-256 of the key is kept in config.
+type APIKey struct {
+	Name      string `mapstructure:"name" json:"name"`
+	KeySHA256 string `mapstructure:"key_sha256" json:"-"`
+	Limit     `mapstructure:",squash"`
+}
+
+// RateLimit holds the per-client limits shared by every route.
+// API keys take precedence over roles, and roles over the default.
+type RateLimit struct {
+	Default Limit            `mapstructure:"default" json:"default"`
+	Roles   map[string]Limit `mapstructure:"roles" json:"roles"`
+	APIKeys []APIKey         `mapstructure:"api_keys" json:"api_keys"`
+
+	keysByHash map[string]*APIKey
+}
+
+func (rl *RateLimit) validate() error {
+	if err := rl.Default.validate(); err != nil {
+		return fmt.Errorf("rate_limit default: %w", err)
+	}
+	if !rl.Default.Enabled() {
+		// The burst defaults to the requests, as for every other limit
+		rl.Default = Limit{Requests: defaultRateLimitRequests, Per: defaultRateLimitPer, Burst: defaultRateLimitRequests}
+	}
+
+	for role, limit := range rl.Roles {
+		if err := limit.validate(); err != nil {
+			return fmt.Errorf("rate_limit role %q: %w", role, err)
+		}
+		if !limit.Enabled() {
+			return fmt.Errorf("rate_limit role %q: requests is required", role)
+		}
+		rl.Roles[role] = limit
+	}
+
+	rl.keysByHash = make(map[string]*APIKey, len(rl.APIKeys))
+	for i := range rl.APIKeys {
+		key := &rl.APIKeys[i]
+		if key.Name == "" {
+			return fmt.Errorf("rate_limit api key %d: name is required", i)
+		}
+		key.KeySHA256 = strings.ToLower(key.KeySHA256)
+		if raw, err := hex.DecodeString(key.KeySHA256); err != nil || len(raw) != sha256.Size {
+			return fmt.Errorf("rate_limit api key %q: key_sha256 must be a hex encoded SHA-256", key.Name)
+		}
+		if err := key.Limit.validate(); err != nil {
+			return fmt.Errorf("rate_limit api key %q: %w", key.Name, err)
+		}
+		if !key.Limit.Enabled() {
+			return fmt.Errorf("rate_limit api key %q: requests is required", key.Name)
+		}
+		rl.keysByHash[key.KeySHA256] = key
+	}
+	return nil
+}
+
+// LookupAPIKey returns the configured API key matching the raw key sent by a client
+func (rl *RateLimit) LookupAPIKey(raw string) (*APIKey, bool) {
+	sum := sha256.Sum256([]byte(raw))
+	key, ok := rl.keysByHash[hex.EncodeToString(sum[:])]
+	return key, ok
+}
+
+// ForRole returns the limit of the given role, or the default when the role has none
+func (rl *RateLimit) ForRole(role string) Limit {
+	if limit, ok := rl.Roles[role]; ok {
+		return limit
+	}
+	return rl.Default
+}
@@ -0,0 +1,371 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/spf13/viper"
+)
+
+const (
+	defaultRouteTimeout      = 30 * time.Second
+	defaultHealthPath        = "/readyz"
+	defaultHealthInterval    = 10 * time.Second
+	defaultHealthTimeout     = 2 * time.Second
+	defaultHealthMaxFails    = 3
+	defaultHealthFailTimeout = 30 * time.Second
+	defaultUpstreamWeight    = 1
+	defaultBalancingStrategy = "round_robin"
+	defaultMaxAttempts       = 3
+	defaultRetryBackoff      = 100 * time.Millisecond
+	defaultMaxRetryBackoff   = 2 * time.Second
+	defaultFailureThreshold  = 5
+	defaultOpenTimeout       = 30 * time.Second
+	defaultHalfOpenRequests  = 1
+)
+
+var balancingStrategies = map[string]bool{
+	"round_robin":       true,
+	"least_connections": true,
+	"weighted":          true,
+}
+
+// Upstream is one instance of the service behind a route
+type Upstream struct {
+	URL    string `mapstructure:"url" json:"url"`
+	Weight int    `mapstructure:"weight" json:"weight"`
+}
+
+// HealthCheck configures active probing and passive ejection of upstream instances
+type HealthCheck struct {
+	Path        string        `mapstructure:"path" json:"path"`
+	Interval    time.Duration `mapstructure:"interval" json:"interval"`
+	Timeout     time.Duration `mapstructure:"timeout" json:"timeout"`
+	MaxFails    int           `mapstructure:"max_fails" json:"max_fails"`
+	FailTimeout time.Duration `mapstructure:"fail_timeout" json:"fail_timeout"`
+}
+
+// Retry configures retries of idempotent requests; MaxAttempts includes the first try
+type Retry struct {
+	MaxAttempts int           `mapstructure:"max_attempts" json:"max_attempts"`
+	Backoff     time.Duration `mapstructure:"backoff" json:"backoff"`
+	MaxBackoff  time.Duration `mapstructure:"max_backoff" json:"max_backoff"`
+}
+
+// CircuitBreaker configures the breaker kept for every upstream instance of a route
+type CircuitBreaker struct {
+	FailureThreshold int           `mapstructure:"failure_threshold" json:"failure_threshold"`
+	OpenTimeout      time.Duration `mapstructure:"open_timeout" json:"open_timeout"`
+	HalfOpenRequests int           `mapstructure:"half_open_requests" json:"half_open_requests"`
+}
+
+// Cache configures caching of GET responses for a route; a zero TTL disables it.
+// Vary lists the request headers that select between different responses.
+type Cache struct {
+	TTL  time.Duration `mapstructure:"ttl" json:"ttl"`
+	Vary []string      `mapstructure:"vary" json:"vary"`
+}
+
+// Route describes how a path prefix on the gateway maps to an upstream service
+type Route struct {
+	Prefix         string         `mapstructure:"prefix" json:"prefix"`
+	Upstreams      []Upstream     `mapstructure:"upstreams" json:"upstreams"`
+	Strategy       string         `mapstructure:"strategy" json:"strategy"`
+	HealthCheck    HealthCheck    `mapstructure:"health_check" json:"health_check"`
+	Retry          Retry          `mapstructure:"retry" json:"retry"`
+	CircuitBreaker CircuitBreaker `mapstructure:"circuit_breaker" json:"circuit_breaker"`
+	RateLimit      Limit          `mapstructure:"rate_limit" json:"rate_limit"`
+	Cache          Cache          `mapstructure:"cache" json:"cache"`
+	StripPrefix    bool           `mapstructure:"strip_prefix" json:"strip_prefix"`
+	Timeout        time.Duration  `mapstructure:"timeout" json:"timeout"`
+	AuthRequired   bool           `mapstructure:"auth_required" json:"auth_required"`
+}
+
+// RouteTable is an immutable, validated set of routes ordered by prefix length
+type RouteTable struct {
+	Routes    []Route   `mapstructure:"routes" json:"routes"`
+	RateLimit RateLimit `mapstructure:"rate_limit" json:"rate_limit"`
+}
+
+// UpstreamPath returns the path to request on the upstream for an incoming path
+func (r *Route) UpstreamPath(path string) string {
+	if !r.StripPrefix {
+		return path
+	}
+	trimmed := strings.TrimPrefix(path, r.Prefix)
+	if trimmed == "" || trimmed[0] != '/' {
+		trimmed = "/" + trimmed
+	}
+	return trimmed
+}
+
+// Validate checks every route and prepares the table for matching
+func (t *RouteTable) Validate() error {
+	if len(t.Routes) == 0 {
+		return errors.New("route table is empty")
+	}
+
+	seen := make(map[string]bool)
+	for i := range t.Routes {
+		route := &t.Routes[i]
+		if route.Prefix == "" || route.Prefix[0] != '/' {
+			return fmt.Errorf("route %d: prefix %q must start with '/'", i, route.Prefix)
+		}
+		route.Prefix = strings.TrimSuffix(route.Prefix, "/")
+		if seen[route.Prefix] {
+			return fmt.Errorf("route %d: duplicate prefix %q", i, route.Prefix)
+		}
+		seen[route.Prefix] = true
+
+		if len(route.Upstreams) == 0 {
+			return fmt.Errorf("route %q: at least one upstream is required", route.Prefix)
+		}
+		for j := range route.Upstreams {
+			upstream := &route.Upstreams[j]
+			u, err := url.Parse(upstream.URL)
+			if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+				return fmt.Errorf("route %q: invalid upstream %q", route.Prefix, upstream.URL)
+			}
+			upstream.URL = strings.TrimSuffix(upstream.URL, "/")
+			if upstream.Weight < 0 {
+				return fmt.Errorf("route %q: upstream %q has a negative weight", route.Prefix, upstream.URL)
+			}
+			if upstream.Weight == 0 {
+				upstream.Weight = defaultUpstreamWeight
+			}
+		}
+
+		if route.Strategy == "" {
+			route.Strategy = defaultBalancingStrategy
+		}
+		if !balancingStrategies[route.Strategy] {
+			return fmt.Errorf("route %q: unknown strategy %q", route.Prefix, route.Strategy)
+		}
+		if err := route.HealthCheck.validate(); err != nil {
+			return fmt.Errorf("route %q: %w", route.Prefix, err)
+		}
+		if err := route.Retry.validate(); err != nil {
+			return fmt.Errorf("route %q: %w", route.Prefix, err)
+		}
+		if err := route.CircuitBreaker.validate(); err != nil {
+			return fmt.Errorf("route %q: %w", route.Prefix, err)
+		}
+		if err := route.RateLimit.validate(); err != nil {
+			return fmt.Errorf("route %q: %w", route.Prefix, err)
+		}
+		if err := route.Cache.validate(); err != nil {
+			return fmt.Errorf("route %q: %w", route.Prefix, err)
+		}
+
+		if route.Timeout < 0 {
+			return fmt.Errorf("route %q: timeout must not be negative", route.Prefix)
+		}
+		if route.Timeout == 0 {
+			route.Timeout = defaultRouteTimeout
+		}
+	}
+
+	if err := t.RateLimit.validate(); err != nil {
+		return err
+	}
+
+	// Longest prefix first so that /api/cartItems is never shadowed by /api/carts
+	sort.SliceStable(t.Routes, func(i, j int) bool {
+		return len(t.Routes[i].Prefix) > len(t.Routes[j].Prefix)
+	})
+	return nil
+}
+
+func (h *HealthCheck) validate() error {
+	if h.Interval < 0 || h.Timeout < 0 || h.FailTimeout < 0 || h.MaxFails < 0 {
+		return errors.New("health_check values must not be negative")
+	}
+	if h.Path == "" {
+		h.Path = defaultHealthPath
+	}
+	if h.Path[0] != '/' {
+		return fmt.Errorf("health_check path %q must start with '/'", h.Path)
+	}
+	if h.Interval == 0 {
+		h.Interval = defaultHealthInterval
+	}
+	if h.Timeout == 0 {
+		h.Timeout = defaultHealthTimeout
+	}
+	if h.MaxFails == 0 {
+		h.MaxFails = defaultHealthMaxFails
+	}
+	if h.FailTimeout == 0 {
+		h.FailTimeout = defaultHealthFailTimeout
+	}
+	return nil
+}
+
+func (r *Retry) validate() error {
+	if r.MaxAttempts < 0 || r.Backoff < 0 || r.MaxBackoff < 0 {
+		return errors.New("retry values must not be negative")
+	}
+	if r.MaxAttempts == 0 {
+		r.MaxAttempts = defaultMaxAttempts
+	}
+	if r.Backoff == 0 {
+		r.Backoff = defaultRetryBackoff
+	}
+	if r.MaxBackoff == 0 {
+		r.MaxBackoff = defaultMaxRetryBackoff
+	}
+	return nil
+}
+
+func (cb *CircuitBreaker) validate() error {
+	if cb.FailureThreshold < 0 || cb.OpenTimeout < 0 || cb.HalfOpenRequests < 0 {
+		return errors.New("circuit_breaker values must not be negative")
+	}
+	if cb.FailureThreshold == 0 {
+		cb.FailureThreshold = defaultFailureThreshold
+	}
+	if cb.OpenTimeout == 0 {
+		cb.OpenTimeout = defaultOpenTimeout
+	}
+	if cb.HalfOpenRequests == 0 {
+		cb.HalfOpenRequests = defaultHalfOpenRequests
+	}
+	return nil
+}
+
+// Enabled reports whether responses of the route may be cached
+func (c *Cache) Enabled() bool {
+	return c.TTL > 0
+}
+
+func (c *Cache) validate() error {
+	if c.TTL < 0 {
+		return errors.New("cache ttl must not be negative")
+	}
+	for i, name := range c.Vary {
+		if name == "" || name == "*" {
+			return fmt.Errorf("cache vary header %q is not allowed", name)
+		}
+		c.Vary[i] = http.CanonicalHeaderKey(name)
+	}
+	sort.Strings(c.Vary)
+	return nil
+}
+
+// Match returns the route owning the given request path
+func (t *RouteTable) Match(path string) (*Route, bool) {
+	for i := range t.Routes {
+		prefix := t.Routes[i].Prefix
+		if path == prefix || strings.HasPrefix(path, prefix+"/") {
+			return &t.Routes[i], true
+		}
+	}
+	return nil, false
+}
+
+// Store holds the active route table so it can be swapped while requests are in flight
+type Store struct {
+	table    atomic.Pointer[RouteTable]
+	mu       sync.Mutex
+	onReload []func(*RouteTable)
+}
+
+func NewStore(table *RouteTable) *Store {
+	s := &Store{}
+	s.table.Store(table)
+	return s
+}
+
+func (s *Store) Table() *RouteTable {
+	return s.table.Load()
+}
+
+func (s *Store) Match(path string) (*Route, bool) {
+	return s.table.Load().Match(path)
+}
+
+// OnReload registers a callback invoked after a new table has been swapped in
+func (s *Store) OnReload(fn func(*RouteTable)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.onReload = append(s.onReload, fn)
+}
+
+func (s *Store) swap(table *RouteTable) {
+	s.table.Store(table)
+
+	s.mu.Lock()
+	callbacks := append([]func(*RouteTable){}, s.onReload...)
+	s.mu.Unlock()
+
+	for _, fn := range callbacks {
+		fn(table)
+	}
+}
+
+// LoadRoutes reads the route table from the Routes of cfg (JSON) or, when it is empty, from its
+// YAML/JSON RoutesFile
+func LoadRoutes(cfg Config) (*RouteTable, error) {
+	if raw := cfg.Routes; raw != "" {
+		v := viper.New()
+		v.SetConfigType("json")
+		if err := v.ReadConfig(strings.NewReader(raw)); err != nil {
+			return nil, fmt.Errorf("failed to parse GATEWAY_ROUTES: %w", err)
+		}
+		return decodeRoutes(v)
+	}
+
+	v := viper.New()
+	v.SetConfigFile(cfg.RoutesFile)
+	if err := v.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("failed to read route config %s: %w", cfg.RoutesFile, err)
+	}
+	return decodeRoutes(v)
+}
+
+// WatchRoutes reloads the route file on change and swaps it into the store.
+// An invalid file is logged and ignored so the gateway keeps serving the last good table.
+func WatchRoutes(cfg Config, store *Store) {
+	if cfg.Routes != "" {
+		log.Println("Routes loaded from GATEWAY_ROUTES, hot reload disabled")
+		return
+	}
+
+	v := viper.New()
+	v.SetConfigFile(cfg.RoutesFile)
+	if err := v.ReadInConfig(); err != nil {
+		log.Printf("Failed to watch route config %s: %v", cfg.RoutesFile, err)
+		return
+	}
+
+	v.OnConfigChange(func(e fsnotify.Event) {
+		table, err := decodeRoutes(v)
+		if err != nil {
+			log.Printf("Ignoring route config change: %v", err)
+			return
+		}
+		store.swap(table)
+		log.Printf("Route config reloaded from %s (%d routes)", e.Name, len(table.Routes))
+	})
+	v.WatchConfig()
+}
+
+func decodeRoutes(v *viper.Viper) (*RouteTable, error) {
+	var table RouteTable
+	if err := v.Unmarshal(&table); err != nil {
+		return nil, fmt.Errorf("failed to decode route config: %w", err)
+	}
+	if err := table.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid route config: %w", err)
+	}
+	return &table, nil
+}
@@ -0,0 +1,271 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func upstreams(urls ...string) []Upstream {
+	list := make([]Upstream, 0, len(urls))
+	for _, url := range urls {
+		list = append(list, Upstream{URL: url})
+	}
+	return list
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		routes []Route
+		err    string
+	}{
+		{name: "empty", err: "route table is empty"},
+		{
+			name:   "relative prefix",
+			routes: []Route{{Prefix: "api/products", Upstreams: upstreams("http://product:8081")}},
+			err:    `route 0: prefix "api/products" must start with '/'`,
+		},
+		{
+			name: "duplicate prefix",
+			routes: []Route{
+				{Prefix: "/api/products", Upstreams: upstreams("http://product:8081")},
+				{Prefix: "/api/products/", Upstreams: upstreams("http://product:8081")},
+			},
+			err: `route 1: duplicate prefix "/api/products"`,
+		},
+		{
+			name:   "no upstream",
+			routes: []Route{{Prefix: "/api/products"}},
+			err:    `route "/api/products": at least one upstream is required`,
+		},
+		{
+			name:   "upstream without a scheme",
+			routes: []Route{{Prefix: "/api/products", Upstreams: upstreams("product:8081")}},
+			err:    `route "/api/products": invalid upstream "product:8081"`,
+		},
+		{
+			name:   "negative weight",
+			routes: []Route{{Prefix: "/api/products", Upstreams: []Upstream{{URL: "http://product:8081", Weight: -1}}}},
+			err:    `route "/api/products": upstream "http://product:8081" has a negative weight`,
+		},
+		{
+			name:   "unknown strategy",
+			routes: []Route{{Prefix: "/api/products", Upstreams: upstreams("http://product:8081"), Strategy: "random"}},
+			err:    `route "/api/products": unknown strategy "random"`,
+		},
+		{
+			name:   "relative health path",
+			routes: []Route{{Prefix: "/api/products", Upstreams: upstreams("http://product:8081"), HealthCheck: HealthCheck{Path: "readyz"}}},
+			err:    `route "/api/products": health_check path "readyz" must start with '/'`,
+		},
+		{
+			name:   "negative retry",
+			routes: []Route{{Prefix: "/api/products", Upstreams: upstreams("http://product:8081"), Retry: Retry{MaxAttempts: -1}}},
+			err:    `route "/api/products": retry values must not be negative`,
+		},
+		{
+			name:   "wildcard vary",
+			routes: []Route{{Prefix: "/api/products", Upstreams: upstreams("http://product:8081"), Cache: Cache{TTL: time.Minute, Vary: []string{"*"}}}},
+			err:    `route "/api/products": cache vary header "*" is not allowed`,
+		},
+		{
+			name:   "negative timeout",
+			routes: []Route{{Prefix: "/api/products", Upstreams: upstreams("http://product:8081"), Timeout: -time.Second}},
+			err:    `route "/api/products": timeout must not be negative`,
+		},
+		{
+			name:   "valid",
+			routes: []Route{{Prefix: "/api/products", Upstreams: upstreams("http://product:8081")}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			table := &RouteTable{Routes: tt.routes}
+			err := table.Validate()
+			if tt.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.err)
+			}
+		})
+	}
+}
+
+func TestValidateSetsDefaults(t *testing.T) {
+	table := &RouteTable{Routes: []Route{{
+		Prefix:    "/api/products/",
+		Upstreams: upstreams("http://product:8081/"),
+		Cache:     Cache{TTL: time.Minute, Vary: []string{"accept-language", "accept"}},
+	}}}
+	require.NoError(t, table.Validate())
+
+	route := table.Routes[0]
+	assert.Equal(t, "/api/products", route.Prefix)
+	assert.Equal(t, []Upstream{{URL: "http://product:8081", Weight: defaultUpstreamWeight}}, route.Upstreams)
+	assert.Equal(t, defaultBalancingStrategy, route.Strategy)
+	assert.Equal(t, HealthCheck{
+		Path:        "/readyz",
+		Interval:    defaultHealthInterval,
+		Timeout:     defaultHealthTimeout,
+		MaxFails:    defaultHealthMaxFails,
+		FailTimeout: defaultHealthFailTimeout,
+	}, route.HealthCheck)
+	assert.Equal(t, Retry{MaxAttempts: defaultMaxAttempts, Backoff: defaultRetryBackoff, MaxBackoff: defaultMaxRetryBackoff}, route.Retry)
+	assert.Equal(t, CircuitBreaker{FailureThreshold: defaultFailureThreshold, OpenTimeout: defaultOpenTimeout, HalfOpenRequests: defaultHalfOpenRequests}, route.CircuitBreaker)
+	assert.Equal(t, defaultRouteTimeout, route.Timeout)
+	assert.Equal(t, []string{"Accept", "Accept-Language"}, route.Cache.Vary)
+	assert.True(t, table.RateLimit.Default.Enabled())
+}
+
+func TestMatch(t *testing.T) {
+	table := &RouteTable{Routes: []Route{
+		{Prefix: "/api/carts", Upstreams: upstreams("http://cart:8085")},
+		{Prefix: "/api/cartItems", Upstreams: upstreams("http://cart_item:8084")},
+		{Prefix: "/api", Upstreams: upstreams("http://fallback:8000")},
+	}}
+	require.NoError(t, table.Validate())
+	store := NewStore(table)
+
+	tests := []struct {
+		path   string
+		prefix string
+	}{
+		{path: "/api/carts", prefix: "/api/carts"},
+		{path: "/api/carts/7", prefix: "/api/carts"},
+		{path: "/api/cartItems/7", prefix: "/api/cartItems"},
+		// Prefixes match whole segments only
+		{path: "/api/cartsx", prefix: "/api"},
+		{path: "/api", prefix: "/api"},
+		{path: "/apix"},
+		{path: "/"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			route, ok := store.Match(tt.path)
+			if tt.prefix == "" {
+				assert.False(t, ok)
+				return
+			}
+			require.True(t, ok)
+			assert.Equal(t, tt.prefix, route.Prefix)
+		})
+	}
+}
+
+func TestUpstreamPath(t *testing.T) {
+	tests := []struct {
+		name  string
+		strip bool
+		path  string
+		want  string
+	}{
+		{name: "kept", path: "/auth/login", want: "/auth/login"},
+		{name: "stripped", strip: true, path: "/auth/login", want: "/login"},
+		{name: "stripped to the root", strip: true, path: "/auth", want: "/"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			route := &Route{Prefix: "/auth", StripPrefix: tt.strip}
+			assert.Equal(t, tt.want, route.UpstreamPath(tt.path))
+		})
+	}
+}
+
+const testRoutesYAML = `
+routes:
+  - prefix: /api/products
+    upstreams:
+      - url: http://product:8081
+        weight: 2
+    timeout: 5s
+    cache:
+      ttl: 30s
+`
+
+func writeRoutes(t *testing.T, path, content string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+}
+
+func TestLoadRoutes(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "routes.yaml")
+	writeRoutes(t, file, testRoutesYAML)
+
+	table, err := LoadRoutes(Config{RoutesFile: file})
+	require.NoError(t, err)
+	require.Len(t, table.Routes, 1)
+	route := table.Routes[0]
+	assert.Equal(t, []Upstream{{URL: "http://product:8081", Weight: 2}}, route.Upstreams)
+	assert.Equal(t, 5*time.Second, route.Timeout)
+	assert.Equal(t, 30*time.Second, route.Cache.TTL)
+	assert.Equal(t, "/readyz", route.HealthCheck.Path)
+
+	// GATEWAY_ROUTES takes precedence over the file
+	table, err = LoadRoutes(Config{
+		RoutesFile: file,
+		Routes:     `{"routes": [{"prefix": "/api/news", "upstreams": [{"url": "http://news:8083"}]}]}`,
+	})
+	require.NoError(t, err)
+	require.Len(t, table.Routes, 1)
+	assert.Equal(t, "/api/news", table.Routes[0].Prefix)
+
+	_, err = LoadRoutes(Config{Routes: `{"routes": [`})
+	assert.ErrorContains(t, err, "failed to parse GATEWAY_ROUTES")
+
+	_, err = LoadRoutes(Config{RoutesFile: filepath.Join(t.TempDir(), "missing.yaml")})
+	assert.ErrorContains(t, err, "failed to read route config")
+
+	writeRoutes(t, file, "routes: []\n")
+	_, err = LoadRoutes(Config{RoutesFile: file})
+	assert.EqualError(t, err, "invalid route config: route table is empty")
+}
+
+func TestWatchRoutesKeepsTheLastValidTable(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "routes.yaml")
+	writeRoutes(t, file, testRoutesYAML)
+
+	cfg := Config{RoutesFile: file}
+	table, err := LoadRoutes(cfg)
+	require.NoError(t, err)
+	store := NewStore(table)
+	reloaded := make(chan *RouteTable, 1)
+	store.OnReload(func(table *RouteTable) {
+		// A save may notify more than once
+		select {
+		case reloaded <- table:
+		default:
+		}
+	})
+	WatchRoutes(cfg, store)
+
+	writeRoutes(t, file, "routes: []\n")
+	select {
+	case table := <-reloaded:
+		t.Fatalf("invalid table swapped in: %+v", table)
+	case <-time.After(300 * time.Millisecond):
+	}
+	assert.Same(t, table, store.Table())
+
+	writeRoutes(t, file, `
+routes:
+  - prefix: /api/news
+    upstreams:
+      - url: http://news:8083
+`)
+	select {
+	case table := <-reloaded:
+		assert.Same(t, table, store.Table())
+		_, ok := store.Match("/api/news/1")
+		assert.True(t, ok)
+		_, ok = store.Match("/api/products")
+		assert.False(t, ok)
+	case <-time.After(5 * time.Second):
+		t.Fatal("route table was not reloaded")
+	}
+}
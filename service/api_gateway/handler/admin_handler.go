@@ -0,0 +1,15 @@
+package handler
+
+import (
+	"net/http"
+	"th3y3m/e-commerce-microservices/service/api_gateway/loadbalancer"
+
+	"github.com/gin-gonic/gin"
+)
+
+// UpstreamStatus reports the health and circuit breaker state of every upstream
+func UpstreamStatus(balancers *loadbalancer.Manager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, balancers.Status())
+	}
+}
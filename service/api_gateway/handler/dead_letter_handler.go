@@ -0,0 +1,85 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"th3y3m/e-commerce-microservices/pkg/rabbitmq"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	defaultDeadLetterLimit = 20
+	maxDeadLetterLimit     = 1000
+)
+
+// DeadLetterHandler inspects and replays the messages consumers gave up on
+type DeadLetterHandler struct {
+	conn *rabbitmq.Connection
+}
+
+func NewDeadLetterHandler(conn *rabbitmq.Connection) *DeadLetterHandler {
+	return &DeadLetterHandler{conn: conn}
+}
+
+// ListDeadLetters shows the oldest dead-lettered messages of a queue without removing them.
+// Listed messages are flagged as redelivered by RabbitMQ, as they are read and returned to the queue.
+func (h *DeadLetterHandler) ListDeadLetters(c *gin.Context) {
+	limit, ok := deadLetterLimit(c)
+	if !ok {
+		return
+	}
+
+	queue := c.Param("queue")
+	letters, total, err := h.conn.DeadLetters(queue, limit)
+	if err != nil {
+		writeDeadLetterError(c, queue, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"queue": rabbitmq.DeadLetterQueue(queue), "total": total, "messages": letters})
+}
+
+// ReplayDeadLetters sends the oldest dead-lettered messages of a queue back to it
+func (h *DeadLetterHandler) ReplayDeadLetters(c *gin.Context) {
+	limit, ok := deadLetterLimit(c)
+	if !ok {
+		return
+	}
+
+	queue := c.Param("queue")
+	replayed, err := h.conn.ReplayDeadLetters(c.Request.Context(), queue, limit)
+	if err != nil && replayed == 0 {
+		writeDeadLetterError(c, queue, err)
+		return
+	}
+	if err != nil {
+		logrus.WithContext(c.Request.Context()).Errorf("Replay of %s stopped after %d messages: %v", queue, replayed, err)
+		c.JSON(http.StatusOK, gin.H{"queue": queue, "replayed": replayed, "error": "Replay stopped early"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"queue": queue, "replayed": replayed})
+}
+
+func deadLetterLimit(c *gin.Context) (int, bool) {
+	limit := defaultDeadLetterLimit
+	if value := c.Query("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > maxDeadLetterLimit {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be between 1 and 1000"})
+			return 0, false
+		}
+		limit = parsed
+	}
+	return limit, true
+}
+
+func writeDeadLetterError(c *gin.Context, queue string, err error) {
+	if errors.Is(err, rabbitmq.ErrNoDeadLetterQueue) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Queue has no dead-letter queue"})
+		return
+	}
+	logrus.WithContext(c.Request.Context()).Errorf("Error reading dead letters of %s: %v", queue, err)
+	c.JSON(http.StatusServiceUnavailable, gin.H{"error": "RabbitMQ is unavailable"})
+}
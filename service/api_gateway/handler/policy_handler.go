@@ -0,0 +1,157 @@
+package handler
+
+import (
+	"net/http"
+	"regexp"
+
+	"github.com/casbin/casbin/v2"
+	"github.com/gin-gonic/gin"
+)
+
+// Policy allows a subject (role) to perform the actions matching the Action regex on Object
+type Policy struct {
+	Subject string `json:"subject" binding:"required"`
+	Object  string `json:"object" binding:"required"`
+	Action  string `json:"action" binding:"required"`
+}
+
+// RoleAssignment makes Subject inherit every policy of Role
+type RoleAssignment struct {
+	Subject string `json:"subject" binding:"required"`
+	Role    string `json:"role" binding:"required"`
+}
+
+// PolicyHandler manages the Casbin policies of the gateway. Changes are saved through the
+// enforcer's adapter and broadcast to the other replicas by its watcher.
+type PolicyHandler struct {
+	enforcer *casbin.SyncedEnforcer
+}
+
+func NewPolicyHandler(enforcer *casbin.SyncedEnforcer) *PolicyHandler {
+	return &PolicyHandler{enforcer: enforcer}
+}
+
+func (h *PolicyHandler) ListPolicies(c *gin.Context) {
+	rules, err := h.enforcer.GetPolicy()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal Server Error"})
+		return
+	}
+
+	policies := make([]Policy, 0, len(rules))
+	for _, rule := range rules {
+		if len(rule) >= 3 {
+			policies = append(policies, Policy{Subject: rule[0], Object: rule[1], Action: rule[2]})
+		}
+	}
+	c.JSON(http.StatusOK, policies)
+}
+
+func (h *PolicyHandler) AddPolicy(c *gin.Context) {
+	var req Policy
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Bad Request"})
+		return
+	}
+	if _, err := regexp.Compile(req.Action); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "action must be a valid regular expression"})
+		return
+	}
+
+	// Casbin reports an existing rule as added, so duplicates are looked up first
+	exists, err := h.enforcer.HasPolicy(req.Subject, req.Object, req.Action)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal Server Error"})
+		return
+	}
+	if exists {
+		c.JSON(http.StatusConflict, gin.H{"error": "Policy already exists"})
+		return
+	}
+	if _, err := h.enforcer.AddPolicy(req.Subject, req.Object, req.Action); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal Server Error"})
+		return
+	}
+	c.JSON(http.StatusCreated, req)
+}
+
+func (h *PolicyHandler) RemovePolicy(c *gin.Context) {
+	var req Policy
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Bad Request"})
+		return
+	}
+
+	removed, err := h.enforcer.RemovePolicy(req.Subject, req.Object, req.Action)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal Server Error"})
+		return
+	}
+	if !removed {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Policy not found"})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (h *PolicyHandler) ListRoles(c *gin.Context) {
+	rules, err := h.enforcer.GetGroupingPolicy()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal Server Error"})
+		return
+	}
+
+	roles := make([]RoleAssignment, 0, len(rules))
+	for _, rule := range rules {
+		if len(rule) >= 2 {
+			roles = append(roles, RoleAssignment{Subject: rule[0], Role: rule[1]})
+		}
+	}
+	c.JSON(http.StatusOK, roles)
+}
+
+func (h *PolicyHandler) AddRole(c *gin.Context) {
+	var req RoleAssignment
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Bad Request"})
+		return
+	}
+	if req.Subject == req.Role {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "A role cannot inherit from itself"})
+		return
+	}
+
+	exists, err := h.enforcer.HasGroupingPolicy(req.Subject, req.Role)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal Server Error"})
+		return
+	}
+	if exists {
+		c.JSON(http.StatusConflict, gin.H{"error": "Role assignment already exists"})
+		return
+	}
+	if _, err := h.enforcer.AddGroupingPolicy(req.Subject, req.Role); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal Server Error"})
+		return
+	}
+	c.JSON(http.StatusCreated, req)
+}
+
+func (h *PolicyHandler) RemoveRole(c *gin.Context) {
+	var req RoleAssignment
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Bad Request"})
+		return
+	}
+
+	removed, err := h.enforcer.RemoveGroupingPolicy(req.Subject, req.Role)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal Server Error"})
+		return
+	}
+	if !removed {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Role assignment not found"})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
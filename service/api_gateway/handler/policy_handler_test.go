@@ -0,0 +1,104 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/casbin/casbin/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func policyRouter(t *testing.T) (*gin.Engine, *casbin.SyncedEnforcer) {
+	enforcer, err := casbin.NewSyncedEnforcer("../rbac/rbac_model.conf")
+	require.NoError(t, err)
+
+	policies := NewPolicyHandler(enforcer)
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/policies", policies.ListPolicies)
+	r.POST("/policies", policies.AddPolicy)
+	r.DELETE("/policies", policies.RemovePolicy)
+	r.GET("/roles", policies.ListRoles)
+	r.POST("/roles", policies.AddRole)
+	r.DELETE("/roles", policies.RemoveRole)
+	return r, enforcer
+}
+
+func send(r *gin.Engine, method, path string, body interface{}) *httptest.ResponseRecorder {
+	var buf bytes.Buffer
+	if body != nil {
+		_ = json.NewEncoder(&buf).Encode(body)
+	}
+	req := httptest.NewRequest(method, path, &buf)
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestPolicyHandlerManagesPolicies(t *testing.T) {
+	r, enforcer := policyRouter(t)
+	policy := Policy{Subject: "support", Object: "/api/orders/:id", Action: "GET"}
+
+	rec := send(r, http.MethodPost, "/policies", policy)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	rec = send(r, http.MethodPost, "/policies", policy)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+
+	allowed, err := enforcer.Enforce("support", "/api/orders/7", "GET")
+	require.NoError(t, err)
+	assert.True(t, allowed)
+
+	rec = send(r, http.MethodGet, "/policies", nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var listed []Policy
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &listed))
+	assert.Equal(t, []Policy{policy}, listed)
+
+	rec = send(r, http.MethodDelete, "/policies", policy)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	rec = send(r, http.MethodDelete, "/policies", policy)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestPolicyHandlerValidatesPolicies(t *testing.T) {
+	r, _ := policyRouter(t)
+
+	rec := send(r, http.MethodPost, "/policies", Policy{Subject: "support", Object: "/api/orders"})
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = send(r, http.MethodPost, "/policies", Policy{Subject: "support", Object: "/api/orders", Action: "GET|("})
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestPolicyHandlerManagesRoles(t *testing.T) {
+	r, enforcer := policyRouter(t)
+	role := RoleAssignment{Subject: "alice", Role: "support"}
+
+	rec := send(r, http.MethodPost, "/roles", RoleAssignment{Subject: "support", Role: "support"})
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = send(r, http.MethodPost, "/roles", role)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	rec = send(r, http.MethodPost, "/roles", role)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+
+	hasRole, err := enforcer.HasRoleForUser("alice", "support")
+	require.NoError(t, err)
+	assert.True(t, hasRole)
+
+	rec = send(r, http.MethodGet, "/roles", nil)
+	var listed []RoleAssignment
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &listed))
+	assert.Equal(t, []RoleAssignment{role}, listed)
+
+	rec = send(r, http.MethodDelete, "/roles", role)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	rec = send(r, http.MethodDelete, "/roles", role)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
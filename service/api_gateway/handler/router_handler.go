@@ -1,56 +1,48 @@
 package handler
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
 	"io"
+	"math"
+	"math/rand/v2"
 	"net/http"
-	"net/url"
-	"strings"
+	"strconv"
+	"th3y3m/e-commerce-microservices/pkg/logging"
+	"th3y3m/e-commerce-microservices/pkg/metrics"
+	"th3y3m/e-commerce-microservices/pkg/tracing"
+	"th3y3m/e-commerce-microservices/service/api_gateway/config"
+	"th3y3m/e-commerce-microservices/service/api_gateway/loadbalancer"
+	"time"
 )
 
-// Microservice base URLs (replace with the actual URLs for your services)
-const (
-	oauthServiceBaseURL           = "http://localhost:8080/auth"
-	productServiceBaseURL         = "http://localhost:8081/api/products"
-	userServiceBaseURL            = "http://localhost:8082/api/users"
-	newsServiceBaseURL            = "http://localhost:8083/api/news"
-	cartItemServiceBaseURL        = "http://localhost:8084/api/cartItems"
-	cartServiceBaseURL            = "http://localhost:8085/api/carts"
-	categoryServiceBaseURL        = "http://localhost:8086/api/categories"
-	courierServiceBaseURL         = "http://localhost:8087/api/couriers"
-	discountServiceBaseURL        = "http://localhost:8088/api/discounts"
-	freightRateServiceBaseURL     = "http://localhost:8089/api/freightRates"
-	orderServiceBaseURL           = "http://localhost:8090/api/orders"
-	orderDetailsServiceBaseURL    = "http://localhost:8091/api/orderDetails"
-	productDiscountServiceBaseURL = "http://localhost:8092/api/productDiscounts"
-	reviewServiceBaseURL          = "http://localhost:8093/api/reviews"
-	paymentServiceBaseURL         = "http://localhost:8094/api/payments"
-	voucherServiceBaseURL         = "http://localhost:8095/api/vouchers"
-	mailServiceBaseURL            = "http://localhost:8096/api/mail"
-	momoServiceBaseURL            = "http://localhost:8097/api/momo"
-	vnpayServiceBaseURL           = "http://localhost:8098/api/vnpay"
-	authServiceBaseURL            = "http://localhost:8099/api/authentication"
-)
-
-var productServiceURLs = []string{
-	"http://localhost:8081/api/products",
-	"http://localhost:9081/api/products", // Another instance of product service
+// Router forwards requests to the upstreams configured in the route table
+type Router struct {
+	routes    *config.Store
+	balancers *loadbalancer.Manager
+	client    *http.Client
 }
 
-var currentProductIndex = 0
+func NewRouter(routes *config.Store, balancers *loadbalancer.Manager) *Router {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConnsPerHost = 100
 
-// ForwardRequest forwards the incoming request to the appropriate microservice
-func ForwardRequest(w http.ResponseWriter, r *http.Request, targetURL string) {
-	// Create a new request based on the incoming request
-	proxyURL, err := url.Parse(targetURL)
-	if err != nil {
-		http.Error(w, "Invalid service URL", http.StatusInternalServerError)
-		return
+	return &Router{
+		routes:    routes,
+		balancers: balancers,
+		// Deadlines are applied per route through the request context
+		client: &http.Client{Transport: tracing.Transport(transport)},
 	}
+}
 
-	proxyReq, err := http.NewRequest(r.Method, proxyURL.String(), r.Body)
+// ForwardRequest sends the incoming request with the given body to targetURL.
+// The caller owns the returned response body.
+func (rt *Router) ForwardRequest(ctx context.Context, r *http.Request, targetURL string, body []byte) (*http.Response, error) {
+	proxyReq, err := http.NewRequestWithContext(ctx, r.Method, targetURL, bytes.NewReader(body))
 	if err != nil {
-		http.Error(w, "Failed to create request", http.StatusInternalServerError)
-		return
+		return nil, err
 	}
 
 	// Copy original headers
@@ -60,116 +52,156 @@ func ForwardRequest(w http.ResponseWriter, r *http.Request, targetURL string) {
 		}
 	}
 
-	// Perform the request
-	client := &http.Client{}
-	resp, err := client.Do(proxyReq)
-	if err != nil {
-		http.Error(w, "Error forwarding the request", http.StatusInternalServerError)
+	return rt.client.Do(proxyReq)
+}
+
+func (rt *Router) RouteHandler(w http.ResponseWriter, r *http.Request) {
+	route, ok := rt.routes.Match(r.URL.Path)
+	if !ok {
+		writeError(w, http.StatusNotFound, "Not Found")
 		return
 	}
-	defer resp.Body.Close()
 
-	// Copy response headers
-	for name, values := range resp.Header {
-		for _, value := range values {
-			w.Header().Add(name, value)
-		}
+	// Filled in for the access log
+	info := logging.RequestInfoFromContext(r.Context())
+	if info == nil {
+		info = &logging.RequestInfo{}
 	}
+	info.Route = route.Prefix
 
-	// Set the status code and write the response body
-	w.WriteHeader(resp.StatusCode)
-	io.Copy(w, resp.Body)
-}
-
-func getNextProductServiceURL() string {
-	// Round-robin load balancing
-	url := productServiceURLs[currentProductIndex]
-	currentProductIndex = (currentProductIndex + 1) % len(productServiceURLs)
-	return url
-}
-
-func RouteHandler(w http.ResponseWriter, r *http.Request) {
-	path := r.URL.Path
-
-	switch {
-	case strings.HasPrefix(path, "/api/products"):
-		// Load balance between product service instances
-		targetURL := getNextProductServiceURL() + strings.TrimPrefix(path, "/api/products")
-		ForwardRequest(w, r, targetURL)
-
-	case strings.HasPrefix(path, "/api/users"):
-		targetURL := userServiceBaseURL + strings.TrimPrefix(path, "/api/users")
-		ForwardRequest(w, r, targetURL)
-
-	case strings.HasPrefix(path, "/api/news"):
-		targetURL := newsServiceBaseURL + strings.TrimPrefix(path, "/api/news")
-		ForwardRequest(w, r, targetURL)
-
-	case strings.HasPrefix(path, "/api/cartItems"):
-		targetURL := cartItemServiceBaseURL + strings.TrimPrefix(path, "/api/cartItems")
-		ForwardRequest(w, r, targetURL)
+	pool, ok := rt.balancers.Pool(route)
+	if !ok {
+		writeError(w, http.StatusServiceUnavailable, "Service Unavailable")
+		return
+	}
 
-	case strings.HasPrefix(path, "/api/carts"):
-		targetURL := cartServiceBaseURL + strings.TrimPrefix(path, "/api/carts")
-		ForwardRequest(w, r, targetURL)
+	// The body is buffered so that it can be replayed on retries
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Failed to read request body")
+		return
+	}
 
-	case strings.HasPrefix(path, "/api/categories"):
-		targetURL := categoryServiceBaseURL + strings.TrimPrefix(path, "/api/categories")
-		ForwardRequest(w, r, targetURL)
+	path := route.UpstreamPath(r.URL.Path)
+	if r.URL.RawQuery != "" {
+		path += "?" + r.URL.RawQuery
+	}
 
-	case strings.HasPrefix(path, "/api/couriers"):
-		targetURL := courierServiceBaseURL + strings.TrimPrefix(path, "/api/couriers")
-		ForwardRequest(w, r, targetURL)
+	ctx, cancel := context.WithTimeout(r.Context(), route.Timeout)
+	defer cancel()
 
-	case strings.HasPrefix(path, "/api/discounts"):
-		targetURL := discountServiceBaseURL + strings.TrimPrefix(path, "/api/discounts")
-		ForwardRequest(w, r, targetURL)
+	maxAttempts := 1
+	if isIdempotent(r.Method) {
+		maxAttempts = route.Retry.MaxAttempts
+	}
 
-	case strings.HasPrefix(path, "/api/freightRates"):
-		targetURL := freightRateServiceBaseURL + strings.TrimPrefix(path, "/api/freightRates")
-		ForwardRequest(w, r, targetURL)
+	for attempt := 1; ; attempt++ {
+		backend, err := pool.Next()
+		if err != nil {
+			writeUpstreamError(w, r, err)
+			return
+		}
 
-	case strings.HasPrefix(path, "/api/orders"):
-		targetURL := orderServiceBaseURL + strings.TrimPrefix(path, "/api/orders")
-		ForwardRequest(w, r, targetURL)
+		attemptStart := time.Now()
+		resp, err := rt.ForwardRequest(ctx, r, backend.URL+path, body)
+		latency := time.Since(attemptStart)
+		info.Upstream = backend.URL
+		info.UpstreamLatency += latency
+		statusCode := 0
+		if err == nil {
+			statusCode = resp.StatusCode
+		}
+		pool.Done(backend, statusCode, err)
+		metrics.ObserveUpstream(backend.URL, statusCode, err, latency)
+
+		retryable := (err != nil && ctx.Err() == nil) || (err == nil && isRetryableStatus(statusCode))
+		if !retryable || attempt >= maxAttempts {
+			if err != nil {
+				writeUpstreamError(w, r, err)
+				return
+			}
+			copyResponse(w, resp)
+			return
+		}
 
-	case strings.HasPrefix(path, "/api/orderDetails"):
-		targetURL := orderDetailsServiceBaseURL + strings.TrimPrefix(path, "/api/orderDetails")
-		ForwardRequest(w, r, targetURL)
+		if resp != nil {
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
 
-	case strings.HasPrefix(path, "/api/productDiscounts"):
-		targetURL := productDiscountServiceBaseURL + strings.TrimPrefix(path, "/api/productDiscounts")
-		ForwardRequest(w, r, targetURL)
+		select {
+		case <-time.After(backoff(route.Retry, attempt)):
+		case <-ctx.Done():
+			writeUpstreamError(w, r, ctx.Err())
+			return
+		}
+	}
+}
 
-	case strings.HasPrefix(path, "/api/reviews"):
-		targetURL := reviewServiceBaseURL + strings.TrimPrefix(path, "/api/reviews")
-		ForwardRequest(w, r, targetURL)
+func copyResponse(w http.ResponseWriter, resp *http.Response) {
+	defer resp.Body.Close()
 
-	case strings.HasPrefix(path, "/api/payments"):
-		targetURL := paymentServiceBaseURL + strings.TrimPrefix(path, "/api/payments")
-		ForwardRequest(w, r, targetURL)
+	// Copy response headers, the request ID is already set by the gateway
+	for name, values := range resp.Header {
+		if name == logging.HeaderRequestID {
+			continue
+		}
+		for _, value := range values {
+			w.Header().Add(name, value)
+		}
+	}
 
-	case strings.HasPrefix(path, "/api/vouchers"):
-		targetURL := voucherServiceBaseURL + strings.TrimPrefix(path, "/api/vouchers")
-		ForwardRequest(w, r, targetURL)
+	// Set the status code and write the response body
+	w.WriteHeader(resp.StatusCode)
+	io.Copy(w, resp.Body)
+}
 
-	case strings.HasPrefix(path, "/api/mail"):
-		targetURL := mailServiceBaseURL + strings.TrimPrefix(path, "/api/mail")
-		ForwardRequest(w, r, targetURL)
+// writeUpstreamError maps a failed upstream call to a gateway status code
+func writeUpstreamError(w http.ResponseWriter, r *http.Request, err error) {
+	var unavailable *loadbalancer.UnavailableError
+	switch {
+	case errors.As(err, &unavailable):
+		seconds := int(math.Ceil(unavailable.RetryAfter.Seconds()))
+		if seconds < 1 {
+			seconds = 1
+		}
+		w.Header().Set("Retry-After", strconv.Itoa(seconds))
+		writeError(w, http.StatusServiceUnavailable, "Service Unavailable")
+	case r.Context().Err() != nil:
+		// The client went away, nobody is left to read the response
+	case errors.Is(err, context.DeadlineExceeded):
+		writeError(w, http.StatusGatewayTimeout, "Upstream timed out")
+	default:
+		writeError(w, http.StatusBadGateway, "Upstream unavailable")
+	}
+}
 
-	case strings.HasPrefix(path, "/api/momo"):
-		targetURL := momoServiceBaseURL + strings.TrimPrefix(path, "/api/momo")
-		ForwardRequest(w, r, targetURL)
+func writeError(w http.ResponseWriter, statusCode int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(map[string]string{"error": message})
+}
 
-	case strings.HasPrefix(path, "/api/vnpay"):
-		targetURL := vnpayServiceBaseURL + strings.TrimPrefix(path, "/api/vnpay")
-		ForwardRequest(w, r, targetURL)
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
 
-	case strings.HasPrefix(path, "/api/authentication"):
-		targetURL := authServiceBaseURL + strings.TrimPrefix(path, "/api/authentication")
-		ForwardRequest(w, r, targetURL)
+func isRetryableStatus(statusCode int) bool {
+	return statusCode == http.StatusBadGateway ||
+		statusCode == http.StatusServiceUnavailable ||
+		statusCode == http.StatusGatewayTimeout
+}
 
-	default:
-		http.Error(w, "Not Found", http.StatusNotFound)
+// backoff returns an exponential delay with jitter for the given attempt (1-based)
+func backoff(retry config.Retry, attempt int) time.Duration {
+	delay := retry.Backoff << (attempt - 1)
+	if delay <= 0 || delay > retry.MaxBackoff {
+		delay = retry.MaxBackoff
 	}
+	half := delay / 2
+	return half + rand.N(half+1)
 }
@@ -0,0 +1,75 @@
+package loadbalancer
+
+import (
+	"sync"
+	"th3y3m/e-commerce-microservices/service/api_gateway/circuitbreaker"
+	"th3y3m/e-commerce-microservices/service/api_gateway/config"
+	"time"
+)
+
+// Manager keeps one pool per route and rebuilds them when the route table is reloaded
+type Manager struct {
+	mu    sync.RWMutex
+	pools map[string]*Pool
+}
+
+func NewManager(table *config.RouteTable) *Manager {
+	m := &Manager{pools: make(map[string]*Pool)}
+	m.Sync(table)
+	return m
+}
+
+// Pool returns the pool serving the given route
+func (m *Manager) Pool(route *config.Route) (*Pool, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	pool, ok := m.pools[route.Prefix]
+	return pool, ok
+}
+
+// Sync replaces the pools with ones built from the new table and stops the old health checks.
+// Backends whose upstream is unchanged are carried over with their health and breaker state.
+func (m *Manager) Sync(table *config.RouteTable) {
+	m.mu.Lock()
+	old := m.pools
+	pools := make(map[string]*Pool, len(table.Routes))
+	for i := range table.Routes {
+		pool := newPool(&table.Routes[i], old[table.Routes[i].Prefix])
+		pool.StartHealthChecks()
+		pools[table.Routes[i].Prefix] = pool
+	}
+	m.pools = pools
+	m.mu.Unlock()
+
+	for _, pool := range old {
+		pool.Stop()
+	}
+}
+
+// BackendStatus is the admin view of one upstream instance
+type BackendStatus struct {
+	URL               string                  `json:"url"`
+	Available         bool                    `json:"available"`
+	ActiveConnections int64                   `json:"active_connections"`
+	Breaker           circuitbreaker.Snapshot `json:"breaker"`
+}
+
+// Status reports health and breaker state of every upstream, keyed by route prefix
+func (m *Manager) Status() map[string][]BackendStatus {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	now := time.Now()
+	status := make(map[string][]BackendStatus, len(m.pools))
+	for prefix, pool := range m.pools {
+		for _, b := range pool.Backends() {
+			status[prefix] = append(status[prefix], BackendStatus{
+				URL:               b.URL,
+				Available:         b.Available(now),
+				ActiveConnections: b.ActiveConnections(),
+				Breaker:           b.Breaker.Snapshot(),
+			})
+		}
+	}
+	return status
+}
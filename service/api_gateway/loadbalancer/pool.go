@@ -0,0 +1,241 @@
+package loadbalancer
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"th3y3m/e-commerce-microservices/service/api_gateway/circuitbreaker"
+	"th3y3m/e-commerce-microservices/service/api_gateway/config"
+	"time"
+)
+
+// UnavailableError is returned when no upstream of a route can take the request,
+// either because all are unhealthy or because their circuit breakers are open
+type UnavailableError struct {
+	RetryAfter time.Duration
+}
+
+func (e *UnavailableError) Error() string {
+	return "no healthy upstream available"
+}
+
+// Backend is a single upstream instance of a route
+type Backend struct {
+	URL     string
+	Weight  int
+	Breaker *circuitbreaker.Breaker
+
+	healthy     atomic.Bool
+	active      atomic.Int64
+	mu          sync.Mutex
+	fails       int
+	ejectedTill time.Time
+}
+
+// Available reports whether the backend passed its last probe and is not ejected
+func (b *Backend) Available(now time.Time) bool {
+	if !b.healthy.Load() {
+		return false
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return now.After(b.ejectedTill)
+}
+
+func (b *Backend) ActiveConnections() int64 {
+	return b.active.Load()
+}
+
+// Pool balances requests over the backends of one route
+type Pool struct {
+	prefix   string
+	backends []*Backend
+	strategy Strategy
+	health   config.HealthCheck
+	breaker  config.CircuitBreaker
+	client   *http.Client
+	cancel   context.CancelFunc
+}
+
+func NewPool(route *config.Route) *Pool {
+	return newPool(route, nil)
+}
+
+// newPool builds the pool of the route, taking over the backends of the previous pool of the
+// route that are unchanged so their health, ejection and breaker state survive a reload
+func newPool(route *config.Route, previous *Pool) *Pool {
+	kept := make(map[string]*Backend)
+	if previous != nil && previous.breaker == route.CircuitBreaker {
+		for _, b := range previous.backends {
+			kept[b.URL] = b
+		}
+	}
+
+	backends := make([]*Backend, 0, len(route.Upstreams))
+	for _, upstream := range route.Upstreams {
+		if b, ok := kept[upstream.URL]; ok && b.Weight == upstream.Weight {
+			backends = append(backends, b)
+			continue
+		}
+		b := &Backend{
+			URL:    upstream.URL,
+			Weight: upstream.Weight,
+			Breaker: circuitbreaker.NewBreaker(
+				route.CircuitBreaker.FailureThreshold,
+				route.CircuitBreaker.OpenTimeout,
+				route.CircuitBreaker.HalfOpenRequests,
+			),
+		}
+		b.healthy.Store(true)
+		backends = append(backends, b)
+	}
+
+	return &Pool{
+		prefix:   route.Prefix,
+		backends: backends,
+		strategy: NewStrategy(route.Strategy),
+		health:   route.HealthCheck,
+		breaker:  route.CircuitBreaker,
+		client:   &http.Client{Timeout: route.HealthCheck.Timeout},
+	}
+}
+
+// Next picks an available backend whose breaker lets the call through and reserves
+// a connection slot on it. Callers must call Done with the outcome once the upstream call finishes.
+func (p *Pool) Next() (*Backend, error) {
+	now := time.Now()
+	candidates := make([]*Backend, 0, len(p.backends))
+	for _, b := range p.backends {
+		if b.Available(now) {
+			candidates = append(candidates, b)
+		}
+	}
+	if len(candidates) == 0 {
+		return nil, &UnavailableError{RetryAfter: p.health.Interval}
+	}
+
+	var retryAfter time.Duration
+	for len(candidates) > 0 {
+		b := p.strategy.Pick(candidates)
+		allowed, wait := b.Breaker.Allow()
+		if allowed {
+			b.active.Add(1)
+			return b, nil
+		}
+		if retryAfter == 0 || wait < retryAfter {
+			retryAfter = wait
+		}
+		candidates = remove(candidates, b)
+	}
+	return nil, &UnavailableError{RetryAfter: retryAfter}
+}
+
+func remove(backends []*Backend, target *Backend) []*Backend {
+	out := backends[:0:0]
+	for _, b := range backends {
+		if b != target {
+			out = append(out, b)
+		}
+	}
+	return out
+}
+
+// Done releases the connection slot and records the outcome for the circuit breaker
+// and passive ejection. A connection error or a 5xx response counts as a failure. A call the
+// client canceled says nothing about the backend and is not counted.
+func (p *Pool) Done(b *Backend, statusCode int, err error) {
+	b.active.Add(-1)
+
+	if errors.Is(err, context.Canceled) {
+		b.Breaker.Release()
+		return
+	}
+
+	switch {
+	case err != nil:
+		b.Breaker.Failure(err.Error())
+	case statusCode >= http.StatusInternalServerError:
+		b.Breaker.Failure(http.StatusText(statusCode))
+	default:
+		b.Breaker.Success()
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err == nil && statusCode < http.StatusInternalServerError {
+		b.fails = 0
+		return
+	}
+
+	b.fails++
+	if b.fails >= p.health.MaxFails {
+		b.fails = 0
+		b.ejectedTill = time.Now().Add(p.health.FailTimeout)
+		log.Printf("Ejected upstream %s of %s for %v", b.URL, p.prefix, p.health.FailTimeout)
+	}
+}
+
+// Backends returns the instances of the pool
+func (p *Pool) Backends() []*Backend {
+	return p.backends
+}
+
+// StartHealthChecks probes every backend on the configured interval until Stop is called
+func (p *Pool) StartHealthChecks() {
+	ctx, cancel := context.WithCancel(context.Background())
+	p.cancel = cancel
+
+	go func() {
+		ticker := time.NewTicker(p.health.Interval)
+		defer ticker.Stop()
+
+		for {
+			p.probeAll(ctx)
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+func (p *Pool) Stop() {
+	if p.cancel != nil {
+		p.cancel()
+	}
+}
+
+func (p *Pool) probeAll(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, b := range p.backends {
+		wg.Add(1)
+		go func(b *Backend) {
+			defer wg.Done()
+			healthy := p.probe(ctx, b)
+			if b.healthy.Swap(healthy) != healthy {
+				log.Printf("Upstream %s of %s is now healthy=%v", b.URL, p.prefix, healthy)
+			}
+		}(b)
+	}
+	wg.Wait()
+}
+
+func (p *Pool) probe(ctx context.Context, b *Backend) bool {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, b.URL+p.health.Path, nil)
+	if err != nil {
+		return false
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return false
+	}
+	defer resp.Body.Close()
+
+	// A missing health endpoint, answering 404, must not pass for a healthy backend
+	return resp.StatusCode >= 200 && resp.StatusCode < 300
+}
@@ -0,0 +1,90 @@
+package loadbalancer
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"th3y3m/e-commerce-microservices/service/api_gateway/config"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProbeNeedsA2xx(t *testing.T) {
+	status := http.StatusOK
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+
+	pool := NewPool(&config.Route{
+		Prefix:      "/api/products",
+		Upstreams:   []config.Upstream{{URL: srv.URL, Weight: 1}},
+		HealthCheck: config.HealthCheck{Path: "/readyz", Timeout: time.Second},
+	})
+	b := pool.Backends()[0]
+
+	assert.True(t, pool.probe(context.Background(), b))
+
+	status = http.StatusNotFound
+	assert.False(t, pool.probe(context.Background(), b))
+
+	status = http.StatusServiceUnavailable
+	assert.False(t, pool.probe(context.Background(), b))
+}
+
+func testRoute(urls ...string) config.Route {
+	route := config.Route{
+		Prefix:         "/api/products",
+		HealthCheck:    config.HealthCheck{Path: "/readyz", Interval: time.Hour, Timeout: time.Second, MaxFails: 1, FailTimeout: time.Minute},
+		CircuitBreaker: config.CircuitBreaker{FailureThreshold: 1, OpenTimeout: time.Minute, HalfOpenRequests: 1},
+	}
+	for _, url := range urls {
+		route.Upstreams = append(route.Upstreams, config.Upstream{URL: url, Weight: 1})
+	}
+	return route
+}
+
+func TestCanceledCallsDoNotCountAsFailures(t *testing.T) {
+	route := testRoute("http://product-1")
+	pool := NewPool(&route)
+
+	b, err := pool.Next()
+	assert.NoError(t, err)
+	pool.Done(b, 0, context.Canceled)
+
+	assert.True(t, b.Available(time.Now()))
+	assert.Equal(t, int64(0), b.ActiveConnections())
+	assert.Equal(t, 0, b.Breaker.Snapshot().Failures)
+
+	b, err = pool.Next()
+	assert.NoError(t, err)
+	pool.Done(b, 0, context.DeadlineExceeded)
+	assert.False(t, b.Available(time.Now()))
+}
+
+func TestSyncKeepsUnchangedBackends(t *testing.T) {
+	route := testRoute("http://product-1", "http://product-2")
+	manager := &Manager{pools: make(map[string]*Pool)}
+	manager.Sync(&config.RouteTable{Routes: []config.Route{route}})
+	defer manager.Sync(&config.RouteTable{})
+
+	pool, _ := manager.Pool(&route)
+	kept := pool.Backends()[0]
+	kept.Breaker.Failure("boom")
+
+	reloaded := testRoute("http://product-1", "http://product-3")
+	manager.Sync(&config.RouteTable{Routes: []config.Route{reloaded}})
+
+	pool, _ = manager.Pool(&reloaded)
+	assert.Same(t, kept, pool.Backends()[0])
+	assert.Equal(t, 1, pool.Backends()[0].Breaker.Snapshot().Failures)
+	assert.Equal(t, "http://product-3", pool.Backends()[1].URL)
+
+	// New breaker settings start the backends over
+	reloaded.CircuitBreaker.FailureThreshold = 5
+	manager.Sync(&config.RouteTable{Routes: []config.Route{reloaded}})
+	pool, _ = manager.Pool(&reloaded)
+	assert.NotSame(t, kept, pool.Backends()[0])
+}
@@ -0,0 +1,72 @@
+package loadbalancer
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+const (
+	StrategyRoundRobin       = "round_robin"
+	StrategyLeastConnections = "least_connections"
+	StrategyWeighted         = "weighted"
+)
+
+// Strategy chooses one backend out of the currently available ones
+type Strategy interface {
+	Pick(backends []*Backend) *Backend
+}
+
+func NewStrategy(name string) Strategy {
+	switch name {
+	case StrategyLeastConnections:
+		return &leastConnections{}
+	case StrategyWeighted:
+		return &weighted{current: make(map[*Backend]int)}
+	default:
+		return &roundRobin{}
+	}
+}
+
+type roundRobin struct {
+	next atomic.Uint64
+}
+
+func (s *roundRobin) Pick(backends []*Backend) *Backend {
+	n := s.next.Add(1) - 1
+	return backends[n%uint64(len(backends))]
+}
+
+type leastConnections struct{}
+
+func (s *leastConnections) Pick(backends []*Backend) *Backend {
+	best := backends[0]
+	for _, b := range backends[1:] {
+		if b.ActiveConnections() < best.ActiveConnections() {
+			best = b
+		}
+	}
+	return best
+}
+
+// weighted implements smooth weighted round-robin (as used by nginx)
+type weighted struct {
+	mu      sync.Mutex
+	current map[*Backend]int
+}
+
+func (s *weighted) Pick(backends []*Backend) *Backend {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	total := 0
+	var best *Backend
+	for _, b := range backends {
+		s.current[b] += b.Weight
+		total += b.Weight
+		if best == nil || s.current[b] > s.current[best] {
+			best = b
+		}
+	}
+	s.current[best] -= total
+	return best
+}
@@ -0,0 +1,44 @@
+package loadbalancer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newBackend(url string, weight int) *Backend {
+	b := &Backend{URL: url, Weight: weight}
+	b.healthy.Store(true)
+	return b
+}
+
+func TestRoundRobin(t *testing.T) {
+	backends := []*Backend{newBackend("a", 1), newBackend("b", 1)}
+	strategy := NewStrategy(StrategyRoundRobin)
+
+	assert.Equal(t, "a", strategy.Pick(backends).URL)
+	assert.Equal(t, "b", strategy.Pick(backends).URL)
+	assert.Equal(t, "a", strategy.Pick(backends).URL)
+}
+
+func TestLeastConnections(t *testing.T) {
+	busy := newBackend("busy", 1)
+	busy.active.Store(5)
+	idle := newBackend("idle", 1)
+	strategy := NewStrategy(StrategyLeastConnections)
+
+	assert.Equal(t, "idle", strategy.Pick([]*Backend{busy, idle}).URL)
+}
+
+func TestWeighted(t *testing.T) {
+	backends := []*Backend{newBackend("heavy", 3), newBackend("light", 1)}
+	strategy := NewStrategy(StrategyWeighted)
+
+	picks := make(map[string]int)
+	for i := 0; i < 8; i++ {
+		picks[strategy.Pick(backends).URL]++
+	}
+
+	assert.Equal(t, 6, picks["heavy"])
+	assert.Equal(t, 2, picks["light"])
+}
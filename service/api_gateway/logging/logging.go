@@ -1,34 +1,37 @@
 package logging
 
 import (
-	"log"
-	"net/http"
+	"th3y3m/e-commerce-microservices/pkg/logging"
 	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
 )
 
-func LogRequests(next http.HandlerFunc) http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
+// AccessLog accepts the client's X-Request-ID or generates one, forwards it to the upstreams
+// and writes one JSON line per request once it has been served.
+func AccessLog() gin.HandlerFunc {
+	return func(c *gin.Context) {
 		start := time.Now()
 
-		// Create a response recorder to capture the status code
-		recorder := &responseRecorder{ResponseWriter: w, statusCode: http.StatusOK}
+		info := &logging.RequestInfo{RequestID: logging.RequestID(c.GetHeader(logging.HeaderRequestID))}
+		c.Request.Header.Set(logging.HeaderRequestID, info.RequestID)
+		c.Request = c.Request.WithContext(logging.WithRequestInfo(c.Request.Context(), info))
+		c.Header(logging.HeaderRequestID, info.RequestID)
 
-		// Forward the request
-		next.ServeHTTP(recorder, r)
+		c.Next()
 
-		// Log the request details
-		log.Printf("Method: %s, Path: %s, Status: %d, Duration: %v",
-			r.Method, r.URL.Path, recorder.statusCode, time.Since(start))
+		fields := logrus.Fields{
+			"method":     c.Request.Method,
+			"path":       c.Request.URL.Path,
+			"status":     c.Writer.Status(),
+			"latency_ms": time.Since(start).Milliseconds(),
+			"client_ip":  c.ClientIP(),
+		}
+		if info.Upstream != "" {
+			fields["upstream"] = info.Upstream
+			fields["upstream_latency_ms"] = info.UpstreamLatency.Milliseconds()
+		}
+		logging.Logger().WithContext(c.Request.Context()).WithFields(fields).Info("request")
 	}
 }
-
-// responseRecorder is used to capture the status code
-type responseRecorder struct {
-	http.ResponseWriter
-	statusCode int
-}
-
-func (rec *responseRecorder) WriteHeader(code int) {
-	rec.statusCode = code
-	rec.ResponseWriter.WriteHeader(code)
-}
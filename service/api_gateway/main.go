@@ -1,41 +1,175 @@
 package main
 
 import (
+	"context"
 	"log"
+	"th3y3m/e-commerce-microservices/pkg/authz"
+	"th3y3m/e-commerce-microservices/pkg/broker"
+	pkgconfig "th3y3m/e-commerce-microservices/pkg/config"
+	"th3y3m/e-commerce-microservices/pkg/discovery"
+	"th3y3m/e-commerce-microservices/pkg/health"
+	pkglogging "th3y3m/e-commerce-microservices/pkg/logging"
+	"th3y3m/e-commerce-microservices/pkg/metrics"
+	"th3y3m/e-commerce-microservices/pkg/postgresql"
+	pkgrabbitmq "th3y3m/e-commerce-microservices/pkg/rabbitmq"
+	redis_client "th3y3m/e-commerce-microservices/pkg/redis"
+	"th3y3m/e-commerce-microservices/pkg/server"
+	"th3y3m/e-commerce-microservices/pkg/tracing"
+	"th3y3m/e-commerce-microservices/service/api_gateway/cache"
+	"th3y3m/e-commerce-microservices/service/api_gateway/config"
 	"th3y3m/e-commerce-microservices/service/api_gateway/handler"
+	"th3y3m/e-commerce-microservices/service/api_gateway/loadbalancer"
 	"th3y3m/e-commerce-microservices/service/api_gateway/logging"
 	"th3y3m/e-commerce-microservices/service/api_gateway/middleware"
+	"th3y3m/e-commerce-microservices/service/api_gateway/rabbitmq"
+	"th3y3m/e-commerce-microservices/service/api_gateway/ratelimit"
+	"th3y3m/e-commerce-microservices/service/api_gateway/rbac"
+	"time"
 
 	"github.com/casbin/casbin/v2"
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	rbacModelFile  = "rbac/rbac_model.conf"
+	rbacPolicyFile = "rbac/rbac_policy.csv"
+	// Redis is on the request path, so a slow call is treated as an outage
+	rateLimitTimeout  = 100 * time.Millisecond
+	rateLimitCooldown = 5 * time.Second
+	cacheTimeout      = 200 * time.Millisecond
+	cacheMaxBytes     = 64 << 20
 )
 
 func main() {
-	enforcer, err := casbin.NewEnforcer("rbac/rbac_model.conf", "rbac/rbac_policy.csv")
+	pkglogging.Setup("api_gateway")
+
+	var cfg config.Config
+	pkgconfig.MustLoad("api_gateway", &cfg)
+
+	shutdownTracing := tracing.Setup("api_gateway", cfg.Tracing)
+	defer shutdownTracing(context.Background())
+
+	resolver, err := discovery.New(cfg.Discovery, cfg.Redis)
+	if err != nil {
+		log.Fatalf("Failed to set up service discovery: %v", err)
+	}
+
+	routeTable, err := config.LoadRoutes(cfg)
+	if err != nil {
+		log.Fatalf("Failed to load route config: %v", err)
+	}
+	routes := config.NewStore(routeTable)
+	balancers := loadbalancer.NewManager(routeTable)
+	routes.OnReload(balancers.Sync)
+	config.WatchRoutes(cfg, routes)
+	router := handler.NewRouter(routes, balancers)
+
+	// Limits are shared through Redis and fall back to per-replica buckets when it is down
+	// Responses are cached in Redis as well, or in a bounded per-replica LRU without it
+	var limiter ratelimit.Limiter = ratelimit.NewMemoryLimiter()
+	var responseCache cache.Store = cache.NewLRU(cacheMaxBytes)
+	redisClient, err := redis_client.NewClient(cfg.Redis)
+	if err != nil {
+		log.Printf("Redis is not configured, rate limits and cache are per replica: %v", err)
+	} else {
+		limiter = ratelimit.NewFallbackLimiter(ratelimit.NewRedisLimiter(redisClient, rateLimitTimeout), limiter, rateLimitCooldown)
+		responseCache = cache.NewRedis(redisClient, cacheTimeout)
+	}
+
+	// Cached responses expire on their own, so the gateway keeps serving without the events
+	// while RabbitMQ is down, and the consumer reconnects in the background
+	consumeInvalidations := func(ctx context.Context) error {
+		if cfg.RabbitMQ.URI == "" && !cfg.RabbitMQ.InMemory() {
+			log.Println("RABBITMQ_URI is not set, cache invalidation events are disabled")
+			return nil
+		}
+		return rabbitmq.ConsumeCacheInvalidations(ctx, broker.FromConfig(cfg.RabbitMQ, pkgrabbitmq.DefaultBroker), responseCache, routes)
+	}
+
+	enforcer, err := newEnforcer(cfg, redisClient)
 	if err != nil {
 		log.Fatalf("Failed to load Casbin model and policy: %v", err)
 	}
+	policies := handler.NewPolicyHandler(enforcer)
+	deadLetters := handler.NewDeadLetterHandler(pkgrabbitmq.Default(cfg.RabbitMQ))
 
-	r := gin.Default()
+	r := gin.New()
+	r.ContextWithFallback = true
+	r.Use(gin.Recovery(), tracing.Middleware("api_gateway"), logging.AccessLog(), metrics.Middleware())
 
 	// Set up CORS middleware (optional, if needed)
 	r.Use(cors.New(cors.Config{
 		AllowOrigins:     []string{"http://localhost:3000"},
 		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization"},
-		ExposeHeaders:    []string{"Content-Length"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization", "X-API-Key", "If-None-Match", "Cache-Control", "X-Request-ID"},
+		ExposeHeaders:    []string{"Content-Length", "X-RateLimit-Limit", "X-RateLimit-Remaining", "X-RateLimit-Reset", "Retry-After", "ETag", "X-Cache", "Age", "X-Request-ID"},
 		AllowCredentials: true,
 	}))
 
+	// Scraped by Prometheus and probed by the orchestrator, registered before the auth
+	// middleware so they need no token. Every dependency of the gateway has a fallback.
+	r.GET(metrics.Path, metrics.Handler())
+	checker := health.NewChecker()
+	checker.Register(r)
+
 	// Set up Auth middleware with Casbin
-	r.Use(middleware.AuthMiddleware(enforcer))
+	r.Use(middleware.AuthMiddleware(enforcer, routes, cfg.JWTSecret, authz.NewKeys(cfg.Auth)))
 
-	// Use rate limiting, logging, and caching
-	r.Use(gin.WrapF(middleware.CacheMiddleware(logging.LogRequests(middleware.RateLimit(handler.RouteHandler)))))
+	// Gateway administration, restricted to admins by the Casbin policy
+	admin := r.Group("/admin")
+	{
+		admin.GET("/upstreams", handler.UpstreamStatus(balancers))
+
+		admin.GET("/policies", policies.ListPolicies)
+		admin.POST("/policies", policies.AddPolicy)
+		admin.DELETE("/policies", policies.RemovePolicy)
+		admin.GET("/roles", policies.ListRoles)
+		admin.POST("/roles", policies.AddRole)
+		admin.DELETE("/roles", policies.RemoveRole)
+
+		admin.GET("/dead-letters/:queue", deadLetters.ListDeadLetters)
+		admin.POST("/dead-letters/:queue/replay", deadLetters.ReplayDeadLetters)
+	}
+
+	// Everything else is proxied, with rate limiting and caching. Rate limiting comes first so
+	// responses served from the cache count against the limits too.
+	r.NoRoute(gin.WrapF(middleware.RateLimit(limiter, routes, middleware.CacheMiddleware(responseCache, routes, router.RouteHandler))))
 
 	log.Println("API Gateway running on port 9000...")
-	if err := r.Run(":9000"); err != nil {
-		log.Fatalf("Failed to start server: %v", err)
+	if err := server.Run(":9000", cfg.Server, r, checker, discovery.Heartbeat(discovery.APIGateway, resolver, cfg.Discovery), consumeInvalidations); err != nil {
+		log.Fatalf("Failed to run server: %v", err)
+	}
+}
+
+// newEnforcer keeps the policies in Postgres and follows changes made by other replicas through
+// Redis. Without CONNECTION_STRING the CSV file is used and changes are lost on restart.
+func newEnforcer(cfg config.Config, redisClient *redis.Client) (*casbin.SyncedEnforcer, error) {
+	if cfg.ConnectionString == "" {
+		log.Printf("CONNECTION_STRING is not set, policies are read from %s and not persisted", rbacPolicyFile)
+		return casbin.NewSyncedEnforcer(rbacModelFile, rbacPolicyFile)
+	}
+
+	db, err := postgresql.NewGormDB(pkgconfig.Postgres{ConnectionString: cfg.ConnectionString})
+	if err != nil {
+		return nil, err
+	}
+	adapter, err := rbac.NewAdapter(db)
+	if err != nil {
+		return nil, err
+	}
+	enforcer, err := rbac.NewEnforcer(rbacModelFile, rbacPolicyFile, adapter)
+	if err != nil {
+		return nil, err
+	}
+
+	if redisClient == nil {
+		log.Println("Redis is not configured, policy changes reach other replicas on the next periodic reload")
+		return enforcer, nil
+	}
+	if err := rbac.Watch(enforcer, rbac.NewWatcher(context.Background(), redisClient)); err != nil {
+		return nil, err
 	}
+	return enforcer, nil
 }
@@ -1,50 +1,336 @@
 package middleware
 
 import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"log"
 	"net/http"
+	"strconv"
 	"strings"
+	"th3y3m/e-commerce-microservices/service/api_gateway/cache"
+	"th3y3m/e-commerce-microservices/service/api_gateway/config"
+	"time"
 )
 
-// Global cache
-var cache = make(map[string][]byte)
+// Responses larger than this are passed through without being cached
+const maxCachedBodyBytes = 1 << 20
 
-// Basic caching logic
-func CacheMiddleware(next http.HandlerFunc) http.HandlerFunc {
+// Headers that describe a single exchange and must not be replayed from the cache
+var uncachedHeaders = []string{
+	"Set-Cookie",
+	"Retry-After",
+	"X-RateLimit-Limit",
+	"X-RateLimit-Remaining",
+	"X-RateLimit-Reset",
+	"X-Request-ID",
+}
+
+// CacheMiddleware serves GET requests of routes with a cache TTL from the store.
+// Keys cover the method, path, query, the route's Vary headers and, for authenticated
+// requests, the caller. A GET with a body is neither looked up nor stored, since some list
+// handlers still read their filters from it and the key does not cover it. Cache-Control on
+// both sides is honoured, an ETag is added to every cached response and If-None-Match is
+// answered with 304. Successful unsafe requests purge the route.
+func CacheMiddleware(store cache.Store, routes *config.Store, next http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		// Check if the response is cached
-		if cachedResp, found := cache[r.URL.Path]; found {
-			w.Header().Set("Content-Type", "application/json")
-			w.Write(cachedResp)
+		route, ok := routes.Match(r.URL.Path)
+		if !ok || !route.Cache.Enabled() {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if r.Method != http.MethodGet {
+			recorder := &statusRecorder{ResponseWriter: w, statusCode: http.StatusOK}
+			next.ServeHTTP(recorder, r)
+			if r.Method != http.MethodHead && r.Method != http.MethodOptions && recorder.statusCode < http.StatusBadRequest {
+				if err := store.Purge(context.WithoutCancel(r.Context()), route.Prefix); err != nil {
+					log.Printf("Failed to purge cache of %s: %v", route.Prefix, err)
+				}
+			}
+			return
+		}
+
+		if r.ContentLength != 0 {
+			w.Header().Set("X-Cache", "BYPASS")
+			next.ServeHTTP(w, r)
 			return
 		}
 
-		// Capture the response using a buffer
-		recorder := &responseRecorder{ResponseWriter: w, statusCode: http.StatusOK, body: &strings.Builder{}}
-		next.ServeHTTP(recorder, r)
+		key := cacheKey(r, route)
+		requestDirectives := parseCacheControl(r.Header.Get("Cache-Control"))
+		if !requestDirectives.has("no-cache") && !requestDirectives.has("no-store") && requestDirectives["max-age"] != "0" {
+			entry, err := store.Get(r.Context(), route.Prefix, key)
+			if err != nil {
+				log.Printf("Cache lookup failed for %s: %v", r.URL.Path, err)
+			}
+			if entry != nil {
+				writeEntry(w, r, entry, "HIT")
+				return
+			}
+		}
 
-		// Cache the response if it's a success
-		if recorder.statusCode == http.StatusOK {
-			cache[r.URL.Path] = []byte(recorder.body.String())
+		// Only a response that may be cached is held back, to add its ETag and store it once
+		// complete. Any other response goes straight to the client.
+		writer := &cacheWriter{w: w, header: make(http.Header), route: route, requestDirectives: requestDirectives}
+		next.ServeHTTP(writer, r)
+		if !writer.finish() {
+			return
 		}
 
-		// Write the response
-		w.Header().Set("Content-Type", "application/json")
-		w.Write([]byte(recorder.body.String()))
+		entry := &cache.Entry{
+			Path:     r.URL.Path,
+			Status:   writer.statusCode,
+			Body:     writer.body.Bytes(),
+			StoredAt: time.Now(),
+		}
+		entry.Header = writer.header.Clone()
+		for _, name := range uncachedHeaders {
+			entry.Header.Del(name)
+		}
+		entry.ETag = writer.header.Get("ETag")
+		if entry.ETag == "" {
+			sum := sha256.Sum256(entry.Body)
+			entry.ETag = `"` + hex.EncodeToString(sum[:16]) + `"`
+			entry.Header.Set("ETag", entry.ETag)
+			writer.header.Set("ETag", entry.ETag)
+		}
+		entry.Expires = entry.StoredAt.Add(writer.ttl)
+
+		if err := store.Set(r.Context(), route.Prefix, key, entry); err != nil {
+			log.Printf("Failed to cache %s: %v", r.URL.Path, err)
+		}
+
+		writeResponse(w, r, &cache.Entry{
+			Status: entry.Status,
+			Header: writer.header,
+			Body:   entry.Body,
+			ETag:   entry.ETag,
+		}, "MISS")
 	}
 }
 
-type responseRecorder struct {
+// cacheKey identifies a response variant. The key is hashed so it can be used as a Redis key.
+func cacheKey(r *http.Request, route *config.Route) string {
+	var key strings.Builder
+	key.WriteString(r.Method + " " + r.URL.Path + "?" + r.URL.Query().Encode())
+	for _, name := range route.Cache.Vary {
+		key.WriteString("\n" + name + ": " + strings.Join(r.Header.Values(name), ","))
+	}
+
+	// Authenticated responses may be specific to the caller, so every caller gets its own copy
+	if auth := r.Header.Get("Authorization"); auth != "" {
+		if identity, ok := IdentityFromContext(r.Context()); ok && identity.UserID != "" {
+			key.WriteString("\nuser: " + identity.UserID)
+		} else {
+			sum := sha256.Sum256([]byte(auth))
+			key.WriteString("\nauthorization: " + hex.EncodeToString(sum[:]))
+		}
+	}
+
+	sum := sha256.Sum256([]byte(key.String()))
+	return hex.EncodeToString(sum[:])
+}
+
+// cacheTTL decides from its status and headers whether a response may be stored and for how long
+func cacheTTL(statusCode int, header http.Header, route *config.Route, requestDirectives cacheControl) (time.Duration, bool) {
+	if statusCode != http.StatusOK || requestDirectives.has("no-store") {
+		return 0, false
+	}
+	if header.Get("Set-Cookie") != "" {
+		return 0, false
+	}
+
+	for _, value := range header.Values("Vary") {
+		for _, name := range strings.Split(value, ",") {
+			name = http.CanonicalHeaderKey(strings.TrimSpace(name))
+			if name == "" || name == "Authorization" {
+				continue
+			}
+			if name == "*" || !containsString(route.Cache.Vary, name) {
+				return 0, false
+			}
+		}
+	}
+
+	directives := parseCacheControl(header.Get("Cache-Control"))
+	if directives.has("no-store") || directives.has("no-cache") || directives.has("private") {
+		return 0, false
+	}
+
+	ttl := route.Cache.TTL
+	for _, name := range []string{"s-maxage", "max-age"} {
+		if value, ok := directives[name]; ok {
+			seconds, err := strconv.Atoi(value)
+			if err != nil || seconds <= 0 {
+				return 0, false
+			}
+			ttl = time.Duration(seconds) * time.Second
+			break
+		}
+	}
+	return ttl, true
+}
+
+func writeEntry(w http.ResponseWriter, r *http.Request, entry *cache.Entry, status string) {
+	age := int(time.Since(entry.StoredAt).Seconds())
+	w.Header().Set("Age", strconv.Itoa(age))
+	writeResponse(w, r, entry, status)
+}
+
+func writeResponse(w http.ResponseWriter, r *http.Request, entry *cache.Entry, status string) {
+	for name, values := range entry.Header {
+		for _, value := range values {
+			w.Header().Add(name, value)
+		}
+	}
+	w.Header().Set("X-Cache", status)
+
+	if entry.ETag != "" && etagMatches(r.Header.Get("If-None-Match"), entry.ETag) {
+		w.Header().Del("Content-Length")
+		w.Header().Del("Content-Type")
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.WriteHeader(entry.Status)
+	w.Write(entry.Body)
+}
+
+// etagMatches applies the weak comparison used for If-None-Match
+func etagMatches(ifNoneMatch, etag string) bool {
+	if ifNoneMatch == "" {
+		return false
+	}
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// cacheControl holds the directives of a Cache-Control header, names are lower case
+type cacheControl map[string]string
+
+func parseCacheControl(header string) cacheControl {
+	directives := make(cacheControl)
+	for _, part := range strings.Split(header, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		name, value, _ := strings.Cut(part, "=")
+		directives[strings.ToLower(strings.TrimSpace(name))] = strings.Trim(strings.TrimSpace(value), `"`)
+	}
+	return directives
+}
+
+func (cc cacheControl) has(name string) bool {
+	_, ok := cc[name]
+	return ok
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// cacheWriter decides when the upstream writes the header whether the response may be cached.
+// Such a response is buffered, any other is passed through as it comes, and so is a buffered
+// one that grows beyond maxCachedBodyBytes.
+type cacheWriter struct {
+	w                 http.ResponseWriter
+	route             *config.Route
+	requestDirectives cacheControl
+
+	header      http.Header
+	statusCode  int
+	wroteHeader bool
+	buffering   bool
+	ttl         time.Duration
+	body        bytes.Buffer
+}
+
+func (c *cacheWriter) Header() http.Header {
+	return c.header
+}
+
+func (c *cacheWriter) WriteHeader(code int) {
+	if c.wroteHeader {
+		return
+	}
+	c.wroteHeader = true
+	c.statusCode = code
+
+	c.ttl, c.buffering = cacheTTL(code, c.header, c.route, c.requestDirectives)
+	if !c.buffering {
+		c.passThrough()
+	}
+}
+
+func (c *cacheWriter) Write(p []byte) (int, error) {
+	if !c.wroteHeader {
+		c.WriteHeader(http.StatusOK)
+	}
+	if !c.buffering {
+		return c.w.Write(p)
+	}
+	if c.body.Len()+len(p) <= maxCachedBodyBytes {
+		return c.body.Write(p)
+	}
+
+	// Too large to cache, send what was held back and stream the rest
+	c.buffering = false
+	c.passThrough()
+	if _, err := c.w.Write(c.body.Bytes()); err != nil {
+		return 0, err
+	}
+	c.body.Reset()
+	return c.w.Write(p)
+}
+
+// Flush sends what was written so far, unless the response is held back to be cached
+func (c *cacheWriter) Flush() {
+	if c.buffering {
+		return
+	}
+	if flusher, ok := c.w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// passThrough writes the header to the client, the body follows as it is written
+func (c *cacheWriter) passThrough() {
+	for name, values := range c.header {
+		c.w.Header()[name] = values
+	}
+	c.w.Header().Set("X-Cache", "BYPASS")
+	c.w.WriteHeader(c.statusCode)
+}
+
+// finish reports whether the response was held back to be cached. Otherwise it has been sent.
+func (c *cacheWriter) finish() bool {
+	if !c.wroteHeader {
+		c.WriteHeader(http.StatusOK)
+	}
+	return c.buffering
+}
+
+// statusRecorder passes the response through and remembers its status code
+type statusRecorder struct {
 	http.ResponseWriter
 	statusCode int
-	body       *strings.Builder
 }
 
-func (rec *responseRecorder) WriteHeader(code int) {
+func (rec *statusRecorder) WriteHeader(code int) {
 	rec.statusCode = code
 	rec.ResponseWriter.WriteHeader(code)
 }
-
-func (rec *responseRecorder) Write(b []byte) (int, error) {
-	rec.body.Write(b)
-	return rec.ResponseWriter.Write(b)
-}
@@ -0,0 +1,98 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"th3y3m/e-commerce-microservices/service/api_gateway/cache"
+	"th3y3m/e-commerce-microservices/service/api_gateway/config"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testRoutes(t *testing.T, route config.Route) *config.Store {
+	route.Upstreams = []config.Upstream{{URL: "http://product:8081", Weight: 1}}
+	table := &config.RouteTable{Routes: []config.Route{route}}
+	require.NoError(t, table.Validate())
+	return config.NewStore(table)
+}
+
+func TestCacheMiddlewareStoresCacheableResponses(t *testing.T) {
+	routes := testRoutes(t, config.Route{Prefix: "/api/products", Cache: config.Cache{TTL: time.Minute}})
+	calls := 0
+	handler := CacheMiddleware(cache.NewLRU(1<<20), routes, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Write([]byte(`[{"product_id": 1}]`))
+	})
+
+	rec := httptest.NewRecorder()
+	handler(rec, httptest.NewRequest(http.MethodGet, "/api/products", nil))
+	assert.Equal(t, "MISS", rec.Header().Get("X-Cache"))
+	assert.NotEmpty(t, rec.Header().Get("ETag"))
+
+	rec = httptest.NewRecorder()
+	handler(rec, httptest.NewRequest(http.MethodGet, "/api/products", nil))
+	assert.Equal(t, "HIT", rec.Header().Get("X-Cache"))
+	assert.Equal(t, `[{"product_id": 1}]`, rec.Body.String())
+	assert.Equal(t, 1, calls)
+}
+
+func TestCacheMiddlewareStreamsUncacheableResponses(t *testing.T) {
+	routes := testRoutes(t, config.Route{Prefix: "/api/products", Cache: config.Cache{TTL: time.Minute}})
+
+	rec := httptest.NewRecorder()
+	handler := CacheMiddleware(cache.NewLRU(1<<20), routes, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "no-store")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("first"))
+		// Already sent to the client while the upstream is still writing
+		assert.Equal(t, "first", rec.Body.String())
+		assert.Equal(t, "BYPASS", rec.Header().Get("X-Cache"))
+		w.Write([]byte(" second"))
+	})
+
+	handler(rec, httptest.NewRequest(http.MethodGet, "/api/products", nil))
+	assert.Equal(t, "first second", rec.Body.String())
+}
+
+func TestCacheMiddlewarePassesLargeResponsesThrough(t *testing.T) {
+	routes := testRoutes(t, config.Route{Prefix: "/api/products", Cache: config.Cache{TTL: time.Minute}})
+	chunk := strings.Repeat("x", maxCachedBodyBytes/2+1)
+	calls := 0
+	handler := CacheMiddleware(cache.NewLRU(8<<20), routes, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Write([]byte(chunk))
+		w.Write([]byte(chunk))
+	})
+
+	for i := 0; i < 2; i++ {
+		rec := httptest.NewRecorder()
+		handler(rec, httptest.NewRequest(http.MethodGet, "/api/products", nil))
+		assert.Equal(t, "BYPASS", rec.Header().Get("X-Cache"))
+		assert.Equal(t, 2*len(chunk), rec.Body.Len())
+	}
+	assert.Equal(t, 2, calls)
+}
+
+func TestCacheMiddlewareBypassesGetsWithABody(t *testing.T) {
+	routes := testRoutes(t, config.Route{Prefix: "/api/products", Cache: config.Cache{TTL: time.Minute}})
+	calls := 0
+	handler := CacheMiddleware(cache.NewLRU(1<<20), routes, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		w.Write(body)
+	})
+
+	for _, body := range []string{`{"paging": {"page_index": 1}}`, `{"paging": {"page_index": 2}}`} {
+		rec := httptest.NewRecorder()
+		handler(rec, httptest.NewRequest(http.MethodGet, "/api/products", strings.NewReader(body)))
+		assert.Equal(t, "BYPASS", rec.Header().Get("X-Cache"))
+		assert.Equal(t, body, rec.Body.String())
+	}
+	assert.Equal(t, 2, calls)
+}
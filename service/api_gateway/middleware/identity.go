@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"th3y3m/e-commerce-microservices/pkg/authz"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type identityKey struct{}
+
+// Identity is the authenticated caller, taken from the JWT claims
+type Identity struct {
+	UserID string
+	Role   string
+	Email  string
+}
+
+func identityFromClaims(claims jwt.MapClaims) Identity {
+	identity := Identity{}
+	if id, ok := claims["Id"]; ok && id != nil {
+		identity.UserID = fmt.Sprint(id)
+		// Numeric claims are decoded as float64, print them without an exponent
+		if f, ok := id.(float64); ok {
+			identity.UserID = fmt.Sprintf("%.0f", f)
+		}
+	}
+	identity.Role, _ = claims["Role"].(string)
+	identity.Email, _ = claims["Email"].(string)
+	return identity
+}
+
+func withIdentity(ctx context.Context, identity Identity) context.Context {
+	return context.WithValue(ctx, identityKey{}, identity)
+}
+
+// IdentityFromContext returns the caller attached by AuthMiddleware, if any
+func IdentityFromContext(ctx context.Context) (Identity, bool) {
+	identity, ok := ctx.Value(identityKey{}).(Identity)
+	return identity, ok
+}
+
+// signIdentity sets the X-User-* headers, signed with keys, the services trust instead of the token
+func signIdentity(keys *authz.Keys, header http.Header, identity Identity) error {
+	userID, err := strconv.ParseInt(identity.UserID, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid user ID %q: %w", identity.UserID, err)
+	}
+	return keys.SignIdentity(header, authz.Caller{UserID: userID, Role: identity.Role, Email: identity.Email})
+}
@@ -1,70 +1,126 @@
 package middleware
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"strings"
+	"th3y3m/e-commerce-microservices/pkg/authz"
+	"th3y3m/e-commerce-microservices/pkg/logging"
+	"th3y3m/e-commerce-microservices/service/api_gateway/config"
 
 	"github.com/casbin/casbin/v2"
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
-	"github.com/spf13/viper"
+	"github.com/sirupsen/logrus"
 )
 
-// AuthMiddleware validates JWT and applies Casbin authorization
-func AuthMiddleware(enforcer *casbin.Enforcer) gin.HandlerFunc {
+// Casbin subject of requests without a valid token
+const anonymousSubject = "anonymous"
+
+// AuthMiddleware validates the JWT when one is sent and applies Casbin authorization.
+// Requests without a token are authorized as the anonymous subject, so public paths are
+// decided by the policy. A token that fails verification is rejected, even on public paths,
+// rather than silently dropped. Routes with auth_required always need a token. Tokens are
+// signed with jwtSecret, and the identity passed on to the services is signed with keys.
+func AuthMiddleware(enforcer *casbin.SyncedEnforcer, routes *config.Store, jwtSecret string, keys *authz.Keys) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// Get the JWT secret from the environment variables
-		jwtSecret := []byte(viper.GetString("JWT_SECRET"))
-		tokenStr := c.GetHeader("Authorization")
-		if tokenStr == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header missing"})
+		// Only identity signed below may reach the services, and clients never act as one
+		authz.StripIdentity(c.Request.Header)
+		c.Request.Header.Del(authz.HeaderServiceToken)
+
+		claims, tokenErr := parseToken(c.GetHeader("Authorization"), []byte(jwtSecret))
+		if tokenErr != nil && !errors.Is(tokenErr, errMissingToken) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": tokenErr.Error()})
 			c.Abort()
 			return
 		}
 
-		// Remove "Bearer " prefix
-		tokenStr = strings.TrimPrefix(tokenStr, "Bearer ")
-
-		// Parse and validate the JWT
-		token, err := jwt.Parse(tokenStr, func(token *jwt.Token) (interface{}, error) {
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-			}
-			return jwtSecret, nil
-		})
-
-		if err != nil || !token.Valid {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
-			c.Abort()
-			return
+		sub := anonymousSubject
+		if tokenErr == nil {
+			sub = claims["Role"].(string)
 		}
 
-		// Extract claims and role
-		claims, ok := token.Claims.(jwt.MapClaims)
-		if !ok || claims["Role"] == nil {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid claims"})
+		if route, ok := routes.Match(c.Request.URL.Path); ok && route.AuthRequired && tokenErr != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": tokenErr.Error()})
 			c.Abort()
 			return
 		}
 
-		role := claims["Role"].(string)
 		obj := c.Request.URL.Path
 		act := c.Request.Method
 
 		// Casbin authorization
-		allowed, err := enforcer.Enforce(role, obj, act)
+		allowed, err := enforcer.Enforce(sub, obj, act)
 		if err != nil {
-			fmt.Printf("Error during Casbin enforcement: %v\n", err)
+			logrus.WithContext(c.Request.Context()).Errorf("Error during Casbin enforcement: %v", err)
 		}
 		if !allowed {
-			fmt.Printf("Access denied for role: %s, object: %s, action: %s\n", role, obj, act)
+			// Anonymous callers may be allowed once they authenticate
+			if tokenErr != nil {
+				c.JSON(http.StatusUnauthorized, gin.H{"error": tokenErr.Error()})
+				c.Abort()
+				return
+			}
+			logrus.WithContext(c.Request.Context()).Warnf("Access denied for role: %s, object: %s, action: %s", sub, obj, act)
 			c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
 			c.Abort()
 			return
 		}
 
+		// Make the caller available to the handlers and the services behind the gateway
+		if tokenErr == nil {
+			identity := identityFromClaims(claims)
+			if err := signIdentity(keys, c.Request.Header, identity); err != nil {
+				logrus.WithContext(c.Request.Context()).Errorf("Error signing identity headers: %v", err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal Server Error"})
+				c.Abort()
+				return
+			}
+			if info := logging.RequestInfoFromContext(c.Request.Context()); info != nil {
+				info.UserID = identity.UserID
+			}
+			c.Request = c.Request.WithContext(withIdentity(c.Request.Context(), identity))
+		}
+
 		// Proceed to the next handler if authorized
 		c.Next()
 	}
 }
+
+var (
+	errMissingToken  = errors.New("Authorization header missing")
+	errInvalidToken  = errors.New("Invalid token")
+	errInvalidClaims = errors.New("Invalid claims")
+)
+
+// parseToken validates the bearer token and returns its claims
+func parseToken(header string, jwtSecret []byte) (jwt.MapClaims, error) {
+	if header == "" {
+		return nil, errMissingToken
+	}
+
+	// Remove "Bearer " prefix
+	tokenStr := strings.TrimPrefix(header, "Bearer ")
+
+	// Parse and validate the JWT
+	token, err := jwt.Parse(tokenStr, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		return jwtSecret, nil
+	})
+	if err != nil || !token.Valid {
+		return nil, errInvalidToken
+	}
+
+	// Extract claims and role
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return nil, errInvalidClaims
+	}
+	if role, ok := claims["Role"].(string); !ok || role == "" || role == anonymousSubject {
+		return nil, errInvalidClaims
+	}
+	return claims, nil
+}
@@ -0,0 +1,126 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"th3y3m/e-commerce-microservices/pkg/authz"
+	pkgconfig "th3y3m/e-commerce-microservices/pkg/config"
+	"th3y3m/e-commerce-microservices/pkg/util"
+	"th3y3m/e-commerce-microservices/service/api_gateway/config"
+
+	"github.com/casbin/casbin/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testJWTSecret = "test-jwt-secret"
+
+var testKeys = authz.NewKeys(pkgconfig.Auth{IdentitySecret: "test-identity-secret", ServiceTokenSecret: "test-service-secret"})
+
+// authRouter serves every path behind AuthMiddleware and reports the caller it saw
+func authRouter(t *testing.T) *gin.Engine {
+	enforcer, err := casbin.NewSyncedEnforcer("../rbac/rbac_model.conf")
+	require.NoError(t, err)
+	_, err = enforcer.AddPolicies([][]string{
+		{anonymousSubject, "/api/products", "GET"},
+		{anonymousSubject, "/api/carts", "GET"},
+		{"customer", "/api/orders", "GET"},
+	})
+	require.NoError(t, err)
+	_, err = enforcer.AddGroupingPolicy("customer", anonymousSubject)
+	require.NoError(t, err)
+
+	table := &config.RouteTable{Routes: []config.Route{
+		{Prefix: "/api/products", Upstreams: []config.Upstream{{URL: "http://product:8081", Weight: 1}}},
+		{Prefix: "/api/carts", AuthRequired: true, Upstreams: []config.Upstream{{URL: "http://cart:8082", Weight: 1}}},
+		{Prefix: "/api/orders", AuthRequired: true, Upstreams: []config.Upstream{{URL: "http://order:8083", Weight: 1}}},
+	}}
+	require.NoError(t, table.Validate())
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(AuthMiddleware(enforcer, config.NewStore(table), testJWTSecret, testKeys))
+	r.NoRoute(func(c *gin.Context) {
+		identity, _ := IdentityFromContext(c.Request.Context())
+		c.String(http.StatusOK, identity.UserID+":"+c.GetHeader(authz.HeaderUserRole))
+	})
+	return r
+}
+
+func serve(r *gin.Engine, method, path, token string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, nil)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestAuthMiddlewareAllowsAnonymousOnPublicPaths(t *testing.T) {
+	r := authRouter(t)
+
+	rec := serve(r, http.MethodGet, "/api/products", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, ":", rec.Body.String())
+}
+
+func TestAuthMiddlewareRejectsForgedIdentity(t *testing.T) {
+	r := authRouter(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/products", nil)
+	req.Header.Set(authz.HeaderUserRole, "admin")
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, ":", rec.Body.String())
+}
+
+func TestAuthMiddlewareNeedsATokenOutsidePublicPaths(t *testing.T) {
+	r := authRouter(t)
+
+	rec := serve(r, http.MethodGet, "/api/orders", "")
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+func TestAuthMiddlewareRejectsInvalidTokensOnPublicPaths(t *testing.T) {
+	r := authRouter(t)
+
+	forged, err := util.GenerateJWT("another-secret", 7, "customer", "a@b.c")
+	require.NoError(t, err)
+
+	rec := serve(r, http.MethodGet, "/api/products", forged)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	rec = serve(r, http.MethodGet, "/api/products", "not-a-jwt")
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+func TestAuthMiddlewareEnforcesAuthRequired(t *testing.T) {
+	r := authRouter(t)
+
+	// The policy lets anonymous callers in, the route does not
+	rec := serve(r, http.MethodGet, "/api/carts", "")
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	token, err := util.GenerateJWT(testJWTSecret, 7, "customer", "a@b.c")
+	require.NoError(t, err)
+	rec = serve(r, http.MethodGet, "/api/carts", token)
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestAuthMiddlewareSignsTheCaller(t *testing.T) {
+	r := authRouter(t)
+
+	token, err := util.GenerateJWT(testJWTSecret, 7, "customer", "a@b.c")
+	require.NoError(t, err)
+
+	rec := serve(r, http.MethodGet, "/api/orders", token)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "7:customer", rec.Body.String())
+
+	rec = serve(r, http.MethodDelete, "/api/orders", token)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+}
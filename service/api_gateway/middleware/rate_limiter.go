@@ -1,31 +1,81 @@
 package middleware
 
 import (
+	"encoding/json"
+	"log"
+	"math"
+	"net"
 	"net/http"
+	"strconv"
+	"th3y3m/e-commerce-microservices/service/api_gateway/config"
+	"th3y3m/e-commerce-microservices/service/api_gateway/ratelimit"
 	"time"
 )
 
-var rateLimiter = make(map[string]time.Time)
+const apiKeyHeader = "X-API-Key"
 
-// Basic rate limiting logic
-func RateLimit(next http.HandlerFunc) http.HandlerFunc {
+// RateLimit applies token bucket limits to every proxied request. Each client has a bucket
+// sized by its API key, role or the default limit, plus one per route when the route sets
+// its own limit. Clients are API keys, then authenticated users, then remote IPs.
+func RateLimit(limiter ratelimit.Limiter, routes *config.Store, next http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		clientIP := r.RemoteAddr
-		now := time.Now()
-
-		// Check if the client has made a request recently
-		if lastRequestTime, exists := rateLimiter[clientIP]; exists {
-			// Allow one request per second
-			if now.Sub(lastRequestTime) < time.Second {
-				http.Error(w, "Rate limit exceeded", http.StatusTooManyRequests)
-				return
-			}
+		table := routes.Table()
+		client, limit := clientLimit(r, &table.RateLimit)
+
+		buckets := []ratelimit.Bucket{{Key: client + ":global", Limit: limit}}
+		if route, ok := table.Match(r.URL.Path); ok && route.RateLimit.Enabled() {
+			buckets = append(buckets, ratelimit.Bucket{Key: client + ":route:" + route.Prefix, Limit: route.RateLimit})
+		}
+
+		// A request denied by one bucket takes no token from the others
+		result, err := limiter.Allow(r.Context(), buckets...)
+		if err != nil {
+			// Never reject traffic because the limiter itself is broken
+			log.Printf("Rate limiting skipped for %s: %v", client, err)
+			next.ServeHTTP(w, r)
+			return
 		}
 
-		// Record current request time
-		rateLimiter[clientIP] = now
+		setRateLimitHeaders(w, result)
+		if !result.Allowed {
+			w.Header().Set("Retry-After", strconv.Itoa(ceilSeconds(result.RetryAfter)))
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusTooManyRequests)
+			json.NewEncoder(w).Encode(map[string]string{"error": "Rate limit exceeded"})
+			return
+		}
 
 		// Forward to the next handler if within the rate limit
 		next.ServeHTTP(w, r)
 	}
 }
+
+// clientLimit identifies the caller and picks the limit that applies to it
+func clientLimit(r *http.Request, limits *config.RateLimit) (string, config.Limit) {
+	if raw := r.Header.Get(apiKeyHeader); raw != "" {
+		if key, ok := limits.LookupAPIKey(raw); ok {
+			return "key:" + key.Name, key.Limit
+		}
+	}
+
+	if identity, ok := IdentityFromContext(r.Context()); ok && identity.UserID != "" {
+		return "user:" + identity.UserID, limits.ForRole(identity.Role)
+	}
+
+	// RemoteAddr includes the port, which changes with every connection
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return "ip:" + host, limits.Default
+}
+
+func setRateLimitHeaders(w http.ResponseWriter, result ratelimit.Result) {
+	w.Header().Set("X-RateLimit-Limit", strconv.Itoa(result.Limit))
+	w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(result.Remaining))
+	w.Header().Set("X-RateLimit-Reset", strconv.Itoa(ceilSeconds(result.Reset)))
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
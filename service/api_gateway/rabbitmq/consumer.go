@@ -0,0 +1,47 @@
+package rabbitmq
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"th3y3m/e-commerce-microservices/pkg/broker"
+	"th3y3m/e-commerce-microservices/pkg/events"
+	"th3y3m/e-commerce-microservices/pkg/logging"
+	"th3y3m/e-commerce-microservices/service/api_gateway/cache"
+	"th3y3m/e-commerce-microservices/service/api_gateway/config"
+
+	"github.com/google/uuid"
+)
+
+// cacheInvalidations subscribes the replica to the data services changed. Every replica has a
+// queue of its own, since each one purges its own cache when Redis is not configured.
+func cacheInvalidations() broker.Subscription {
+	instance, err := os.Hostname()
+	if err != nil || instance == "" {
+		instance = uuid.NewString()
+	}
+	return broker.Subscription{
+		Queue:     "gateway_cache_invalidation." + instance,
+		Events:    []string{events.TypeCacheInvalidated},
+		Exclusive: true,
+	}
+}
+
+// ConsumeCacheInvalidations purges the cached responses of the route owning the path of
+// every message, so services can drop stale responses after changing data.
+func ConsumeCacheInvalidations(ctx context.Context, b broker.Broker, store cache.Store, routes *config.Store) error {
+	return broker.Consume(ctx, b, cacheInvalidations(), func(ctx context.Context, _ *events.Envelope, event events.CacheInvalidated) error {
+		route, ok := routes.Match(event.Path)
+		if !ok {
+			return broker.Permanent(fmt.Errorf("no route for cache invalidation of %q", event.Path))
+		}
+
+		if err := store.Purge(ctx, route.Prefix); err != nil {
+			logging.Logger().WithContext(ctx).Errorf("Failed to purge cache of %s: %v", route.Prefix, err)
+			return err
+		}
+
+		logging.Logger().WithContext(ctx).Infof("Purged cache of %s after %s", route.Prefix, event.Reason)
+		return nil
+	})
+}
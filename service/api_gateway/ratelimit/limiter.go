@@ -0,0 +1,94 @@
+package ratelimit
+
+import (
+	"context"
+	"log"
+	"math"
+	"sync/atomic"
+	"th3y3m/e-commerce-microservices/service/api_gateway/config"
+	"time"
+)
+
+// Result is the state of a bucket after a request has been counted
+type Result struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	RetryAfter time.Duration
+	// Reset is how long it takes until the bucket is full again
+	Reset time.Duration
+}
+
+// Bucket identifies a token bucket and the limit it is kept with
+type Bucket struct {
+	Key   string
+	Limit config.Limit
+}
+
+// Limiter takes one token from each of the buckets, and none at all unless every bucket has
+// one. The result is the one of the most restrictive bucket.
+type Limiter interface {
+	Allow(ctx context.Context, buckets ...Bucket) (Result, error)
+}
+
+// newResult derives the response headers values from the tokens left in a bucket
+func newResult(allowed bool, tokens float64, limit config.Limit) Result {
+	rate := limit.Rate()
+	result := Result{
+		Allowed:   allowed,
+		Limit:     limit.Burst,
+		Remaining: int(math.Floor(tokens)),
+		Reset:     time.Duration((float64(limit.Burst) - tokens) / rate * float64(time.Second)),
+	}
+	if !allowed {
+		result.RetryAfter = time.Duration((1 - tokens) / rate * float64(time.Second))
+	}
+	return result
+}
+
+// mostRestrictive returns the result a client should see: the denying bucket it has to wait
+// longest for, or the bucket with the fewest tokens left
+func mostRestrictive(results []Result) Result {
+	var out Result
+	for i, result := range results {
+		switch {
+		case i == 0:
+			out = result
+		case out.Allowed && !result.Allowed:
+			out = result
+		case !out.Allowed && !result.Allowed && result.RetryAfter > out.RetryAfter:
+			out = result
+		case out.Allowed && result.Allowed && result.Remaining < out.Remaining:
+			out = result
+		}
+	}
+	return out
+}
+
+// FallbackLimiter uses the primary limiter and switches to the fallback while the primary
+// is failing, so that a Redis outage degrades to per-replica limits instead of no limits.
+type FallbackLimiter struct {
+	primary  Limiter
+	fallback Limiter
+	cooldown time.Duration
+
+	downUntil atomic.Int64
+}
+
+func NewFallbackLimiter(primary, fallback Limiter, cooldown time.Duration) *FallbackLimiter {
+	return &FallbackLimiter{primary: primary, fallback: fallback, cooldown: cooldown}
+}
+
+func (f *FallbackLimiter) Allow(ctx context.Context, buckets ...Bucket) (Result, error) {
+	if time.Now().UnixNano() >= f.downUntil.Load() {
+		result, err := f.primary.Allow(ctx, buckets...)
+		if err == nil {
+			return result, nil
+		}
+		// Only the request that flips the state logs, the others just use the fallback
+		if f.downUntil.Swap(time.Now().Add(f.cooldown).UnixNano()) <= time.Now().UnixNano() {
+			log.Printf("Rate limiter backend failed, using in-memory limits for %v: %v", f.cooldown, err)
+		}
+	}
+	return f.fallback.Allow(ctx, buckets...)
+}
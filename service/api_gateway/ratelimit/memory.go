@@ -0,0 +1,80 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+const memorySweepInterval = time.Minute
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+	full   time.Time
+}
+
+// MemoryLimiter keeps token buckets in process. Limits are per gateway replica.
+type MemoryLimiter struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+func NewMemoryLimiter() *MemoryLimiter {
+	return &MemoryLimiter{
+		buckets:   make(map[string]*bucket),
+		lastSweep: time.Now(),
+		now:       time.Now,
+	}
+}
+
+func (m *MemoryLimiter) Allow(_ context.Context, buckets ...Bucket) (Result, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	m.sweep(now)
+
+	// Refill every bucket before taking from any, so a denied request costs nothing
+	states := make([]*bucket, len(buckets))
+	allowed := true
+	for i, spec := range buckets {
+		b, ok := m.buckets[spec.Key]
+		if !ok {
+			b = &bucket{tokens: float64(spec.Limit.Burst), last: now}
+			m.buckets[spec.Key] = b
+		}
+
+		elapsed := now.Sub(b.last).Seconds()
+		b.tokens = math.Min(float64(spec.Limit.Burst), b.tokens+elapsed*spec.Limit.Rate())
+		b.last = now
+		states[i] = b
+		allowed = allowed && b.tokens >= 1
+	}
+
+	results := make([]Result, len(buckets))
+	for i, b := range states {
+		if allowed {
+			b.tokens--
+		}
+		results[i] = newResult(allowed, b.tokens, buckets[i].Limit)
+		b.full = now.Add(results[i].Reset)
+	}
+	return mostRestrictive(results), nil
+}
+
+// sweep drops buckets that have refilled completely, they are equivalent to new ones
+func (m *MemoryLimiter) sweep(now time.Time) {
+	if now.Sub(m.lastSweep) < memorySweepInterval {
+		return
+	}
+	m.lastSweep = now
+	for key, b := range m.buckets {
+		if now.After(b.full) {
+			delete(m.buckets, key)
+		}
+	}
+}
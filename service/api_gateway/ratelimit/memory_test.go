@@ -0,0 +1,77 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"th3y3m/e-commerce-microservices/service/api_gateway/config"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryLimiterBurstAndRefill(t *testing.T) {
+	now := time.Unix(0, 0)
+	limiter := NewMemoryLimiter()
+	limiter.now = func() time.Time { return now }
+	limit := config.Limit{Requests: 1, Per: time.Second, Burst: 2}
+
+	for i := 0; i < 2; i++ {
+		result, err := limiter.Allow(context.Background(), Bucket{Key: "client", Limit: limit})
+		assert.NoError(t, err)
+		assert.True(t, result.Allowed)
+	}
+
+	result, _ := limiter.Allow(context.Background(), Bucket{Key: "client", Limit: limit})
+	assert.False(t, result.Allowed)
+	assert.Equal(t, 0, result.Remaining)
+	assert.Equal(t, time.Second, result.RetryAfter)
+
+	// Other clients have their own bucket
+	result, _ = limiter.Allow(context.Background(), Bucket{Key: "other", Limit: limit})
+	assert.True(t, result.Allowed)
+
+	now = now.Add(time.Second)
+	result, _ = limiter.Allow(context.Background(), Bucket{Key: "client", Limit: limit})
+	assert.True(t, result.Allowed)
+}
+
+func TestMemoryLimiterTakesNoTokenWhenABucketDenies(t *testing.T) {
+	now := time.Unix(0, 0)
+	limiter := NewMemoryLimiter()
+	limiter.now = func() time.Time { return now }
+	global := Bucket{Key: "client:global", Limit: config.Limit{Requests: 1, Per: time.Second, Burst: 3}}
+	route := Bucket{Key: "client:route", Limit: config.Limit{Requests: 1, Per: time.Second, Burst: 1}}
+
+	result, _ := limiter.Allow(context.Background(), global, route)
+	assert.True(t, result.Allowed)
+	// The route bucket is the most restrictive
+	assert.Equal(t, 0, result.Remaining)
+
+	for i := 0; i < 3; i++ {
+		result, _ = limiter.Allow(context.Background(), global, route)
+		assert.False(t, result.Allowed)
+	}
+
+	// The denied requests left the global bucket alone
+	result, _ = limiter.Allow(context.Background(), global)
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 1, result.Remaining)
+}
+
+type failingLimiter struct{}
+
+func (failingLimiter) Allow(context.Context, ...Bucket) (Result, error) {
+	return Result{}, assert.AnError
+}
+
+func TestFallbackLimiterUsesFallbackWhenPrimaryFails(t *testing.T) {
+	limiter := NewFallbackLimiter(failingLimiter{}, NewMemoryLimiter(), time.Minute)
+	limit := config.Limit{Requests: 1, Per: time.Second, Burst: 1}
+
+	result, err := limiter.Allow(context.Background(), Bucket{Key: "client", Limit: limit})
+	assert.NoError(t, err)
+	assert.True(t, result.Allowed)
+
+	result, _ = limiter.Allow(context.Background(), Bucket{Key: "client", Limit: limit})
+	assert.False(t, result.Allowed)
+}
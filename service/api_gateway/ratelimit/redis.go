@@ -0,0 +1,88 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// tokenBuckets refills the buckets and takes one token from each of them atomically, only when
+// every bucket has one. ARGV holds the rate and burst of each key in turn. The Redis clock is
+// used so that every gateway replica sees the same time. Tokens are returned as strings because
+// Redis truncates Lua numbers to integers.
+var tokenBuckets = redis.NewScript(`
+local time = redis.call('TIME')
+local now = tonumber(time[1]) + tonumber(time[2]) / 1000000
+
+local tokens = {}
+local allowed = 1
+for i, key in ipairs(KEYS) do
+  local rate = tonumber(ARGV[2 * i - 1])
+  local burst = tonumber(ARGV[2 * i])
+  local state = redis.call('HMGET', key, 'tokens', 'ts')
+  local current = tonumber(state[1]) or burst
+  local ts = tonumber(state[2]) or now
+  tokens[i] = math.min(burst, current + math.max(0, now - ts) * rate)
+  if tokens[i] < 1 then
+    allowed = 0
+  end
+end
+
+local reply = {allowed}
+for i, key in ipairs(KEYS) do
+  local rate = tonumber(ARGV[2 * i - 1])
+  local burst = tonumber(ARGV[2 * i])
+  if allowed == 1 then
+    tokens[i] = tokens[i] - 1
+  end
+  redis.call('HSET', key, 'tokens', tostring(tokens[i]), 'ts', tostring(now))
+  redis.call('PEXPIRE', key, math.ceil(burst / rate * 1000) + 1000)
+  reply[i + 1] = tostring(tokens[i])
+end
+return reply
+`)
+
+// RedisLimiter keeps token buckets in Redis so that limits are shared by all gateway replicas
+type RedisLimiter struct {
+	client  *redis.Client
+	timeout time.Duration
+}
+
+func NewRedisLimiter(client *redis.Client, timeout time.Duration) *RedisLimiter {
+	return &RedisLimiter{client: client, timeout: timeout}
+}
+
+func (l *RedisLimiter) Allow(ctx context.Context, buckets ...Bucket) (Result, error) {
+	ctx, cancel := context.WithTimeout(ctx, l.timeout)
+	defer cancel()
+
+	keys := make([]string, len(buckets))
+	args := make([]any, 0, 2*len(buckets))
+	for i, b := range buckets {
+		keys[i] = "ratelimit:" + b.Key
+		args = append(args, b.Limit.Rate(), b.Limit.Burst)
+	}
+
+	reply, err := tokenBuckets.Run(ctx, l.client, keys, args...).Slice()
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to run token bucket script: %w", err)
+	}
+	if len(reply) != len(buckets)+1 {
+		return Result{}, fmt.Errorf("unexpected token bucket reply: %v", reply)
+	}
+
+	allowed, _ := reply[0].(int64)
+	results := make([]Result, len(buckets))
+	for i, b := range buckets {
+		raw, _ := reply[i+1].(string)
+		tokens, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return Result{}, fmt.Errorf("invalid token count %q: %w", raw, err)
+		}
+		results[i] = newResult(allowed == 1, tokens, b.Limit)
+	}
+	return mostRestrictive(results), nil
+}
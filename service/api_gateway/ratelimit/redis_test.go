@@ -0,0 +1,36 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"th3y3m/e-commerce-microservices/service/api_gateway/config"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedisLimiterTakesNoTokenWhenABucketDenies(t *testing.T) {
+	srv := miniredis.RunT(t)
+	limiter := NewRedisLimiter(redis.NewClient(&redis.Options{Addr: srv.Addr()}), time.Second)
+	global := Bucket{Key: "client:global", Limit: config.Limit{Requests: 1, Per: time.Hour, Burst: 3}}
+	route := Bucket{Key: "client:route", Limit: config.Limit{Requests: 1, Per: time.Hour, Burst: 1}}
+
+	result, err := limiter.Allow(context.Background(), global, route)
+	require.NoError(t, err)
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 0, result.Remaining)
+
+	for i := 0; i < 3; i++ {
+		result, err = limiter.Allow(context.Background(), global, route)
+		require.NoError(t, err)
+		assert.False(t, result.Allowed)
+	}
+
+	result, err = limiter.Allow(context.Background(), global)
+	require.NoError(t, err)
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 1, result.Remaining)
+}
@@ -0,0 +1,130 @@
+package rbac
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/casbin/casbin/v2/model"
+	"github.com/casbin/casbin/v2/persist"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// casbinRule is one policy or role assignment, stored in the usual Casbin layout
+type casbinRule struct {
+	ID    uint   `gorm:"primaryKey;autoIncrement"`
+	Ptype string `gorm:"size:100;uniqueIndex:idx_casbin_rule"`
+	V0    string `gorm:"size:100;uniqueIndex:idx_casbin_rule"`
+	V1    string `gorm:"size:100;uniqueIndex:idx_casbin_rule"`
+	V2    string `gorm:"size:100;uniqueIndex:idx_casbin_rule"`
+	V3    string `gorm:"size:100;uniqueIndex:idx_casbin_rule"`
+	V4    string `gorm:"size:100;uniqueIndex:idx_casbin_rule"`
+	V5    string `gorm:"size:100;uniqueIndex:idx_casbin_rule"`
+}
+
+func (casbinRule) TableName() string {
+	return "casbin_rule"
+}
+
+func newCasbinRule(ptype string, rule []string) casbinRule {
+	r := casbinRule{Ptype: ptype}
+	values := []*string{&r.V0, &r.V1, &r.V2, &r.V3, &r.V4, &r.V5}
+	for i := 0; i < len(rule) && i < len(values); i++ {
+		*values[i] = rule[i]
+	}
+	return r
+}
+
+func (r casbinRule) values() []string {
+	values := []string{r.Ptype, r.V0, r.V1, r.V2, r.V3, r.V4, r.V5}
+	// Trailing empty fields are not part of the rule
+	for len(values) > 1 && values[len(values)-1] == "" {
+		values = values[:len(values)-1]
+	}
+	return values
+}
+
+// Adapter persists Casbin policies in Postgres through GORM
+type Adapter struct {
+	db *gorm.DB
+}
+
+var _ persist.Adapter = (*Adapter)(nil)
+
+// NewAdapter creates the casbin_rule table if needed
+func NewAdapter(db *gorm.DB) (*Adapter, error) {
+	if err := db.AutoMigrate(&casbinRule{}); err != nil {
+		return nil, fmt.Errorf("failed to migrate casbin_rule: %w", err)
+	}
+	return &Adapter{db: db}, nil
+}
+
+// Empty reports whether no policy has been stored yet
+func (a *Adapter) Empty() (bool, error) {
+	var count int64
+	if err := a.db.Model(&casbinRule{}).Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count == 0, nil
+}
+
+func (a *Adapter) LoadPolicy(m model.Model) error {
+	var rules []casbinRule
+	if err := a.db.Order("id").Find(&rules).Error; err != nil {
+		return err
+	}
+	for _, r := range rules {
+		if err := persist.LoadPolicyArray(r.values(), m); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (a *Adapter) SavePolicy(m model.Model) error {
+	var rules []casbinRule
+	for _, sec := range []string{"p", "g"} {
+		for ptype, ast := range m[sec] {
+			for _, rule := range ast.Policy {
+				rules = append(rules, newCasbinRule(ptype, rule))
+			}
+		}
+	}
+
+	return a.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(&casbinRule{}).Error; err != nil {
+			return err
+		}
+		if len(rules) == 0 {
+			return nil
+		}
+		return tx.CreateInBatches(rules, 100).Error
+	})
+}
+
+func (a *Adapter) AddPolicy(_ string, ptype string, rule []string) error {
+	r := newCasbinRule(ptype, rule)
+	return a.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&r).Error
+}
+
+func (a *Adapter) RemovePolicy(_ string, ptype string, rule []string) error {
+	r := newCasbinRule(ptype, rule)
+	return a.db.Where(map[string]interface{}{
+		"ptype": r.Ptype, "v0": r.V0, "v1": r.V1, "v2": r.V2, "v3": r.V3, "v4": r.V4, "v5": r.V5,
+	}).Delete(&casbinRule{}).Error
+}
+
+func (a *Adapter) RemoveFilteredPolicy(_ string, ptype string, fieldIndex int, fieldValues ...string) error {
+	if fieldIndex < 0 || fieldIndex+len(fieldValues) > 6 {
+		return errors.New("invalid policy filter")
+	}
+
+	query := a.db.Where("ptype = ?", ptype)
+	for i, value := range fieldValues {
+		// An empty value matches anything
+		if value != "" {
+			query = query.Where(fmt.Sprintf("v%d = ?", fieldIndex+i), value)
+		}
+	}
+	return query.Delete(&casbinRule{}).Error
+}
@@ -0,0 +1,89 @@
+package rbac
+
+import (
+	"testing"
+
+	"github.com/casbin/casbin/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func testAdapter(t *testing.T) *Adapter {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{Logger: logger.Discard})
+	require.NoError(t, err)
+	adapter, err := NewAdapter(db)
+	require.NoError(t, err)
+	return adapter
+}
+
+func TestNewEnforcerSeedsAnEmptyTable(t *testing.T) {
+	adapter := testAdapter(t)
+
+	enforcer, err := NewEnforcer("rbac_model.conf", "rbac_policy.csv", adapter)
+	require.NoError(t, err)
+	defer enforcer.StopAutoLoadPolicy()
+
+	empty, err := adapter.Empty()
+	require.NoError(t, err)
+	assert.False(t, empty)
+
+	seed, err := casbin.NewEnforcer("rbac_model.conf", "rbac_policy.csv")
+	require.NoError(t, err)
+	want, _ := seed.GetPolicy()
+	got, _ := enforcer.GetPolicy()
+	assert.ElementsMatch(t, want, got)
+	wantRoles, _ := seed.GetGroupingPolicy()
+	gotRoles, _ := enforcer.GetGroupingPolicy()
+	assert.ElementsMatch(t, wantRoles, gotRoles)
+}
+
+func TestAdapterPersistsChanges(t *testing.T) {
+	adapter := testAdapter(t)
+
+	enforcer, err := casbin.NewSyncedEnforcer("rbac_model.conf", adapter)
+	require.NoError(t, err)
+
+	_, err = enforcer.AddPolicy("support", "/api/orders/:id", "GET")
+	require.NoError(t, err)
+	_, err = enforcer.AddPolicy("support", "/api/users/:id", "GET|PUT")
+	require.NoError(t, err)
+	_, err = enforcer.AddGroupingPolicy("alice", "support")
+	require.NoError(t, err)
+	_, err = enforcer.RemovePolicy("support", "/api/users/:id", "GET|PUT")
+	require.NoError(t, err)
+
+	// Rules are read back from the table, not from the enforcer that wrote them
+	reloaded, err := casbin.NewSyncedEnforcer("rbac_model.conf", adapter)
+	require.NoError(t, err)
+	policies, _ := reloaded.GetPolicy()
+	assert.Equal(t, [][]string{{"support", "/api/orders/:id", "GET"}}, policies)
+	roles, _ := reloaded.GetGroupingPolicy()
+	assert.Equal(t, [][]string{{"alice", "support"}}, roles)
+
+	allowed, err := reloaded.Enforce("alice", "/api/orders/7", "GET")
+	require.NoError(t, err)
+	assert.True(t, allowed)
+}
+
+func TestAdapterIgnoresDuplicatesAndFiltersRemovals(t *testing.T) {
+	adapter := testAdapter(t)
+
+	require.NoError(t, adapter.AddPolicy("p", "p", []string{"customer", "/api/carts", "GET"}))
+	require.NoError(t, adapter.AddPolicy("p", "p", []string{"customer", "/api/carts", "GET"}))
+	require.NoError(t, adapter.AddPolicy("p", "p", []string{"customer", "/api/carts", "POST"}))
+	require.NoError(t, adapter.AddPolicy("p", "p", []string{"admin", "/api/carts", "GET"}))
+
+	var count int64
+	require.NoError(t, adapter.db.Model(&casbinRule{}).Count(&count).Error)
+	assert.Equal(t, int64(3), count)
+
+	// An empty value matches any object
+	require.NoError(t, adapter.RemoveFilteredPolicy("p", "p", 0, "customer", ""))
+	require.NoError(t, adapter.db.Model(&casbinRule{}).Count(&count).Error)
+	assert.Equal(t, int64(1), count)
+
+	assert.Error(t, adapter.RemoveFilteredPolicy("p", "p", 5, "a", "b"))
+}
@@ -0,0 +1,51 @@
+package rbac
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/casbin/casbin/v2"
+)
+
+// Replicas also reload on this interval, in case a watcher message was missed
+const reloadInterval = time.Minute
+
+// NewEnforcer loads the policies stored by the adapter. On first start the table is
+// seeded from the CSV policy file so the gateway never comes up without any rules.
+func NewEnforcer(modelPath, seedPolicyPath string, adapter *Adapter) (*casbin.SyncedEnforcer, error) {
+	empty, err := adapter.Empty()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read policies: %w", err)
+	}
+	if empty {
+		seed, err := casbin.NewEnforcer(modelPath, seedPolicyPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load seed policies: %w", err)
+		}
+		if err := adapter.SavePolicy(seed.GetModel()); err != nil {
+			return nil, fmt.Errorf("failed to seed policies: %w", err)
+		}
+		log.Printf("Seeded policies from %s", seedPolicyPath)
+	}
+
+	enforcer, err := casbin.NewSyncedEnforcer(modelPath, adapter)
+	if err != nil {
+		return nil, err
+	}
+	enforcer.StartAutoLoadPolicy(reloadInterval)
+	return enforcer, nil
+}
+
+// Watch reloads the enforcer whenever another replica changes the policies
+func Watch(enforcer *casbin.SyncedEnforcer, watcher *Watcher) error {
+	if err := enforcer.SetWatcher(watcher); err != nil {
+		return err
+	}
+	// The default callback reloads without holding the enforcer lock
+	return watcher.SetUpdateCallback(func(string) {
+		if err := enforcer.LoadPolicy(); err != nil {
+			log.Printf("Failed to reload policies: %v", err)
+		}
+	})
+}
@@ -0,0 +1,73 @@
+package rbac
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log"
+	"sync"
+
+	"github.com/casbin/casbin/v2/persist"
+	"github.com/redis/go-redis/v9"
+)
+
+const policyChannel = "casbin:policy:updated"
+
+// Watcher tells the other gateway replicas to reload their policies through Redis pub/sub.
+// Messages carry the id of the sender so a replica does not reload its own changes.
+type Watcher struct {
+	client *redis.Client
+	id     string
+	pubsub *redis.PubSub
+
+	mu       sync.Mutex
+	callback func(string)
+}
+
+var _ persist.Watcher = (*Watcher)(nil)
+
+func NewWatcher(ctx context.Context, client *redis.Client) *Watcher {
+	id := make([]byte, 8)
+	rand.Read(id)
+
+	w := &Watcher{
+		client: client,
+		id:     hex.EncodeToString(id),
+		pubsub: client.Subscribe(ctx, policyChannel),
+	}
+	go w.listen()
+	return w
+}
+
+func (w *Watcher) SetUpdateCallback(callback func(string)) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.callback = callback
+	return nil
+}
+
+func (w *Watcher) Update() error {
+	return w.client.Publish(context.Background(), policyChannel, w.id).Err()
+}
+
+func (w *Watcher) Close() {
+	w.pubsub.Close()
+}
+
+// listen runs until Close, the subscription reconnects on its own when Redis restarts
+func (w *Watcher) listen() {
+	for msg := range w.pubsub.Channel() {
+		if msg.Payload == w.id {
+			continue
+		}
+
+		w.mu.Lock()
+		callback := w.callback
+		w.mu.Unlock()
+
+		if callback != nil {
+			log.Printf("Policy changed by gateway %s, reloading", msg.Payload)
+			callback(msg.Payload)
+		}
+	}
+}
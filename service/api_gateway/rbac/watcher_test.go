@@ -0,0 +1,49 @@
+package rbac
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatcherNotifiesOtherReplicas(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer client.Close()
+
+	ctx := context.Background()
+	sender := NewWatcher(ctx, client)
+	defer sender.Close()
+	receiver := NewWatcher(ctx, client)
+	defer receiver.Close()
+
+	own := make(chan string, 1)
+	require.NoError(t, sender.SetUpdateCallback(func(id string) { own <- id }))
+	others := make(chan string, 1)
+	require.NoError(t, receiver.SetUpdateCallback(func(id string) { others <- id }))
+
+	// Both subscriptions must be active before publishing
+	require.Eventually(t, func() bool {
+		return len(mr.PubSubNumSub(policyChannel)) == 1 && mr.PubSubNumSub(policyChannel)[policyChannel] == 2
+	}, time.Second, 10*time.Millisecond)
+
+	require.NoError(t, sender.Update())
+
+	select {
+	case id := <-others:
+		assert.Equal(t, sender.id, id)
+	case <-time.After(time.Second):
+		t.Fatal("the other replica was not notified")
+	}
+
+	select {
+	case <-own:
+		t.Fatal("a replica reloaded its own change")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
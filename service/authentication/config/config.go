@@ -0,0 +1,9 @@
+package config
+
+import pkgconfig "th3y3m/e-commerce-microservices/pkg/config"
+
+// Config of the authentication service
+type Config struct {
+	pkgconfig.Base
+	pkgconfig.JWT
+}
@@ -8,16 +8,16 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-func Login(c *gin.Context) {
+func (h *handler) Login(c *gin.Context) {
 	var user model.LoginRequest
 	if err := c.ShouldBindJSON(&user); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	module := dependency_injection.NewAuthUsecaseProvider()
+	module := dependency_injection.NewAuthUsecaseProvider(h.cfg, h.services)
 
-	token, err := module.Login(user.Email, user.Password)
+	token, err := module.Login(c, user.Email, user.Password)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -26,16 +26,16 @@ func Login(c *gin.Context) {
 	c.JSON(http.StatusOK, token)
 }
 
-func Register(c *gin.Context) {
+func (h *handler) Register(c *gin.Context) {
 	var user model.RegisterRequest
 	if err := c.ShouldBindJSON(&user); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	module := dependency_injection.NewAuthUsecaseProvider()
+	module := dependency_injection.NewAuthUsecaseProvider(h.cfg, h.services)
 
-	err := module.RegisterCustomer(user.Email, user.Password, user.ConfirmPassword)
+	err := module.RegisterCustomer(c, user.Email, user.Password, user.ConfirmPassword)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -44,16 +44,16 @@ func Register(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "Verify your email to complete registration"})
 }
 
-func VerifyUserEmail(c *gin.Context) {
+func (h *handler) VerifyUserEmail(c *gin.Context) {
 	token := c.Query("token")
 	if token == "" {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "token is required"})
 		return
 	}
 
-	module := dependency_injection.NewAuthUsecaseProvider()
+	module := dependency_injection.NewAuthUsecaseProvider(h.cfg, h.services)
 
-	err := module.VerifyUserEmail(token)
+	err := module.VerifyUserEmail(c, token)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
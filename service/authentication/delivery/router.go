@@ -1,17 +1,41 @@
 package delivery
 
 import (
+	"net/http"
+	"th3y3m/e-commerce-microservices/pkg/authz"
+	"th3y3m/e-commerce-microservices/pkg/discovery"
+	"th3y3m/e-commerce-microservices/pkg/logging"
+	"th3y3m/e-commerce-microservices/pkg/metrics"
+	"th3y3m/e-commerce-microservices/pkg/tracing"
+	"th3y3m/e-commerce-microservices/service/authentication/config"
+	"th3y3m/e-commerce-microservices/service/authentication/dependency_injection"
+
 	"github.com/gin-gonic/gin"
 )
 
-func RegisterHandlers() *gin.Engine {
-	r := gin.Default()
+// handler serves the routes of the service, with the usecases built from cfg
+type handler struct {
+	cfg config.Config
+	// Calls the other services for the usecases
+	services *http.Client
+}
+
+func RegisterHandlers(cfg config.Config, resolver discovery.Resolver) *gin.Engine {
+	h := &handler{cfg: cfg, services: dependency_injection.NewServiceClientProvider(cfg, resolver)}
+	keys := authz.NewKeys(cfg.Auth)
+
+	r := gin.New()
+	// Lets the request context, and the trace in it, reach the usecases through *gin.Context
+	r.ContextWithFallback = true
+	r.Use(gin.Recovery(), tracing.Middleware("authentication"), logging.Middleware(), metrics.Middleware())
+	r.GET(metrics.Path, metrics.Handler())
+	r.Use(keys.Middleware())
 
 	authen := r.Group("/api/authen")
 	{
-		authen.POST("/login", Login)
-		authen.POST("/register", Register)
-		authen.GET("/verify-email", VerifyUserEmail)
+		authen.POST("/login", h.Login)
+		authen.POST("/register", h.Register)
+		authen.GET("/verify-email", h.VerifyUserEmail)
 	}
 
 	return r
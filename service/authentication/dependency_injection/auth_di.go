@@ -1,12 +1,22 @@
 package dependency_injection
 
 import (
+	"net/http"
+	"th3y3m/e-commerce-microservices/pkg/authz"
+	"th3y3m/e-commerce-microservices/pkg/clients"
+	"th3y3m/e-commerce-microservices/pkg/discovery"
+	"th3y3m/e-commerce-microservices/pkg/logging"
+	"th3y3m/e-commerce-microservices/service/authentication/config"
 	"th3y3m/e-commerce-microservices/service/authentication/usecase"
-
-	"github.com/sirupsen/logrus"
 )
 
-func NewAuthUsecaseProvider() usecase.IAuthUsecase {
-	log := logrus.New()
-	return usecase.NewAuthUsecase(log)
+func NewAuthUsecaseProvider(cfg config.Config, httpClient *http.Client) usecase.IAuthUsecase {
+	log := logging.Logger()
+	return usecase.NewAuthUsecase(log, cfg.JWTSecret, clients.NewUserClient(httpClient, clients.NewOptions(cfg.Clients)))
+}
+
+// NewServiceClientProvider returns the HTTP client the authentication service calls other
+// services with, signed with its service token and sent to the instances resolver finds
+func NewServiceClientProvider(cfg config.Config, resolver discovery.Resolver) *http.Client {
+	return authz.NewKeys(cfg.Auth).NewServiceClient("authentication", resolver)
 }
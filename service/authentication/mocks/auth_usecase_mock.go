@@ -1,17 +1,21 @@
-// Code generated by mockery v2.46.3. DO NOT EDIT.
+// Code generated by mockery v2.53.5. DO NOT EDIT.
 
 package mocks
 
-import mock "github.com/stretchr/testify/mock"
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
 
 // IAuthUsecase is an autogenerated mock type for the IAuthUsecase type
 type IAuthUsecase struct {
 	mock.Mock
 }
 
-// Login provides a mock function with given fields: email, password
-func (_m *IAuthUsecase) Login(email string, password string) (string, error) {
-	ret := _m.Called(email, password)
+// Login provides a mock function with given fields: ctx, email, password
+func (_m *IAuthUsecase) Login(ctx context.Context, email string, password string) (string, error) {
+	ret := _m.Called(ctx, email, password)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Login")
@@ -19,17 +23,17 @@ func (_m *IAuthUsecase) Login(email string, password string) (string, error) {
 
 	var r0 string
 	var r1 error
-	if rf, ok := ret.Get(0).(func(string, string) (string, error)); ok {
-		return rf(email, password)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (string, error)); ok {
+		return rf(ctx, email, password)
 	}
-	if rf, ok := ret.Get(0).(func(string, string) string); ok {
-		r0 = rf(email, password)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) string); ok {
+		r0 = rf(ctx, email, password)
 	} else {
 		r0 = ret.Get(0).(string)
 	}
 
-	if rf, ok := ret.Get(1).(func(string, string) error); ok {
-		r1 = rf(email, password)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, email, password)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -37,17 +41,17 @@ func (_m *IAuthUsecase) Login(email string, password string) (string, error) {
 	return r0, r1
 }
 
-// RegisterCustomer provides a mock function with given fields: email, password, confirmPassword
-func (_m *IAuthUsecase) RegisterCustomer(email string, password string, confirmPassword string) error {
-	ret := _m.Called(email, password, confirmPassword)
+// RegisterCustomer provides a mock function with given fields: ctx, email, password, confirmPassword
+func (_m *IAuthUsecase) RegisterCustomer(ctx context.Context, email string, password string, confirmPassword string) error {
+	ret := _m.Called(ctx, email, password, confirmPassword)
 
 	if len(ret) == 0 {
 		panic("no return value specified for RegisterCustomer")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, string, string) error); ok {
-		r0 = rf(email, password, confirmPassword)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) error); ok {
+		r0 = rf(ctx, email, password, confirmPassword)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -55,17 +59,17 @@ func (_m *IAuthUsecase) RegisterCustomer(email string, password string, confirmP
 	return r0
 }
 
-// VerifyUserEmail provides a mock function with given fields: token
-func (_m *IAuthUsecase) VerifyUserEmail(token string) error {
-	ret := _m.Called(token)
+// VerifyUserEmail provides a mock function with given fields: ctx, token
+func (_m *IAuthUsecase) VerifyUserEmail(ctx context.Context, token string) error {
+	ret := _m.Called(ctx, token)
 
 	if len(ret) == 0 {
 		panic("no return value specified for VerifyUserEmail")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string) error); ok {
-		r0 = rf(token)
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, token)
 	} else {
 		r0 = ret.Error(0)
 	}
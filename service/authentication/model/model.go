@@ -2,9 +2,6 @@ package model
 
 import "time"
 
-type VerifyTokenResponse struct {
-	IsValid bool `json:"is_valid"`
-}
 type LoginRequest struct {
 	Email    string `json:"email"`
 	Password string `json:"password"`
@@ -15,21 +12,6 @@ type RegisterRequest struct {
 	Password        string `json:"password"`
 	ConfirmPassword string `json:"confirm_password"`
 }
-type UpdateUserRequest struct {
-	UserID       int64     `json:"user_id"`
-	Email        string    `json:"email"`
-	PasswordHash string    `json:"password_hash"`
-	FullName     string    `json:"full_name"`
-	PhoneNumber  string    `json:"phone_number"`
-	Address      string    `json:"address"`
-	Role         string    `json:"role"`
-	ImageURL     string    `json:"image_url"`
-	Token        string    `json:"token"`
-	TokenExpires time.Time `json:"token_expires"`
-	IsVerified   bool      `json:"is_verified"`
-	IsDeleted    bool      `json:"is_deleted"`
-}
-
 type User struct {
 	UserID       int64     `json:"user_id"`
 	Email        string    `json:"email"`
@@ -47,14 +29,6 @@ type User struct {
 	IsVerified   bool      `json:"is_verified"`
 	IsDeleted    bool      `json:"is_deleted"`
 }
-type CreateUserRequest struct {
-	Email      string `json:"email"`
-	Password   string `json:"password"`
-	Role       string `json:"role"`
-	ImageURL   string `json:"image_url"`
-	Provider   string `json:"provider"`
-	IsVerified *bool  `json:"is_verified"`
-}
 type OrderDetail struct {
 	OrderID   int64   `json:"order_id"`
 	ProductID int64   `json:"product_id"`
@@ -97,20 +71,3 @@ type SendOrderDetailsRequest struct {
 	Order        Order         `json:"order" binding:"required"`
 	OrderDetails []OrderDetail `json:"order_details" binding:"required"`
 }
-type GetUserResponse struct {
-	UserID       int64  `json:"user_id"`
-	Email        string `json:"email"`
-	PasswordHash string `json:"password_hash"`
-	FullName     string `json:"full_name"`
-	PhoneNumber  string `json:"phone_number"`
-	Address      string `json:"address"`
-	Role         string `json:"role"`
-	ImageURL     string `json:"image_url"`
-	Provider     string `json:"provider"`
-	CreatedAt    string `json:"created_at"`
-	UpdatedAt    string `json:"updated_at"`
-	Token        string `json:"token"`
-	TokenExpires string `json:"token_expires"`
-	IsVerified   bool   `json:"is_verified"`
-	IsDeleted    bool   `json:"is_deleted"`
-}
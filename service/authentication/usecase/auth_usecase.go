@@ -1,66 +1,52 @@
 package usecase
 
 import (
-	"bytes"
-	"encoding/json"
+	"context"
 	"errors"
 	"fmt"
-	"net/http"
+	"th3y3m/e-commerce-microservices/pkg/authz"
+	"th3y3m/e-commerce-microservices/pkg/clients"
 	"th3y3m/e-commerce-microservices/pkg/constant"
 	"th3y3m/e-commerce-microservices/pkg/util"
-	"th3y3m/e-commerce-microservices/service/authentication/model"
 	"time"
 
 	"github.com/sirupsen/logrus"
 )
 
 type authUsecase struct {
-	log *logrus.Logger
+	log       *logrus.Logger
+	jwtSecret string
+	// Reads and writes the accounts in the user service
+	userClient *clients.UserClient
 }
 
 type IAuthUsecase interface {
-	Login(email, password string) (string, error)
-	RegisterCustomer(email, password, confirmPassword string) error
-	VerifyUserEmail(token string) error
+	Login(ctx context.Context, email, password string) (string, error)
+	RegisterCustomer(ctx context.Context, email, password, confirmPassword string) error
+	VerifyUserEmail(ctx context.Context, token string) error
 }
 
-func NewAuthUsecase(log *logrus.Logger) IAuthUsecase {
+// NewAuthUsecase returns the usecase issuing and verifying tokens signed with jwtSecret
+func NewAuthUsecase(log *logrus.Logger, jwtSecret string, userClient *clients.UserClient) IAuthUsecase {
 	return &authUsecase{
-		log: log,
+		log:        log,
+		jwtSecret:  jwtSecret,
+		userClient: userClient,
 	}
 }
 
-func (o *authUsecase) Login(email, password string) (string, error) {
+func (o *authUsecase) Login(ctx context.Context, email, password string) (string, error) {
+	// The caller is not signed in yet, users are looked up by the service itself
+	ctx = authz.AsSystem(ctx)
+
 	if email == "" || password == "" {
 		return "", errors.New("email and password are required")
 	}
 
 	// Call the user service to check if the user exists by their email
-	url := constant.USER_SERVICE + "/get-user"
-
-	// Create the request body
-	requestBody := fmt.Sprintf(`{"email": "%s"}`, email)
-	req, err := http.NewRequest("GET", url, bytes.NewBuffer([]byte(requestBody)))
-	if err != nil {
-		o.log.Errorf("Failed to create request: %v", err)
-		return "", err
-	}
-
-	// Set the appropriate headers
-	req.Header.Set("Content-Type", "application/json")
-
-	client := &http.Client{}
-	res, err := client.Do(req)
+	user, err := o.userClient.GetByEmail(ctx, email)
 	if err != nil {
-		o.log.Errorf("Failed to fetch user from user service: %v", err)
-		return "", err
-	}
-	defer res.Body.Close()
-
-	// Parse the response body into a User struct
-	var user model.GetUserResponse
-	if err := json.NewDecoder(res.Body).Decode(&user); err != nil {
-		o.log.Errorf("Failed to decode user response: %v", err)
+		o.log.WithContext(ctx).Errorf("Failed to fetch user from user service: %v", err)
 		return "", err
 	}
 
@@ -68,7 +54,7 @@ func (o *authUsecase) Login(email, password string) (string, error) {
 		return "", errors.New("invalid password")
 	}
 
-	token, err := util.GenerateJWT(user.UserID, user.Role, user.Email)
+	token, err := util.GenerateJWT(o.jwtSecret, user.UserID, user.Role, user.Email)
 	if err != nil {
 		return "", err
 	}
@@ -76,7 +62,10 @@ func (o *authUsecase) Login(email, password string) (string, error) {
 	return token, nil
 }
 
-func (o *authUsecase) RegisterCustomer(email, password, confirmPassword string) error {
+func (o *authUsecase) RegisterCustomer(ctx context.Context, email, password, confirmPassword string) error {
+	// The account does not exist yet, it is created by the service itself
+	ctx = authz.AsSystem(ctx)
+
 	if email == "" || password == "" {
 		return errors.New("email and password are required")
 	}
@@ -91,31 +80,9 @@ func (o *authUsecase) RegisterCustomer(email, password, confirmPassword string)
 	}
 
 	// Call the user service to check if the user exists by their email
-	url := constant.USER_SERVICE + "/get-user"
-
-	// Create the request body
-	requestBody := fmt.Sprintf(`{"email": "%s"}`, email)
-	req, err := http.NewRequest("GET", url, bytes.NewBuffer([]byte(requestBody)))
-	if err != nil {
-		o.log.Errorf("Failed to create request: %v", err)
-		return err
-	}
-
-	// Set the appropriate headers
-	req.Header.Set("Content-Type", "application/json")
-
-	client := &http.Client{}
-	res, err := client.Do(req)
+	user, err := o.userClient.GetByEmail(ctx, email)
 	if err != nil {
-		o.log.Errorf("Failed to fetch user from user service: %v", err)
-		return err
-	}
-	defer res.Body.Close()
-
-	// Parse the response body into a User struct
-	var user model.GetUserResponse
-	if err := json.NewDecoder(res.Body).Decode(&user); err != nil {
-		o.log.Errorf("Failed to decode user response: %v", err)
+		o.log.WithContext(ctx).Errorf("Failed to fetch user from user service: %v", err)
 		return err
 	}
 
@@ -126,49 +93,26 @@ func (o *authUsecase) RegisterCustomer(email, password, confirmPassword string)
 
 	var defaultVerification = false
 
-	newUser := model.CreateUserRequest{
+	// Create the new user in the user service
+	createdUser, err := o.userClient.Create(ctx, clients.CreateUser{
 		Email:      email,
 		Password:   hashedPassword,
 		Role:       "Customer",
 		Provider:   "System",
 		ImageURL:   constant.DEFAULT_USER_IMAGE,
 		IsVerified: &defaultVerification,
-	}
-
-	// Marshal the new user data to JSON
-	userData, err := json.Marshal(newUser)
+	})
 	if err != nil {
-		o.log.Errorf("Failed to marshal new user data: %v", err)
+		o.log.WithContext(ctx).Errorf("Error creating new user: %v", err)
 		return err
 	}
 
-	// Create the new user in the user service via HTTP POST
-	res, err = http.Post(constant.USER_SERVICE, "application/json", bytes.NewBuffer(userData))
-	if err != nil {
-		o.log.Errorf("Error creating new user: %v", err)
-		return err
-	}
-	defer res.Body.Close()
-
-	// Check for a successful status code
-	if res.StatusCode != http.StatusOK {
-		o.log.Errorf("Error creating user: received status %v", res.StatusCode)
-		return errors.New("error creating user")
-	}
-
-	// Decode the response body into the `createdUser` struct
-	var createdUser model.GetUserResponse
-	if err := json.NewDecoder(res.Body).Decode(&createdUser); err != nil {
-		o.log.Errorf("Failed to decode created user response: %v", err)
-		return err
-	}
-
-	token, err := util.GenerateJWT(createdUser.UserID, createdUser.Role, email)
+	token, err := util.GenerateJWT(o.jwtSecret, createdUser.UserID, createdUser.Role, email)
 	if err != nil {
 		return fmt.Errorf("error generating token: %w", err)
 	}
 
-	updateUserRequest := model.UpdateUserRequest{
+	_, err = o.userClient.Update(ctx, clients.UpdateUser{
 		UserID:       createdUser.UserID,
 		Email:        createdUser.Email,
 		PasswordHash: createdUser.PasswordHash,
@@ -181,48 +125,16 @@ func (o *authUsecase) RegisterCustomer(email, password, confirmPassword string)
 		TokenExpires: time.Now().Add(24 * time.Hour), // Example token expiry time
 		IsVerified:   createdUser.IsVerified,
 		IsDeleted:    createdUser.IsDeleted,
-	}
-
-	userData, err = json.Marshal(updateUserRequest)
-	if err != nil {
-		o.log.Errorf("Failed to marshal updated user data: %v", err)
-		return err
-	}
-
-	// Create the HTTP PUT request
-	req, err = http.NewRequest("PUT", constant.USER_SERVICE, bytes.NewBuffer(userData))
+	})
 	if err != nil {
-		o.log.Errorf("Failed to create request: %v", err)
-		return err
-	}
-
-	// Set the appropriate headers
-	req.Header.Set("Content-Type", "application/json")
-
-	res, err = client.Do(req)
-	if err != nil {
-		o.log.Errorf("Failed to update user in user service: %v", err)
-		return err
-	}
-	defer res.Body.Close()
-
-	// Check for a successful status code
-	if res.StatusCode != http.StatusOK {
-		o.log.Errorf("Error updating user: received status %v", res.StatusCode)
-		return errors.New("error updating user")
-	}
-
-	// Optionally, parse the response body if needed
-	var updatedUser model.GetUserResponse
-	if err := json.NewDecoder(res.Body).Decode(&updatedUser); err != nil {
-		o.log.Errorf("Failed to decode updated user response: %v", err)
+		o.log.WithContext(ctx).Errorf("Failed to update user in user service: %v", err)
 		return err
 	}
 
 	// Send a verification email to the user
 	// url = constant.MAIL_SERVICE + "/send-mail?to=" + newUser.Email + "&token=" + token
 
-	// res, err = http.Post(url, "application/json", nil)
+	// res, err = serviceClient.Post(url, "application/json", nil)
 	// if err != nil {
 	// 	o.log.Errorf("Failed to send verification email: %v", err)
 	// 	return err
@@ -238,48 +150,23 @@ func (o *authUsecase) RegisterCustomer(email, password, confirmPassword string)
 	return nil
 }
 
-func (a *authUsecase) VerifyUserEmail(token string) error {
+func (a *authUsecase) VerifyUserEmail(ctx context.Context, token string) error {
+	// The link in the email is opened without signing in
+	ctx = authz.AsSystem(ctx)
 
 	// Extract the user ID from the token (using your existing JWT decode logic)
-	userID, err := util.DecodeJWT(token)
+	userID, err := util.DecodeJWT(a.jwtSecret, token)
 	if err != nil {
 		return fmt.Errorf("error decoding token: %w", err)
 	}
 
-	url := constant.USER_SERVICE + "/verify?token=" + token + "&user_id=" + fmt.Sprintf("%d", userID)
-
-	// Create the HTTP PUT request
-	req, err := http.NewRequest("POST", url, nil)
-	if err != nil {
-		a.log.Errorf("Failed to create request: %v", err)
-		return err
-	}
-
-	// Set the appropriate headers
-	req.Header.Set("Content-Type", "application/json")
-
-	client := &http.Client{}
-	res, err := client.Do(req)
+	valid, err := a.userClient.VerifyToken(ctx, userID, token)
 	if err != nil {
-		a.log.Errorf("Failed to verify user email: %v", err)
-		return err
-	}
-
-	defer res.Body.Close()
-
-	// Check for a successful status code
-	if res.StatusCode != http.StatusOK {
-		a.log.Errorf("Error verifying user email: received status %v", res.StatusCode)
-		return errors.New("error verifying user email")
-	}
-
-	var resp model.VerifyTokenResponse
-	if err := json.NewDecoder(res.Body).Decode(&resp); err != nil {
-		a.log.Errorf("Failed to decode verify token response: %v", err)
+		a.log.WithContext(ctx).Errorf("Failed to verify user email: %v", err)
 		return err
 	}
 
-	if !resp.IsValid {
+	if !valid {
 		return errors.New("invalid token")
 	}
 
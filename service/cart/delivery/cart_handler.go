@@ -2,55 +2,71 @@ package delivery
 
 import (
 	"strconv"
+	"th3y3m/e-commerce-microservices/pkg/authz"
 	"th3y3m/e-commerce-microservices/service/cart/dependency_injection"
 	"th3y3m/e-commerce-microservices/service/cart/model"
+	"th3y3m/e-commerce-microservices/service/cart/usecase"
 
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
 )
 
-func GetCartByID(c *gin.Context) {
+func (h *handler) GetCartByID(c *gin.Context) {
 	var req model.GetCartRequest
 
 	err := c.BindJSON(&req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(400, gin.H{
 			"error": "Bad Request",
 		})
 		return
 	}
 
-	module := dependency_injection.NewCartUsecaseProvider()
+	module := dependency_injection.NewCartUsecaseProvider(h.cfg, h.services)
+
+	caller, ok := authz.Authorize(c)
+	if !ok {
+		return
+	}
 
 	cart, err := module.GetCart(c, &req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(500, gin.H{
 			"error": "Internal Server Error",
 		})
 		return
 	}
 
+	if !authz.RequireOwner(c, caller, cart.UserID) {
+		return
+	}
+
 	c.JSON(200, cart)
 }
 
-func CreateCart(c *gin.Context) {
+func (h *handler) CreateCart(c *gin.Context) {
 	var req model.CreateCartRequest
 	err := c.BindJSON(&req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(400, gin.H{
 			"error": "Bad Request",
 		})
 		return
 	}
 
-	module := dependency_injection.NewCartUsecaseProvider()
+	caller, ok := authz.Authorize(c)
+	if !ok || !authz.RequireOwner(c, caller, req.UserID) {
+		return
+	}
+
+	module := dependency_injection.NewCartUsecaseProvider(h.cfg, h.services)
 
 	cart, err := module.CreateCart(c, &req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(500, gin.H{
 			"error": "Internal Server Error",
 		})
@@ -60,22 +76,27 @@ func CreateCart(c *gin.Context) {
 	c.JSON(200, cart)
 }
 
-func UpdateCart(c *gin.Context) {
+func (h *handler) UpdateCart(c *gin.Context) {
 	var req model.UpdateCartRequest
 	err := c.BindJSON(&req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(400, gin.H{
 			"error": "Bad Request",
 		})
 		return
 	}
 
-	module := dependency_injection.NewCartUsecaseProvider()
+	module := dependency_injection.NewCartUsecaseProvider(h.cfg, h.services)
+
+	caller, ok := authz.Authorize(c)
+	if !ok || !requireCartOwner(c, module, caller, req.CartID) {
+		return
+	}
 
 	cart, err := module.UpdateCart(c, &req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(500, gin.H{
 			"error": "Internal Server Error",
 		})
@@ -85,23 +106,28 @@ func UpdateCart(c *gin.Context) {
 	c.JSON(200, cart)
 }
 
-func DeleteCart(c *gin.Context) {
+func (h *handler) DeleteCart(c *gin.Context) {
 	var req model.DeleteCartRequest
 
 	err := c.BindJSON(&req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(400, gin.H{
 			"error": "Bad Request",
 		})
 		return
 	}
 
-	module := dependency_injection.NewCartUsecaseProvider()
+	module := dependency_injection.NewCartUsecaseProvider(h.cfg, h.services)
+
+	caller, ok := authz.Authorize(c)
+	if !ok || !requireCartOwner(c, module, caller, req.CartID) {
+		return
+	}
 
 	err = module.DeleteCart(c, &req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(500, gin.H{
 			"error": "Internal Server Error",
 		})
@@ -113,22 +139,27 @@ func DeleteCart(c *gin.Context) {
 	})
 }
 
-func GetUserCart(c *gin.Context) {
+func (h *handler) GetUserCart(c *gin.Context) {
 	userIDStr := c.Param("user_id")
 	userID, err := strconv.ParseInt(userIDStr, 10, 64)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(400, gin.H{
 			"error": "Invalid user ID",
 		})
 		return
 	}
 
-	module := dependency_injection.NewCartUsecaseProvider()
+	caller, ok := authz.Authorize(c)
+	if !ok || !authz.RequireOwner(c, caller, userID) {
+		return
+	}
+
+	module := dependency_injection.NewCartUsecaseProvider(h.cfg, h.services)
 
 	cart, err := module.GetUserCart(c, userID)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(500, gin.H{
 			"error": "Internal Server Error",
 		})
@@ -138,22 +169,27 @@ func GetUserCart(c *gin.Context) {
 	c.JSON(200, cart)
 }
 
-func AddProductToShoppingCart(c *gin.Context) {
+func (h *handler) AddProductToShoppingCart(c *gin.Context) {
 	var req model.AddProductToShoppingCartRequest
 	err := c.BindJSON(&req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(400, gin.H{
 			"error": "Bad Request",
 		})
 		return
 	}
 
-	module := dependency_injection.NewCartUsecaseProvider()
+	caller, ok := authz.Authorize(c)
+	if !ok || !authz.RequireOwner(c, caller, req.UserID) {
+		return
+	}
+
+	module := dependency_injection.NewCartUsecaseProvider(h.cfg, h.services)
 
 	err = module.AddProductToShoppingCart(c, req.UserID, req.ProductID, req.Quantity)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(500, gin.H{
 			"error": "Internal Server Error",
 		})
@@ -163,22 +199,27 @@ func AddProductToShoppingCart(c *gin.Context) {
 	c.JSON(200, gin.H{"message": "Product added to shopping cart successfully"})
 }
 
-func RemoveProductFromShoppingCart(c *gin.Context) {
+func (h *handler) RemoveProductFromShoppingCart(c *gin.Context) {
 	var req model.RemoveProductFromShoppingCartRequest
 	err := c.BindJSON(&req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(400, gin.H{
 			"error": "Bad Request",
 		})
 		return
 	}
 
-	module := dependency_injection.NewCartUsecaseProvider()
+	caller, ok := authz.Authorize(c)
+	if !ok || !authz.RequireOwner(c, caller, req.UserID) {
+		return
+	}
+
+	module := dependency_injection.NewCartUsecaseProvider(h.cfg, h.services)
 
 	err = module.RemoveProductFromShoppingCart(c, req.UserID, req.ProductID, req.Quantity)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(500, gin.H{
 			"error": "Internal Server Error",
 		})
@@ -188,15 +229,15 @@ func RemoveProductFromShoppingCart(c *gin.Context) {
 	c.JSON(200, gin.H{"message": "Product removed from shopping cart successfully"})
 }
 
-func DeleteUnitItem(c *gin.Context) {
+func (h *handler) DeleteUnitItem(c *gin.Context) {
 	// Initialize cart usecase module
-	module := dependency_injection.NewCartUsecaseProvider()
+	module := dependency_injection.NewCartUsecaseProvider(h.cfg, h.services)
 
 	// Extract productId from URL or request
 	productIDParam := c.Param("productId") // Assuming productId is part of the URL
 	productId, err := strconv.ParseInt(productIDParam, 10, 64)
 	if err != nil {
-		logrus.Error("Invalid product ID")
+		logrus.WithContext(c).Error("Invalid product ID")
 		c.JSON(400, gin.H{
 			"error": "Invalid product ID",
 		})
@@ -206,7 +247,7 @@ func DeleteUnitItem(c *gin.Context) {
 	// Call the DeleteUnitItem method, passing the required Gin context objects
 	err = module.DeleteUnitItem(c.Writer, c.Request, productId)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(500, gin.H{
 			"error": "Internal Server Error",
 		})
@@ -217,15 +258,15 @@ func DeleteUnitItem(c *gin.Context) {
 	c.JSON(200, gin.H{"message": "Unit item deleted successfully"})
 }
 
-func RemoveFromCart(c *gin.Context) {
+func (h *handler) RemoveFromCart(c *gin.Context) {
 	// Initialize cart usecase module
-	module := dependency_injection.NewCartUsecaseProvider()
+	module := dependency_injection.NewCartUsecaseProvider(h.cfg, h.services)
 
 	// Extract productId from URL or request
 	productIDParam := c.Query("productId") // Assuming productId is part of the URL
 	productId, err := strconv.ParseInt(productIDParam, 10, 64)
 	if err != nil {
-		logrus.Error("Invalid product ID")
+		logrus.WithContext(c).Error("Invalid product ID")
 		c.JSON(400, gin.H{
 			"error": "Invalid product ID",
 		})
@@ -235,7 +276,7 @@ func RemoveFromCart(c *gin.Context) {
 	// Call the RemoveFromCart method, passing the required Gin context objects
 	err = module.RemoveFromCart(c.Writer, c.Request, productId)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(500, gin.H{
 			"error": "Internal Server Error",
 		})
@@ -246,14 +287,14 @@ func RemoveFromCart(c *gin.Context) {
 	c.JSON(200, gin.H{"message": "Product removed from cart successfully"})
 }
 
-func DeleteCartInCookie(c *gin.Context) {
+func (h *handler) DeleteCartInCookie(c *gin.Context) {
 	// Initialize cart usecase module
-	module := dependency_injection.NewCartUsecaseProvider()
+	module := dependency_injection.NewCartUsecaseProvider(h.cfg, h.services)
 
 	// Call the DeleteCartInCookie method, passing the required Gin context objects
 	err := module.DeleteCartInCookie(c.Writer)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(500, gin.H{
 			"error": "Internal Server Error",
 		})
@@ -264,14 +305,14 @@ func DeleteCartInCookie(c *gin.Context) {
 	c.JSON(200, gin.H{"message": "Cart deleted from cookie successfully"})
 }
 
-func NumberOfItemsInCartCookie(c *gin.Context) {
+func (h *handler) NumberOfItemsInCartCookie(c *gin.Context) {
 	// Initialize cart usecase module
-	module := dependency_injection.NewCartUsecaseProvider()
+	module := dependency_injection.NewCartUsecaseProvider(h.cfg, h.services)
 
 	// Call the NumberOfItemsInCartCookie method, passing the required Gin context objects
 	numItems, err := module.NumberOfItemsInCartCookie(c.Request)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(500, gin.H{
 			"error": "Internal Server Error",
 		})
@@ -282,15 +323,15 @@ func NumberOfItemsInCartCookie(c *gin.Context) {
 	c.JSON(200, gin.H{"numItems": numItems})
 }
 
-func SaveCartToCookieHandler(c *gin.Context) {
+func (h *handler) SaveCartToCookieHandler(c *gin.Context) {
 	// Initialize cart usecase module
-	module := dependency_injection.NewCartUsecaseProvider()
+	module := dependency_injection.NewCartUsecaseProvider(h.cfg, h.services)
 
 	// Extract productId from URL or request
 	productIDParam := c.Query("productId") // Assuming productId is part of the URL
 	productId, err := strconv.ParseInt(productIDParam, 10, 64)
 	if err != nil {
-		logrus.Error("Invalid product ID")
+		logrus.WithContext(c).Error("Invalid product ID")
 		c.JSON(400, gin.H{
 			"error": "Invalid product ID",
 		})
@@ -300,7 +341,7 @@ func SaveCartToCookieHandler(c *gin.Context) {
 	// Call the SaveCartToCookieHandler method, passing the required Gin context objects
 	err = module.SaveCartToCookieHandler(c.Writer, c.Request, productId)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(500, gin.H{
 			"error": "Internal Server Error",
 		})
@@ -310,3 +351,21 @@ func SaveCartToCookieHandler(c *gin.Context) {
 	// Return success response
 	c.JSON(200, gin.H{"message": "Cart saved to cookie successfully"})
 }
+
+// requireCartOwner aborts with 403 unless the cart belongs to the caller
+func requireCartOwner(c *gin.Context, module usecase.ICartUsecase, caller *authz.Caller, cartID int64) bool {
+	if caller.Privileged() {
+		return true
+	}
+
+	cart, err := module.GetCart(c, &model.GetCartRequest{CartID: cartID})
+	if err != nil {
+		logrus.WithContext(c).Error(err)
+		c.JSON(500, gin.H{
+			"error": "Internal Server Error",
+		})
+		return false
+	}
+
+	return authz.RequireOwner(c, caller, cart.UserID)
+}
@@ -1,27 +1,51 @@
 package delivery
 
 import (
+	"net/http"
+	"th3y3m/e-commerce-microservices/pkg/authz"
+	"th3y3m/e-commerce-microservices/pkg/discovery"
+	"th3y3m/e-commerce-microservices/pkg/logging"
+	"th3y3m/e-commerce-microservices/pkg/metrics"
+	"th3y3m/e-commerce-microservices/pkg/tracing"
+	"th3y3m/e-commerce-microservices/service/cart/config"
+	"th3y3m/e-commerce-microservices/service/cart/dependency_injection"
+
 	"github.com/gin-gonic/gin"
 )
 
-func RegisterHandlers() *gin.Engine {
-	r := gin.Default()
+// handler serves the routes of the service, with the usecases built from cfg
+type handler struct {
+	cfg config.Config
+	// Calls the other services for the usecases
+	services *http.Client
+}
+
+func RegisterHandlers(cfg config.Config, resolver discovery.Resolver) *gin.Engine {
+	h := &handler{cfg: cfg, services: dependency_injection.NewServiceClientProvider(cfg, resolver)}
+	keys := authz.NewKeys(cfg.Auth)
+
+	r := gin.New()
+	// Lets the request context, and the trace in it, reach the usecases through *gin.Context
+	r.ContextWithFallback = true
+	r.Use(gin.Recovery(), tracing.Middleware("cart"), logging.Middleware(), metrics.Middleware())
+	r.GET(metrics.Path, metrics.Handler())
+	r.Use(keys.Middleware("user_id"))
 
 	cart := r.Group("/api/carts")
 	{
-		cart.GET("/:cart_id", GetCartByID)
-		cart.POST("", CreateCart)
-		cart.PUT("", UpdateCart)
-		cart.DELETE("", DeleteCart)
-		cart.GET("/get-user-cart/:user_id", GetUserCart)
-		cart.POST("/add-item", AddProductToShoppingCart)
-		cart.PUT("/remove-item", RemoveProductFromShoppingCart)
-
-		cart.POST("/delete-unit-item", DeleteUnitItem)
-		cart.POST("/save-cart-to-cookie-handler", SaveCartToCookieHandler)
-		cart.DELETE("/remove-from-cart", RemoveFromCart)
-		cart.DELETE("/delete-cart-in-cookie", DeleteCartInCookie)
-		cart.GET("/number-of-items-in-cart-cookie", NumberOfItemsInCartCookie)
+		cart.GET("/:cart_id", h.GetCartByID)
+		cart.POST("", h.CreateCart)
+		cart.PUT("", h.UpdateCart)
+		cart.DELETE("", h.DeleteCart)
+		cart.GET("/get-user-cart/:user_id", h.GetUserCart)
+		cart.POST("/add-item", h.AddProductToShoppingCart)
+		cart.PUT("/remove-item", h.RemoveProductFromShoppingCart)
+
+		cart.POST("/delete-unit-item", h.DeleteUnitItem)
+		cart.POST("/save-cart-to-cookie-handler", h.SaveCartToCookieHandler)
+		cart.DELETE("/remove-from-cart", h.RemoveFromCart)
+		cart.DELETE("/delete-cart-in-cookie", h.DeleteCartInCookie)
+		cart.GET("/number-of-items-in-cart-cookie", h.NumberOfItemsInCartCookie)
 
 	}
 
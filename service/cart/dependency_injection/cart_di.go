@@ -1,21 +1,25 @@
 package dependency_injection
 
 import (
+	"net/http"
+	"th3y3m/e-commerce-microservices/pkg/authz"
+	"th3y3m/e-commerce-microservices/pkg/clients"
+	"th3y3m/e-commerce-microservices/pkg/discovery"
+	"th3y3m/e-commerce-microservices/pkg/logging"
 	"th3y3m/e-commerce-microservices/pkg/postgresql"
 	redis_client "th3y3m/e-commerce-microservices/pkg/redis"
+	"th3y3m/e-commerce-microservices/service/cart/config"
 	"th3y3m/e-commerce-microservices/service/cart/repository"
 	"th3y3m/e-commerce-microservices/service/cart/usecase"
-
-	"github.com/sirupsen/logrus"
 )
 
-func NewCartRepositoryProvider() repository.ICartRepository {
-	log := logrus.New()
-	db, err := postgresql.NewGormDB()
+func NewCartRepositoryProvider(cfg config.Config) repository.ICartRepository {
+	log := logging.Logger()
+	db, err := postgresql.NewGormDB(cfg.Postgres)
 	if err != nil {
 		log.Error(err)
 	}
-	redis, err := redis_client.ConnectToRedis()
+	redis, err := redis_client.ConnectToRedis(cfg.Redis)
 	if err != nil {
 		log.Error(err)
 	}
@@ -23,8 +27,14 @@ func NewCartRepositoryProvider() repository.ICartRepository {
 	return repository.NewCartRepository(db, redis, log)
 }
 
-func NewCartUsecaseProvider() usecase.ICartUsecase {
-	log := logrus.New()
-	cartRepository := NewCartRepositoryProvider()
-	return usecase.NewCartUsecase(cartRepository, log)
+func NewCartUsecaseProvider(cfg config.Config, httpClient *http.Client) usecase.ICartUsecase {
+	log := logging.Logger()
+	cartRepository := NewCartRepositoryProvider(cfg)
+	return usecase.NewCartUsecase(cartRepository, clients.NewCartItemClient(httpClient, clients.NewOptions(cfg.Clients)), log)
+}
+
+// NewServiceClientProvider returns the HTTP client the cart service calls other
+// services with, signed with its service token and sent to the instances resolver finds
+func NewServiceClientProvider(cfg config.Config, resolver discovery.Resolver) *http.Client {
+	return authz.NewKeys(cfg.Auth).NewServiceClient("cart", resolver)
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"th3y3m/e-commerce-microservices/pkg/metrics"
 
 	"github.com/redis/go-redis/v9"
 	"github.com/sirupsen/logrus"
@@ -33,7 +34,7 @@ func NewCartRepository(db *gorm.DB, redis *redis.Client, log *logrus.Logger) ICa
 }
 
 func (pr *cartRepository) Get(ctx context.Context, cartID *int64) (*Cart, error) {
-	pr.log.Infof("Fetching cart with ID: %d", *cartID)
+	pr.log.WithContext(ctx).Infof("Fetching cart with ID: %d", *cartID)
 	cacheKey := fmt.Sprintf("cart:%d", *cartID)
 
 	// Try to get the cart from Redis cache
@@ -42,21 +43,23 @@ func (pr *cartRepository) Get(ctx context.Context, cartID *int64) (*Cart, error)
 		if err == nil {
 			var cart Cart
 			if err := json.Unmarshal([]byte(cachedCart), &cart); err == nil {
-				pr.log.Infof("Cart found in cache: %+v", cart)
+				pr.log.WithContext(ctx).Infof("Cart found in cache: %+v", cart)
+				metrics.CacheHit("cartRepository.Get")
 				return &cart, nil
 			}
 		} else if err != redis.Nil {
-			pr.log.Warnf("Failed to get cart from Redis: %v", err)
+			pr.log.WithContext(ctx).Warnf("Failed to get cart from Redis: %v", err)
 		}
+		metrics.CacheMiss("cartRepository.Get")
 	} else {
-		pr.log.Warn("Redis client is not initialized")
+		pr.log.WithContext(ctx).Warn("Redis client is not initialized")
 	}
 
 	// If not found in cache, get from database
 	var cart Cart
 	result := pr.db.WithContext(ctx).First(&cart, cartID)
 	if result.Error != nil {
-		pr.log.Errorf("Error fetching cart: %v", result.Error)
+		pr.log.WithContext(ctx).Errorf("Error fetching cart: %v", result.Error)
 		return nil, result.Error
 	}
 
@@ -64,9 +67,9 @@ func (pr *cartRepository) Get(ctx context.Context, cartID *int64) (*Cart, error)
 	if pr.redis != nil {
 		cartJSON, _ := json.Marshal(cart)
 		if err := pr.redis.Set(ctx, cacheKey, cartJSON, 0).Err(); err != nil {
-			pr.log.Warnf("Failed to save cart to Redis: %v", err)
+			pr.log.WithContext(ctx).Warnf("Failed to save cart to Redis: %v", err)
 		} else {
-			pr.log.Infof("Cart saved to cache: %d", *cartID)
+			pr.log.WithContext(ctx).Infof("Cart saved to cache: %d", *cartID)
 		}
 	}
 
@@ -74,9 +77,9 @@ func (pr *cartRepository) Get(ctx context.Context, cartID *int64) (*Cart, error)
 }
 
 func (pr *cartRepository) Create(ctx context.Context, cart *Cart) (*Cart, error) {
-	pr.log.Infof("Creating cart: %+v", cart)
+	pr.log.WithContext(ctx).Infof("Creating cart: %+v", cart)
 	if err := pr.db.WithContext(ctx).Create(cart).Error; err != nil {
-		pr.log.Errorf("Error creating cart: %v", err)
+		pr.log.WithContext(ctx).Errorf("Error creating cart: %v", err)
 		return nil, err
 	}
 
@@ -86,16 +89,16 @@ func (pr *cartRepository) Create(ctx context.Context, cart *Cart) (*Cart, error)
 	if pr.redis != nil {
 		cartJSON, _ := json.Marshal(cart)
 		if err := pr.redis.Set(ctx, cacheKey, cartJSON, 0).Err(); err != nil {
-			pr.log.Warnf("Failed to save cart to Redis: %v", err)
+			pr.log.WithContext(ctx).Warnf("Failed to save cart to Redis: %v", err)
 		} else {
-			pr.log.Infof("Cart saved to cache: %d", cart.CartID)
+			pr.log.WithContext(ctx).Infof("Cart saved to cache: %d", cart.CartID)
 		}
 
 		// Invalidate the cache for all records
 		if err := pr.redis.Del(ctx, "all_carts").Err(); err != nil {
-			pr.log.Warnf("Failed to invalidate all carts cache: %v", err)
+			pr.log.WithContext(ctx).Warnf("Failed to invalidate all carts cache: %v", err)
 		} else {
-			pr.log.Info("Invalidated cache for all carts")
+			pr.log.WithContext(ctx).Info("Invalidated cache for all carts")
 		}
 	}
 
@@ -103,9 +106,9 @@ func (pr *cartRepository) Create(ctx context.Context, cart *Cart) (*Cart, error)
 }
 
 func (pr *cartRepository) Update(ctx context.Context, cart *Cart) (*Cart, error) {
-	pr.log.Infof("Updating cart with ID: %d", cart.CartID)
+	pr.log.WithContext(ctx).Infof("Updating cart with ID: %d", cart.CartID)
 	if err := pr.db.WithContext(ctx).Save(cart).Error; err != nil {
-		pr.log.Errorf("Error updating cart: %v", err)
+		pr.log.WithContext(ctx).Errorf("Error updating cart: %v", err)
 		return nil, err
 	}
 
@@ -115,9 +118,9 @@ func (pr *cartRepository) Update(ctx context.Context, cart *Cart) (*Cart, error)
 	if pr.redis != nil {
 		cartJSON, _ := json.Marshal(cart)
 		if err := pr.redis.Set(ctx, cacheKey, cartJSON, 0).Err(); err != nil {
-			pr.log.Warnf("Failed to save cart to Redis: %v", err)
+			pr.log.WithContext(ctx).Warnf("Failed to save cart to Redis: %v", err)
 		} else {
-			pr.log.Infof("Cart saved to cache: %d", cart.CartID)
+			pr.log.WithContext(ctx).Infof("Cart saved to cache: %d", cart.CartID)
 		}
 	}
 
@@ -143,16 +146,16 @@ func (pr *cartRepository) Update(ctx context.Context, cart *Cart) (*Cart, error)
 //	}
 
 func (pr *cartRepository) Delete(ctx context.Context, cartID int64) error {
-	pr.log.Infof("Deleting cart with ID: %d", cartID)
+	pr.log.WithContext(ctx).Infof("Deleting cart with ID: %d", cartID)
 
 	cart, err := pr.Get(ctx, &cartID)
 	if err != nil {
-		pr.log.Errorf("Error fetching cart: %v", err)
+		pr.log.WithContext(ctx).Errorf("Error fetching cart: %v", err)
 		return err
 	}
 
 	if err := pr.db.WithContext(ctx).Delete(&Cart{}, cartID).Error; err != nil {
-		pr.log.Errorf("Error deleting cart: %v", err)
+		pr.log.WithContext(ctx).Errorf("Error deleting cart: %v", err)
 		return err
 	}
 
@@ -162,15 +165,15 @@ func (pr *cartRepository) Delete(ctx context.Context, cartID int64) error {
 	// Delete from cache if Redis is available
 	if pr.redis != nil {
 		if err := pr.redis.Del(ctx, cacheKey).Err(); err != nil {
-			pr.log.Warnf("Failed to delete cart from Redis: %v", err)
+			pr.log.WithContext(ctx).Warnf("Failed to delete cart from Redis: %v", err)
 		} else {
-			pr.log.Infof("Cart deleted from cache: %d", cartID)
+			pr.log.WithContext(ctx).Infof("Cart deleted from cache: %d", cartID)
 		}
 
 		if err := pr.redis.Del(ctx, cacheKeyUser).Err(); err != nil {
-			pr.log.Warnf("Failed to delete user cart from Redis: %v", err)
+			pr.log.WithContext(ctx).Warnf("Failed to delete user cart from Redis: %v", err)
 		} else {
-			pr.log.Infof("User cart deleted from cache: %d", cart.UserID)
+			pr.log.WithContext(ctx).Infof("User cart deleted from cache: %d", cart.UserID)
 		}
 	}
 
@@ -178,7 +181,7 @@ func (pr *cartRepository) Delete(ctx context.Context, cartID int64) error {
 }
 
 func (pr *cartRepository) GetUserCart(ctx context.Context, userID int64) (*Cart, error) {
-	pr.log.Infof("Fetching cart for user: %d", userID)
+	pr.log.WithContext(ctx).Infof("Fetching cart for user: %d", userID)
 	cacheKey := fmt.Sprintf("user_cart:%d", userID)
 
 	// Try to get the cart from Redis cache
@@ -187,14 +190,16 @@ func (pr *cartRepository) GetUserCart(ctx context.Context, userID int64) (*Cart,
 		if err == nil {
 			var cart Cart
 			if err := json.Unmarshal([]byte(cachedCart), &cart); err == nil {
-				pr.log.Infof("Cart found in cache for user: %d", userID)
+				pr.log.WithContext(ctx).Infof("Cart found in cache for user: %d", userID)
+				metrics.CacheHit("cartRepository.GetUserCart")
 				return &cart, nil
 			}
 		} else if err != redis.Nil {
-			pr.log.Warnf("Failed to get cart from Redis: %v", err)
+			pr.log.WithContext(ctx).Warnf("Failed to get cart from Redis: %v", err)
 		}
+		metrics.CacheMiss("cartRepository.GetUserCart")
 	} else {
-		pr.log.Warn("Redis client is not initialized")
+		pr.log.WithContext(ctx).Warn("Redis client is not initialized")
 	}
 
 	// If not found in cache, get from database
@@ -202,7 +207,7 @@ func (pr *cartRepository) GetUserCart(ctx context.Context, userID int64) (*Cart,
 	result := pr.db.WithContext(ctx).Where("user_id = ? AND is_deleted = ?", userID, false).Order("created_at DESC").First(&cart)
 	if result.Error != nil {
 		if result.Error == gorm.ErrRecordNotFound {
-			pr.log.Infof("Cart not found for user: %d, creating new cart", userID)
+			pr.log.WithContext(ctx).Infof("Cart not found for user: %d, creating new cart", userID)
 			newCart := &Cart{
 				UserID: userID,
 			}
@@ -212,7 +217,7 @@ func (pr *cartRepository) GetUserCart(ctx context.Context, userID int64) (*Cart,
 			}
 			return createdCart, nil
 		}
-		pr.log.Errorf("Error fetching cart: %v", result.Error)
+		pr.log.WithContext(ctx).Errorf("Error fetching cart: %v", result.Error)
 		return nil, result.Error
 	}
 
@@ -220,9 +225,9 @@ func (pr *cartRepository) GetUserCart(ctx context.Context, userID int64) (*Cart,
 	if pr.redis != nil {
 		cartJSON, _ := json.Marshal(cart)
 		if err := pr.redis.Set(ctx, cacheKey, cartJSON, 0).Err(); err != nil {
-			pr.log.Warnf("Failed to save cart to Redis: %v", err)
+			pr.log.WithContext(ctx).Warnf("Failed to save cart to Redis: %v", err)
 		} else {
-			pr.log.Infof("Cart saved to cache for user: %d", userID)
+			pr.log.WithContext(ctx).Infof("Cart saved to cache for user: %d", userID)
 		}
 	}
 
@@ -1,12 +1,10 @@
 package usecase
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
 	"fmt"
 	"net/http"
-	"th3y3m/e-commerce-microservices/pkg/constant"
+	"th3y3m/e-commerce-microservices/pkg/clients"
 	"th3y3m/e-commerce-microservices/pkg/util"
 	"th3y3m/e-commerce-microservices/service/cart/model"
 	"th3y3m/e-commerce-microservices/service/cart/repository"
@@ -20,6 +18,8 @@ const tsCreateTimeLayout = "2006-01-02 15:04:05 +0700"
 type cartUsecase struct {
 	log      *logrus.Logger
 	cartRepo repository.ICartRepository
+	// Keeps the items of the carts in the cart item service
+	cartItemClient *clients.CartItemClient
 }
 
 type ICartUsecase interface {
@@ -41,22 +41,23 @@ type ICartUsecase interface {
 	SaveCartToCookieHandler(w http.ResponseWriter, r *http.Request, productId int64) error
 }
 
-func NewCartUsecase(cartRepo repository.ICartRepository, log *logrus.Logger) ICartUsecase {
+func NewCartUsecase(cartRepo repository.ICartRepository, cartItemClient *clients.CartItemClient, log *logrus.Logger) ICartUsecase {
 	return &cartUsecase{
-		cartRepo: cartRepo,
-		log:      log,
+		cartRepo:       cartRepo,
+		cartItemClient: cartItemClient,
+		log:            log,
 	}
 }
 
 func (pu *cartUsecase) GetCart(ctx context.Context, req *model.GetCartRequest) (*model.GetCartResponse, error) {
-	pu.log.Infof("Fetching cart with ID: %d", req.CartID)
+	pu.log.WithContext(ctx).Infof("Fetching cart with ID: %d", req.CartID)
 	cart, err := pu.cartRepo.Get(ctx, &req.CartID)
 	if err != nil {
-		pu.log.Errorf("Error fetching cart: %v", err)
+		pu.log.WithContext(ctx).Errorf("Error fetching cart: %v", err)
 		return nil, err
 	}
 
-	pu.log.Infof("Fetched cart: %+v", cart)
+	pu.log.WithContext(ctx).Infof("Fetched cart: %+v", cart)
 	return &model.GetCartResponse{
 		CartID:    cart.CartID,
 		UserID:    cart.UserID,
@@ -67,18 +68,18 @@ func (pu *cartUsecase) GetCart(ctx context.Context, req *model.GetCartRequest) (
 }
 
 func (pu *cartUsecase) CreateCart(ctx context.Context, cart *model.CreateCartRequest) (*model.GetCartResponse, error) {
-	pu.log.Infof("Creating cart: %+v", cart)
+	pu.log.WithContext(ctx).Infof("Creating cart: %+v", cart)
 	createdCart, err := pu.cartRepo.Create(ctx, &repository.Cart{
 		UserID:    cart.UserID,
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
 	})
 	if err != nil {
-		pu.log.Errorf("Error creating cart: %v", err)
+		pu.log.WithContext(ctx).Errorf("Error creating cart: %v", err)
 		return nil, err
 	}
 
-	pu.log.Infof("Created cart: %+v", createdCart)
+	pu.log.WithContext(ctx).Infof("Created cart: %+v", createdCart)
 	return &model.GetCartResponse{
 		CartID:    createdCart.CartID,
 		UserID:    createdCart.UserID,
@@ -89,22 +90,22 @@ func (pu *cartUsecase) CreateCart(ctx context.Context, cart *model.CreateCartReq
 }
 
 func (pu *cartUsecase) DeleteCart(ctx context.Context, req *model.DeleteCartRequest) error {
-	pu.log.Infof("Deleting cart with ID: %d", req.CartID)
+	pu.log.WithContext(ctx).Infof("Deleting cart with ID: %d", req.CartID)
 	err := pu.cartRepo.Delete(ctx, req.CartID)
 	if err != nil {
-		pu.log.Errorf("Error deleting cart: %v", err)
+		pu.log.WithContext(ctx).Errorf("Error deleting cart: %v", err)
 		return err
 	}
 
-	pu.log.Infof("Deleted cart with ID: %d", req.CartID)
+	pu.log.WithContext(ctx).Infof("Deleted cart with ID: %d", req.CartID)
 	return nil
 }
 
 func (pu *cartUsecase) UpdateCart(ctx context.Context, rep *model.UpdateCartRequest) (*model.GetCartResponse, error) {
-	pu.log.Infof("Updating cart with ID: %d", rep.CartID)
+	pu.log.WithContext(ctx).Infof("Updating cart with ID: %d", rep.CartID)
 	cart, err := pu.cartRepo.Get(ctx, &rep.CartID)
 	if err != nil {
-		pu.log.Errorf("Error fetching cart: %v", err)
+		pu.log.WithContext(ctx).Errorf("Error fetching cart: %v", err)
 		return nil, err
 	}
 
@@ -113,11 +114,11 @@ func (pu *cartUsecase) UpdateCart(ctx context.Context, rep *model.UpdateCartRequ
 
 	updatedCart, err := pu.cartRepo.Update(ctx, cart)
 	if err != nil {
-		pu.log.Errorf("Error updating cart: %v", err)
+		pu.log.WithContext(ctx).Errorf("Error updating cart: %v", err)
 		return nil, err
 	}
 
-	pu.log.Infof("Updated cart: %+v", updatedCart)
+	pu.log.WithContext(ctx).Infof("Updated cart: %+v", updatedCart)
 	return &model.GetCartResponse{
 		CartID:    updatedCart.CartID,
 		UserID:    updatedCart.UserID,
@@ -128,14 +129,14 @@ func (pu *cartUsecase) UpdateCart(ctx context.Context, rep *model.UpdateCartRequ
 }
 
 func (pu *cartUsecase) GetUserCart(ctx context.Context, userID int64) (*model.GetCartResponse, error) {
-	pu.log.Infof("Fetching cart for user with ID: %d", userID)
+	pu.log.WithContext(ctx).Infof("Fetching cart for user with ID: %d", userID)
 	cart, err := pu.cartRepo.GetUserCart(ctx, userID)
 	if err != nil {
-		pu.log.Errorf("Error fetching cart: %v", err)
+		pu.log.WithContext(ctx).Errorf("Error fetching cart: %v", err)
 		return nil, err
 	}
 
-	pu.log.Infof("Fetched cart: %+v", cart)
+	pu.log.WithContext(ctx).Infof("Fetched cart: %+v", cart)
 	return &model.GetCartResponse{
 		CartID:    cart.CartID,
 		UserID:    cart.UserID,
@@ -152,38 +153,9 @@ func (pu *cartUsecase) AddProductToShoppingCart(ctx context.Context, userID, pro
 		return err
 	}
 
-	cartItemReq := model.GetCartItemsRequest{
-		CartID: &cart.CartID,
-	}
-	cartItemData, err := json.Marshal(cartItemReq)
-	if err != nil {
-		pu.log.Errorf("Failed to marshal order data: %v", err)
-		return err
-	}
-
-	url := constant.CART_ITEM_SERVICE
-	req, err := http.NewRequest("GET", url, bytes.NewBuffer(cartItemData))
-	if err != nil {
-		return err
-	}
-
-	// Set the context and execute the request
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-
-	// Check if the request was successful
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("cart item service returned non-OK status: %d", resp.StatusCode)
-	}
-
-	// Decode the response into cart items
-	var cartItems []model.GetCartItemResponse
-	err = json.NewDecoder(resp.Body).Decode(&cartItems)
+	cartItems, err := pu.cartItemClient.List(ctx, clients.CartItemFilter{CartID: &cart.CartID})
 	if err != nil {
+		pu.log.WithContext(ctx).Errorf("Failed to fetch cart items: %v", err)
 		return err
 	}
 
@@ -201,38 +173,11 @@ func (pu *cartUsecase) AddProductToShoppingCart(ctx context.Context, userID, pro
 	}
 
 	// Update or create the cart item
-	cartItem := model.CartItem{
+	return pu.cartItemClient.Save(ctx, clients.CartItem{
 		CartID:    cart.CartID,
 		ProductID: productID,
 		Quantity:  productList[productID],
-	}
-
-	data, err := json.Marshal(cartItem)
-	if err != nil {
-		pu.log.Errorf("Failed to marshal order data: %v", err)
-		return err
-	}
-
-	url = constant.CART_ITEM_SERVICE + "/UpdateOrCreateCartItem"
-	req, err = http.NewRequest("PUT", url, bytes.NewBuffer(data))
-	if err != nil {
-		return err
-	}
-
-	// Set the context and execute the request
-
-	resp, err = client.Do(req)
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-
-	// Check if the request was successful
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("cart item service returned non-OK status: %d", resp.StatusCode)
-	}
-
-	return nil
+	})
 }
 
 func (pu *cartUsecase) RemoveProductFromShoppingCart(ctx context.Context, userID, productID int64, quantity int) error {
@@ -243,38 +188,9 @@ func (pu *cartUsecase) RemoveProductFromShoppingCart(ctx context.Context, userID
 	}
 
 	// Retrieve the cart items
-	cartItemReq := model.GetCartItemsRequest{
-		CartID: &cart.CartID,
-	}
-	cartItemData, err := json.Marshal(cartItemReq)
-	if err != nil {
-		pu.log.Errorf("Failed to marshal order data: %v", err)
-		return err
-	}
-
-	url := constant.CART_ITEM_SERVICE
-	req, err := http.NewRequest("GET", url, bytes.NewBuffer(cartItemData))
-	if err != nil {
-		return err
-	}
-
-	// Set the context and execute the request
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-
-	// Check if the request was successful
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("cart item service returned non-OK status: %d", resp.StatusCode)
-	}
-
-	// Decode the response into cart items
-	var cartItems []*model.GetCartItemResponse
-	err = json.NewDecoder(resp.Body).Decode(&cartItems)
+	cartItems, err := pu.cartItemClient.List(ctx, clients.CartItemFilter{CartID: &cart.CartID})
 	if err != nil {
+		pu.log.WithContext(ctx).Errorf("Failed to fetch cart items: %v", err)
 		return err
 	}
 
@@ -296,66 +212,16 @@ func (pu *cartUsecase) RemoveProductFromShoppingCart(ctx context.Context, userID
 	// Update the cart items
 	for _, item := range cartItems {
 		if _, ok := productList[item.ProductID]; ok {
-			cartItem := model.CartItem{
+			err = pu.cartItemClient.Save(ctx, clients.CartItem{
 				CartID:    cart.CartID,
 				ProductID: item.ProductID,
 				Quantity:  productList[item.ProductID],
-			}
-
-			data, err := json.Marshal(cartItem)
-			if err != nil {
-				pu.log.Errorf("Failed to marshal order data: %v", err)
-				return err
-			}
-
-			url = constant.CART_ITEM_SERVICE + "/UpdateOrCreateCartItem"
-			req, err = http.NewRequest("PUT", url, bytes.NewBuffer(data))
-			if err != nil {
-				return err
-			}
-
-			// Set the context and execute the request
-
-			resp, err = client.Do(req)
-			if err != nil {
-				return err
-			}
-			defer resp.Body.Close()
-
-			// Check if the request was successful
-			if resp.StatusCode != http.StatusOK {
-				return fmt.Errorf("cart item service returned non-OK status: %d", resp.StatusCode)
-			}
+			})
 		} else {
-			deleteCartItemRequest := model.DeleteCartItemRequest{
-				CartID:    cart.CartID,
-				ProductID: item.ProductID,
-			}
-
-			data, err := json.Marshal(deleteCartItemRequest)
-			if err != nil {
-				pu.log.Errorf("Failed to marshal order data: %v", err)
-				return err
-			}
-
-			url = constant.CART_ITEM_SERVICE
-			req, err = http.NewRequest("DELETE", url, bytes.NewBuffer(data))
-			if err != nil {
-				return err
-			}
-
-			// Set the context and execute the request
-
-			resp, err = client.Do(req)
-			if err != nil {
-				return err
-			}
-			defer resp.Body.Close()
-
-			// Check if the request was successful
-			if resp.StatusCode != http.StatusOK {
-				return fmt.Errorf("cart item service returned non-OK status: %d", resp.StatusCode)
-			}
+			err = pu.cartItemClient.Delete(ctx, cart.CartID, item.ProductID)
+		}
+		if err != nil {
+			return err
 		}
 	}
 
@@ -370,73 +236,18 @@ func (pu *cartUsecase) ClearShoppingCart(ctx context.Context, userID int64) erro
 	}
 
 	// Retrieve the cart items
-	cartItemReq := model.GetCartItemsRequest{
-		CartID: &cart.CartID,
-	}
-	cartItemData, err := json.Marshal(cartItemReq)
-	if err != nil {
-		pu.log.Errorf("Failed to marshal order data: %v", err)
-		return err
-	}
-
-	url := constant.CART_ITEM_SERVICE
-	req, err := http.NewRequest("GET", url, bytes.NewBuffer(cartItemData))
-	if err != nil {
-		return err
-	}
-
-	// Set the context and execute the request
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-
-	// Check if the request was successful
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("cart item service returned non-OK status: %d", resp.StatusCode)
-	}
-
-	// Decode the response into cart items
-	var cartItems []model.GetCartItemResponse
-	err = json.NewDecoder(resp.Body).Decode(&cartItems)
+	cartItems, err := pu.cartItemClient.List(ctx, clients.CartItemFilter{CartID: &cart.CartID})
 	if err != nil {
+		pu.log.WithContext(ctx).Errorf("Failed to fetch cart items: %v", err)
 		return err
 	}
 
 	// Delete all cart items
 	for _, item := range cartItems {
-		deleteCartItemRequest := model.DeleteCartItemRequest{
-			CartID:    cart.CartID,
-			ProductID: item.ProductID,
-		}
-
-		data, err := json.Marshal(deleteCartItemRequest)
-		if err != nil {
-			pu.log.Errorf("Failed to marshal delete cart item request: %v", err)
-			return err
-		}
-
-		url := constant.CART_ITEM_SERVICE
-		req, err := http.NewRequest("DELETE", url, bytes.NewBuffer(data))
-		if err != nil {
+		if err := pu.cartItemClient.Delete(ctx, cart.CartID, item.ProductID); err != nil {
+			pu.log.WithContext(ctx).Errorf("Failed to delete cart item: %v", err)
 			return err
 		}
-		req.Header.Set("Content-Type", "application/json")
-
-		// Set the context and execute the request
-		client := &http.Client{}
-		resp, err := client.Do(req)
-		if err != nil {
-			return err
-		}
-		defer resp.Body.Close()
-
-		// Check if the request was successful
-		if resp.StatusCode != http.StatusOK {
-			return fmt.Errorf("cart item service returned non-OK status: %d", resp.StatusCode)
-		}
 	}
 
 	return nil
@@ -450,38 +261,9 @@ func (pu *cartUsecase) NumberOfItemsInCart(ctx context.Context, userID int64) (i
 	}
 
 	// Retrieve the cart items
-	cartItemReq := model.GetCartItemsRequest{
-		CartID: &cart.CartID,
-	}
-	cartItemData, err := json.Marshal(cartItemReq)
-	if err != nil {
-		pu.log.Errorf("Failed to marshal order data: %v", err)
-		return 0, err
-	}
-
-	url := constant.CART_ITEM_SERVICE
-	req, err := http.NewRequest("GET", url, bytes.NewBuffer(cartItemData))
-	if err != nil {
-		return 0, err
-	}
-
-	// Set the context and execute the request
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		return 0, err
-	}
-	defer resp.Body.Close()
-
-	// Check if the request was successful
-	if resp.StatusCode != http.StatusOK {
-		return 0, fmt.Errorf("cart item service returned non-OK status: %d", resp.StatusCode)
-	}
-
-	// Decode the response into cart items
-	var cartItems []model.GetCartItemResponse
-	err = json.NewDecoder(resp.Body).Decode(&cartItems)
+	cartItems, err := pu.cartItemClient.List(ctx, clients.CartItemFilter{CartID: &cart.CartID})
 	if err != nil {
+		pu.log.WithContext(ctx).Errorf("Failed to fetch cart items: %v", err)
 		return 0, err
 	}
 
@@ -8,22 +8,22 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
-func GetCartItemByID(c *gin.Context) {
+func (h *handler) GetCartItemByID(c *gin.Context) {
 	var req model.GetCartItemRequest
-	err := c.BindJSON(&req)
+	err := c.ShouldBindQuery(&req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(400, gin.H{
 			"error": "Bad Request",
 		})
 		return
 	}
 
-	module := dependency_injection.NewCartItemUsecaseProvider()
+	module := dependency_injection.NewCartItemUsecaseProvider(h.cfg)
 
 	cartItem, err := module.GetCartItem(c, &req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(500, gin.H{
 			"error": "Internal Server Error",
 		})
@@ -33,22 +33,22 @@ func GetCartItemByID(c *gin.Context) {
 	c.JSON(200, cartItem)
 }
 
-func CreateCartItem(c *gin.Context) {
+func (h *handler) CreateCartItem(c *gin.Context) {
 	var req model.CreateCartItemRequest
 	err := c.BindJSON(&req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(400, gin.H{
 			"error": "Bad Request",
 		})
 		return
 	}
 
-	module := dependency_injection.NewCartItemUsecaseProvider()
+	module := dependency_injection.NewCartItemUsecaseProvider(h.cfg)
 
 	cartItem, err := module.CreateCartItem(c, &req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(500, gin.H{
 			"error": "Internal Server Error",
 		})
@@ -58,22 +58,22 @@ func CreateCartItem(c *gin.Context) {
 	c.JSON(200, cartItem)
 }
 
-func UpdateCartItem(c *gin.Context) {
+func (h *handler) UpdateCartItem(c *gin.Context) {
 	var req model.UpdateCartItemRequest
 	err := c.BindJSON(&req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(400, gin.H{
 			"error": "Bad Request",
 		})
 		return
 	}
 
-	module := dependency_injection.NewCartItemUsecaseProvider()
+	module := dependency_injection.NewCartItemUsecaseProvider(h.cfg)
 
 	cartItem, err := module.UpdateCartItem(c, &req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(500, gin.H{
 			"error": "Internal Server Error",
 		})
@@ -83,23 +83,23 @@ func UpdateCartItem(c *gin.Context) {
 	c.JSON(200, cartItem)
 }
 
-func DeleteCartItem(c *gin.Context) {
+func (h *handler) DeleteCartItem(c *gin.Context) {
 	var req model.DeleteCartItemRequest
 
 	err := c.BindJSON(&req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(400, gin.H{
 			"error": "Bad Request",
 		})
 		return
 	}
 
-	module := dependency_injection.NewCartItemUsecaseProvider()
+	module := dependency_injection.NewCartItemUsecaseProvider(h.cfg)
 
 	err = module.DeleteCartItem(c, &req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(500, gin.H{
 			"error": "Internal Server Error",
 		})
@@ -111,22 +111,22 @@ func DeleteCartItem(c *gin.Context) {
 	})
 }
 
-func GetCartItems(c *gin.Context) {
+func (h *handler) GetCartItems(c *gin.Context) {
 	var req model.GetCartItemsRequest
-	err := c.BindJSON(&req)
+	err := c.ShouldBindQuery(&req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(400, gin.H{
 			"error": "Bad Request",
 		})
 		return
 	}
 
-	module := dependency_injection.NewCartItemUsecaseProvider()
+	module := dependency_injection.NewCartItemUsecaseProvider(h.cfg)
 
 	cartItems, err := module.GetCartItemList(c, &req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(500, gin.H{
 			"error": "Internal Server Error",
 		})
@@ -136,22 +136,22 @@ func GetCartItems(c *gin.Context) {
 	c.JSON(200, cartItems)
 }
 
-func UpdateOrCreateCartItem(c *gin.Context) {
+func (h *handler) UpdateOrCreateCartItem(c *gin.Context) {
 	var req model.UpdateOrCreateRequest
 	err := c.BindJSON(&req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(400, gin.H{
 			"error": "Bad Request",
 		})
 		return
 	}
 
-	module := dependency_injection.NewCartItemUsecaseProvider()
+	module := dependency_injection.NewCartItemUsecaseProvider(h.cfg)
 
 	err = module.UpdateOrCreate(c, &req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(500, gin.H{
 			"error": "Internal Server Error",
 		})
@@ -1,20 +1,39 @@
 package delivery
 
 import (
+	"th3y3m/e-commerce-microservices/pkg/authz"
+	"th3y3m/e-commerce-microservices/pkg/logging"
+	"th3y3m/e-commerce-microservices/pkg/metrics"
+	"th3y3m/e-commerce-microservices/pkg/tracing"
+	"th3y3m/e-commerce-microservices/service/cart_item/config"
+
 	"github.com/gin-gonic/gin"
 )
 
-func RegisterHandlers() *gin.Engine {
-	r := gin.Default()
+// handler serves the routes of the service, with the usecases built from cfg
+type handler struct {
+	cfg config.Config
+}
+
+func RegisterHandlers(cfg config.Config) *gin.Engine {
+	h := &handler{cfg: cfg}
+	keys := authz.NewKeys(cfg.Auth)
+
+	r := gin.New()
+	// Lets the request context, and the trace in it, reach the usecases through *gin.Context
+	r.ContextWithFallback = true
+	r.Use(gin.Recovery(), tracing.Middleware("cart_item"), logging.Middleware(), metrics.Middleware())
+	r.GET(metrics.Path, metrics.Handler())
+	r.Use(keys.Middleware())
 
 	cartItem := r.Group("/api/cartItems")
 	{
-		cartItem.GET("/GetCartItemByID", GetCartItemByID)
-		cartItem.GET("", GetCartItems)
-		cartItem.POST("", CreateCartItem)
-		cartItem.PUT("", UpdateCartItem)
-		cartItem.PUT("/UpdateOrCreateCartItem", UpdateOrCreateCartItem)
-		cartItem.DELETE("", DeleteCartItem)
+		cartItem.GET("/GetCartItemByID", h.GetCartItemByID)
+		cartItem.GET("", h.GetCartItems)
+		cartItem.POST("", h.CreateCartItem)
+		cartItem.PUT("", h.UpdateCartItem)
+		cartItem.PUT("/UpdateOrCreateCartItem", keys.InternalOnly("cart"), h.UpdateOrCreateCartItem)
+		cartItem.DELETE("", h.DeleteCartItem)
 	}
 
 	return r
@@ -1,21 +1,21 @@
 package dependency_injection
 
 import (
+	"th3y3m/e-commerce-microservices/pkg/logging"
 	"th3y3m/e-commerce-microservices/pkg/postgresql"
 	redis_client "th3y3m/e-commerce-microservices/pkg/redis"
+	"th3y3m/e-commerce-microservices/service/cart_item/config"
 	"th3y3m/e-commerce-microservices/service/cart_item/repository"
 	"th3y3m/e-commerce-microservices/service/cart_item/usecase"
-
-	"github.com/sirupsen/logrus"
 )
 
-func NewCartItemRepositoryProvider() repository.ICartItemRepository {
-	log := logrus.New()
-	db, err := postgresql.NewGormDB()
+func NewCartItemRepositoryProvider(cfg config.Config) repository.ICartItemRepository {
+	log := logging.Logger()
+	db, err := postgresql.NewGormDB(cfg.Postgres)
 	if err != nil {
 		log.Error(err)
 	}
-	redis, err := redis_client.ConnectToRedis()
+	redis, err := redis_client.ConnectToRedis(cfg.Redis)
 	if err != nil {
 		log.Error(err)
 	}
@@ -23,8 +23,8 @@ func NewCartItemRepositoryProvider() repository.ICartItemRepository {
 	return repository.NewCartItemRepository(db, redis, log)
 }
 
-func NewCartItemUsecaseProvider() usecase.ICartItemUsecase {
-	log := logrus.New()
-	cartItemRepository := NewCartItemRepositoryProvider()
+func NewCartItemUsecaseProvider(cfg config.Config) usecase.ICartItemUsecase {
+	log := logging.Logger()
+	cartItemRepository := NewCartItemRepositoryProvider(cfg)
 	return usecase.NewCartItemUsecase(cartItemRepository, log)
 }
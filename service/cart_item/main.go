@@ -1,34 +1,38 @@
 package main
 
 import (
+	"context"
 	"log"
-	"os"
-	"strings"
+	pkgconfig "th3y3m/e-commerce-microservices/pkg/config"
+	"th3y3m/e-commerce-microservices/pkg/discovery"
+	"th3y3m/e-commerce-microservices/pkg/health"
+	"th3y3m/e-commerce-microservices/pkg/logging"
+	"th3y3m/e-commerce-microservices/pkg/server"
+	"th3y3m/e-commerce-microservices/pkg/tracing"
+	"th3y3m/e-commerce-microservices/service/cart_item/config"
 	"th3y3m/e-commerce-microservices/service/cart_item/delivery"
-
-	"github.com/spf13/viper"
 )
 
 func main() {
-	viper.SetConfigFile("../../.env")
-	viper.AutomaticEnv()
-	if err := viper.ReadInConfig(); err != nil {
-		log.Printf("error while reading config file: %s", err.Error())
-		return
-	}
-	log.Println("Config file loaded successfully")
+	logging.Setup("cart_item")
+
+	var cfg config.Config
+	pkgconfig.MustLoad("cart_item", &cfg)
+
+	shutdownTracing := tracing.Setup("cart_item", cfg.Tracing)
+	defer shutdownTracing(context.Background())
 
-	for _, env := range viper.AllKeys() {
-		if viper.GetString(env) != "" {
-			_ = os.Setenv(env, viper.GetString(env))
-			_ = os.Setenv(strings.ToUpper(env), viper.GetString(env))
-		}
+	resolver, err := discovery.New(cfg.Discovery, cfg.Redis)
+	if err != nil {
+		log.Fatalf("Failed to set up service discovery: %v", err)
 	}
 
-	r := delivery.RegisterHandlers()
+	checker := health.NewChecker(health.Postgres(cfg.Postgres), health.Redis(cfg.Redis))
+	r := delivery.RegisterHandlers(cfg)
+	checker.Register(r)
 
 	log.Println("Starting server on port 8084")
-	if err := r.Run(":8084"); err != nil {
-		log.Fatalf("Error starting server: %v", err)
+	if err := server.Run(":8084", cfg.Server, r, checker, discovery.Heartbeat(discovery.CartItem, resolver, cfg.Discovery)); err != nil {
+		log.Fatalf("Error running server: %v", err)
 	}
 }
@@ -7,13 +7,13 @@ type UpdateOrCreateRequest struct {
 }
 
 type GetCartItemRequest struct {
-	CartID    int64 `json:"cart_id"`
-	ProductID int64 `json:"product_id"`
+	CartID    int64 `json:"cart_id" form:"cart_id"`
+	ProductID int64 `json:"product_id" form:"product_id"`
 }
 
 type GetCartItemsRequest struct {
-	CartID    *int64 `json:"cart_id"`
-	ProductID *int64 `json:"product_id"`
+	CartID    *int64 `json:"cart_id" form:"cart_id"`
+	ProductID *int64 `json:"product_id" form:"product_id"`
 }
 
 type DeleteCartItemRequest struct {
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"th3y3m/e-commerce-microservices/pkg/metrics"
 	"time"
 
 	"github.com/redis/go-redis/v9"
@@ -35,7 +36,7 @@ func NewCartItemRepository(db *gorm.DB, redis *redis.Client, log *logrus.Logger)
 }
 
 func (pr *cartItemRepository) Get(ctx context.Context, cartID, productID int64) (*CartItem, error) {
-	pr.log.Infof("Fetching cartItem with cartID: %d and productID: %d", cartID, productID)
+	pr.log.WithContext(ctx).Infof("Fetching cartItem with cartID: %d and productID: %d", cartID, productID)
 	cacheKey := fmt.Sprintf("cartItem:%d:%d", cartID, productID)
 
 	// Try to get the cartItem from Redis cache
@@ -44,21 +45,23 @@ func (pr *cartItemRepository) Get(ctx context.Context, cartID, productID int64)
 		if err == nil {
 			var cartItem CartItem
 			if err := json.Unmarshal([]byte(cachedCartItem), &cartItem); err == nil {
-				pr.log.Infof("CartItem found in cache: %+v", cartItem)
+				pr.log.WithContext(ctx).Infof("CartItem found in cache: %+v", cartItem)
+				metrics.CacheHit("cartItemRepository.Get")
 				return &cartItem, nil
 			}
 		} else if err != redis.Nil {
-			pr.log.Warnf("Failed to get cartItem from Redis: %v", err)
+			pr.log.WithContext(ctx).Warnf("Failed to get cartItem from Redis: %v", err)
 		}
+		metrics.CacheMiss("cartItemRepository.Get")
 	} else {
-		pr.log.Warn("Redis client is not initialized")
+		pr.log.WithContext(ctx).Warn("Redis client is not initialized")
 	}
 
 	// If not found in cache, get from database
 	var cartItem CartItem
 	result := pr.db.WithContext(ctx).First(&cartItem, "cart_id = ? AND product_id = ?", cartID, productID)
 	if result.Error != nil {
-		pr.log.Errorf("Error fetching cartItem: %v", result.Error)
+		pr.log.WithContext(ctx).Errorf("Error fetching cartItem: %v", result.Error)
 		return nil, result.Error
 	}
 
@@ -66,9 +69,9 @@ func (pr *cartItemRepository) Get(ctx context.Context, cartID, productID int64)
 	if pr.redis != nil {
 		cartItemJSON, _ := json.Marshal(cartItem)
 		if err := pr.redis.Set(ctx, cacheKey, cartItemJSON, time.Hour).Err(); err != nil {
-			pr.log.Warnf("Failed to save cartItem to Redis: %v", err)
+			pr.log.WithContext(ctx).Warnf("Failed to save cartItem to Redis: %v", err)
 		} else {
-			pr.log.Infof("CartItem saved to cache: %d:%d", cartID, productID)
+			pr.log.WithContext(ctx).Infof("CartItem saved to cache: %d:%d", cartID, productID)
 		}
 	}
 
@@ -76,10 +79,10 @@ func (pr *cartItemRepository) Get(ctx context.Context, cartID, productID int64)
 }
 
 func (pr *cartItemRepository) Create(ctx context.Context, cartItem *CartItem) (*CartItem, error) {
-	pr.log.Infof("Creating cartItem: %+v", cartItem)
+	pr.log.WithContext(ctx).Infof("Creating cartItem: %+v", cartItem)
 	result := pr.db.WithContext(ctx).Create(cartItem)
 	if result.Error != nil {
-		pr.log.Errorf("Error creating cartItem: %v", result.Error)
+		pr.log.WithContext(ctx).Errorf("Error creating cartItem: %v", result.Error)
 		return nil, result.Error
 	}
 	cacheKey := fmt.Sprintf("cartItem:%d:%d", cartItem.CartID, cartItem.ProductID)
@@ -88,9 +91,9 @@ func (pr *cartItemRepository) Create(ctx context.Context, cartItem *CartItem) (*
 	if pr.redis != nil {
 		cartItemJSON, _ := json.Marshal(cartItem)
 		if err := pr.redis.Set(ctx, cacheKey, cartItemJSON, time.Hour).Err(); err != nil {
-			pr.log.Warnf("Failed to save cartItem to Redis: %v", err)
+			pr.log.WithContext(ctx).Warnf("Failed to save cartItem to Redis: %v", err)
 		} else {
-			pr.log.Infof("CartItem saved to cache: %d:%d", cartItem.CartID, cartItem.ProductID)
+			pr.log.WithContext(ctx).Infof("CartItem saved to cache: %d:%d", cartItem.CartID, cartItem.ProductID)
 		}
 	}
 
@@ -98,10 +101,10 @@ func (pr *cartItemRepository) Create(ctx context.Context, cartItem *CartItem) (*
 }
 
 func (pr *cartItemRepository) Update(ctx context.Context, cartItem *CartItem) (*CartItem, error) {
-	pr.log.Infof("Updating cartItem: %+v", cartItem)
+	pr.log.WithContext(ctx).Infof("Updating cartItem: %+v", cartItem)
 	result := pr.db.WithContext(ctx).Save(cartItem)
 	if result.Error != nil {
-		pr.log.Errorf("Error updating cartItem: %v", result.Error)
+		pr.log.WithContext(ctx).Errorf("Error updating cartItem: %v", result.Error)
 		return nil, result.Error
 	}
 	cacheKey := fmt.Sprintf("cartItem:%d:%d", cartItem.CartID, cartItem.ProductID)
@@ -110,9 +113,9 @@ func (pr *cartItemRepository) Update(ctx context.Context, cartItem *CartItem) (*
 	if pr.redis != nil {
 		cartItemJSON, _ := json.Marshal(cartItem)
 		if err := pr.redis.Set(ctx, cacheKey, cartItemJSON, time.Hour).Err(); err != nil {
-			pr.log.Warnf("Failed to save cartItem to Redis: %v", err)
+			pr.log.WithContext(ctx).Warnf("Failed to save cartItem to Redis: %v", err)
 		} else {
-			pr.log.Infof("CartItem saved to cache: %d:%d", cartItem.CartID, cartItem.ProductID)
+			pr.log.WithContext(ctx).Infof("CartItem saved to cache: %d:%d", cartItem.CartID, cartItem.ProductID)
 		}
 	}
 
@@ -120,22 +123,22 @@ func (pr *cartItemRepository) Update(ctx context.Context, cartItem *CartItem) (*
 }
 
 func (pr *cartItemRepository) Delete(ctx context.Context, cartID, productID int64) error {
-	pr.log.Infof("Deleting cartItem with cartID: %d and productID: %d", cartID, productID)
+	pr.log.WithContext(ctx).Infof("Deleting cartItem with cartID: %d and productID: %d", cartID, productID)
 	cacheKey := fmt.Sprintf("cartItem:%d:%d", cartID, productID)
 
 	// Delete the cartItem from the database
 	result := pr.db.WithContext(ctx).Delete(&CartItem{}, "cart_id = ? AND product_id = ?", cartID, productID)
 	if result.Error != nil {
-		pr.log.Errorf("Error deleting cartItem: %v", result.Error)
+		pr.log.WithContext(ctx).Errorf("Error deleting cartItem: %v", result.Error)
 		return result.Error
 	}
 
 	// Delete the cartItem from the cache if Redis is available
 	if pr.redis != nil {
 		if err := pr.redis.Del(ctx, cacheKey).Err(); err != nil {
-			pr.log.Warnf("Failed to delete cartItem from Redis: %v", err)
+			pr.log.WithContext(ctx).Warnf("Failed to delete cartItem from Redis: %v", err)
 		} else {
-			pr.log.Infof("CartItem deleted from cache: %d:%d", cartID, productID)
+			pr.log.WithContext(ctx).Infof("CartItem deleted from cache: %d:%d", cartID, productID)
 		}
 	}
 
@@ -143,7 +146,7 @@ func (pr *cartItemRepository) Delete(ctx context.Context, cartID, productID int6
 }
 
 func (pr *cartItemRepository) GetList(ctx context.Context, cartID, productID *int64) ([]*CartItem, error) {
-	pr.log.Info("Fetching cartItems")
+	pr.log.WithContext(ctx).Info("Fetching cartItems")
 	var cartItems []*CartItem
 	cacheKey := "cartItems"
 
@@ -163,20 +166,22 @@ func (pr *cartItemRepository) GetList(ctx context.Context, cartID, productID *in
 		cachedCartItems, err := pr.redis.Get(ctx, cacheKey).Result()
 		if err == nil {
 			if err := json.Unmarshal([]byte(cachedCartItems), &cartItems); err == nil {
-				pr.log.Info("CartItems found in cache")
+				pr.log.WithContext(ctx).Info("CartItems found in cache")
+				metrics.CacheHit("cartItemRepository.GetList")
 				return cartItems, nil
 			}
 		} else if err != redis.Nil {
-			pr.log.Warnf("Failed to get cartItems from Redis: %v", err)
+			pr.log.WithContext(ctx).Warnf("Failed to get cartItems from Redis: %v", err)
 		}
+		metrics.CacheMiss("cartItemRepository.GetList")
 	} else {
-		pr.log.Warn("Redis client is not initialized")
+		pr.log.WithContext(ctx).Warn("Redis client is not initialized")
 	}
 
 	// If not found in cache, get from database
 	result := query.Find(&cartItems)
 	if result.Error != nil {
-		pr.log.Errorf("Error fetching cartItems: %v", result.Error)
+		pr.log.WithContext(ctx).Errorf("Error fetching cartItems: %v", result.Error)
 		return nil, result.Error
 	}
 
@@ -184,9 +189,9 @@ func (pr *cartItemRepository) GetList(ctx context.Context, cartID, productID *in
 	if pr.redis != nil {
 		cartItemsJSON, _ := json.Marshal(cartItems)
 		if err := pr.redis.Set(ctx, cacheKey, cartItemsJSON, time.Hour).Err(); err != nil {
-			pr.log.Warnf("Failed to save cartItems to Redis: %v", err)
+			pr.log.WithContext(ctx).Warnf("Failed to save cartItems to Redis: %v", err)
 		} else {
-			pr.log.Info("CartItems saved to cache")
+			pr.log.WithContext(ctx).Info("CartItems saved to cache")
 		}
 	}
 
@@ -194,7 +199,7 @@ func (pr *cartItemRepository) GetList(ctx context.Context, cartID, productID *in
 }
 
 func (pr *cartItemRepository) UpdateOrCreate(ctx context.Context, cartItem CartItem) error {
-	pr.log.Infof("Updating or creating cart item with cart ID: %d and product ID: %d", cartItem.CartID, cartItem.ProductID)
+	pr.log.WithContext(ctx).Infof("Updating or creating cart item with cart ID: %d and product ID: %d", cartItem.CartID, cartItem.ProductID)
 
 	var existingCartItem CartItem
 	cacheKey := fmt.Sprintf("cartItem:%d:%d", cartItem.CartID, cartItem.ProductID)
@@ -203,21 +208,21 @@ func (pr *cartItemRepository) UpdateOrCreate(ctx context.Context, cartItem CartI
 	if err := pr.db.WithContext(ctx).Where("cart_id = ? AND product_id = ?", cartItem.CartID, cartItem.ProductID).First(&existingCartItem).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			// Create new cart item if not found
-			pr.log.Infof("Cart item not found, creating new cart item with cart ID: %d and product ID: %d", cartItem.CartID, cartItem.ProductID)
+			pr.log.WithContext(ctx).Infof("Cart item not found, creating new cart item with cart ID: %d and product ID: %d", cartItem.CartID, cartItem.ProductID)
 			if err := pr.db.WithContext(ctx).Create(&cartItem).Error; err != nil {
-				pr.log.Error("Failed to create new cart item:", err)
+				pr.log.WithContext(ctx).Error("Failed to create new cart item:", err)
 				return err
 			}
 		} else {
-			pr.log.Error("Failed to fetch cart item for update or create:", err)
+			pr.log.WithContext(ctx).Error("Failed to fetch cart item for update or create:", err)
 			return err
 		}
 	} else {
 		// Update existing cart item
-		pr.log.Infof("Cart item found, updating cart item with cart ID: %d and product ID: %d", cartItem.CartID, cartItem.ProductID)
+		pr.log.WithContext(ctx).Infof("Cart item found, updating cart item with cart ID: %d and product ID: %d", cartItem.CartID, cartItem.ProductID)
 		existingCartItem.Quantity = cartItem.Quantity
 		if err := pr.db.WithContext(ctx).Save(&existingCartItem).Error; err != nil {
-			pr.log.Error("Failed to update cart item:", err)
+			pr.log.WithContext(ctx).Error("Failed to update cart item:", err)
 			return err
 		}
 	}
@@ -226,20 +231,20 @@ func (pr *cartItemRepository) UpdateOrCreate(ctx context.Context, cartItem CartI
 	if pr.redis != nil {
 		cartItemJSON, _ := json.Marshal(cartItem)
 		if err := pr.redis.Set(ctx, cacheKey, cartItemJSON, time.Hour).Err(); err != nil {
-			pr.log.Warnf("Failed to save cartItem to Redis: %v", err)
+			pr.log.WithContext(ctx).Warnf("Failed to save cartItem to Redis: %v", err)
 		} else {
-			pr.log.Infof("CartItem saved to cache: %d:%d", cartItem.CartID, cartItem.ProductID)
+			pr.log.WithContext(ctx).Infof("CartItem saved to cache: %d:%d", cartItem.CartID, cartItem.ProductID)
 		}
 
 		// Invalidate related cache entries
 		if err := pr.redis.Del(ctx, fmt.Sprintf("cartItems:cartID:%d", cartItem.CartID)).Err(); err != nil {
-			pr.log.Warnf("Failed to invalidate cartItems cache by cartID: %v", err)
+			pr.log.WithContext(ctx).Warnf("Failed to invalidate cartItems cache by cartID: %v", err)
 		}
 		if err := pr.redis.Del(ctx, fmt.Sprintf("cartItems:productID:%d", cartItem.ProductID)).Err(); err != nil {
-			pr.log.Warnf("Failed to invalidate cartItems cache by productID: %v", err)
+			pr.log.WithContext(ctx).Warnf("Failed to invalidate cartItems cache by productID: %v", err)
 		}
 	}
 
-	pr.log.Infof("Successfully updated or created cart item with cart ID: %d and product ID: %d", cartItem.CartID, cartItem.ProductID)
+	pr.log.WithContext(ctx).Infof("Successfully updated or created cart item with cart ID: %d and product ID: %d", cartItem.CartID, cartItem.ProductID)
 	return nil
 }
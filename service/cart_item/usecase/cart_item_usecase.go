@@ -30,7 +30,7 @@ func NewCartItemUsecase(cartItemRepo repository.ICartItemRepository, log *logrus
 }
 
 func (pu *cartItemUsecase) UpdateOrCreate(ctx context.Context, req *model.UpdateOrCreateRequest) error {
-	pu.log.Infof("Updating or creating cartItem: %+v", req)
+	pu.log.WithContext(ctx).Infof("Updating or creating cartItem: %+v", req)
 
 	cartItem := &repository.CartItem{
 		CartID:    req.CartID,
@@ -40,23 +40,23 @@ func (pu *cartItemUsecase) UpdateOrCreate(ctx context.Context, req *model.Update
 
 	err := pu.cartItemRepo.UpdateOrCreate(ctx, *cartItem)
 	if err != nil {
-		pu.log.Errorf("Error updating or creating cartItem: %v", err)
+		pu.log.WithContext(ctx).Errorf("Error updating or creating cartItem: %v", err)
 		return err
 	}
 
-	pu.log.Infof("Updated or created cartItem: %+v", cartItem)
+	pu.log.WithContext(ctx).Infof("Updated or created cartItem: %+v", cartItem)
 	return nil
 }
 
 func (pu *cartItemUsecase) GetCartItem(ctx context.Context, req *model.GetCartItemRequest) (*model.GetCartItemResponse, error) {
-	pu.log.Infof("Fetching cartItem with cartID: %d and productID: %d", req.CartID, req.ProductID)
+	pu.log.WithContext(ctx).Infof("Fetching cartItem with cartID: %d and productID: %d", req.CartID, req.ProductID)
 	cartItem, err := pu.cartItemRepo.Get(ctx, req.CartID, req.ProductID)
 	if err != nil {
-		pu.log.Errorf("Error fetching cartItem: %v", err)
+		pu.log.WithContext(ctx).Errorf("Error fetching cartItem: %v", err)
 		return nil, err
 	}
 
-	pu.log.Infof("Fetched cartItem: %+v", cartItem)
+	pu.log.WithContext(ctx).Infof("Fetched cartItem: %+v", cartItem)
 	return &model.GetCartItemResponse{
 		CartID:    cartItem.CartID,
 		ProductID: cartItem.ProductID,
@@ -65,18 +65,18 @@ func (pu *cartItemUsecase) GetCartItem(ctx context.Context, req *model.GetCartIt
 }
 
 func (pu *cartItemUsecase) CreateCartItem(ctx context.Context, cartItem *model.CreateCartItemRequest) (*model.GetCartItemResponse, error) {
-	pu.log.Infof("Creating cartItem: %+v", cartItem)
+	pu.log.WithContext(ctx).Infof("Creating cartItem: %+v", cartItem)
 	createdCartItem, err := pu.cartItemRepo.Create(ctx, &repository.CartItem{
 		CartID:    cartItem.CartID,
 		ProductID: cartItem.ProductID,
 		Quantity:  cartItem.Quantity,
 	})
 	if err != nil {
-		pu.log.Errorf("Error creating cartItem: %v", err)
+		pu.log.WithContext(ctx).Errorf("Error creating cartItem: %v", err)
 		return nil, err
 	}
 
-	pu.log.Infof("Created cartItem: %+v", createdCartItem)
+	pu.log.WithContext(ctx).Infof("Created cartItem: %+v", createdCartItem)
 	return &model.GetCartItemResponse{
 		CartID:    createdCartItem.CartID,
 		ProductID: createdCartItem.ProductID,
@@ -85,18 +85,18 @@ func (pu *cartItemUsecase) CreateCartItem(ctx context.Context, cartItem *model.C
 }
 
 func (pu *cartItemUsecase) UpdateCartItem(ctx context.Context, req *model.UpdateCartItemRequest) (*model.GetCartItemResponse, error) {
-	pu.log.Infof("Updating cartItem with cartID: %d and productID: %d", req.CartID, req.ProductID)
+	pu.log.WithContext(ctx).Infof("Updating cartItem with cartID: %d and productID: %d", req.CartID, req.ProductID)
 	updatedCartItem, err := pu.cartItemRepo.Update(ctx, &repository.CartItem{
 		CartID:    req.CartID,
 		ProductID: req.ProductID,
 		Quantity:  req.Quantity,
 	})
 	if err != nil {
-		pu.log.Errorf("Error updating cartItem: %v", err)
+		pu.log.WithContext(ctx).Errorf("Error updating cartItem: %v", err)
 		return nil, err
 	}
 
-	pu.log.Infof("Updated cartItem: %+v", updatedCartItem)
+	pu.log.WithContext(ctx).Infof("Updated cartItem: %+v", updatedCartItem)
 	return &model.GetCartItemResponse{
 		CartID:    updatedCartItem.CartID,
 		ProductID: updatedCartItem.ProductID,
@@ -105,21 +105,21 @@ func (pu *cartItemUsecase) UpdateCartItem(ctx context.Context, req *model.Update
 }
 
 func (pu *cartItemUsecase) DeleteCartItem(ctx context.Context, req *model.DeleteCartItemRequest) error {
-	pu.log.Infof("Deleting cartItem with cartID: %d and productID: %d", req.CartID, req.ProductID)
+	pu.log.WithContext(ctx).Infof("Deleting cartItem with cartID: %d and productID: %d", req.CartID, req.ProductID)
 	err := pu.cartItemRepo.Delete(ctx, req.CartID, req.ProductID)
 	if err != nil {
-		pu.log.Errorf("Error deleting cartItem: %v", err)
+		pu.log.WithContext(ctx).Errorf("Error deleting cartItem: %v", err)
 		return err
 	}
 
-	pu.log.Infof("Deleted cartItem with cartID: %d and productID: %d", req.CartID, req.ProductID)
+	pu.log.WithContext(ctx).Infof("Deleted cartItem with cartID: %d and productID: %d", req.CartID, req.ProductID)
 	return nil
 }
 
 func (pu *cartItemUsecase) GetCartItemList(ctx context.Context, req *model.GetCartItemsRequest) ([]*model.GetCartItemResponse, error) {
 	cartItems, err := pu.cartItemRepo.GetList(ctx, req.CartID, req.ProductID)
 	if err != nil {
-		pu.log.Errorf("Error fetching cartItems: %v", err)
+		pu.log.WithContext(ctx).Errorf("Error fetching cartItems: %v", err)
 		return nil, err
 	}
 
@@ -1,18 +1,37 @@
 package delivery
 
 import (
+	"th3y3m/e-commerce-microservices/pkg/authz"
+	"th3y3m/e-commerce-microservices/pkg/logging"
+	"th3y3m/e-commerce-microservices/pkg/metrics"
+	"th3y3m/e-commerce-microservices/pkg/tracing"
+	"th3y3m/e-commerce-microservices/service/category/config"
+
 	"github.com/gin-gonic/gin"
 )
 
-func RegisterHandlers() *gin.Engine {
-	r := gin.Default()
+// handler serves the routes of the service, with the usecases built from cfg
+type handler struct {
+	cfg config.Config
+}
+
+func RegisterHandlers(cfg config.Config) *gin.Engine {
+	h := &handler{cfg: cfg}
+	keys := authz.NewKeys(cfg.Auth)
+
+	r := gin.New()
+	// Lets the request context, and the trace in it, reach the usecases through *gin.Context
+	r.ContextWithFallback = true
+	r.Use(gin.Recovery(), tracing.Middleware("category"), logging.Middleware(), metrics.Middleware())
+	r.GET(metrics.Path, metrics.Handler())
+	r.Use(keys.Middleware())
 
 	category := r.Group("/api/categories")
 	{
-		category.GET("/:category_id", GetCategoryByID)
-		category.POST("", CreateCategory)
-		category.PUT("", UpdateCategory)
-		category.DELETE("", DeleteCategory)
+		category.GET("/:category_id", h.GetCategoryByID)
+		category.POST("", h.CreateCategory)
+		category.PUT("", h.UpdateCategory)
+		category.DELETE("", h.DeleteCategory)
 	}
 
 	return r
@@ -8,23 +8,23 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
-func GetCategoryByID(c *gin.Context) {
+func (h *handler) GetCategoryByID(c *gin.Context) {
 	var req model.GetCategoryRequest
 
 	err := c.BindJSON(&req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(400, gin.H{
 			"error": "Bad Request",
 		})
 		return
 	}
 
-	module := dependency_injection.NewCategoryUsecaseProvider()
+	module := dependency_injection.NewCategoryUsecaseProvider(h.cfg)
 
 	category, err := module.GetCategory(c, &req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(500, gin.H{
 			"error": "Internal Server Error",
 		})
@@ -34,12 +34,12 @@ func GetCategoryByID(c *gin.Context) {
 	c.JSON(200, category)
 }
 
-func GetAllCategorys(c *gin.Context) {
-	module := dependency_injection.NewCategoryUsecaseProvider()
+func (h *handler) GetAllCategorys(c *gin.Context) {
+	module := dependency_injection.NewCategoryUsecaseProvider(h.cfg)
 
 	categorys, err := module.GetAllCategorys(c)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(500, gin.H{
 			"error": "Internal Server Error",
 		})
@@ -49,22 +49,22 @@ func GetAllCategorys(c *gin.Context) {
 	c.JSON(200, categorys)
 }
 
-func CreateCategory(c *gin.Context) {
+func (h *handler) CreateCategory(c *gin.Context) {
 	var req model.CreateCategoryRequest
 	err := c.BindJSON(&req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(400, gin.H{
 			"error": "Bad Request",
 		})
 		return
 	}
 
-	module := dependency_injection.NewCategoryUsecaseProvider()
+	module := dependency_injection.NewCategoryUsecaseProvider(h.cfg)
 
 	category, err := module.CreateCategory(c, &req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(500, gin.H{
 			"error": "Internal Server Error",
 		})
@@ -74,22 +74,22 @@ func CreateCategory(c *gin.Context) {
 	c.JSON(200, category)
 }
 
-func UpdateCategory(c *gin.Context) {
+func (h *handler) UpdateCategory(c *gin.Context) {
 	var req model.UpdateCategoryRequest
 	err := c.BindJSON(&req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(400, gin.H{
 			"error": "Bad Request",
 		})
 		return
 	}
 
-	module := dependency_injection.NewCategoryUsecaseProvider()
+	module := dependency_injection.NewCategoryUsecaseProvider(h.cfg)
 
 	category, err := module.UpdateCategory(c, &req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(500, gin.H{
 			"error": "Internal Server Error",
 		})
@@ -99,23 +99,23 @@ func UpdateCategory(c *gin.Context) {
 	c.JSON(200, category)
 }
 
-func DeleteCategory(c *gin.Context) {
+func (h *handler) DeleteCategory(c *gin.Context) {
 	var req model.DeleteCategoryRequest
 
 	err := c.BindJSON(&req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(400, gin.H{
 			"error": "Bad Request",
 		})
 		return
 	}
 
-	module := dependency_injection.NewCategoryUsecaseProvider()
+	module := dependency_injection.NewCategoryUsecaseProvider(h.cfg)
 
 	err = module.DeleteCategory(c, &req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(500, gin.H{
 			"error": "Internal Server Error",
 		})
@@ -1,30 +1,31 @@
 package dependency_injection
 
 import (
+	"th3y3m/e-commerce-microservices/pkg/cache"
+	"th3y3m/e-commerce-microservices/pkg/logging"
 	"th3y3m/e-commerce-microservices/pkg/postgresql"
 	redis_client "th3y3m/e-commerce-microservices/pkg/redis"
+	"th3y3m/e-commerce-microservices/service/category/config"
 	"th3y3m/e-commerce-microservices/service/category/repository"
 	"th3y3m/e-commerce-microservices/service/category/usecase"
-
-	"github.com/sirupsen/logrus"
 )
 
-func NewCategoryRepositoryProvider() repository.ICategoryRepository {
-	log := logrus.New()
-	db, err := postgresql.NewGormDB()
+func NewCategoryRepositoryProvider(cfg config.Config) repository.ICategoryRepository {
+	log := logging.Logger()
+	db, err := postgresql.NewGormDB(cfg.Postgres)
 	if err != nil {
 		log.Error(err)
 	}
-	redis, err := redis_client.ConnectToRedis()
+	redis, err := redis_client.ConnectToRedis(cfg.Redis)
 	if err != nil {
 		log.Error(err)
 	}
 
-	return repository.NewCategoryRepository(db, redis, log)
+	return repository.NewCategoryRepository(db, cache.NewStore(redis), cache.NewConfig(cfg.Cache), log)
 }
 
-func NewCategoryUsecaseProvider() usecase.ICategoryUsecase {
-	log := logrus.New()
-	categoryRepository := NewCategoryRepositoryProvider()
+func NewCategoryUsecaseProvider(cfg config.Config) usecase.ICategoryUsecase {
+	log := logging.Logger()
+	categoryRepository := NewCategoryRepositoryProvider(cfg)
 	return usecase.NewCategoryUsecase(categoryRepository, log)
 }
@@ -1,34 +1,38 @@
 package main
 
 import (
+	"context"
 	"log"
-	"os"
-	"strings"
-	"th3y3m/e-commerce-microservices/service/product/delivery"
-
-	"github.com/spf13/viper"
+	pkgconfig "th3y3m/e-commerce-microservices/pkg/config"
+	"th3y3m/e-commerce-microservices/pkg/discovery"
+	"th3y3m/e-commerce-microservices/pkg/health"
+	"th3y3m/e-commerce-microservices/pkg/logging"
+	"th3y3m/e-commerce-microservices/pkg/server"
+	"th3y3m/e-commerce-microservices/pkg/tracing"
+	"th3y3m/e-commerce-microservices/service/category/config"
+	"th3y3m/e-commerce-microservices/service/category/delivery"
 )
 
 func main() {
-	viper.SetConfigFile("../../.env")
-	viper.AutomaticEnv()
-	if err := viper.ReadInConfig(); err != nil {
-		log.Printf("error while reading config file: %s", err.Error())
-		return
-	}
-	log.Println("Config file loaded successfully")
+	logging.Setup("category")
+
+	var cfg config.Config
+	pkgconfig.MustLoad("category", &cfg)
+
+	shutdownTracing := tracing.Setup("category", cfg.Tracing)
+	defer shutdownTracing(context.Background())
 
-	for _, env := range viper.AllKeys() {
-		if viper.GetString(env) != "" {
-			_ = os.Setenv(env, viper.GetString(env))
-			_ = os.Setenv(strings.ToUpper(env), viper.GetString(env))
-		}
+	resolver, err := discovery.New(cfg.Discovery, cfg.Redis)
+	if err != nil {
+		log.Fatalf("Failed to set up service discovery: %v", err)
 	}
 
-	r := delivery.RegisterHandlers()
+	checker := health.NewChecker(health.Postgres(cfg.Postgres), health.Redis(cfg.Redis))
+	r := delivery.RegisterHandlers(cfg)
+	checker.Register(r)
 
 	log.Println("Starting server on port 8086")
-	if err := r.Run(":8086"); err != nil {
-		log.Fatalf("Error starting server: %v", err)
+	if err := server.Run(":8086", cfg.Server, r, checker, discovery.Heartbeat(discovery.Category, resolver, cfg.Discovery)); err != nil {
+		log.Fatalf("Error running server: %v", err)
 	}
 }
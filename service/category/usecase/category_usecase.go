@@ -32,24 +32,24 @@ func NewCategoryUsecase(categoryRepo repository.ICategoryRepository, log *logrus
 }
 
 func (pu *categoryUsecase) GetCategory(ctx context.Context, req *model.GetCategoryRequest) (*model.GetCategoryResponse, error) {
-	pu.log.Infof("Fetching category with ID: %d", req.CategoryID)
+	pu.log.WithContext(ctx).Infof("Fetching category with ID: %d", req.CategoryID)
 	category, err := pu.categoryRepo.Get(ctx, req.CategoryID)
 	if err != nil {
-		pu.log.Errorf("Error fetching category: %v", err)
+		pu.log.WithContext(ctx).Errorf("Error fetching category: %v", err)
 		return nil, err
 	}
 
-	pu.log.Infof("Fetched category: %+v", category)
+	pu.log.WithContext(ctx).Infof("Fetched category: %+v", category)
 	return &model.GetCategoryResponse{
 		CategoryID: category.CategoryID,
 	}, nil
 }
 
 func (pu *categoryUsecase) GetAllCategorys(ctx context.Context) ([]*model.GetCategoryResponse, error) {
-	pu.log.Infof("Fetching all categorys")
+	pu.log.WithContext(ctx).Infof("Fetching all categorys")
 	categorys, err := pu.categoryRepo.GetAll(ctx)
 	if err != nil {
-		pu.log.Errorf("Error fetching categorys: %v", err)
+		pu.log.WithContext(ctx).Errorf("Error fetching categorys: %v", err)
 		return nil, err
 	}
 
@@ -65,21 +65,21 @@ func (pu *categoryUsecase) GetAllCategorys(ctx context.Context) ([]*model.GetCat
 		})
 	}
 
-	pu.log.Infof("Fetched %d categorys", len(categoryResponses))
+	pu.log.WithContext(ctx).Infof("Fetched %d categorys", len(categoryResponses))
 	return categoryResponses, nil
 }
 
 func (pu *categoryUsecase) CreateCategory(ctx context.Context, category *model.CreateCategoryRequest) (*model.GetCategoryResponse, error) {
-	pu.log.Infof("Creating category: %+v", category)
+	pu.log.WithContext(ctx).Infof("Creating category: %+v", category)
 	createdCategory, err := pu.categoryRepo.Create(ctx, &repository.Category{
 		CategoryName: category.CategoryName,
 	})
 	if err != nil {
-		pu.log.Errorf("Error creating category: %v", err)
+		pu.log.WithContext(ctx).Errorf("Error creating category: %v", err)
 		return nil, err
 	}
 
-	pu.log.Infof("Created category: %+v", createdCategory)
+	pu.log.WithContext(ctx).Infof("Created category: %+v", createdCategory)
 	return &model.GetCategoryResponse{
 		CategoryID:   createdCategory.CategoryID,
 		CategoryName: createdCategory.CategoryName,
@@ -90,10 +90,10 @@ func (pu *categoryUsecase) CreateCategory(ctx context.Context, category *model.C
 }
 
 func (pu *categoryUsecase) DeleteCategory(ctx context.Context, req *model.DeleteCategoryRequest) error {
-	pu.log.Infof("Deleting category with ID: %d", req.CategoryID)
+	pu.log.WithContext(ctx).Infof("Deleting category with ID: %d", req.CategoryID)
 	category, err := pu.categoryRepo.Get(ctx, req.CategoryID)
 	if err != nil {
-		pu.log.Errorf("Error fetching category for deletion: %v", err)
+		pu.log.WithContext(ctx).Errorf("Error fetching category for deletion: %v", err)
 		return err
 	}
 
@@ -101,19 +101,19 @@ func (pu *categoryUsecase) DeleteCategory(ctx context.Context, req *model.Delete
 
 	_, err = pu.categoryRepo.Update(ctx, category)
 	if err != nil {
-		pu.log.Errorf("Error updating category for deletion: %v", err)
+		pu.log.WithContext(ctx).Errorf("Error updating category for deletion: %v", err)
 		return err
 	}
 
-	pu.log.Infof("Deleted category with ID: %d", req.CategoryID)
+	pu.log.WithContext(ctx).Infof("Deleted category with ID: %d", req.CategoryID)
 	return nil
 }
 
 func (pu *categoryUsecase) UpdateCategory(ctx context.Context, rep *model.UpdateCategoryRequest) (*model.GetCategoryResponse, error) {
-	pu.log.Infof("Updating category with ID: %d", rep.CategoryID)
+	pu.log.WithContext(ctx).Infof("Updating category with ID: %d", rep.CategoryID)
 	category, err := pu.categoryRepo.Get(ctx, rep.CategoryID)
 	if err != nil {
-		pu.log.Errorf("Error fetching category: %v", err)
+		pu.log.WithContext(ctx).Errorf("Error fetching category: %v", err)
 		return nil, err
 	}
 
@@ -122,11 +122,11 @@ func (pu *categoryUsecase) UpdateCategory(ctx context.Context, rep *model.Update
 
 	updatedCategory, err := pu.categoryRepo.Update(ctx, category)
 	if err != nil {
-		pu.log.Errorf("Error updating category: %v", err)
+		pu.log.WithContext(ctx).Errorf("Error updating category: %v", err)
 		return nil, err
 	}
 
-	pu.log.Infof("Updated category: %+v", updatedCategory)
+	pu.log.WithContext(ctx).Infof("Updated category: %+v", updatedCategory)
 	return &model.GetCategoryResponse{
 		CategoryID:   updatedCategory.CategoryID,
 		CategoryName: updatedCategory.CategoryName,
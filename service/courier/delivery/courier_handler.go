@@ -8,23 +8,23 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
-func GetCourierByID(c *gin.Context) {
+func (h *handler) GetCourierByID(c *gin.Context) {
 	var req model.GetCourierRequest
 
 	err := c.BindJSON(&req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(400, gin.H{
 			"error": "Bad Request",
 		})
 		return
 	}
 
-	module := dependency_injection.NewCourierUsecaseProvider()
+	module := dependency_injection.NewCourierUsecaseProvider(h.cfg)
 
 	courier, err := module.GetCourier(c, &req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(500, gin.H{
 			"error": "Internal Server Error",
 		})
@@ -34,12 +34,12 @@ func GetCourierByID(c *gin.Context) {
 	c.JSON(200, courier)
 }
 
-func GetAllCouriers(c *gin.Context) {
-	module := dependency_injection.NewCourierUsecaseProvider()
+func (h *handler) GetAllCouriers(c *gin.Context) {
+	module := dependency_injection.NewCourierUsecaseProvider(h.cfg)
 
 	couriers, err := module.GetAllCouriers(c)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(500, gin.H{
 			"error": "Internal Server Error",
 		})
@@ -49,22 +49,22 @@ func GetAllCouriers(c *gin.Context) {
 	c.JSON(200, couriers)
 }
 
-func CreateCourier(c *gin.Context) {
+func (h *handler) CreateCourier(c *gin.Context) {
 	var req model.CreateCourierRequest
 	err := c.BindJSON(&req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(400, gin.H{
 			"error": "Bad Request",
 		})
 		return
 	}
 
-	module := dependency_injection.NewCourierUsecaseProvider()
+	module := dependency_injection.NewCourierUsecaseProvider(h.cfg)
 
 	courier, err := module.CreateCourier(c, &req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(500, gin.H{
 			"error": "Internal Server Error",
 		})
@@ -74,22 +74,22 @@ func CreateCourier(c *gin.Context) {
 	c.JSON(200, courier)
 }
 
-func UpdateCourier(c *gin.Context) {
+func (h *handler) UpdateCourier(c *gin.Context) {
 	var req model.UpdateCourierRequest
 	err := c.BindJSON(&req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(400, gin.H{
 			"error": "Bad Request",
 		})
 		return
 	}
 
-	module := dependency_injection.NewCourierUsecaseProvider()
+	module := dependency_injection.NewCourierUsecaseProvider(h.cfg)
 
 	courier, err := module.UpdateCourier(c, &req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(500, gin.H{
 			"error": "Internal Server Error",
 		})
@@ -99,13 +99,13 @@ func UpdateCourier(c *gin.Context) {
 	c.JSON(200, courier)
 }
 
-func DeleteCourier(c *gin.Context) {
-	module := dependency_injection.NewCourierUsecaseProvider()
+func (h *handler) DeleteCourier(c *gin.Context) {
+	module := dependency_injection.NewCourierUsecaseProvider(h.cfg)
 
 	var req model.DeleteCourierRequest
 	err := c.BindJSON(&req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(400, gin.H{
 			"error": "Bad Request",
 		})
@@ -114,7 +114,7 @@ func DeleteCourier(c *gin.Context) {
 
 	err = module.DeleteCourier(c, &req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(500, gin.H{
 			"error": "Internal Server Error",
 		})
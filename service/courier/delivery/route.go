@@ -1,18 +1,37 @@
 package delivery
 
 import (
+	"th3y3m/e-commerce-microservices/pkg/authz"
+	"th3y3m/e-commerce-microservices/pkg/logging"
+	"th3y3m/e-commerce-microservices/pkg/metrics"
+	"th3y3m/e-commerce-microservices/pkg/tracing"
+	"th3y3m/e-commerce-microservices/service/courier/config"
+
 	"github.com/gin-gonic/gin"
 )
 
-func RegisterHandlers() *gin.Engine {
-	r := gin.Default()
+// handler serves the routes of the service, with the usecases built from cfg
+type handler struct {
+	cfg config.Config
+}
+
+func RegisterHandlers(cfg config.Config) *gin.Engine {
+	h := &handler{cfg: cfg}
+	keys := authz.NewKeys(cfg.Auth)
+
+	r := gin.New()
+	// Lets the request context, and the trace in it, reach the usecases through *gin.Context
+	r.ContextWithFallback = true
+	r.Use(gin.Recovery(), tracing.Middleware("courier"), logging.Middleware(), metrics.Middleware())
+	r.GET(metrics.Path, metrics.Handler())
+	r.Use(keys.Middleware())
 
 	courier := r.Group("/api/couriers")
 	{
-		courier.GET("/:courier_id", GetCourierByID)
-		courier.POST("", CreateCourier)
-		courier.PUT("", UpdateCourier)
-		courier.DELETE("", DeleteCourier)
+		courier.GET("/:courier_id", h.GetCourierByID)
+		courier.POST("", h.CreateCourier)
+		courier.PUT("", h.UpdateCourier)
+		courier.DELETE("", h.DeleteCourier)
 	}
 
 	return r
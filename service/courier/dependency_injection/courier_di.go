@@ -1,21 +1,21 @@
 package dependency_injection
 
 import (
+	"th3y3m/e-commerce-microservices/pkg/logging"
 	"th3y3m/e-commerce-microservices/pkg/postgresql"
 	redis_client "th3y3m/e-commerce-microservices/pkg/redis"
+	"th3y3m/e-commerce-microservices/service/courier/config"
 	"th3y3m/e-commerce-microservices/service/courier/repository"
 	"th3y3m/e-commerce-microservices/service/courier/usecase"
-
-	"github.com/sirupsen/logrus"
 )
 
-func NewCourierRepositoryProvider() repository.ICourierRepository {
-	log := logrus.New()
-	db, err := postgresql.NewGormDB()
+func NewCourierRepositoryProvider(cfg config.Config) repository.ICourierRepository {
+	log := logging.Logger()
+	db, err := postgresql.NewGormDB(cfg.Postgres)
 	if err != nil {
 		log.Error(err)
 	}
-	redis, err := redis_client.ConnectToRedis()
+	redis, err := redis_client.ConnectToRedis(cfg.Redis)
 	if err != nil {
 		log.Error(err)
 	}
@@ -23,8 +23,8 @@ func NewCourierRepositoryProvider() repository.ICourierRepository {
 	return repository.NewCourierRepository(db, redis, log)
 }
 
-func NewCourierUsecaseProvider() usecase.ICourierUsecase {
-	log := logrus.New()
-	courierRepository := NewCourierRepositoryProvider()
+func NewCourierUsecaseProvider(cfg config.Config) usecase.ICourierUsecase {
+	log := logging.Logger()
+	courierRepository := NewCourierRepositoryProvider(cfg)
 	return usecase.NewCourierUsecase(courierRepository, log)
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"th3y3m/e-commerce-microservices/pkg/metrics"
 
 	"github.com/redis/go-redis/v9"
 	"github.com/sirupsen/logrus"
@@ -33,7 +34,7 @@ func NewCourierRepository(db *gorm.DB, redis *redis.Client, log *logrus.Logger)
 }
 
 func (pr *courierRepository) Get(ctx context.Context, courierID int64) (*Courier, error) {
-	pr.log.Infof("Fetching courier with ID: %d", courierID)
+	pr.log.WithContext(ctx).Infof("Fetching courier with ID: %d", courierID)
 	cacheKey := fmt.Sprintf("courier:%d", courierID)
 
 	// Try to get the courier from Redis cache
@@ -42,21 +43,23 @@ func (pr *courierRepository) Get(ctx context.Context, courierID int64) (*Courier
 		if err == nil {
 			var courier Courier
 			if err := json.Unmarshal([]byte(cachedCourier), &courier); err == nil {
-				pr.log.Infof("Courier found in cache: %+v", courier)
+				pr.log.WithContext(ctx).Infof("Courier found in cache: %+v", courier)
+				metrics.CacheHit("courierRepository.Get")
 				return &courier, nil
 			}
 		} else if err != redis.Nil {
-			pr.log.Warnf("Failed to get courier from Redis: %v", err)
+			pr.log.WithContext(ctx).Warnf("Failed to get courier from Redis: %v", err)
 		}
+		metrics.CacheMiss("courierRepository.Get")
 	} else {
-		pr.log.Warn("Redis client is not initialized")
+		pr.log.WithContext(ctx).Warn("Redis client is not initialized")
 	}
 
 	// If not found in cache, get from database
 	var courier Courier
 	result := pr.db.WithContext(ctx).First(&courier, courierID)
 	if result.Error != nil {
-		pr.log.Errorf("Error fetching courier: %v", result.Error)
+		pr.log.WithContext(ctx).Errorf("Error fetching courier: %v", result.Error)
 		return nil, result.Error
 	}
 
@@ -64,9 +67,9 @@ func (pr *courierRepository) Get(ctx context.Context, courierID int64) (*Courier
 	if pr.redis != nil {
 		courierJSON, _ := json.Marshal(courier)
 		if err := pr.redis.Set(ctx, cacheKey, courierJSON, 0).Err(); err != nil {
-			pr.log.Warnf("Failed to save courier to Redis: %v", err)
+			pr.log.WithContext(ctx).Warnf("Failed to save courier to Redis: %v", err)
 		} else {
-			pr.log.Infof("Courier saved to cache: %d", courierID)
+			pr.log.WithContext(ctx).Infof("Courier saved to cache: %d", courierID)
 		}
 	}
 
@@ -74,7 +77,7 @@ func (pr *courierRepository) Get(ctx context.Context, courierID int64) (*Courier
 }
 
 func (pr *courierRepository) GetAll(ctx context.Context) ([]*Courier, error) {
-	pr.log.Info("Fetching all couriers")
+	pr.log.WithContext(ctx).Info("Fetching all couriers")
 	cacheKey := "all_couriers"
 
 	// Try to get the couriers from Redis cache
@@ -83,21 +86,23 @@ func (pr *courierRepository) GetAll(ctx context.Context) ([]*Courier, error) {
 		if err == nil {
 			var couriers []*Courier
 			if err := json.Unmarshal([]byte(cachedCouriers), &couriers); err == nil {
-				pr.log.Infof("Couriers found in cache: %+v", couriers)
+				pr.log.WithContext(ctx).Infof("Couriers found in cache: %+v", couriers)
+				metrics.CacheHit("courierRepository.GetAll")
 				return couriers, nil
 			}
 		} else if err != redis.Nil {
-			pr.log.Warnf("Failed to get couriers from Redis: %v", err)
+			pr.log.WithContext(ctx).Warnf("Failed to get couriers from Redis: %v", err)
 		}
+		metrics.CacheMiss("courierRepository.GetAll")
 	} else {
-		pr.log.Warn("Redis client is not initialized")
+		pr.log.WithContext(ctx).Warn("Redis client is not initialized")
 	}
 
 	// If not found in cache, get from database
 	var couriers []*Courier
 	result := pr.db.WithContext(ctx).Find(&couriers)
 	if result.Error != nil {
-		pr.log.Errorf("Error fetching couriers: %v", result.Error)
+		pr.log.WithContext(ctx).Errorf("Error fetching couriers: %v", result.Error)
 		return nil, result.Error
 	}
 
@@ -105,9 +110,9 @@ func (pr *courierRepository) GetAll(ctx context.Context) ([]*Courier, error) {
 	if pr.redis != nil {
 		couriersJSON, _ := json.Marshal(couriers)
 		if err := pr.redis.Set(ctx, cacheKey, couriersJSON, 0).Err(); err != nil {
-			pr.log.Warnf("Failed to save couriers to Redis: %v", err)
+			pr.log.WithContext(ctx).Warnf("Failed to save couriers to Redis: %v", err)
 		} else {
-			pr.log.Info("Couriers saved to cache")
+			pr.log.WithContext(ctx).Info("Couriers saved to cache")
 		}
 	}
 
@@ -115,9 +120,9 @@ func (pr *courierRepository) GetAll(ctx context.Context) ([]*Courier, error) {
 }
 
 func (pr *courierRepository) Create(ctx context.Context, courier *Courier) (*Courier, error) {
-	pr.log.Infof("Creating courier: %+v", courier)
+	pr.log.WithContext(ctx).Infof("Creating courier: %+v", courier)
 	if err := pr.db.WithContext(ctx).Create(courier).Error; err != nil {
-		pr.log.Errorf("Error creating courier: %v", err)
+		pr.log.WithContext(ctx).Errorf("Error creating courier: %v", err)
 		return nil, err
 	}
 	cacheKey := fmt.Sprintf("courier:%d", courier.CourierID)
@@ -126,16 +131,16 @@ func (pr *courierRepository) Create(ctx context.Context, courier *Courier) (*Cou
 	if pr.redis != nil {
 		courierJSON, _ := json.Marshal(courier)
 		if err := pr.redis.Set(ctx, cacheKey, courierJSON, 0).Err(); err != nil {
-			pr.log.Warnf("Failed to save courier to Redis: %v", err)
+			pr.log.WithContext(ctx).Warnf("Failed to save courier to Redis: %v", err)
 		} else {
-			pr.log.Infof("Courier saved to cache: %d", courier.CourierID)
+			pr.log.WithContext(ctx).Infof("Courier saved to cache: %d", courier.CourierID)
 		}
 
 		// Invalidate the cache for all records
 		if err := pr.redis.Del(ctx, "all_couriers").Err(); err != nil {
-			pr.log.Warnf("Failed to invalidate all couriers cache: %v", err)
+			pr.log.WithContext(ctx).Warnf("Failed to invalidate all couriers cache: %v", err)
 		} else {
-			pr.log.Info("Invalidated cache for all couriers")
+			pr.log.WithContext(ctx).Info("Invalidated cache for all couriers")
 		}
 	}
 
@@ -144,9 +149,9 @@ func (pr *courierRepository) Create(ctx context.Context, courier *Courier) (*Cou
 }
 
 func (pr *courierRepository) Update(ctx context.Context, courier *Courier) (*Courier, error) {
-	pr.log.Infof("Updating courier: %+v", courier)
+	pr.log.WithContext(ctx).Infof("Updating courier: %+v", courier)
 	if err := pr.db.WithContext(ctx).Save(courier).Error; err != nil {
-		pr.log.Errorf("Error updating courier: %v", err)
+		pr.log.WithContext(ctx).Errorf("Error updating courier: %v", err)
 		return nil, err
 	}
 
@@ -156,16 +161,16 @@ func (pr *courierRepository) Update(ctx context.Context, courier *Courier) (*Cou
 	if pr.redis != nil {
 		courierJSON, _ := json.Marshal(courier)
 		if err := pr.redis.Set(ctx, cacheKey, courierJSON, 0).Err(); err != nil {
-			pr.log.Warnf("Failed to save courier to Redis: %v", err)
+			pr.log.WithContext(ctx).Warnf("Failed to save courier to Redis: %v", err)
 		} else {
-			pr.log.Infof("Courier saved to cache: %d", courier.CourierID)
+			pr.log.WithContext(ctx).Infof("Courier saved to cache: %d", courier.CourierID)
 		}
 
 		// Invalidate the cache for all records
 		if err := pr.redis.Del(ctx, "all_couriers").Err(); err != nil {
-			pr.log.Warnf("Failed to invalidate all couriers cache: %v", err)
+			pr.log.WithContext(ctx).Warnf("Failed to invalidate all couriers cache: %v", err)
 		} else {
-			pr.log.Info("Invalidated cache for all couriers")
+			pr.log.WithContext(ctx).Info("Invalidated cache for all couriers")
 		}
 	}
 
@@ -174,10 +179,10 @@ func (pr *courierRepository) Update(ctx context.Context, courier *Courier) (*Cou
 }
 
 func (pr *courierRepository) Delete(ctx context.Context, courierID int64) error {
-	pr.log.Infof("Deleting courier with ID: %d", courierID)
+	pr.log.WithContext(ctx).Infof("Deleting courier with ID: %d", courierID)
 	result := pr.db.WithContext(ctx).Delete(&Courier{}, courierID)
 	if result.Error != nil {
-		pr.log.Errorf("Error deleting courier: %v", result.Error)
+		pr.log.WithContext(ctx).Errorf("Error deleting courier: %v", result.Error)
 		return result.Error
 	}
 
@@ -186,16 +191,16 @@ func (pr *courierRepository) Delete(ctx context.Context, courierID int64) error
 	// Delete from cache if Redis is available
 	if pr.redis != nil {
 		if err := pr.redis.Del(ctx, cacheKey).Err(); err != nil {
-			pr.log.Warnf("Failed to delete courier from Redis: %v", err)
+			pr.log.WithContext(ctx).Warnf("Failed to delete courier from Redis: %v", err)
 		} else {
-			pr.log.Infof("Courier deleted from cache: %d", courierID)
+			pr.log.WithContext(ctx).Infof("Courier deleted from cache: %d", courierID)
 		}
 
 		// Invalidate the cache for all records
 		if err := pr.redis.Del(ctx, "all_couriers").Err(); err != nil {
-			pr.log.Warnf("Failed to invalidate all couriers cache: %v", err)
+			pr.log.WithContext(ctx).Warnf("Failed to invalidate all couriers cache: %v", err)
 		} else {
-			pr.log.Info("Invalidated cache for all couriers")
+			pr.log.WithContext(ctx).Info("Invalidated cache for all couriers")
 		}
 	}
 
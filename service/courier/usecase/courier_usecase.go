@@ -32,24 +32,24 @@ func NewCourierUsecase(courierRepo repository.ICourierRepository, log *logrus.Lo
 }
 
 func (pu *courierUsecase) GetCourier(ctx context.Context, req *model.GetCourierRequest) (*model.GetCourierResponse, error) {
-	pu.log.Infof("Fetching courier with ID: %d", req.CourierID)
+	pu.log.WithContext(ctx).Infof("Fetching courier with ID: %d", req.CourierID)
 	courier, err := pu.courierRepo.Get(ctx, req.CourierID)
 	if err != nil {
-		pu.log.Errorf("Error fetching courier: %v", err)
+		pu.log.WithContext(ctx).Errorf("Error fetching courier: %v", err)
 		return nil, err
 	}
 
-	pu.log.Infof("Fetched courier: %+v", courier)
+	pu.log.WithContext(ctx).Infof("Fetched courier: %+v", courier)
 	return &model.GetCourierResponse{
 		CourierID: courier.CourierID,
 	}, nil
 }
 
 func (pu *courierUsecase) GetAllCouriers(ctx context.Context) ([]*model.GetCourierResponse, error) {
-	pu.log.Infof("Fetching all couriers")
+	pu.log.WithContext(ctx).Infof("Fetching all couriers")
 	couriers, err := pu.courierRepo.GetAll(ctx)
 	if err != nil {
-		pu.log.Errorf("Error fetching couriers: %v", err)
+		pu.log.WithContext(ctx).Errorf("Error fetching couriers: %v", err)
 		return nil, err
 	}
 
@@ -64,21 +64,21 @@ func (pu *courierUsecase) GetAllCouriers(ctx context.Context) ([]*model.GetCouri
 		})
 	}
 
-	pu.log.Infof("Fetched %d couriers", len(courierResponses))
+	pu.log.WithContext(ctx).Infof("Fetched %d couriers", len(courierResponses))
 	return courierResponses, nil
 }
 
 func (pu *courierUsecase) CreateCourier(ctx context.Context, courier *model.CreateCourierRequest) (*model.GetCourierResponse, error) {
-	pu.log.Infof("Creating courier: %+v", courier)
+	pu.log.WithContext(ctx).Infof("Creating courier: %+v", courier)
 	createdCourier, err := pu.courierRepo.Create(ctx, &repository.Courier{
 		CourierName: courier.CourierName,
 	})
 	if err != nil {
-		pu.log.Errorf("Error creating courier: %v", err)
+		pu.log.WithContext(ctx).Errorf("Error creating courier: %v", err)
 		return nil, err
 	}
 
-	pu.log.Infof("Created courier: %+v", createdCourier)
+	pu.log.WithContext(ctx).Infof("Created courier: %+v", createdCourier)
 	return &model.GetCourierResponse{
 		CourierID:   createdCourier.CourierID,
 		CourierName: createdCourier.CourierName,
@@ -89,10 +89,10 @@ func (pu *courierUsecase) CreateCourier(ctx context.Context, courier *model.Crea
 }
 
 func (pu *courierUsecase) DeleteCourier(ctx context.Context, req *model.DeleteCourierRequest) error {
-	pu.log.Infof("Deleting courier with ID: %d", req.CourierID)
+	pu.log.WithContext(ctx).Infof("Deleting courier with ID: %d", req.CourierID)
 	courier, err := pu.courierRepo.Get(ctx, req.CourierID)
 	if err != nil {
-		pu.log.Errorf("Error fetching courier for deletion: %v", err)
+		pu.log.WithContext(ctx).Errorf("Error fetching courier for deletion: %v", err)
 		return err
 	}
 
@@ -100,19 +100,19 @@ func (pu *courierUsecase) DeleteCourier(ctx context.Context, req *model.DeleteCo
 
 	_, err = pu.courierRepo.Update(ctx, courier)
 	if err != nil {
-		pu.log.Errorf("Error updating courier for deletion: %v", err)
+		pu.log.WithContext(ctx).Errorf("Error updating courier for deletion: %v", err)
 		return err
 	}
 
-	pu.log.Infof("Deleted courier with ID: %d", req.CourierID)
+	pu.log.WithContext(ctx).Infof("Deleted courier with ID: %d", req.CourierID)
 	return nil
 }
 
 func (pu *courierUsecase) UpdateCourier(ctx context.Context, rep *model.UpdateCourierRequest) (*model.GetCourierResponse, error) {
-	pu.log.Infof("Updating courier with ID: %d", rep.CourierID)
+	pu.log.WithContext(ctx).Infof("Updating courier with ID: %d", rep.CourierID)
 	courier, err := pu.courierRepo.Get(ctx, rep.CourierID)
 	if err != nil {
-		pu.log.Errorf("Error fetching courier: %v", err)
+		pu.log.WithContext(ctx).Errorf("Error fetching courier: %v", err)
 		return nil, err
 	}
 
@@ -121,11 +121,11 @@ func (pu *courierUsecase) UpdateCourier(ctx context.Context, rep *model.UpdateCo
 
 	updatedCourier, err := pu.courierRepo.Update(ctx, courier)
 	if err != nil {
-		pu.log.Errorf("Error updating courier: %v", err)
+		pu.log.WithContext(ctx).Errorf("Error updating courier: %v", err)
 		return nil, err
 	}
 
-	pu.log.Infof("Updated courier: %+v", updatedCourier)
+	pu.log.WithContext(ctx).Infof("Updated courier: %+v", updatedCourier)
 	return &model.GetCourierResponse{
 		CourierID:   updatedCourier.CourierID,
 		CourierName: updatedCourier.CourierName,
@@ -9,15 +9,15 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
-func GetDiscountByID(c *gin.Context) {
+func (h *handler) GetDiscountByID(c *gin.Context) {
 	id := c.Param("discount_id")
 
-	module := dependency_injection.NewDiscountUsecaseProvider()
+	module := dependency_injection.NewDiscountUsecaseProvider(h.cfg)
 
 	var req model.GetDiscountRequest
 	discountID, err := strconv.ParseInt(id, 10, 64)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(400, gin.H{
 			"error": "Invalid discount ID",
 		})
@@ -27,7 +27,7 @@ func GetDiscountByID(c *gin.Context) {
 
 	discount, err := module.GetDiscount(c, &req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(500, gin.H{
 			"error": "Internal Server Error",
 		})
@@ -37,12 +37,12 @@ func GetDiscountByID(c *gin.Context) {
 	c.JSON(200, discount)
 }
 
-func GetAllDiscounts(c *gin.Context) {
-	module := dependency_injection.NewDiscountUsecaseProvider()
+func (h *handler) GetAllDiscounts(c *gin.Context) {
+	module := dependency_injection.NewDiscountUsecaseProvider(h.cfg)
 
 	discounts, err := module.GetAllDiscounts(c)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(500, gin.H{
 			"error": "Internal Server Error",
 		})
@@ -52,22 +52,22 @@ func GetAllDiscounts(c *gin.Context) {
 	c.JSON(200, discounts)
 }
 
-func CreateDiscount(c *gin.Context) {
+func (h *handler) CreateDiscount(c *gin.Context) {
 	var req model.CreateDiscountRequest
 	err := c.BindJSON(&req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(400, gin.H{
 			"error": "Bad Request",
 		})
 		return
 	}
 
-	module := dependency_injection.NewDiscountUsecaseProvider()
+	module := dependency_injection.NewDiscountUsecaseProvider(h.cfg)
 
 	discount, err := module.CreateDiscount(c, &req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(500, gin.H{
 			"error": "Internal Server Error",
 		})
@@ -77,22 +77,22 @@ func CreateDiscount(c *gin.Context) {
 	c.JSON(200, discount)
 }
 
-func UpdateDiscount(c *gin.Context) {
+func (h *handler) UpdateDiscount(c *gin.Context) {
 	var req model.UpdateDiscountRequest
 	err := c.BindJSON(&req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(400, gin.H{
 			"error": "Bad Request",
 		})
 		return
 	}
 
-	module := dependency_injection.NewDiscountUsecaseProvider()
+	module := dependency_injection.NewDiscountUsecaseProvider(h.cfg)
 
 	discount, err := module.UpdateDiscount(c, &req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(500, gin.H{
 			"error": "Internal Server Error",
 		})
@@ -102,13 +102,13 @@ func UpdateDiscount(c *gin.Context) {
 	c.JSON(200, discount)
 }
 
-func DeleteDiscount(c *gin.Context) {
-	module := dependency_injection.NewDiscountUsecaseProvider()
+func (h *handler) DeleteDiscount(c *gin.Context) {
+	module := dependency_injection.NewDiscountUsecaseProvider(h.cfg)
 
 	var req model.DeleteDiscountRequest
 	err := c.BindJSON(&req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(400, gin.H{
 			"error": "Bad Request",
 		})
@@ -117,7 +117,7 @@ func DeleteDiscount(c *gin.Context) {
 
 	err = module.DeleteDiscount(c, &req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(500, gin.H{
 			"error": "Internal Server Error",
 		})
@@ -1,21 +1,21 @@
 package dependency_injection
 
 import (
+	"th3y3m/e-commerce-microservices/pkg/logging"
 	"th3y3m/e-commerce-microservices/pkg/postgresql"
 	redis_client "th3y3m/e-commerce-microservices/pkg/redis"
+	"th3y3m/e-commerce-microservices/service/discount/config"
 	"th3y3m/e-commerce-microservices/service/discount/repository"
 	"th3y3m/e-commerce-microservices/service/discount/usecase"
-
-	"github.com/sirupsen/logrus"
 )
 
-func NewDiscountRepositoryProvider() repository.IDiscountRepository {
-	log := logrus.New()
-	db, err := postgresql.NewGormDB()
+func NewDiscountRepositoryProvider(cfg config.Config) repository.IDiscountRepository {
+	log := logging.Logger()
+	db, err := postgresql.NewGormDB(cfg.Postgres)
 	if err != nil {
 		log.Error(err)
 	}
-	redis, err := redis_client.ConnectToRedis()
+	redis, err := redis_client.ConnectToRedis(cfg.Redis)
 	if err != nil {
 		log.Error(err)
 	}
@@ -23,8 +23,8 @@ func NewDiscountRepositoryProvider() repository.IDiscountRepository {
 	return repository.NewDiscountRepository(db, redis, log)
 }
 
-func NewDiscountUsecaseProvider() usecase.IDiscountUsecase {
-	log := logrus.New()
-	discountRepository := NewDiscountRepositoryProvider()
+func NewDiscountUsecaseProvider(cfg config.Config) usecase.IDiscountUsecase {
+	log := logging.Logger()
+	discountRepository := NewDiscountRepositoryProvider(cfg)
 	return usecase.NewDiscountUsecase(discountRepository, log)
 }
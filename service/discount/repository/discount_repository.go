@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"th3y3m/e-commerce-microservices/pkg/metrics"
 
 	"github.com/redis/go-redis/v9"
 	"github.com/sirupsen/logrus"
@@ -33,7 +34,7 @@ func NewDiscountRepository(db *gorm.DB, redis *redis.Client, log *logrus.Logger)
 }
 
 func (pr *discountRepository) Get(ctx context.Context, discountID int64) (*Discount, error) {
-	pr.log.Infof("Fetching discount with ID: %d", discountID)
+	pr.log.WithContext(ctx).Infof("Fetching discount with ID: %d", discountID)
 	cacheKey := fmt.Sprintf("discount:%d", discountID)
 
 	// Try to get the discount from Redis cache
@@ -42,21 +43,23 @@ func (pr *discountRepository) Get(ctx context.Context, discountID int64) (*Disco
 		if err == nil {
 			var discount Discount
 			if err := json.Unmarshal([]byte(cachedDiscount), &discount); err == nil {
-				pr.log.Infof("Discount found in cache: %+v", discount)
+				pr.log.WithContext(ctx).Infof("Discount found in cache: %+v", discount)
+				metrics.CacheHit("discountRepository.Get")
 				return &discount, nil
 			}
 		} else if err != redis.Nil {
-			pr.log.Warnf("Failed to get discount from Redis: %v", err)
+			pr.log.WithContext(ctx).Warnf("Failed to get discount from Redis: %v", err)
 		}
+		metrics.CacheMiss("discountRepository.Get")
 	} else {
-		pr.log.Warn("Redis client is not initialized")
+		pr.log.WithContext(ctx).Warn("Redis client is not initialized")
 	}
 
 	// If not found in cache, get from database
 	var discount Discount
 	result := pr.db.WithContext(ctx).First(&discount, discountID)
 	if result.Error != nil {
-		pr.log.Errorf("Error fetching discount: %v", result.Error)
+		pr.log.WithContext(ctx).Errorf("Error fetching discount: %v", result.Error)
 		return nil, result.Error
 	}
 
@@ -64,9 +67,9 @@ func (pr *discountRepository) Get(ctx context.Context, discountID int64) (*Disco
 	if pr.redis != nil {
 		discountJSON, _ := json.Marshal(discount)
 		if err := pr.redis.Set(ctx, cacheKey, discountJSON, 0).Err(); err != nil {
-			pr.log.Warnf("Failed to save discount to Redis: %v", err)
+			pr.log.WithContext(ctx).Warnf("Failed to save discount to Redis: %v", err)
 		} else {
-			pr.log.Infof("Discount saved to cache: %d", discountID)
+			pr.log.WithContext(ctx).Infof("Discount saved to cache: %d", discountID)
 		}
 	}
 
@@ -74,7 +77,7 @@ func (pr *discountRepository) Get(ctx context.Context, discountID int64) (*Disco
 }
 
 func (pr *discountRepository) GetAll(ctx context.Context) ([]*Discount, error) {
-	pr.log.Info("Fetching all discounts")
+	pr.log.WithContext(ctx).Info("Fetching all discounts")
 	var discounts []*Discount
 	cacheKey := "all_discounts"
 
@@ -83,19 +86,21 @@ func (pr *discountRepository) GetAll(ctx context.Context) ([]*Discount, error) {
 		cachedDiscounts, err := pr.redis.Get(ctx, cacheKey).Result()
 		if err == nil {
 			if err := json.Unmarshal([]byte(cachedDiscounts), &discounts); err == nil {
-				pr.log.Info("Discounts found in cache")
+				pr.log.WithContext(ctx).Info("Discounts found in cache")
+				metrics.CacheHit("discountRepository.GetAll")
 				return discounts, nil
 			}
 		} else if err != redis.Nil {
-			pr.log.Warnf("Failed to get discounts from Redis: %v", err)
+			pr.log.WithContext(ctx).Warnf("Failed to get discounts from Redis: %v", err)
 		}
+		metrics.CacheMiss("discountRepository.GetAll")
 	} else {
-		pr.log.Warn("Redis client is not initialized")
+		pr.log.WithContext(ctx).Warn("Redis client is not initialized")
 	}
 
 	// If not found in cache, get from database
 	if err := pr.db.WithContext(ctx).Find(&discounts).Error; err != nil {
-		pr.log.Errorf("Error fetching discounts from database: %v", err)
+		pr.log.WithContext(ctx).Errorf("Error fetching discounts from database: %v", err)
 		return nil, err
 	}
 
@@ -103,9 +108,9 @@ func (pr *discountRepository) GetAll(ctx context.Context) ([]*Discount, error) {
 	if pr.redis != nil {
 		discountsJSON, _ := json.Marshal(discounts)
 		if err := pr.redis.Set(ctx, cacheKey, discountsJSON, 0).Err(); err != nil {
-			pr.log.Warnf("Failed to save discounts to Redis: %v", err)
+			pr.log.WithContext(ctx).Warnf("Failed to save discounts to Redis: %v", err)
 		} else {
-			pr.log.Info("Discounts saved to cache")
+			pr.log.WithContext(ctx).Info("Discounts saved to cache")
 		}
 	}
 
@@ -113,9 +118,9 @@ func (pr *discountRepository) GetAll(ctx context.Context) ([]*Discount, error) {
 }
 
 func (pr *discountRepository) Create(ctx context.Context, discount *Discount) (*Discount, error) {
-	pr.log.Infof("Creating discount: %+v", discount)
+	pr.log.WithContext(ctx).Infof("Creating discount: %+v", discount)
 	if err := pr.db.WithContext(ctx).Create(discount).Error; err != nil {
-		pr.log.Errorf("Error creating discount: %v", err)
+		pr.log.WithContext(ctx).Errorf("Error creating discount: %v", err)
 		return nil, err
 	}
 	cacheKey := fmt.Sprintf("discount:%d", discount.DiscountID)
@@ -124,16 +129,16 @@ func (pr *discountRepository) Create(ctx context.Context, discount *Discount) (*
 	if pr.redis != nil {
 		discountJSON, _ := json.Marshal(discount)
 		if err := pr.redis.Set(ctx, cacheKey, discountJSON, 0).Err(); err != nil {
-			pr.log.Warnf("Failed to save discount to Redis: %v", err)
+			pr.log.WithContext(ctx).Warnf("Failed to save discount to Redis: %v", err)
 		} else {
-			pr.log.Infof("Discount saved to cache: %d", discount.DiscountID)
+			pr.log.WithContext(ctx).Infof("Discount saved to cache: %d", discount.DiscountID)
 		}
 
 		// Invalidate the cache for all records
 		if err := pr.redis.Del(ctx, "all_discounts").Err(); err != nil {
-			pr.log.Warnf("Failed to invalidate all discounts cache: %v", err)
+			pr.log.WithContext(ctx).Warnf("Failed to invalidate all discounts cache: %v", err)
 		} else {
-			pr.log.Info("Invalidated cache for all discounts")
+			pr.log.WithContext(ctx).Info("Invalidated cache for all discounts")
 		}
 	}
 
@@ -142,9 +147,9 @@ func (pr *discountRepository) Create(ctx context.Context, discount *Discount) (*
 }
 
 func (pr *discountRepository) Update(ctx context.Context, discount *Discount) (*Discount, error) {
-	pr.log.Infof("Updating discount: %+v", discount)
+	pr.log.WithContext(ctx).Infof("Updating discount: %+v", discount)
 	if err := pr.db.WithContext(ctx).Save(discount).Error; err != nil {
-		pr.log.Errorf("Error updating discount: %v", err)
+		pr.log.WithContext(ctx).Errorf("Error updating discount: %v", err)
 		return nil, err
 	}
 	cacheKey := fmt.Sprintf("discount:%d", discount.DiscountID)
@@ -153,16 +158,16 @@ func (pr *discountRepository) Update(ctx context.Context, discount *Discount) (*
 	if pr.redis != nil {
 		discountJSON, _ := json.Marshal(discount)
 		if err := pr.redis.Set(ctx, cacheKey, discountJSON, 0).Err(); err != nil {
-			pr.log.Warnf("Failed to save discount to Redis: %v", err)
+			pr.log.WithContext(ctx).Warnf("Failed to save discount to Redis: %v", err)
 		} else {
-			pr.log.Infof("Discount saved to cache: %d", discount.DiscountID)
+			pr.log.WithContext(ctx).Infof("Discount saved to cache: %d", discount.DiscountID)
 		}
 
 		// Invalidate the cache for all records
 		if err := pr.redis.Del(ctx, "all_discounts").Err(); err != nil {
-			pr.log.Warnf("Failed to invalidate all discounts cache: %v", err)
+			pr.log.WithContext(ctx).Warnf("Failed to invalidate all discounts cache: %v", err)
 		} else {
-			pr.log.Info("Invalidated cache for all discounts")
+			pr.log.WithContext(ctx).Info("Invalidated cache for all discounts")
 		}
 	}
 
@@ -171,9 +176,9 @@ func (pr *discountRepository) Update(ctx context.Context, discount *Discount) (*
 }
 
 func (pr *discountRepository) Delete(ctx context.Context, discountID int64) error {
-	pr.log.Infof("Deleting discount with ID: %d", discountID)
+	pr.log.WithContext(ctx).Infof("Deleting discount with ID: %d", discountID)
 	if err := pr.db.WithContext(ctx).Delete(&Discount{}, discountID).Error; err != nil {
-		pr.log.Errorf("Error deleting discount: %v", err)
+		pr.log.WithContext(ctx).Errorf("Error deleting discount: %v", err)
 		return err
 	}
 
@@ -182,16 +187,16 @@ func (pr *discountRepository) Delete(ctx context.Context, discountID int64) erro
 	// Delete from cache if Redis is available
 	if pr.redis != nil {
 		if err := pr.redis.Del(ctx, cacheKey).Err(); err != nil {
-			pr.log.Warnf("Failed to delete discount from Redis: %v", err)
+			pr.log.WithContext(ctx).Warnf("Failed to delete discount from Redis: %v", err)
 		} else {
-			pr.log.Infof("Discount deleted from cache: %d", discountID)
+			pr.log.WithContext(ctx).Infof("Discount deleted from cache: %d", discountID)
 		}
 
 		// Invalidate the cache for all records
 		if err := pr.redis.Del(ctx, "all_discounts").Err(); err != nil {
-			pr.log.Warnf("Failed to invalidate all discounts cache: %v", err)
+			pr.log.WithContext(ctx).Warnf("Failed to invalidate all discounts cache: %v", err)
 		} else {
-			pr.log.Info("Invalidated cache for all discounts")
+			pr.log.WithContext(ctx).Info("Invalidated cache for all discounts")
 		}
 	}
 
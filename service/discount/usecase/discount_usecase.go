@@ -32,14 +32,14 @@ func NewDiscountUsecase(discountRepo repository.IDiscountRepository, log *logrus
 }
 
 func (pu *discountUsecase) GetDiscount(ctx context.Context, req *model.GetDiscountRequest) (*model.GetDiscountResponse, error) {
-	pu.log.Infof("Fetching discount with ID: %d", req.DiscountID)
+	pu.log.WithContext(ctx).Infof("Fetching discount with ID: %d", req.DiscountID)
 	discount, err := pu.discountRepo.Get(ctx, req.DiscountID)
 	if err != nil {
-		pu.log.Errorf("Error fetching discount: %v", err)
+		pu.log.WithContext(ctx).Errorf("Error fetching discount: %v", err)
 		return nil, err
 	}
 
-	pu.log.Infof("Fetched discount: %+v", discount)
+	pu.log.WithContext(ctx).Infof("Fetched discount: %+v", discount)
 	return &model.GetDiscountResponse{
 		DiscountID:    discount.DiscountID,
 		DiscountType:  discount.DiscountType,
@@ -53,10 +53,10 @@ func (pu *discountUsecase) GetDiscount(ctx context.Context, req *model.GetDiscou
 }
 
 func (pu *discountUsecase) GetAllDiscounts(ctx context.Context) ([]*model.GetDiscountResponse, error) {
-	pu.log.Info("Fetching all discounts")
+	pu.log.WithContext(ctx).Info("Fetching all discounts")
 	discounts, err := pu.discountRepo.GetAll(ctx)
 	if err != nil {
-		pu.log.Errorf("Error fetching discounts: %v", err)
+		pu.log.WithContext(ctx).Errorf("Error fetching discounts: %v", err)
 		return nil, err
 	}
 
@@ -74,12 +74,12 @@ func (pu *discountUsecase) GetAllDiscounts(ctx context.Context) ([]*model.GetDis
 		})
 	}
 
-	pu.log.Infof("Fetched %d discounts", len(discounts))
+	pu.log.WithContext(ctx).Infof("Fetched %d discounts", len(discounts))
 	return discountResponses, nil
 }
 
 func (pu *discountUsecase) CreateDiscount(ctx context.Context, discount *model.CreateDiscountRequest) (*model.GetDiscountResponse, error) {
-	pu.log.Infof("Creating discount: %+v", discount)
+	pu.log.WithContext(ctx).Infof("Creating discount: %+v", discount)
 	createdDiscount, err := pu.discountRepo.Create(ctx, &repository.Discount{
 		DiscountType:  discount.DiscountType,
 		DiscountValue: discount.DiscountValue,
@@ -87,11 +87,11 @@ func (pu *discountUsecase) CreateDiscount(ctx context.Context, discount *model.C
 		EndDate:       discount.EndDate,
 	})
 	if err != nil {
-		pu.log.Errorf("Error creating discount: %v", err)
+		pu.log.WithContext(ctx).Errorf("Error creating discount: %v", err)
 		return nil, err
 	}
 
-	pu.log.Infof("Created discount: %+v", createdDiscount)
+	pu.log.WithContext(ctx).Infof("Created discount: %+v", createdDiscount)
 	return &model.GetDiscountResponse{
 		DiscountID:    createdDiscount.DiscountID,
 		DiscountType:  createdDiscount.DiscountType,
@@ -105,10 +105,10 @@ func (pu *discountUsecase) CreateDiscount(ctx context.Context, discount *model.C
 }
 
 func (pu *discountUsecase) DeleteDiscount(ctx context.Context, req *model.DeleteDiscountRequest) error {
-	pu.log.Infof("Deleting discount with ID: %d", req.DiscountID)
+	pu.log.WithContext(ctx).Infof("Deleting discount with ID: %d", req.DiscountID)
 	discount, err := pu.discountRepo.Get(ctx, req.DiscountID)
 	if err != nil {
-		pu.log.Errorf("Error fetching discount for deletion: %v", err)
+		pu.log.WithContext(ctx).Errorf("Error fetching discount for deletion: %v", err)
 		return err
 	}
 
@@ -116,19 +116,19 @@ func (pu *discountUsecase) DeleteDiscount(ctx context.Context, req *model.Delete
 
 	_, err = pu.discountRepo.Update(ctx, discount)
 	if err != nil {
-		pu.log.Errorf("Error updating discount for deletion: %v", err)
+		pu.log.WithContext(ctx).Errorf("Error updating discount for deletion: %v", err)
 		return err
 	}
 
-	pu.log.Infof("Deleted discount with ID: %d", req.DiscountID)
+	pu.log.WithContext(ctx).Infof("Deleted discount with ID: %d", req.DiscountID)
 	return nil
 }
 
 func (pu *discountUsecase) UpdateDiscount(ctx context.Context, rep *model.UpdateDiscountRequest) (*model.GetDiscountResponse, error) {
-	pu.log.Infof("Updating discount with ID: %d", rep.DiscountID)
+	pu.log.WithContext(ctx).Infof("Updating discount with ID: %d", rep.DiscountID)
 	discount, err := pu.discountRepo.Get(ctx, rep.DiscountID)
 	if err != nil {
-		pu.log.Errorf("Error fetching discount for update: %v", err)
+		pu.log.WithContext(ctx).Errorf("Error fetching discount for update: %v", err)
 		return nil, err
 	}
 
@@ -140,11 +140,11 @@ func (pu *discountUsecase) UpdateDiscount(ctx context.Context, rep *model.Update
 
 	updatedDiscount, err := pu.discountRepo.Update(ctx, discount)
 	if err != nil {
-		pu.log.Errorf("Error updating discount: %v", err)
+		pu.log.WithContext(ctx).Errorf("Error updating discount: %v", err)
 		return nil, err
 	}
 
-	pu.log.Infof("Updated discount: %+v", updatedDiscount)
+	pu.log.WithContext(ctx).Infof("Updated discount: %+v", updatedDiscount)
 	return &model.GetDiscountResponse{
 		DiscountID:    updatedDiscount.DiscountID,
 		DiscountType:  updatedDiscount.DiscountType,
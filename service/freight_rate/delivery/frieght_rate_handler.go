@@ -8,13 +8,13 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
-func GetFreightRateByID(c *gin.Context) {
-	module := dependency_injection.NewFreightRateUsecaseProvider()
+func (h *handler) GetFreightRateByID(c *gin.Context) {
+	module := dependency_injection.NewFreightRateUsecaseProvider(h.cfg)
 
 	var req model.GetFreightRateRequest
 	err := c.BindJSON(&req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(400, gin.H{
 			"error": "Bad Request",
 		})
@@ -22,7 +22,7 @@ func GetFreightRateByID(c *gin.Context) {
 	}
 	freightRate, err := module.GetFreightRate(c, &req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(500, gin.H{
 			"error": "Internal Server Error",
 		})
@@ -32,12 +32,12 @@ func GetFreightRateByID(c *gin.Context) {
 	c.JSON(200, freightRate)
 }
 
-func GetAllFreightRates(c *gin.Context) {
-	module := dependency_injection.NewFreightRateUsecaseProvider()
+func (h *handler) GetAllFreightRates(c *gin.Context) {
+	module := dependency_injection.NewFreightRateUsecaseProvider(h.cfg)
 
 	freightRates, err := module.GetAllFreightRates(c)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(500, gin.H{
 			"error": "Internal Server Error",
 		})
@@ -47,22 +47,22 @@ func GetAllFreightRates(c *gin.Context) {
 	c.JSON(200, freightRates)
 }
 
-func CreateFreightRate(c *gin.Context) {
+func (h *handler) CreateFreightRate(c *gin.Context) {
 	var req model.CreateFreightRateRequest
 	err := c.BindJSON(&req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(400, gin.H{
 			"error": "Bad Request",
 		})
 		return
 	}
 
-	module := dependency_injection.NewFreightRateUsecaseProvider()
+	module := dependency_injection.NewFreightRateUsecaseProvider(h.cfg)
 
 	freightRate, err := module.CreateFreightRate(c, &req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(500, gin.H{
 			"error": "Internal Server Error",
 		})
@@ -72,22 +72,22 @@ func CreateFreightRate(c *gin.Context) {
 	c.JSON(200, freightRate)
 }
 
-func UpdateFreightRate(c *gin.Context) {
+func (h *handler) UpdateFreightRate(c *gin.Context) {
 	var req model.UpdateFreightRateRequest
 	err := c.BindJSON(&req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(400, gin.H{
 			"error": "Bad Request",
 		})
 		return
 	}
 
-	module := dependency_injection.NewFreightRateUsecaseProvider()
+	module := dependency_injection.NewFreightRateUsecaseProvider(h.cfg)
 
 	freightRate, err := module.UpdateFreightRate(c, &req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(500, gin.H{
 			"error": "Internal Server Error",
 		})
@@ -97,13 +97,13 @@ func UpdateFreightRate(c *gin.Context) {
 	c.JSON(200, freightRate)
 }
 
-func DeleteFreightRate(c *gin.Context) {
-	module := dependency_injection.NewFreightRateUsecaseProvider()
+func (h *handler) DeleteFreightRate(c *gin.Context) {
+	module := dependency_injection.NewFreightRateUsecaseProvider(h.cfg)
 
 	var req model.DeleteFreightRateRequest
 	err := c.BindJSON(&req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(400, gin.H{
 			"error": "Bad Request",
 		})
@@ -112,7 +112,7 @@ func DeleteFreightRate(c *gin.Context) {
 
 	err = module.DeleteFreightRate(c, &req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(500, gin.H{
 			"error": "Internal Server Error",
 		})
@@ -1,21 +1,21 @@
 package dependency_injection
 
 import (
+	"th3y3m/e-commerce-microservices/pkg/logging"
 	"th3y3m/e-commerce-microservices/pkg/postgresql"
 	redis_client "th3y3m/e-commerce-microservices/pkg/redis"
+	"th3y3m/e-commerce-microservices/service/freight_rate/config"
 	"th3y3m/e-commerce-microservices/service/freight_rate/repository"
 	"th3y3m/e-commerce-microservices/service/freight_rate/usecase"
-
-	"github.com/sirupsen/logrus"
 )
 
-func NewFreightRateRepositoryProvider() repository.IFreightRateRepository {
-	log := logrus.New()
-	db, err := postgresql.NewGormDB()
+func NewFreightRateRepositoryProvider(cfg config.Config) repository.IFreightRateRepository {
+	log := logging.Logger()
+	db, err := postgresql.NewGormDB(cfg.Postgres)
 	if err != nil {
 		log.Error(err)
 	}
-	redis, err := redis_client.ConnectToRedis()
+	redis, err := redis_client.ConnectToRedis(cfg.Redis)
 	if err != nil {
 		log.Error(err)
 	}
@@ -23,8 +23,8 @@ func NewFreightRateRepositoryProvider() repository.IFreightRateRepository {
 	return repository.NewFreightRateRepository(db, redis, log)
 }
 
-func NewFreightRateUsecaseProvider() usecase.IFreightRateUsecase {
-	log := logrus.New()
-	freightRateRepository := NewFreightRateRepositoryProvider()
+func NewFreightRateUsecaseProvider(cfg config.Config) usecase.IFreightRateUsecase {
+	log := logging.Logger()
+	freightRateRepository := NewFreightRateRepositoryProvider(cfg)
 	return usecase.NewFreightRateUsecase(freightRateRepository, log)
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"th3y3m/e-commerce-microservices/pkg/metrics"
 
 	"github.com/redis/go-redis/v9"
 	"github.com/sirupsen/logrus"
@@ -33,7 +34,7 @@ func NewFreightRateRepository(db *gorm.DB, redis *redis.Client, log *logrus.Logg
 }
 
 func (pr *freightRateRepository) Get(ctx context.Context, freightRateID int64) (*FreightRate, error) {
-	pr.log.Infof("Fetching freightRate with ID: %d", freightRateID)
+	pr.log.WithContext(ctx).Infof("Fetching freightRate with ID: %d", freightRateID)
 	var freightRate FreightRate
 	cacheKey := fmt.Sprintf("freightRate:%d", freightRateID)
 
@@ -42,19 +43,21 @@ func (pr *freightRateRepository) Get(ctx context.Context, freightRateID int64) (
 		cachedFreightRate, err := pr.redis.Get(ctx, cacheKey).Result()
 		if err == nil {
 			if err := json.Unmarshal([]byte(cachedFreightRate), &freightRate); err == nil {
-				pr.log.Infof("FreightRate found in cache: %d", freightRateID)
+				pr.log.WithContext(ctx).Infof("FreightRate found in cache: %d", freightRateID)
+				metrics.CacheHit("freightRateRepository.Get")
 				return &freightRate, nil
 			}
 		} else if err != redis.Nil {
-			pr.log.Warnf("Failed to get freightRate from Redis: %v", err)
+			pr.log.WithContext(ctx).Warnf("Failed to get freightRate from Redis: %v", err)
 		}
+		metrics.CacheMiss("freightRateRepository.Get")
 	} else {
-		pr.log.Warn("Redis client is not initialized")
+		pr.log.WithContext(ctx).Warn("Redis client is not initialized")
 	}
 
 	// If not found in cache, get from database
 	if err := pr.db.WithContext(ctx).First(&freightRate, freightRateID).Error; err != nil {
-		pr.log.Errorf("Error fetching freightRate from database: %v", err)
+		pr.log.WithContext(ctx).Errorf("Error fetching freightRate from database: %v", err)
 		return nil, err
 	}
 
@@ -62,9 +65,9 @@ func (pr *freightRateRepository) Get(ctx context.Context, freightRateID int64) (
 	if pr.redis != nil {
 		freightRateJSON, _ := json.Marshal(freightRate)
 		if err := pr.redis.Set(ctx, cacheKey, freightRateJSON, 0).Err(); err != nil {
-			pr.log.Warnf("Failed to save freightRate to Redis: %v", err)
+			pr.log.WithContext(ctx).Warnf("Failed to save freightRate to Redis: %v", err)
 		} else {
-			pr.log.Infof("FreightRate saved to cache: %d", freightRateID)
+			pr.log.WithContext(ctx).Infof("FreightRate saved to cache: %d", freightRateID)
 		}
 	}
 
@@ -72,7 +75,7 @@ func (pr *freightRateRepository) Get(ctx context.Context, freightRateID int64) (
 }
 
 func (pr *freightRateRepository) GetAll(ctx context.Context) ([]*FreightRate, error) {
-	pr.log.Info("Fetching all freightRates")
+	pr.log.WithContext(ctx).Info("Fetching all freightRates")
 	var freightRates []*FreightRate
 	cacheKey := "all_freightRates"
 
@@ -81,19 +84,21 @@ func (pr *freightRateRepository) GetAll(ctx context.Context) ([]*FreightRate, er
 		cachedFreightRates, err := pr.redis.Get(ctx, cacheKey).Result()
 		if err == nil {
 			if err := json.Unmarshal([]byte(cachedFreightRates), &freightRates); err == nil {
-				pr.log.Info("FreightRates found in cache")
+				pr.log.WithContext(ctx).Info("FreightRates found in cache")
+				metrics.CacheHit("freightRateRepository.GetAll")
 				return freightRates, nil
 			}
 		} else if err != redis.Nil {
-			pr.log.Warnf("Failed to get freightRates from Redis: %v", err)
+			pr.log.WithContext(ctx).Warnf("Failed to get freightRates from Redis: %v", err)
 		}
+		metrics.CacheMiss("freightRateRepository.GetAll")
 	} else {
-		pr.log.Warn("Redis client is not initialized")
+		pr.log.WithContext(ctx).Warn("Redis client is not initialized")
 	}
 
 	// If not found in cache, get from database
 	if err := pr.db.WithContext(ctx).Find(&freightRates).Error; err != nil {
-		pr.log.Errorf("Error fetching freightRates from database: %v", err)
+		pr.log.WithContext(ctx).Errorf("Error fetching freightRates from database: %v", err)
 		return nil, err
 	}
 
@@ -101,9 +106,9 @@ func (pr *freightRateRepository) GetAll(ctx context.Context) ([]*FreightRate, er
 	if pr.redis != nil {
 		freightRatesJSON, _ := json.Marshal(freightRates)
 		if err := pr.redis.Set(ctx, cacheKey, freightRatesJSON, 0).Err(); err != nil {
-			pr.log.Warnf("Failed to save freightRates to Redis: %v", err)
+			pr.log.WithContext(ctx).Warnf("Failed to save freightRates to Redis: %v", err)
 		} else {
-			pr.log.Info("FreightRates saved to cache")
+			pr.log.WithContext(ctx).Info("FreightRates saved to cache")
 		}
 	}
 
@@ -111,9 +116,9 @@ func (pr *freightRateRepository) GetAll(ctx context.Context) ([]*FreightRate, er
 }
 
 func (pr *freightRateRepository) Create(ctx context.Context, freightRate *FreightRate) (*FreightRate, error) {
-	pr.log.Infof("Creating freightRate: %+v", freightRate)
+	pr.log.WithContext(ctx).Infof("Creating freightRate: %+v", freightRate)
 	if err := pr.db.WithContext(ctx).Create(freightRate).Error; err != nil {
-		pr.log.Errorf("Error creating freightRate: %v", err)
+		pr.log.WithContext(ctx).Errorf("Error creating freightRate: %v", err)
 		return nil, err
 	}
 	cacheKey := fmt.Sprintf("freightRate:%d", freightRate.FreightRateID)
@@ -122,16 +127,16 @@ func (pr *freightRateRepository) Create(ctx context.Context, freightRate *Freigh
 	if pr.redis != nil {
 		freightRateJSON, _ := json.Marshal(freightRate)
 		if err := pr.redis.Set(ctx, cacheKey, freightRateJSON, 0).Err(); err != nil {
-			pr.log.Warnf("Failed to save freightRate to Redis: %v", err)
+			pr.log.WithContext(ctx).Warnf("Failed to save freightRate to Redis: %v", err)
 		} else {
-			pr.log.Infof("FreightRate saved to cache: %d", freightRate.FreightRateID)
+			pr.log.WithContext(ctx).Infof("FreightRate saved to cache: %d", freightRate.FreightRateID)
 		}
 
 		// Invalidate the cache for all records
 		if err := pr.redis.Del(ctx, "all_freightRates").Err(); err != nil {
-			pr.log.Warnf("Failed to invalidate all freightRates cache: %v", err)
+			pr.log.WithContext(ctx).Warnf("Failed to invalidate all freightRates cache: %v", err)
 		} else {
-			pr.log.Info("Invalidated cache for all freightRates")
+			pr.log.WithContext(ctx).Info("Invalidated cache for all freightRates")
 		}
 	}
 
@@ -140,9 +145,9 @@ func (pr *freightRateRepository) Create(ctx context.Context, freightRate *Freigh
 }
 
 func (pr *freightRateRepository) Update(ctx context.Context, freightRate *FreightRate) (*FreightRate, error) {
-	pr.log.Infof("Updating freightRate: %+v", freightRate)
+	pr.log.WithContext(ctx).Infof("Updating freightRate: %+v", freightRate)
 	if err := pr.db.WithContext(ctx).Save(freightRate).Error; err != nil {
-		pr.log.Errorf("Error updating freightRate: %v", err)
+		pr.log.WithContext(ctx).Errorf("Error updating freightRate: %v", err)
 		return nil, err
 	}
 	cacheKey := fmt.Sprintf("freightRate:%d", freightRate.FreightRateID)
@@ -151,16 +156,16 @@ func (pr *freightRateRepository) Update(ctx context.Context, freightRate *Freigh
 	if pr.redis != nil {
 		freightRateJSON, _ := json.Marshal(freightRate)
 		if err := pr.redis.Set(ctx, cacheKey, freightRateJSON, 0).Err(); err != nil {
-			pr.log.Warnf("Failed to save freightRate to Redis: %v", err)
+			pr.log.WithContext(ctx).Warnf("Failed to save freightRate to Redis: %v", err)
 		} else {
-			pr.log.Infof("FreightRate saved to cache: %d", freightRate.FreightRateID)
+			pr.log.WithContext(ctx).Infof("FreightRate saved to cache: %d", freightRate.FreightRateID)
 		}
 
 		// Invalidate the cache for all records
 		if err := pr.redis.Del(ctx, "all_freightRates").Err(); err != nil {
-			pr.log.Warnf("Failed to invalidate all freightRates cache: %v", err)
+			pr.log.WithContext(ctx).Warnf("Failed to invalidate all freightRates cache: %v", err)
 		} else {
-			pr.log.Info("Invalidated cache for all freightRates")
+			pr.log.WithContext(ctx).Info("Invalidated cache for all freightRates")
 		}
 	}
 
@@ -169,9 +174,9 @@ func (pr *freightRateRepository) Update(ctx context.Context, freightRate *Freigh
 }
 
 func (pr *freightRateRepository) Delete(ctx context.Context, freightRateID int64) error {
-	pr.log.Infof("Deleting freightRate with ID: %d", freightRateID)
+	pr.log.WithContext(ctx).Infof("Deleting freightRate with ID: %d", freightRateID)
 	if err := pr.db.WithContext(ctx).Delete(&FreightRate{}, freightRateID).Error; err != nil {
-		pr.log.Errorf("Error deleting freightRate: %v", err)
+		pr.log.WithContext(ctx).Errorf("Error deleting freightRate: %v", err)
 		return err
 	}
 
@@ -180,16 +185,16 @@ func (pr *freightRateRepository) Delete(ctx context.Context, freightRateID int64
 	// Delete from cache if Redis is available
 	if pr.redis != nil {
 		if err := pr.redis.Del(ctx, cacheKey).Err(); err != nil {
-			pr.log.Warnf("Failed to delete freightRate from Redis: %v", err)
+			pr.log.WithContext(ctx).Warnf("Failed to delete freightRate from Redis: %v", err)
 		} else {
-			pr.log.Infof("FreightRate deleted from cache: %d", freightRateID)
+			pr.log.WithContext(ctx).Infof("FreightRate deleted from cache: %d", freightRateID)
 		}
 
 		// Invalidate the cache for all records
 		if err := pr.redis.Del(ctx, "all_freightRates").Err(); err != nil {
-			pr.log.Warnf("Failed to invalidate all freightRates cache: %v", err)
+			pr.log.WithContext(ctx).Warnf("Failed to invalidate all freightRates cache: %v", err)
 		} else {
-			pr.log.Info("Invalidated cache for all freightRates")
+			pr.log.WithContext(ctx).Info("Invalidated cache for all freightRates")
 		}
 	}
 
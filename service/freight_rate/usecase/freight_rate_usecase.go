@@ -32,14 +32,14 @@ func NewFreightRateUsecase(freightRateRepo repository.IFreightRateRepository, lo
 }
 
 func (pu *freightRateUsecase) GetFreightRate(ctx context.Context, req *model.GetFreightRateRequest) (*model.GetFreightRateResponse, error) {
-	pu.log.Infof("Fetching freightRate with ID: %d", req.FreightRateID)
+	pu.log.WithContext(ctx).Infof("Fetching freightRate with ID: %d", req.FreightRateID)
 	freightRate, err := pu.freightRateRepo.Get(ctx, req.FreightRateID)
 	if err != nil {
-		pu.log.Errorf("Error fetching freightRate: %v", err)
+		pu.log.WithContext(ctx).Errorf("Error fetching freightRate: %v", err)
 		return nil, err
 	}
 
-	pu.log.Infof("Fetched freightRate: %+v", freightRate)
+	pu.log.WithContext(ctx).Infof("Fetched freightRate: %+v", freightRate)
 	return &model.GetFreightRateResponse{
 		FreightRateID: freightRate.FreightRateID,
 		CourierID:     freightRate.CourierID,
@@ -54,10 +54,10 @@ func (pu *freightRateUsecase) GetFreightRate(ctx context.Context, req *model.Get
 
 func (pu *freightRateUsecase) GetAllFreightRates(ctx context.Context) ([]*model.GetFreightRateResponse, error) {
 
-	pu.log.Infof("Fetching all freightRates")
+	pu.log.WithContext(ctx).Infof("Fetching all freightRates")
 	freightRates, err := pu.freightRateRepo.GetAll(ctx)
 	if err != nil {
-		pu.log.Errorf("Error fetching freightRates: %v", err)
+		pu.log.WithContext(ctx).Errorf("Error fetching freightRates: %v", err)
 		return nil, err
 	}
 
@@ -75,12 +75,12 @@ func (pu *freightRateUsecase) GetAllFreightRates(ctx context.Context) ([]*model.
 		})
 	}
 
-	pu.log.Infof("Fetched %d freightRates", len(freightRateResponses))
+	pu.log.WithContext(ctx).Infof("Fetched %d freightRates", len(freightRateResponses))
 	return freightRateResponses, nil
 }
 
 func (pu *freightRateUsecase) CreateFreightRate(ctx context.Context, freightRate *model.CreateFreightRateRequest) (*model.GetFreightRateResponse, error) {
-	pu.log.Infof("Creating freightRate: %+v", freightRate)
+	pu.log.WithContext(ctx).Infof("Creating freightRate: %+v", freightRate)
 	newFreightRate := &repository.FreightRate{
 		CourierID:     freightRate.CourierID,
 		DistanceMinKM: freightRate.DistanceMinKM,
@@ -90,11 +90,11 @@ func (pu *freightRateUsecase) CreateFreightRate(ctx context.Context, freightRate
 
 	createdFreightRate, err := pu.freightRateRepo.Create(ctx, newFreightRate)
 	if err != nil {
-		pu.log.Errorf("Error creating freightRate: %v", err)
+		pu.log.WithContext(ctx).Errorf("Error creating freightRate: %v", err)
 		return nil, err
 	}
 
-	pu.log.Infof("Created freightRate: %+v", createdFreightRate)
+	pu.log.WithContext(ctx).Infof("Created freightRate: %+v", createdFreightRate)
 	return &model.GetFreightRateResponse{
 		FreightRateID: createdFreightRate.FreightRateID,
 		CourierID:     createdFreightRate.CourierID,
@@ -108,10 +108,10 @@ func (pu *freightRateUsecase) CreateFreightRate(ctx context.Context, freightRate
 }
 
 func (pu *freightRateUsecase) DeleteFreightRate(ctx context.Context, req *model.DeleteFreightRateRequest) error {
-	pu.log.Infof("Deleting freightRate with ID: %d", req.FreightRateID)
+	pu.log.WithContext(ctx).Infof("Deleting freightRate with ID: %d", req.FreightRateID)
 	freightRate, err := pu.freightRateRepo.Get(ctx, req.FreightRateID)
 	if err != nil {
-		pu.log.Errorf("Error fetching freightRate for deletion: %v", err)
+		pu.log.WithContext(ctx).Errorf("Error fetching freightRate for deletion: %v", err)
 		return err
 	}
 
@@ -119,19 +119,19 @@ func (pu *freightRateUsecase) DeleteFreightRate(ctx context.Context, req *model.
 
 	_, err = pu.freightRateRepo.Update(ctx, freightRate)
 	if err != nil {
-		pu.log.Errorf("Error updating freightRate for deletion: %v", err)
+		pu.log.WithContext(ctx).Errorf("Error updating freightRate for deletion: %v", err)
 		return err
 	}
 
-	pu.log.Infof("Deleted freightRate with ID: %d", req.FreightRateID)
+	pu.log.WithContext(ctx).Infof("Deleted freightRate with ID: %d", req.FreightRateID)
 	return nil
 }
 
 func (pu *freightRateUsecase) UpdateFreightRate(ctx context.Context, rep *model.UpdateFreightRateRequest) (*model.GetFreightRateResponse, error) {
-	pu.log.Infof("Updating freightRate: %+v", rep)
+	pu.log.WithContext(ctx).Infof("Updating freightRate: %+v", rep)
 	freightRate, err := pu.freightRateRepo.Get(ctx, rep.FreightRateID)
 	if err != nil {
-		pu.log.Errorf("Error fetching freightRate for update: %v", err)
+		pu.log.WithContext(ctx).Errorf("Error fetching freightRate for update: %v", err)
 		return nil, err
 	}
 
@@ -144,11 +144,11 @@ func (pu *freightRateUsecase) UpdateFreightRate(ctx context.Context, rep *model.
 
 	updatedFreightRate, err := pu.freightRateRepo.Update(ctx, freightRate)
 	if err != nil {
-		pu.log.Errorf("Error updating freightRate: %v", err)
+		pu.log.WithContext(ctx).Errorf("Error updating freightRate: %v", err)
 		return nil, err
 	}
 
-	pu.log.Infof("Updated freightRate: %+v", updatedFreightRate)
+	pu.log.WithContext(ctx).Infof("Updated freightRate: %+v", updatedFreightRate)
 	return &model.GetFreightRateResponse{
 		FreightRateID: updatedFreightRate.FreightRateID,
 		CourierID:     updatedFreightRate.CourierID,
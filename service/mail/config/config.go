@@ -0,0 +1,19 @@
+package config
+
+import pkgconfig "th3y3m/e-commerce-microservices/pkg/config"
+
+// Config of the mail service
+type Config struct {
+	pkgconfig.Base
+	pkgconfig.RabbitMQ
+	pkgconfig.Inbox
+	SMTP SMTP
+}
+
+// SMTP is the account mails are sent from
+type SMTP struct {
+	Email    string `env:"EMAIL" required:"true"`
+	Password string `env:"PASSWORD" required:"true" secret:"true"`
+	Host     string `env:"SMTP_HOST" required:"true"`
+	Port     string `env:"SMTP_PORT" required:"true"`
+}
@@ -7,7 +7,7 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-func SendMail(c *gin.Context) {
+func (h *handler) SendMail(c *gin.Context) {
 	to := c.Query("to")
 	token := c.Query("token")
 
@@ -18,7 +18,7 @@ func SendMail(c *gin.Context) {
 		return
 	}
 
-	module := dependency_injection.NewMailUsecaseProvider()
+	module := dependency_injection.NewMailUsecaseProvider(h.cfg, h.services)
 
 	err := module.SendMail(to, token)
 	if err != nil {
@@ -41,7 +41,7 @@ func SendMail(c *gin.Context) {
 // 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": err.Error()})
 // 		return
 // 	}
-// 	module := dependency_injection.NewMailUsecaseProvider()
+// 	module := dependency_injection.NewMailUsecaseProvider(h.cfg, h.services)
 // 	// Call the use case to send the order details email
 // 	err := module.SendOrderDetails(request.Customer, request.Order, request.OrderDetails)
 // 	if err != nil {
@@ -54,7 +54,7 @@ func SendMail(c *gin.Context) {
 // 	c.JSON(http.StatusOK, gin.H{"message": "Order details sent successfully"})
 // }
 
-func SendNotification(c *gin.Context) {
+func (h *handler) SendNotification(c *gin.Context) {
 	orderIDStr := c.Query("order_id")
 	url := c.Query("url")
 
@@ -66,7 +66,7 @@ func SendNotification(c *gin.Context) {
 		return
 	}
 
-	module := dependency_injection.NewMailUsecaseProvider()
+	module := dependency_injection.NewMailUsecaseProvider(h.cfg, h.services)
 
 	err = module.SendNotification(c, orderID, url)
 	if err != nil {
@@ -2,31 +2,51 @@ package delivery
 
 import (
 	"context"
-	"log"
+	"net/http"
+	"th3y3m/e-commerce-microservices/pkg/authz"
+	"th3y3m/e-commerce-microservices/pkg/discovery"
+	"th3y3m/e-commerce-microservices/pkg/logging"
+	"th3y3m/e-commerce-microservices/pkg/metrics"
+	"th3y3m/e-commerce-microservices/pkg/tracing"
+	"th3y3m/e-commerce-microservices/service/mail/config"
 	"th3y3m/e-commerce-microservices/service/mail/dependency_injection"
 	"th3y3m/e-commerce-microservices/service/mail/rabbitmq"
 
 	"github.com/gin-gonic/gin"
 )
 
-func RegisterHandlers() *gin.Engine {
-	r := gin.Default()
+// handler serves the routes of the service, with the usecases built from cfg
+type handler struct {
+	cfg config.Config
+	// Calls the other services for the usecases
+	services *http.Client
+}
 
-	module := dependency_injection.NewMailUsecaseProvider()
-	ctx := context.Background()
+func RegisterHandlers(cfg config.Config, resolver discovery.Resolver) *gin.Engine {
+	h := &handler{cfg: cfg, services: dependency_injection.NewServiceClientProvider(cfg, resolver)}
+	keys := authz.NewKeys(cfg.Auth)
 
-	go func() {
-		if err := rabbitmq.ConsumeMailNotification(ctx, module); err != nil {
-			log.Fatalf("Failed to start RabbitMQ consumer: %v", err)
-		}
-	}()
+	r := gin.New()
+	// Lets the request context, and the trace in it, reach the usecases through *gin.Context
+	r.ContextWithFallback = true
+	r.Use(gin.Recovery(), tracing.Middleware("mail"), logging.Middleware(), metrics.Middleware())
+	r.GET(metrics.Path, metrics.Handler())
+	r.Use(keys.Middleware())
 
 	mail := r.Group("/api/mail")
 	{
-		mail.POST("/send-mail", SendMail)
-		mail.POST("/send-noti", SendNotification)
+		mail.POST("/send-mail", h.SendMail)
+		mail.POST("/send-noti", h.SendNotification)
 		// mail.POST("/send-order-details", SendOrderDetails)
 	}
 
 	return r
 }
+
+// ConsumeMailNotification mails the customers of placed orders until ctx is canceled
+func ConsumeMailNotification(cfg config.Config, resolver discovery.Resolver) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		services := dependency_injection.NewServiceClientProvider(cfg, resolver)
+		return rabbitmq.ConsumeMailNotification(ctx, dependency_injection.NewBrokerProvider(cfg), dependency_injection.NewMailUsecaseProvider(cfg, services), dependency_injection.NewInboxProvider(cfg))
+	}
+}
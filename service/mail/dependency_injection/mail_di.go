@@ -1,12 +1,47 @@
 package dependency_injection
 
 import (
+	"net/http"
+	"th3y3m/e-commerce-microservices/pkg/authz"
+	"th3y3m/e-commerce-microservices/pkg/broker"
+	"th3y3m/e-commerce-microservices/pkg/clients"
+	"th3y3m/e-commerce-microservices/pkg/discovery"
+	"th3y3m/e-commerce-microservices/pkg/inbox"
+	"th3y3m/e-commerce-microservices/pkg/logging"
+	"th3y3m/e-commerce-microservices/pkg/rabbitmq"
+	redis_client "th3y3m/e-commerce-microservices/pkg/redis"
+	"th3y3m/e-commerce-microservices/service/mail/config"
 	"th3y3m/e-commerce-microservices/service/mail/usecase"
-
-	"github.com/sirupsen/logrus"
 )
 
-func NewMailUsecaseProvider() usecase.IMailUsecase {
-	log := logrus.New()
-	return usecase.NewMailUsecase(log)
+func NewMailUsecaseProvider(cfg config.Config, httpClient *http.Client) usecase.IMailUsecase {
+	log := logging.Logger()
+	opts := clients.NewOptions(cfg.Clients)
+	return usecase.NewMailUsecase(log, cfg.SMTP, usecase.Clients{
+		Product:     clients.NewProductClient(httpClient, opts),
+		Order:       clients.NewOrderClient(httpClient, opts),
+		OrderDetail: clients.NewOrderDetailClient(httpClient, opts),
+		User:        clients.NewUserClient(httpClient, opts),
+	})
+}
+
+// NewInboxProvider remembers the events the mail service processed in Redis
+func NewInboxProvider(cfg config.Config) inbox.Store {
+	log := logging.Logger()
+	redis, err := redis_client.NewClient(cfg.Redis)
+	if err != nil {
+		log.Error(err)
+	}
+	return inbox.NewRedisStore(redis, cfg.Inbox)
+}
+
+// NewBrokerProvider returns the RabbitMQ broker, or the in-process one when BROKER is memory
+func NewBrokerProvider(cfg config.Config) broker.Broker {
+	return broker.FromConfig(cfg.RabbitMQ, rabbitmq.DefaultBroker)
+}
+
+// NewServiceClientProvider returns the HTTP client the mail service calls other
+// services with, signed with its service token and sent to the instances resolver finds
+func NewServiceClientProvider(cfg config.Config, resolver discovery.Resolver) *http.Client {
+	return authz.NewKeys(cfg.Auth).NewServiceClient("mail", resolver)
 }
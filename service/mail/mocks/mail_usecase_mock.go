@@ -1,4 +1,4 @@
-// Code generated by mockery v2.46.3. DO NOT EDIT.
+// Code generated by mockery v2.53.5. DO NOT EDIT.
 
 package mocks
 
@@ -50,17 +50,17 @@ func (_m *IMailUsecase) SendNotification(ctx context.Context, orderID int64, url
 	return r0
 }
 
-// SendOrderDetails provides a mock function with given fields: Customer, Order, OrderDetails, urlPayment
-func (_m *IMailUsecase) SendOrderDetails(Customer model.User, Order model.Order, OrderDetails []model.OrderDetail, urlPayment string) error {
-	ret := _m.Called(Customer, Order, OrderDetails, urlPayment)
+// SendOrderDetails provides a mock function with given fields: ctx, Customer, Order, OrderDetails, urlPayment
+func (_m *IMailUsecase) SendOrderDetails(ctx context.Context, Customer model.User, Order model.Order, OrderDetails []model.OrderDetail, urlPayment string) error {
+	ret := _m.Called(ctx, Customer, Order, OrderDetails, urlPayment)
 
 	if len(ret) == 0 {
 		panic("no return value specified for SendOrderDetails")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(model.User, model.Order, []model.OrderDetail, string) error); ok {
-		r0 = rf(Customer, Order, OrderDetails, urlPayment)
+	if rf, ok := ret.Get(0).(func(context.Context, model.User, model.Order, []model.OrderDetail, string) error); ok {
+		r0 = rf(ctx, Customer, Order, OrderDetails, urlPayment)
 	} else {
 		r0 = ret.Error(0)
 	}
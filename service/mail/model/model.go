@@ -2,66 +2,6 @@ package model
 
 import "time"
 
-type GetProductResponse struct {
-	ProductID   int64   `json:"product_id"`
-	SellerID    int64   `json:"seller_id"`
-	ProductName string  `json:"product_name"`
-	Description string  `json:"description"`
-	Price       float64 `json:"price"`
-	Quantity    int     `json:"quantity"`
-	CategoryID  int64   `json:"category_id"`
-	ImageURL    string  `json:"image_url"`
-	CreatedAt   string  `json:"created_at"`
-	UpdatedAt   string  `json:"updated_at"`
-	IsDeleted   bool    `json:"is_deleted"`
-}
-type GetOrderDetailsRequest struct {
-	OrderID   *int64 `json:"order_id"`
-	ProductID *int64 `json:"product_id"`
-}
-type GetOrderDetailResponse struct {
-	OrderID   int64   `json:"order_id"`
-	ProductID int64   `json:"product_id"`
-	Quantity  int     `json:"quantity"`
-	UnitPrice float64 `json:"unit_price"`
-}
-type GetUserResponse struct {
-	UserID       int64  `json:"user_id"`
-	Email        string `json:"email"`
-	PasswordHash string `json:"password_hash"`
-	FullName     string `json:"full_name"`
-	PhoneNumber  string `json:"phone_number"`
-	Address      string `json:"address"`
-	Role         string `json:"role"`
-	ImageURL     string `json:"image_url"`
-	Provider     string `json:"provider"`
-	CreatedAt    string `json:"created_at"`
-	UpdatedAt    string `json:"updated_at"`
-	Token        string `json:"token"`
-	TokenExpires string `json:"token_expires"`
-	IsVerified   bool   `json:"is_verified"`
-	IsDeleted    bool   `json:"is_deleted"`
-}
-type GetUserRequest struct {
-	UserID *int64 `json:"user_id"`
-	Email  string `json:"email"`
-}
-type GetOrderResponse struct {
-	OrderID               int64   `json:"order_id"`
-	CustomerID            int64   `json:"customer_id"`
-	OrderDate             string  `json:"order_date"`
-	TotalAmount           float64 `json:"total_amount"`
-	OrderStatus           string  `json:"order_status"`
-	ShippingAddress       string  `json:"shipping_address"`
-	CourierID             int64   `json:"courier_id"`
-	FreightPrice          float64 `json:"freight_price"`
-	EstimatedDeliveryDate string  `json:"estimated_delivery_date"`
-	ActualDeliveryDate    string  `json:"actual_delivery_date"`
-	VoucherID             int64   `json:"voucher_id"`
-	IsDeleted             bool    `json:"is_deleted"`
-	CreatedAt             string  `json:"created_at"`
-	UpdatedAt             string  `json:"updated_at"`
-}
 type User struct {
 	UserID       int64     `json:"user_id"`
 	Email        string    `json:"email"`
@@ -2,28 +2,21 @@ package rabbitmq
 
 import (
 	"context"
-	"log"
-	"strconv"
-	"th3y3m/e-commerce-microservices/pkg/rabbitmq"
+	"th3y3m/e-commerce-microservices/pkg/broker"
+	"th3y3m/e-commerce-microservices/pkg/events"
+	"th3y3m/e-commerce-microservices/pkg/inbox"
+	"th3y3m/e-commerce-microservices/pkg/logging"
 	"th3y3m/e-commerce-microservices/service/mail/usecase"
 )
 
-func ConsumeMailNotification(ctx context.Context, mailUsecase usecase.IMailUsecase) error {
-	return rabbitmq.ConsumeMessages(ctx, "order_notification_queue", func(message map[string]string) error {
-		orderIdStr := message["orderId"]
-		orderId, err := strconv.ParseInt(orderIdStr, 10, 64)
-		if err != nil {
-			log.Printf("Failed to convert orderId to int64: %v", err)
-			return err
-		}
-
-		url := message["url"]
-
-		if err := mailUsecase.SendNotification(ctx, orderId, url); err != nil {
-			log.Printf("Failed to send notification: %v", err)
+// ConsumeMailNotification handles every placed order once, a redelivered order is not mailed again
+func ConsumeMailNotification(ctx context.Context, b broker.Broker, mailUsecase usecase.IMailUsecase, store inbox.Store) error {
+	return broker.Consume(ctx, b, broker.OrderNotifications, inbox.Once(store, broker.OrderNotifications.Queue, func(ctx context.Context, _ *events.Envelope, event events.OrderPlaced) error {
+		if err := mailUsecase.SendNotification(ctx, event.OrderID, event.PaymentURL); err != nil {
+			logging.Logger().WithContext(ctx).Errorf("Failed to send notification: %v", err)
 			return err
 		}
 
 		return nil
-	})
+	}))
 }
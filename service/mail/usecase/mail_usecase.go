@@ -3,47 +3,55 @@ package usecase
 import (
 	"bytes"
 	"context"
-	"encoding/json"
 	"fmt"
 	"log"
-	"net/http"
 	"net/smtp"
 	"os"
 	"path/filepath"
-	"strconv"
 	"strings"
 	"text/template"
+	"th3y3m/e-commerce-microservices/pkg/authz"
+	"th3y3m/e-commerce-microservices/pkg/clients"
 
-	"th3y3m/e-commerce-microservices/pkg/constant"
 	"th3y3m/e-commerce-microservices/pkg/util"
+	"th3y3m/e-commerce-microservices/service/mail/config"
 	"th3y3m/e-commerce-microservices/service/mail/model"
 
 	"github.com/sirupsen/logrus"
-	"github.com/spf13/viper"
 )
 
+// Clients of the services the notifications are written from
+type Clients struct {
+	Product     *clients.ProductClient
+	Order       *clients.OrderClient
+	OrderDetail *clients.OrderDetailClient
+	User        *clients.UserClient
+}
+
 type mailUsecase struct {
-	log *logrus.Logger
+	log     *logrus.Logger
+	smtp    config.SMTP
+	clients Clients
 }
 
 type IMailUsecase interface {
 	SendMail(to string, token string) error
-	SendOrderDetails(Customer model.User, Order model.Order, OrderDetails []model.OrderDetail, urlPayment string) error
+	SendOrderDetails(ctx context.Context, Customer model.User, Order model.Order, OrderDetails []model.OrderDetail, urlPayment string) error
 	SendNotification(ctx context.Context, orderID int64, url string) error
 }
 
-func NewMailUsecase(log *logrus.Logger) IMailUsecase {
+func NewMailUsecase(log *logrus.Logger, smtp config.SMTP, clients Clients) IMailUsecase {
 	return &mailUsecase{
-		log: log,
+		log:     log,
+		smtp:    smtp,
+		clients: clients,
 	}
 }
 
 // SendMail sends the email to the user
 func (m *mailUsecase) SendMail(to string, token string) error {
-
-	// Retrieve environment variables
-	from, password := viper.GetString("EMAIL"), viper.GetString("PASSWORD")
-	smtpHost, smtpPort := viper.GetString("SMTP_HOST"), viper.GetString("SMTP_PORT")
+	from, password := m.smtp.Email, m.smtp.Password
+	smtpHost, smtpPort := m.smtp.Host, m.smtp.Port
 
 	// Construct the file path for the HTML template
 	htmlPath := filepath.Join("templates", "Confirmation.html") // Adjust path as needed
@@ -75,46 +83,29 @@ func (m *mailUsecase) SendMail(to string, token string) error {
 	return nil
 }
 
-func (m *mailUsecase) SendOrderDetails(Customer model.User, Order model.Order, OrderDetails []model.OrderDetail, urlPayment string) error {
-	from, password := viper.GetString("EMAIL"), viper.GetString("PASSWORD")
-	smtpHost, smtpPort := viper.GetString("SMTP_HOST"), viper.GetString("SMTP_PORT")
+func (m *mailUsecase) SendOrderDetails(ctx context.Context, Customer model.User, Order model.Order, OrderDetails []model.OrderDetail, urlPayment string) error {
+	from, password := m.smtp.Email, m.smtp.Password
+	smtpHost, smtpPort := m.smtp.Host, m.smtp.Port
 
 	// Populate the form data
 	type OrderDetailWithProduct struct {
 		OrderDetail model.OrderDetail
-		Product     model.GetProductResponse
+		Product     clients.Product
 	}
 
 	var orderDetailsWithProduct []OrderDetailWithProduct
 	for _, od := range OrderDetails {
 		// Fetch the product details from the product service
-		url := constant.PRODUCT_SERVICE + "/" + strconv.FormatInt(od.ProductID, 10)
-
-		res, err := http.Get(url)
+		product, err := m.clients.Product.Get(ctx, od.ProductID)
 		if err != nil {
 			log.Printf("Failed to get product details for product ID %d: %v", od.ProductID, err)
 			return err
 		}
-		defer res.Body.Close()
-
-		// Check if the request was successful
-		if res.StatusCode != http.StatusOK {
-			log.Printf("Error: Received status code %d from product service", res.StatusCode)
-			return fmt.Errorf("product service returned status code %d", res.StatusCode)
-		}
-
-		// Unmarshal the response into the Product struct
-		var product model.GetProductResponse
-		err = json.NewDecoder(res.Body).Decode(&product)
-		if err != nil {
-			log.Printf("Failed to decode product details for product ID %d: %v", od.ProductID, err)
-			return err
-		}
 
 		// Append the OrderDetail and corresponding Product to the slice
 		orderDetailsWithProduct = append(orderDetailsWithProduct, OrderDetailWithProduct{
 			OrderDetail: od,
-			Product:     product,
+			Product:     *product,
 		})
 	}
 
@@ -216,108 +207,26 @@ func (m *mailUsecase) SendOrderDetails(Customer model.User, Order model.Order, O
 }
 
 func (o *mailUsecase) SendNotification(ctx context.Context, orderID int64, urlPayment string) error {
-	url := constant.ORDER_SERVICE + "/" + strconv.FormatInt(orderID, 10)
+	// Notifications follow events, there is no user to act for
+	ctx = authz.AsSystem(ctx)
 
 	// Fetch the order details from the order service
-	res, err := http.Get(url)
-	if err != nil {
-		o.log.Errorf("Failed to get order details: %v", err)
-		return err
-	}
-
-	defer res.Body.Close()
-
-	// Check if the request was successful
-	if res.StatusCode != http.StatusOK {
-		o.log.Errorf("order service returned non-OK status: %d", res.StatusCode)
-		return fmt.Errorf("order service returned non-OK status: %d", res.StatusCode)
-	}
-
-	// Decode the response into the GetOrderResponse struct
-	var order model.GetOrderResponse
-	err = json.NewDecoder(res.Body).Decode(&order)
+	order, err := o.clients.Order.Get(ctx, orderID)
 	if err != nil {
-		o.log.Errorf("Failed to decode response: %v", err)
+		o.log.WithContext(ctx).Errorf("Failed to get order details: %v", err)
 		return err
 	}
 
 	// Fetch the user details from the user service
-
-	getUserRequest := model.GetUserRequest{
-		UserID: &order.CustomerID,
-	}
-
-	userData, err := json.Marshal(getUserRequest)
+	customer, err := o.clients.User.Get(ctx, order.CustomerID)
 	if err != nil {
-		o.log.Errorf("Failed to marshal order data: %v", err)
+		o.log.WithContext(ctx).Errorf("Failed to get customer: %v", err)
 		return err
 	}
 
-	url = constant.USER_SERVICE + "/get-user"
-	req, err := http.NewRequest("GET", url, bytes.NewBuffer(userData))
-	if err != nil {
-		o.log.Errorf("Failed to create request: %v", err)
-		return err
-	}
-
-	// Set the context and execute the request
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		o.log.Errorf("Failed to execute request: %v", err)
-		return err
-	}
-
-	// Check if the request was successful
-	if resp.StatusCode != http.StatusOK {
-		o.log.Errorf("user service returned non-OK status: %d", resp.StatusCode)
-		return fmt.Errorf("user service returned non-OK status: %d", resp.StatusCode)
-	}
-
-	// Decode the response into cart items
-	var customer model.GetUserResponse
-	err = json.NewDecoder(resp.Body).Decode(&customer)
-	if err != nil {
-		o.log.Errorf("Failed to decode response: %v", err)
-		return err
-	}
-
-	orderDetailsRequest := model.GetOrderDetailsRequest{
-		OrderID: &order.OrderID,
-	}
-
-	orderDetailsData, err := json.Marshal(orderDetailsRequest)
-	if err != nil {
-		o.log.Errorf("Failed to marshal order data: %v", err)
-		return err
-	}
-
-	url = constant.ORDER_DETAILS_SERVICE
-	req, err = http.NewRequest("GET", url, bytes.NewBuffer(orderDetailsData))
-	if err != nil {
-		o.log.Errorf("Failed to create request: %v", err)
-		return err
-	}
-
-	// Set the context and execute the request
-
-	resp, err = client.Do(req)
-	if err != nil {
-		o.log.Errorf("Failed to execute request: %v", err)
-		return err
-	}
-
-	// Check if the request was successful
-	if resp.StatusCode != http.StatusOK {
-		o.log.Errorf("order detail service returned non-OK status: %d", resp.StatusCode)
-		return fmt.Errorf("order detail service returned non-OK status: %d", resp.StatusCode)
-	}
-
-	// Decode the response into cart items
-	var orderDetails []model.GetOrderDetailResponse
-	err = json.NewDecoder(resp.Body).Decode(&orderDetails)
+	orderDetails, err := o.clients.OrderDetail.List(ctx, clients.OrderDetailFilter{OrderID: &order.OrderID})
 	if err != nil {
-		o.log.Errorf("Failed to decode response: %v", err)
+		o.log.WithContext(ctx).Errorf("Failed to get order details: %v", err)
 		return err
 	}
 
@@ -364,9 +273,9 @@ func (o *mailUsecase) SendNotification(ctx context.Context, orderID int64, urlPa
 		})
 	}
 
-	err = o.SendOrderDetails(user, orderModel, orderDetailsModel, urlPayment)
+	err = o.SendOrderDetails(ctx, user, orderModel, orderDetailsModel, urlPayment)
 	if err != nil {
-		o.log.Errorf("Failed to send email: %v", err)
+		o.log.WithContext(ctx).Errorf("Failed to send email: %v", err)
 		return err
 	}
 
@@ -0,0 +1,21 @@
+package config
+
+import pkgconfig "th3y3m/e-commerce-microservices/pkg/config"
+
+// Config of the MoMo service
+type Config struct {
+	pkgconfig.Base
+	MoMo MoMo
+}
+
+// MoMo is the merchant account payments are created with
+type MoMo struct {
+	Endpoint    string `env:"MOMO_ENDPOINT" required:"true"`
+	SecretKey   string `env:"MOMO_SECRET_KEY" required:"true" secret:"true"`
+	AccessKey   string `env:"MOMO_ACCESS_KEY" required:"true" secret:"true"`
+	ReturnURL   string `env:"MOMO_RETURN_URL" required:"true"`
+	NotifyURL   string `env:"MOMO_NOTIFY_URL" required:"true"`
+	PartnerCode string `env:"MOMO_PARTNER_CODE" required:"true"`
+	RequestType string `env:"MOMO_REQUEST_TYPE" required:"true"`
+	ExtraData   string `env:"MOMO_EXTRA_DATA"`
+}
@@ -8,8 +8,8 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-func CreateMoMoUrl(c *gin.Context) {
-	module := dependency_injection.NewMoMoUsecaseProvider()
+func (h *handler) CreateMoMoUrl(c *gin.Context) {
+	module := dependency_injection.NewMoMoUsecaseProvider(h.cfg, h.services)
 
 	amountStr := c.Query("amount")
 	amount, err := strconv.ParseFloat(amountStr, 64)
@@ -32,11 +32,11 @@ func CreateMoMoUrl(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"payment_url": paymentUrl})
 }
 
-func ValidateMoMoResponse(c *gin.Context) {
-	module := dependency_injection.NewMoMoUsecaseProvider()
+func (h *handler) ValidateMoMoResponse(c *gin.Context) {
+	module := dependency_injection.NewMoMoUsecaseProvider(h.cfg, h.services)
 
 	queryParams := c.Request.URL.Query()
-	res, err := module.ValidateMoMoResponse(queryParams)
+	res, err := module.ValidateMoMoResponse(c, queryParams)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -1,8 +1,9 @@
-// Code generated by mockery v2.46.3. DO NOT EDIT.
+// Code generated by mockery v2.53.5. DO NOT EDIT.
 
 package mocks
 
 import (
+	context "context"
 	url "net/url"
 	model "th3y3m/e-commerce-microservices/service/momo/model"
 
@@ -42,9 +43,9 @@ func (_m *IMoMoUsecase) CreateMoMoUrl(amount float64, orderId string) (string, e
 	return r0, r1
 }
 
-// ValidateMoMoResponse provides a mock function with given fields: queryString
-func (_m *IMoMoUsecase) ValidateMoMoResponse(queryString url.Values) (*model.PaymentResponse, error) {
-	ret := _m.Called(queryString)
+// ValidateMoMoResponse provides a mock function with given fields: ctx, queryString
+func (_m *IMoMoUsecase) ValidateMoMoResponse(ctx context.Context, queryString url.Values) (*model.PaymentResponse, error) {
+	ret := _m.Called(ctx, queryString)
 
 	if len(ret) == 0 {
 		panic("no return value specified for ValidateMoMoResponse")
@@ -52,19 +53,19 @@ func (_m *IMoMoUsecase) ValidateMoMoResponse(queryString url.Values) (*model.Pay
 
 	var r0 *model.PaymentResponse
 	var r1 error
-	if rf, ok := ret.Get(0).(func(url.Values) (*model.PaymentResponse, error)); ok {
-		return rf(queryString)
+	if rf, ok := ret.Get(0).(func(context.Context, url.Values) (*model.PaymentResponse, error)); ok {
+		return rf(ctx, queryString)
 	}
-	if rf, ok := ret.Get(0).(func(url.Values) *model.PaymentResponse); ok {
-		r0 = rf(queryString)
+	if rf, ok := ret.Get(0).(func(context.Context, url.Values) *model.PaymentResponse); ok {
+		r0 = rf(ctx, queryString)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*model.PaymentResponse)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(url.Values) error); ok {
-		r1 = rf(queryString)
+	if rf, ok := ret.Get(1).(func(context.Context, url.Values) error); ok {
+		r1 = rf(ctx, queryString)
 	} else {
 		r1 = ret.Error(1)
 	}
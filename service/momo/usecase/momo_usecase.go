@@ -1,40 +1,49 @@
 package usecase
 
 import (
-	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
-	"net/http"
 	"net/url"
 	"strconv"
 	"strings"
+	"th3y3m/e-commerce-microservices/pkg/authz"
+	"th3y3m/e-commerce-microservices/pkg/clients"
 	"th3y3m/e-commerce-microservices/pkg/constant"
+	"th3y3m/e-commerce-microservices/pkg/metrics"
 	"th3y3m/e-commerce-microservices/pkg/util"
+	"th3y3m/e-commerce-microservices/service/momo/config"
 	"th3y3m/e-commerce-microservices/service/momo/model"
 
 	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
-	"github.com/spf13/viper"
 )
 
+// Clients of the services a payment is recorded in
+type Clients struct {
+	Order   *clients.OrderClient
+	Payment *clients.PaymentClient
+}
+
 // IMoMoUsecase is the interface that defines the MoMo usecase methods.
 type IMoMoUsecase interface {
 	CreateMoMoUrl(amount float64, orderId string) (string, error)
-	ValidateMoMoResponse(queryString url.Values) (*model.PaymentResponse, error)
+	ValidateMoMoResponse(ctx context.Context, queryString url.Values) (*model.PaymentResponse, error)
 }
 
-func NewMoMoUsecase(log *logrus.Logger) IMoMoUsecase {
+func NewMoMoUsecase(log *logrus.Logger, momo config.MoMo, clients Clients) IMoMoUsecase {
 
 	return &MoMoService{
-		endpoint:    viper.GetString("MOMO_ENDPOINT"),
-		secretKey:   viper.GetString("MOMO_SECRET_KEY"),
-		accessKey:   viper.GetString("MOMO_ACCESS_KEY"),
-		returnUrl:   viper.GetString("MOMO_RETURN_URL"),
-		notifyUrl:   viper.GetString("MOMO_NOTIFY_URL"),
-		partnerCode: viper.GetString("MOMO_PARTNER_CODE"),
-		requestType: viper.GetString("MOMO_REQUEST_TYPE"),
-		extraData:   viper.GetString("MOMO_EXTRA_DATA"),
+		endpoint:    momo.Endpoint,
+		secretKey:   momo.SecretKey,
+		accessKey:   momo.AccessKey,
+		returnUrl:   momo.ReturnURL,
+		notifyUrl:   momo.NotifyURL,
+		partnerCode: momo.PartnerCode,
+		requestType: momo.RequestType,
+		extraData:   momo.ExtraData,
+		clients:     clients,
 		log:         log,
 	}
 }
@@ -48,6 +57,7 @@ type MoMoService struct {
 	partnerCode string
 	requestType string
 	extraData   string
+	clients     Clients
 	log         *logrus.Logger
 }
 
@@ -103,7 +113,10 @@ func (s *MoMoService) CreateMoMoUrl(amount float64, orderId string) (string, err
 	return "", errors.New("unexpected response from MoMo API")
 }
 
-func (s *MoMoService) ValidateMoMoResponse(queryString url.Values) (*model.PaymentResponse, error) {
+func (s *MoMoService) ValidateMoMoResponse(ctx context.Context, queryString url.Values) (*model.PaymentResponse, error) {
+	// MoMo calls back without a user, the order and payment are updated by the service itself
+	ctx = authz.AsSystem(ctx)
+
 	combinedOrderId := queryString.Get("orderId")
 	resultCode := queryString.Get("resultCode")
 	amount := queryString.Get("amount")
@@ -115,20 +128,15 @@ func (s *MoMoService) ValidateMoMoResponse(queryString url.Values) (*model.Payme
 	}
 	orderId := orderIdParts[0]
 
-	// Fetch the order details
-	res, err := http.Get(fmt.Sprintf("%s/%s", constant.ORDER_SERVICE, orderId))
+	id, err := strconv.ParseInt(orderId, 10, 64)
 	if err != nil {
-		return nil, err
-	}
-	defer res.Body.Close()
-
-	if res.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("error: received status code %d from order service", res.StatusCode)
+		return nil, fmt.Errorf("invalid orderId format")
 	}
 
-	var order model.GetOrderResponse
-	if err := json.NewDecoder(res.Body).Decode(&order); err != nil {
-		return nil, fmt.Errorf("error decoding order response: %v", err)
+	// Fetch the order details
+	order, err := s.clients.Order.Get(ctx, id)
+	if err != nil {
+		return nil, err
 	}
 
 	if order.OrderID == 0 || order.OrderStatus == constant.ORDER_STATUS_COMPLETED {
@@ -139,88 +147,30 @@ func (s *MoMoService) ValidateMoMoResponse(queryString url.Values) (*model.Payme
 	}
 
 	if resultCode == "0" {
-		order.OrderStatus = constant.ORDER_STATUS_COMPLETED
-		updateModel := model.UpdateOrderRequest{
-			OrderID:               order.OrderID,
-			CustomerID:            order.CustomerID,
-			OrderDate:             util.ParseTime(order.OrderDate),
-			OrderStatus:           order.OrderStatus,
-			ActualDeliveryDate:    util.ParseTime(order.ActualDeliveryDate),
-			EstimatedDeliveryDate: util.ParseTime(order.EstimatedDeliveryDate),
-			ShippingAddress:       order.ShippingAddress,
-			CourierID:             order.CourierID,
-			FreightPrice:          order.FreightPrice,
-			TotalAmount:           order.TotalAmount,
-			VoucherID:             order.VoucherID,
-			IsDeleted:             order.IsDeleted,
-		}
-
 		// Update the order status
-		url := constant.ORDER_SERVICE
-		orderData, err := json.Marshal(updateModel)
-		if err != nil {
-			s.log.Errorf("Failed to marshal order data: %v", err)
-			return nil, err
-		}
-
-		req, err := http.NewRequest("PUT", url, bytes.NewBuffer(orderData))
-		if err != nil {
-			s.log.Errorf("Failed to create request: %v", err)
-			return nil, err
-		}
-		req.Header.Set("Content-Type", "application/json")
-
-		client := &http.Client{}
-		res, err := client.Do(req)
-		if err != nil {
-			s.log.Errorf("Failed to update order in order service: %v", err)
+		if err := s.clients.Order.UpdateStatus(ctx, order, constant.ORDER_STATUS_COMPLETED); err != nil {
+			s.log.WithContext(ctx).Errorf("Failed to update order in order service: %v", err)
 			return nil, err
 		}
-		defer res.Body.Close()
-
-		if res.StatusCode != http.StatusOK {
-			s.log.Errorf("Error updating order: received status %v", res.StatusCode)
-			return nil, errors.New("error updating order")
-		}
 
 		// Create the payment record
 		paymentAmount, err := strconv.ParseFloat(amount, 64)
 		if err != nil {
 			return nil, fmt.Errorf("invalid payment amount: %v", err)
 		}
-		paymentCreateModel := &model.CreatePaymentRequest{
+		err = s.clients.Payment.Create(ctx, clients.CreatePayment{
 			OrderID:          order.OrderID,
 			PaymentAmount:    paymentAmount,
 			PaymentStatus:    constant.PAYMENT_STATUS_COMPLETED,
 			PaymentSignature: signature,
 			PaymentMethod:    constant.PAYMENT_METHOD_MOMO,
-		}
-
-		url = constant.PAYMENT_SERVICE
-		paymentData, err := json.Marshal(paymentCreateModel)
-		if err != nil {
-			s.log.Errorf("Failed to marshal payment data: %v", err)
-			return nil, err
-		}
-
-		req, err = http.NewRequest("POST", url, bytes.NewBuffer(paymentData))
-		if err != nil {
-			s.log.Errorf("Failed to create request: %v", err)
-			return nil, err
-		}
-		req.Header.Set("Content-Type", "application/json")
-
-		res, err = client.Do(req)
+			TransactionID:    queryString.Get("transId"),
+		})
 		if err != nil {
-			s.log.Errorf("Failed to create payment in payment service: %v", err)
+			s.log.WithContext(ctx).Errorf("Failed to create payment in payment service: %v", err)
 			return nil, err
 		}
-		defer res.Body.Close()
-
-		if res.StatusCode != http.StatusOK {
-			s.log.Errorf("Error creating payment: received status %v", res.StatusCode)
-			return nil, errors.New("error creating payment")
-		}
+		metrics.PaymentCompleted(constant.PAYMENT_METHOD_MOMO)
 
 		return &model.PaymentResponse{
 			IsSuccessful: true,
@@ -229,48 +179,10 @@ func (s *MoMoService) ValidateMoMoResponse(queryString url.Values) (*model.Payme
 	}
 
 	// Handle payment failure
-	order.OrderStatus = constant.ORDER_STATUS_FAILED
-	updateModel := model.UpdateOrderRequest{
-		OrderID:               order.OrderID,
-		CustomerID:            order.CustomerID,
-		OrderDate:             util.ParseTime(order.OrderDate),
-		OrderStatus:           order.OrderStatus,
-		ActualDeliveryDate:    util.ParseTime(order.ActualDeliveryDate),
-		EstimatedDeliveryDate: util.ParseTime(order.EstimatedDeliveryDate),
-		ShippingAddress:       order.ShippingAddress,
-		CourierID:             order.CourierID,
-		FreightPrice:          order.FreightPrice,
-		TotalAmount:           order.TotalAmount,
-		VoucherID:             order.VoucherID,
-		IsDeleted:             order.IsDeleted,
-	}
-
-	url := constant.ORDER_SERVICE
-	orderData, err := json.Marshal(updateModel)
-	if err != nil {
-		s.log.Errorf("Failed to marshal order data: %v", err)
-		return nil, err
-	}
-
-	req, err := http.NewRequest("PUT", url, bytes.NewBuffer(orderData))
-	if err != nil {
-		s.log.Errorf("Failed to create request: %v", err)
+	if err := s.clients.Order.UpdateStatus(ctx, order, constant.ORDER_STATUS_FAILED); err != nil {
+		s.log.WithContext(ctx).Errorf("Failed to update order in order service: %v", err)
 		return nil, err
 	}
-	req.Header.Set("Content-Type", "application/json")
-
-	client := &http.Client{}
-	res, err = client.Do(req)
-	if err != nil {
-		s.log.Errorf("Failed to update order in order service: %v", err)
-		return nil, err
-	}
-	defer res.Body.Close()
-
-	if res.StatusCode != http.StatusOK {
-		s.log.Errorf("Error updating order: received status %v", res.StatusCode)
-		return nil, errors.New("error updating order")
-	}
 
 	return &model.PaymentResponse{
 		IsSuccessful: false,
@@ -9,13 +9,13 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
-func GetNewsByID(c *gin.Context) {
+func (h *handler) GetNewsByID(c *gin.Context) {
 	newID := c.Param("new_id")
-	module := dependency_injection.NewNewsUsecaseProvider()
+	module := dependency_injection.NewNewsUsecaseProvider(h.cfg)
 
 	id, err := strconv.ParseInt(newID, 10, 64)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(400, gin.H{
 			"error": "Bad Request",
 		})
@@ -27,7 +27,7 @@ func GetNewsByID(c *gin.Context) {
 
 	new, err := module.GetNews(c, &req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(500, gin.H{
 			"error": "Internal Server Error",
 		})
@@ -37,12 +37,12 @@ func GetNewsByID(c *gin.Context) {
 	c.JSON(200, new)
 }
 
-func GetAllNews(c *gin.Context) {
-	module := dependency_injection.NewNewsUsecaseProvider()
+func (h *handler) GetAllNews(c *gin.Context) {
+	module := dependency_injection.NewNewsUsecaseProvider(h.cfg)
 
 	news, err := module.GetAllNews(c)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(500, gin.H{
 			"error": "Internal Server Error",
 		})
@@ -52,22 +52,22 @@ func GetAllNews(c *gin.Context) {
 	c.JSON(200, news)
 }
 
-func CreateNews(c *gin.Context) {
+func (h *handler) CreateNews(c *gin.Context) {
 	var req model.CreateNewsRequest
 	err := c.BindJSON(&req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(400, gin.H{
 			"error": "Bad Request",
 		})
 		return
 	}
 
-	module := dependency_injection.NewNewsUsecaseProvider()
+	module := dependency_injection.NewNewsUsecaseProvider(h.cfg)
 
 	new, err := module.CreateNews(c, &req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(500, gin.H{
 			"error": "Internal Server Error",
 		})
@@ -77,22 +77,22 @@ func CreateNews(c *gin.Context) {
 	c.JSON(200, new)
 }
 
-func UpdateNews(c *gin.Context) {
+func (h *handler) UpdateNews(c *gin.Context) {
 	var req model.UpdateNewsRequest
 	err := c.BindJSON(&req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(400, gin.H{
 			"error": "Bad Request",
 		})
 		return
 	}
 
-	module := dependency_injection.NewNewsUsecaseProvider()
+	module := dependency_injection.NewNewsUsecaseProvider(h.cfg)
 
 	new, err := module.UpdateNews(c, &req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(500, gin.H{
 			"error": "Internal Server Error",
 		})
@@ -102,13 +102,13 @@ func UpdateNews(c *gin.Context) {
 	c.JSON(200, new)
 }
 
-func DeleteNews(c *gin.Context) {
+func (h *handler) DeleteNews(c *gin.Context) {
 	newID := c.Param("new_id")
-	module := dependency_injection.NewNewsUsecaseProvider()
+	module := dependency_injection.NewNewsUsecaseProvider(h.cfg)
 
 	id, err := strconv.ParseInt(newID, 10, 64)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(400, gin.H{
 			"error": "Bad Request",
 		})
@@ -120,7 +120,7 @@ func DeleteNews(c *gin.Context) {
 
 	err = module.DeleteNews(c, &req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(500, gin.H{
 			"error": "Internal Server Error",
 		})
@@ -132,13 +132,13 @@ func DeleteNews(c *gin.Context) {
 	})
 }
 
-func GetPaginatedNews(c *gin.Context) {
-	module := dependency_injection.NewNewsUsecaseProvider()
+func (h *handler) GetPaginatedNews(c *gin.Context) {
+	module := dependency_injection.NewNewsUsecaseProvider(h.cfg)
 
 	var req model.GetNewsRequest
 	err := c.BindJSON(&req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(400, gin.H{
 			"error": "Bad Request",
 		})
@@ -154,7 +154,7 @@ func GetPaginatedNews(c *gin.Context) {
 
 	news, err := module.GetNewsList(c, &req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(500, gin.H{
 			"error": "Internal Server Error",
 		})
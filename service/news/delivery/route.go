@@ -1,19 +1,38 @@
 package delivery
 
 import (
+	"th3y3m/e-commerce-microservices/pkg/authz"
+	"th3y3m/e-commerce-microservices/pkg/logging"
+	"th3y3m/e-commerce-microservices/pkg/metrics"
+	"th3y3m/e-commerce-microservices/pkg/tracing"
+	"th3y3m/e-commerce-microservices/service/news/config"
+
 	"github.com/gin-gonic/gin"
 )
 
-func RegisterHandlers() *gin.Engine {
-	r := gin.Default()
+// handler serves the routes of the service, with the usecases built from cfg
+type handler struct {
+	cfg config.Config
+}
+
+func RegisterHandlers(cfg config.Config) *gin.Engine {
+	h := &handler{cfg: cfg}
+	keys := authz.NewKeys(cfg.Auth)
+
+	r := gin.New()
+	// Lets the request context, and the trace in it, reach the usecases through *gin.Context
+	r.ContextWithFallback = true
+	r.Use(gin.Recovery(), tracing.Middleware("news"), logging.Middleware(), metrics.Middleware())
+	r.GET(metrics.Path, metrics.Handler())
+	r.Use(keys.Middleware())
 
 	new := r.Group("/api/news")
 	{
-		new.GET("/:new_id", GetNewsByID)
-		new.GET("", GetPaginatedNews)
-		new.POST("", CreateNews)
-		new.PUT("/:new_id", UpdateNews)
-		new.DELETE("/:new_id", DeleteNews)
+		new.GET("/:new_id", h.GetNewsByID)
+		new.GET("", h.GetPaginatedNews)
+		new.POST("", h.CreateNews)
+		new.PUT("/:new_id", h.UpdateNews)
+		new.DELETE("/:new_id", h.DeleteNews)
 	}
 
 	return r
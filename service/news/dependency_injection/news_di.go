@@ -1,21 +1,21 @@
 package dependency_injection
 
 import (
+	"th3y3m/e-commerce-microservices/pkg/logging"
 	"th3y3m/e-commerce-microservices/pkg/postgresql"
 	redis_client "th3y3m/e-commerce-microservices/pkg/redis"
+	"th3y3m/e-commerce-microservices/service/news/config"
 	"th3y3m/e-commerce-microservices/service/news/repository"
 	"th3y3m/e-commerce-microservices/service/news/usecase"
-
-	"github.com/sirupsen/logrus"
 )
 
-func NewNewsRepositoryProvider() repository.INewRepository {
-	log := logrus.New()
-	db, err := postgresql.NewGormDB()
+func NewNewsRepositoryProvider(cfg config.Config) repository.INewRepository {
+	log := logging.Logger()
+	db, err := postgresql.NewGormDB(cfg.Postgres)
 	if err != nil {
 		log.Error(err)
 	}
-	redis, err := redis_client.ConnectToRedis()
+	redis, err := redis_client.ConnectToRedis(cfg.Redis)
 	if err != nil {
 		log.Error(err)
 	}
@@ -23,8 +23,8 @@ func NewNewsRepositoryProvider() repository.INewRepository {
 	return repository.NewNewsRepository(db, redis, log)
 }
 
-func NewNewsUsecaseProvider() usecase.INewUsecase {
-	log := logrus.New()
-	newRepository := NewNewsRepositoryProvider()
+func NewNewsUsecaseProvider(cfg config.Config) usecase.INewUsecase {
+	log := logging.Logger()
+	newRepository := NewNewsRepositoryProvider(cfg)
 	return usecase.NewNewsUsecase(newRepository, log)
 }
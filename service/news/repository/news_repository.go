@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"th3y3m/e-commerce-microservices/pkg/metrics"
 	"th3y3m/e-commerce-microservices/service/news/model"
 
 	"github.com/redis/go-redis/v9"
@@ -36,7 +37,7 @@ func NewNewsRepository(db *gorm.DB, redis *redis.Client, log *logrus.Logger) INe
 }
 
 func (pr *newRepository) Get(ctx context.Context, newID int64) (*News, error) {
-	pr.log.Infof("Fetching new with ID: %d", newID)
+	pr.log.WithContext(ctx).Infof("Fetching new with ID: %d", newID)
 	var new News
 	cacheKey := fmt.Sprintf("new:%d", newID)
 
@@ -45,19 +46,21 @@ func (pr *newRepository) Get(ctx context.Context, newID int64) (*News, error) {
 		cachedNew, err := pr.redis.Get(ctx, cacheKey).Result()
 		if err == nil {
 			if err := json.Unmarshal([]byte(cachedNew), &new); err == nil {
-				pr.log.Infof("New found in cache: %d", newID)
+				pr.log.WithContext(ctx).Infof("New found in cache: %d", newID)
+				metrics.CacheHit("newRepository.Get")
 				return &new, nil
 			}
 		} else if err != redis.Nil {
-			pr.log.Warnf("Failed to get new from Redis: %v", err)
+			pr.log.WithContext(ctx).Warnf("Failed to get new from Redis: %v", err)
 		}
+		metrics.CacheMiss("newRepository.Get")
 	} else {
-		pr.log.Warn("Redis client is not initialized")
+		pr.log.WithContext(ctx).Warn("Redis client is not initialized")
 	}
 
 	// If not found in cache, get from database
 	if err := pr.db.WithContext(ctx).First(&new, newID).Error; err != nil {
-		pr.log.Errorf("Error fetching new from database: %v", err)
+		pr.log.WithContext(ctx).Errorf("Error fetching new from database: %v", err)
 		return nil, err
 	}
 
@@ -65,9 +68,9 @@ func (pr *newRepository) Get(ctx context.Context, newID int64) (*News, error) {
 	if pr.redis != nil {
 		newJSON, _ := json.Marshal(new)
 		if err := pr.redis.Set(ctx, cacheKey, newJSON, 0).Err(); err != nil {
-			pr.log.Warnf("Failed to save new to Redis: %v", err)
+			pr.log.WithContext(ctx).Warnf("Failed to save new to Redis: %v", err)
 		} else {
-			pr.log.Infof("New saved to cache: %d", newID)
+			pr.log.WithContext(ctx).Infof("New saved to cache: %d", newID)
 		}
 	}
 
@@ -75,7 +78,7 @@ func (pr *newRepository) Get(ctx context.Context, newID int64) (*News, error) {
 }
 
 func (pr *newRepository) GetAll(ctx context.Context) ([]*News, error) {
-	pr.log.Info("Fetching all news")
+	pr.log.WithContext(ctx).Info("Fetching all news")
 	var news []*News
 	cacheKey := "all_news"
 
@@ -84,19 +87,21 @@ func (pr *newRepository) GetAll(ctx context.Context) ([]*News, error) {
 		cachedNews, err := pr.redis.Get(ctx, cacheKey).Result()
 		if err == nil {
 			if err := json.Unmarshal([]byte(cachedNews), &news); err == nil {
-				pr.log.Info("News found in cache")
+				pr.log.WithContext(ctx).Info("News found in cache")
+				metrics.CacheHit("newRepository.GetAll")
 				return news, nil
 			}
 		} else if err != redis.Nil {
-			pr.log.Warnf("Failed to get news from Redis: %v", err)
+			pr.log.WithContext(ctx).Warnf("Failed to get news from Redis: %v", err)
 		}
+		metrics.CacheMiss("newRepository.GetAll")
 	} else {
-		pr.log.Warn("Redis client is not initialized")
+		pr.log.WithContext(ctx).Warn("Redis client is not initialized")
 	}
 
 	// If not found in cache, get from database
 	if err := pr.db.WithContext(ctx).Find(&news).Error; err != nil {
-		pr.log.Errorf("Error fetching news from database: %v", err)
+		pr.log.WithContext(ctx).Errorf("Error fetching news from database: %v", err)
 		return nil, err
 	}
 
@@ -104,9 +109,9 @@ func (pr *newRepository) GetAll(ctx context.Context) ([]*News, error) {
 	if pr.redis != nil {
 		newsJSON, _ := json.Marshal(news)
 		if err := pr.redis.Set(ctx, cacheKey, newsJSON, 0).Err(); err != nil {
-			pr.log.Warnf("Failed to save news to Redis: %v", err)
+			pr.log.WithContext(ctx).Warnf("Failed to save news to Redis: %v", err)
 		} else {
-			pr.log.Info("News saved to cache")
+			pr.log.WithContext(ctx).Info("News saved to cache")
 		}
 	}
 
@@ -114,9 +119,9 @@ func (pr *newRepository) GetAll(ctx context.Context) ([]*News, error) {
 }
 
 func (pr *newRepository) Create(ctx context.Context, new *News) (*News, error) {
-	pr.log.Infof("Creating new: %+v", new)
+	pr.log.WithContext(ctx).Infof("Creating new: %+v", new)
 	if err := pr.db.WithContext(ctx).Create(new).Error; err != nil {
-		pr.log.Errorf("Error creating new: %v", err)
+		pr.log.WithContext(ctx).Errorf("Error creating new: %v", err)
 		return nil, err
 	}
 	cacheKey := fmt.Sprintf("new:%d", new.NewsID)
@@ -125,16 +130,16 @@ func (pr *newRepository) Create(ctx context.Context, new *News) (*News, error) {
 	if pr.redis != nil {
 		newJSON, _ := json.Marshal(new)
 		if err := pr.redis.Set(ctx, cacheKey, newJSON, 0).Err(); err != nil {
-			pr.log.Warnf("Failed to save new to Redis: %v", err)
+			pr.log.WithContext(ctx).Warnf("Failed to save new to Redis: %v", err)
 		} else {
-			pr.log.Infof("New saved to cache: %d", new.NewsID)
+			pr.log.WithContext(ctx).Infof("New saved to cache: %d", new.NewsID)
 		}
 
 		// Invalidate the cache for all records
 		if err := pr.redis.Del(ctx, "all_news").Err(); err != nil {
-			pr.log.Warnf("Failed to invalidate all news cache: %v", err)
+			pr.log.WithContext(ctx).Warnf("Failed to invalidate all news cache: %v", err)
 		} else {
-			pr.log.Info("Invalidated cache for all news")
+			pr.log.WithContext(ctx).Info("Invalidated cache for all news")
 		}
 	}
 
@@ -143,9 +148,9 @@ func (pr *newRepository) Create(ctx context.Context, new *News) (*News, error) {
 }
 
 func (pr *newRepository) Update(ctx context.Context, new *News) (*News, error) {
-	pr.log.Infof("Updating new: %+v", new)
+	pr.log.WithContext(ctx).Infof("Updating new: %+v", new)
 	if err := pr.db.WithContext(ctx).Save(new).Error; err != nil {
-		pr.log.Errorf("Error updating new: %v", err)
+		pr.log.WithContext(ctx).Errorf("Error updating new: %v", err)
 		return nil, err
 	}
 	cacheKey := fmt.Sprintf("new:%d", new.NewsID)
@@ -154,16 +159,16 @@ func (pr *newRepository) Update(ctx context.Context, new *News) (*News, error) {
 	if pr.redis != nil {
 		newJSON, _ := json.Marshal(new)
 		if err := pr.redis.Set(ctx, cacheKey, newJSON, 0).Err(); err != nil {
-			pr.log.Warnf("Failed to save new to Redis: %v", err)
+			pr.log.WithContext(ctx).Warnf("Failed to save new to Redis: %v", err)
 		} else {
-			pr.log.Infof("New saved to cache: %d", new.NewsID)
+			pr.log.WithContext(ctx).Infof("New saved to cache: %d", new.NewsID)
 		}
 
 		// Invalidate the cache for all records
 		if err := pr.redis.Del(ctx, "all_news").Err(); err != nil {
-			pr.log.Warnf("Failed to invalidate all news cache: %v", err)
+			pr.log.WithContext(ctx).Warnf("Failed to invalidate all news cache: %v", err)
 		} else {
-			pr.log.Info("Invalidated cache for all news")
+			pr.log.WithContext(ctx).Info("Invalidated cache for all news")
 		}
 	}
 
@@ -172,9 +177,9 @@ func (pr *newRepository) Update(ctx context.Context, new *News) (*News, error) {
 }
 
 func (pr *newRepository) Delete(ctx context.Context, newID int64) error {
-	pr.log.Infof("Deleting new with ID: %d", newID)
+	pr.log.WithContext(ctx).Infof("Deleting new with ID: %d", newID)
 	if err := pr.db.WithContext(ctx).Delete(&News{}, newID).Error; err != nil {
-		pr.log.Errorf("Error deleting new: %v", err)
+		pr.log.WithContext(ctx).Errorf("Error deleting new: %v", err)
 		return err
 	}
 
@@ -183,16 +188,16 @@ func (pr *newRepository) Delete(ctx context.Context, newID int64) error {
 	// Delete from cache if Redis is available
 	if pr.redis != nil {
 		if err := pr.redis.Del(ctx, cacheKey).Err(); err != nil {
-			pr.log.Warnf("Failed to delete new from Redis: %v", err)
+			pr.log.WithContext(ctx).Warnf("Failed to delete new from Redis: %v", err)
 		} else {
-			pr.log.Infof("New deleted from cache: %d", newID)
+			pr.log.WithContext(ctx).Infof("New deleted from cache: %d", newID)
 		}
 
 		// Invalidate the cache for all records
 		if err := pr.redis.Del(ctx, "all_news").Err(); err != nil {
-			pr.log.Warnf("Failed to invalidate all news cache: %v", err)
+			pr.log.WithContext(ctx).Warnf("Failed to invalidate all news cache: %v", err)
 		} else {
-			pr.log.Info("Invalidated cache for all news")
+			pr.log.WithContext(ctx).Info("Invalidated cache for all news")
 		}
 	}
 
@@ -218,7 +223,7 @@ func (pr *newRepository) getQuerySearch(db *gorm.DB, req *model.GetNewsRequest)
 }
 
 func (pr *newRepository) GetList(ctx context.Context, req *model.GetNewsRequest) ([]*News, error) {
-	pr.log.Infof("Fetching new list with request: %+v", req)
+	pr.log.WithContext(ctx).Infof("Fetching new list with request: %+v", req)
 	var news []*News
 
 	db := pr.db.WithContext(ctx)
@@ -243,10 +248,10 @@ func (pr *newRepository) GetList(ctx context.Context, req *model.GetNewsRequest)
 
 	result := db.Table("news").Offset(int(req.Paging.PageIndex-1) * int(req.Paging.PageSize)).Limit(int(req.Paging.PageSize)).Find(&news)
 	if result.Error != nil {
-		pr.log.Errorf("Error fetching new list: %v", result.Error)
+		pr.log.WithContext(ctx).Errorf("Error fetching new list: %v", result.Error)
 		return nil, result.Error
 	}
 
-	pr.log.Infof("Fetched %d news", len(news))
+	pr.log.WithContext(ctx).Infof("Fetched %d news", len(news))
 	return news, nil
 }
@@ -34,24 +34,24 @@ func NewNewsUsecase(newRepo repository.INewRepository, log *logrus.Logger) INewU
 }
 
 func (pu *newUsecase) GetNews(ctx context.Context, req *model.GetNewRequest) (*model.GetNewsResponse, error) {
-	pu.log.Infof("Fetching new with ID: %d", req.NewsID)
+	pu.log.WithContext(ctx).Infof("Fetching new with ID: %d", req.NewsID)
 	new, err := pu.newRepo.Get(ctx, req.NewsID)
 	if err != nil {
-		pu.log.Errorf("Error fetching new: %v", err)
+		pu.log.WithContext(ctx).Errorf("Error fetching new: %v", err)
 		return nil, err
 	}
 
-	pu.log.Infof("Fetched new: %+v", new)
+	pu.log.WithContext(ctx).Infof("Fetched new: %+v", new)
 	return &model.GetNewsResponse{
 		NewsID: new.NewsID,
 	}, nil
 }
 
 func (pu *newUsecase) GetAllNews(ctx context.Context) ([]*model.GetNewsResponse, error) {
-	pu.log.Info("Fetching all news")
+	pu.log.WithContext(ctx).Info("Fetching all news")
 	news, err := pu.newRepo.GetAll(ctx)
 	if err != nil {
-		pu.log.Errorf("Error fetching all news: %v", err)
+		pu.log.WithContext(ctx).Errorf("Error fetching all news: %v", err)
 		return nil, err
 	}
 
@@ -71,12 +71,12 @@ func (pu *newUsecase) GetAllNews(ctx context.Context) ([]*model.GetNewsResponse,
 		})
 	}
 
-	pu.log.Infof("Fetched %d news", len(newResponses))
+	pu.log.WithContext(ctx).Infof("Fetched %d news", len(newResponses))
 	return newResponses, nil
 }
 
 func (pu *newUsecase) CreateNews(ctx context.Context, new *model.CreateNewsRequest) (*model.GetNewsResponse, error) {
-	pu.log.Infof("Creating new: %+v", new)
+	pu.log.WithContext(ctx).Infof("Creating new: %+v", new)
 	newData := repository.News{
 		Title:         new.Title,
 		Content:       new.Content,
@@ -89,11 +89,11 @@ func (pu *newUsecase) CreateNews(ctx context.Context, new *model.CreateNewsReque
 
 	createdNew, err := pu.newRepo.Create(ctx, &newData)
 	if err != nil {
-		pu.log.Errorf("Error creating new: %v", err)
+		pu.log.WithContext(ctx).Errorf("Error creating new: %v", err)
 		return nil, err
 	}
 
-	pu.log.Infof("Created new: %+v", createdNew)
+	pu.log.WithContext(ctx).Infof("Created new: %+v", createdNew)
 	return &model.GetNewsResponse{
 		NewsID:        createdNew.NewsID,
 		Title:         createdNew.Title,
@@ -109,10 +109,10 @@ func (pu *newUsecase) CreateNews(ctx context.Context, new *model.CreateNewsReque
 }
 
 func (pu *newUsecase) DeleteNews(ctx context.Context, req *model.DeleteNewsRequest) error {
-	pu.log.Infof("Deleting new with ID: %d", req.NewsID)
+	pu.log.WithContext(ctx).Infof("Deleting new with ID: %d", req.NewsID)
 	new, err := pu.newRepo.Get(ctx, req.NewsID)
 	if err != nil {
-		pu.log.Errorf("Error fetching new for deletion: %v", err)
+		pu.log.WithContext(ctx).Errorf("Error fetching new for deletion: %v", err)
 		return err
 	}
 
@@ -120,19 +120,19 @@ func (pu *newUsecase) DeleteNews(ctx context.Context, req *model.DeleteNewsReque
 
 	_, err = pu.newRepo.Update(ctx, new)
 	if err != nil {
-		pu.log.Errorf("Error updating new for deletion: %v", err)
+		pu.log.WithContext(ctx).Errorf("Error updating new for deletion: %v", err)
 		return err
 	}
 
-	pu.log.Infof("Deleted new with ID: %d", req.NewsID)
+	pu.log.WithContext(ctx).Infof("Deleted new with ID: %d", req.NewsID)
 	return nil
 }
 
 func (pu *newUsecase) UpdateNews(ctx context.Context, rep *model.UpdateNewsRequest) (*model.GetNewsResponse, error) {
-	pu.log.Infof("Updating new with ID: %d", rep.NewsID)
+	pu.log.WithContext(ctx).Infof("Updating new with ID: %d", rep.NewsID)
 	new, err := pu.newRepo.Get(ctx, rep.NewsID)
 	if err != nil {
-		pu.log.Errorf("Error fetching new for update: %v", err)
+		pu.log.WithContext(ctx).Errorf("Error fetching new for update: %v", err)
 		return nil, err
 	}
 
@@ -146,11 +146,11 @@ func (pu *newUsecase) UpdateNews(ctx context.Context, rep *model.UpdateNewsReque
 
 	updatedNew, err := pu.newRepo.Update(ctx, new)
 	if err != nil {
-		pu.log.Errorf("Error updating new: %v", err)
+		pu.log.WithContext(ctx).Errorf("Error updating new: %v", err)
 		return nil, err
 	}
 
-	pu.log.Infof("Updated new: %+v", updatedNew)
+	pu.log.WithContext(ctx).Infof("Updated new: %+v", updatedNew)
 	return &model.GetNewsResponse{
 		NewsID:        updatedNew.NewsID,
 		Title:         updatedNew.Title,
@@ -166,10 +166,10 @@ func (pu *newUsecase) UpdateNews(ctx context.Context, rep *model.UpdateNewsReque
 }
 
 func (pu *newUsecase) GetNewsList(ctx context.Context, req *model.GetNewsRequest) (*util.PaginatedList[model.GetNewsResponse], error) {
-	pu.log.Infof("Fetching new list with request: %+v", req)
+	pu.log.WithContext(ctx).Infof("Fetching new list with request: %+v", req)
 	news, err := pu.newRepo.GetList(ctx, req)
 	if err != nil {
-		pu.log.Errorf("Error fetching new list: %v", err)
+		pu.log.WithContext(ctx).Errorf("Error fetching new list: %v", err)
 		return nil, err
 	}
 
@@ -199,6 +199,6 @@ func (pu *newUsecase) GetNewsList(ctx context.Context, req *model.GetNewsRequest
 
 	list.GetTotalPages()
 
-	pu.log.Infof("Fetched %d news", len(newResponses))
+	pu.log.WithContext(ctx).Infof("Fetched %d news", len(newResponses))
 	return list, nil
 }
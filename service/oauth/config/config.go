@@ -0,0 +1,20 @@
+package config
+
+import pkgconfig "th3y3m/e-commerce-microservices/pkg/config"
+
+// Config of the OAuth service
+type Config struct {
+	pkgconfig.Base
+	pkgconfig.JWT
+	OAuth OAuth
+}
+
+// OAuth holds the apps registered with the identity providers
+type OAuth struct {
+	GoogleClientID       string `env:"GOOGLE_CLIENT_ID" required:"true"`
+	GoogleClientSecret   string `env:"GOOGLE_CLIENT_SECRET" required:"true" secret:"true"`
+	FacebookClientID     string `env:"FACEBOOK_CLIENT_ID" required:"true"`
+	FacebookClientSecret string `env:"FACEBOOK_CLIENT_SECRET" required:"true" secret:"true"`
+	// Signs the session cookies kept during the login
+	SessionSecret string `env:"SESSION_SECRET" required:"true" secret:"true"`
+}
@@ -4,6 +4,7 @@ import (
 	"log"
 	"net/http"
 	"os"
+	"th3y3m/e-commerce-microservices/service/oauth/config"
 	"th3y3m/e-commerce-microservices/service/oauth/dependency_injection"
 
 	"github.com/gin-contrib/sessions/cookie"
@@ -12,7 +13,6 @@ import (
 	"github.com/markbates/goth/gothic"
 	"github.com/markbates/goth/providers/facebook"
 	"github.com/markbates/goth/providers/google"
-	"github.com/spf13/viper"
 )
 
 // JWTResponse represents the response containing a JWT token.
@@ -21,27 +21,11 @@ type JWTResponse struct {
 }
 
 // InitializeOAuth initializes the OAuth providers and sets up the session store.
-func InitializeOAuth() {
-	// Set up environment variables
-	clientID := viper.GetString("GOOGLE_CLIENT_ID")
-	clientSecret := viper.GetString("GOOGLE_CLIENT_SECRET")
-	facebookID, facebookSecret := viper.GetString("FACEBOOK_CLIENT_ID"), viper.GetString("FACEBOOK_CLIENT_SECRET")
-
-	// Check if client ID and secret are set
-	if clientID == "" || clientSecret == "" {
-		log.Fatal("GOOGLE_CLIENT_ID or GOOGLE_CLIENT_SECRET environment variables are not set")
-	}
-
-	if facebookID == "" || facebookSecret == "" {
-		log.Fatal("FACEBOOK_CLIENT_ID or FACEBOOK_CLIENT_SECRET environment variables are not set")
-	}
-
-	sessionSecret := viper.GetString("SESSION_SECRET")
-
-	// Check if session secret is set
-	if sessionSecret == "" {
-		log.Fatal("SESSION_SECRET environment variable is not set")
-	}
+// The settings are validated when the config is loaded.
+func InitializeOAuth(cfg config.OAuth) {
+	clientID, clientSecret := cfg.GoogleClientID, cfg.GoogleClientSecret
+	facebookID, facebookSecret := cfg.FacebookClientID, cfg.FacebookClientSecret
+	sessionSecret := cfg.SessionSecret
 
 	// Set SESSION_SECRET for Gothic
 	os.Setenv("SESSION_SECRET", sessionSecret)
@@ -58,12 +42,12 @@ func InitializeOAuth() {
 	gothic.Store = store
 }
 
-func GoogleLogin(c *gin.Context) {
+func (h *handler) GoogleLogin(c *gin.Context) {
 	c.Request.URL.RawQuery = "provider=google"
 	gothic.BeginAuthHandler(c.Writer, c.Request)
 }
 
-func GoogleCallback(c *gin.Context) {
+func (h *handler) GoogleCallback(c *gin.Context) {
 	// Complete the user authentication with Gothic
 	user, err := gothic.CompleteUserAuth(c.Writer, c.Request)
 	if err != nil {
@@ -71,10 +55,10 @@ func GoogleCallback(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to authenticate with Google"})
 		return
 	}
-	service := dependency_injection.NewOAuthUsecaseProvider()
+	service := dependency_injection.NewOAuthUsecaseProvider(h.cfg, h.services)
 
 	// Handle Google user and generate JWT token
-	token, err := service.HandleOAuthUserGoogle(user)
+	token, err := service.HandleOAuthUserGoogle(c, user)
 	if err != nil {
 		log.Printf("Error handling Google user: %v", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to handle Google user"})
@@ -85,7 +69,7 @@ func GoogleCallback(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"token": token})
 }
 
-func GoogleLogout(c *gin.Context) {
+func (h *handler) GoogleLogout(c *gin.Context) {
 	if err := gothic.Logout(c.Writer, c.Request); err != nil {
 		log.Printf("Error logging out: %v", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to logout"})
@@ -94,12 +78,12 @@ func GoogleLogout(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "Logged out successfully"})
 }
 
-func FacebookLogin(c *gin.Context) {
+func (h *handler) FacebookLogin(c *gin.Context) {
 	c.Request.URL.RawQuery = "provider=facebook"
 	gothic.BeginAuthHandler(c.Writer, c.Request)
 }
 
-func FacebookCallback(c *gin.Context) {
+func (h *handler) FacebookCallback(c *gin.Context) {
 	// Complete the user authentication with Gothic
 	user, err := gothic.CompleteUserAuth(c.Writer, c.Request)
 	if err != nil {
@@ -107,10 +91,10 @@ func FacebookCallback(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to authenticate with Facebook"})
 		return
 	}
-	service := dependency_injection.NewOAuthUsecaseProvider()
+	service := dependency_injection.NewOAuthUsecaseProvider(h.cfg, h.services)
 
 	// Handle Facebook user and generate JWT token
-	token, err := service.HandleOAuthUserFacebook(user)
+	token, err := service.HandleOAuthUserFacebook(c, user)
 	if err != nil {
 		log.Printf("Error handling Facebook user: %v", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to handle Facebook user"})
@@ -121,7 +105,7 @@ func FacebookCallback(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"token": token})
 }
 
-func FacebookLogout(c *gin.Context) {
+func (h *handler) FacebookLogout(c *gin.Context) {
 	if err := gothic.Logout(c.Writer, c.Request); err != nil {
 		log.Printf("Error logging out: %v", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to logout"})
@@ -1,12 +1,22 @@
 package dependency_injection
 
 import (
+	"net/http"
+	"th3y3m/e-commerce-microservices/pkg/authz"
+	"th3y3m/e-commerce-microservices/pkg/clients"
+	"th3y3m/e-commerce-microservices/pkg/discovery"
+	"th3y3m/e-commerce-microservices/pkg/logging"
+	"th3y3m/e-commerce-microservices/service/oauth/config"
 	"th3y3m/e-commerce-microservices/service/oauth/usecase"
-
-	"github.com/sirupsen/logrus"
 )
 
-func NewOAuthUsecaseProvider() usecase.IOAuthUsecase {
-	log := logrus.New()
-	return usecase.NewOAuthUsecase(log)
+func NewOAuthUsecaseProvider(cfg config.Config, httpClient *http.Client) usecase.IOAuthUsecase {
+	log := logging.Logger()
+	return usecase.NewOAuthUsecase(log, cfg.JWTSecret, clients.NewUserClient(httpClient, clients.NewOptions(cfg.Clients)))
+}
+
+// NewServiceClientProvider returns the HTTP client the OAuth service calls other
+// services with, signed with its service token and sent to the instances resolver finds
+func NewServiceClientProvider(cfg config.Config, resolver discovery.Resolver) *http.Client {
+	return authz.NewKeys(cfg.Auth).NewServiceClient("oauth", resolver)
 }
@@ -1,8 +1,10 @@
-// Code generated by mockery v2.46.3. DO NOT EDIT.
+// Code generated by mockery v2.53.5. DO NOT EDIT.
 
 package mocks
 
 import (
+	context "context"
+
 	goth "github.com/markbates/goth"
 	mock "github.com/stretchr/testify/mock"
 )
@@ -12,9 +14,9 @@ type IOAuthUsecase struct {
 	mock.Mock
 }
 
-// HandleOAuthUserFacebook provides a mock function with given fields: user
-func (_m *IOAuthUsecase) HandleOAuthUserFacebook(user goth.User) (string, error) {
-	ret := _m.Called(user)
+// HandleOAuthUserFacebook provides a mock function with given fields: ctx, user
+func (_m *IOAuthUsecase) HandleOAuthUserFacebook(ctx context.Context, user goth.User) (string, error) {
+	ret := _m.Called(ctx, user)
 
 	if len(ret) == 0 {
 		panic("no return value specified for HandleOAuthUserFacebook")
@@ -22,17 +24,17 @@ func (_m *IOAuthUsecase) HandleOAuthUserFacebook(user goth.User) (string, error)
 
 	var r0 string
 	var r1 error
-	if rf, ok := ret.Get(0).(func(goth.User) (string, error)); ok {
-		return rf(user)
+	if rf, ok := ret.Get(0).(func(context.Context, goth.User) (string, error)); ok {
+		return rf(ctx, user)
 	}
-	if rf, ok := ret.Get(0).(func(goth.User) string); ok {
-		r0 = rf(user)
+	if rf, ok := ret.Get(0).(func(context.Context, goth.User) string); ok {
+		r0 = rf(ctx, user)
 	} else {
 		r0 = ret.Get(0).(string)
 	}
 
-	if rf, ok := ret.Get(1).(func(goth.User) error); ok {
-		r1 = rf(user)
+	if rf, ok := ret.Get(1).(func(context.Context, goth.User) error); ok {
+		r1 = rf(ctx, user)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -40,9 +42,9 @@ func (_m *IOAuthUsecase) HandleOAuthUserFacebook(user goth.User) (string, error)
 	return r0, r1
 }
 
-// HandleOAuthUserGoogle provides a mock function with given fields: user
-func (_m *IOAuthUsecase) HandleOAuthUserGoogle(user goth.User) (string, error) {
-	ret := _m.Called(user)
+// HandleOAuthUserGoogle provides a mock function with given fields: ctx, user
+func (_m *IOAuthUsecase) HandleOAuthUserGoogle(ctx context.Context, user goth.User) (string, error) {
+	ret := _m.Called(ctx, user)
 
 	if len(ret) == 0 {
 		panic("no return value specified for HandleOAuthUserGoogle")
@@ -50,17 +52,17 @@ func (_m *IOAuthUsecase) HandleOAuthUserGoogle(user goth.User) (string, error) {
 
 	var r0 string
 	var r1 error
-	if rf, ok := ret.Get(0).(func(goth.User) (string, error)); ok {
-		return rf(user)
+	if rf, ok := ret.Get(0).(func(context.Context, goth.User) (string, error)); ok {
+		return rf(ctx, user)
 	}
-	if rf, ok := ret.Get(0).(func(goth.User) string); ok {
-		r0 = rf(user)
+	if rf, ok := ret.Get(0).(func(context.Context, goth.User) string); ok {
+		r0 = rf(ctx, user)
 	} else {
 		r0 = ret.Get(0).(string)
 	}
 
-	if rf, ok := ret.Get(1).(func(goth.User) error); ok {
-		r1 = rf(user)
+	if rf, ok := ret.Get(1).(func(context.Context, goth.User) error); ok {
+		r1 = rf(ctx, user)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -2,28 +2,6 @@ package model
 
 import "time"
 
-type UpdateUserRequest struct {
-	UserID       int64     `json:"user_id"`
-	Email        string    `json:"email"`
-	PasswordHash string    `json:"password_hash"`
-	FullName     string    `json:"full_name"`
-	PhoneNumber  string    `json:"phone_number"`
-	Address      string    `json:"address"`
-	Role         string    `json:"role"`
-	ImageURL     string    `json:"image_url"`
-	Token        string    `json:"token"`
-	TokenExpires time.Time `json:"token_expires"`
-	IsVerified   bool      `json:"is_verified"`
-	IsDeleted    bool      `json:"is_deleted"`
-}
-type CreateUserRequest struct {
-	Email      string `json:"email"`
-	Password   string `json:"password"`
-	Role       string `json:"role"`
-	ImageURL   string `json:"image_url"`
-	Provider   string `json:"provider"`
-	IsVerified bool   `json:"is_verified"`
-}
 type User struct {
 	UserID       int64     `gorm:"primaryKey;autoIncrement;column:user_id"`
 	Email        string    `gorm:"unique;not null;column:email"`
@@ -78,21 +56,3 @@ type Product struct {
 	UpdatedAt   time.Time `gorm:"type:timestamp without time zone;column:created_at;default:current_timestamp"`
 	IsDeleted   bool      `gorm:"column:is_deleted;default:false"`
 }
-
-type GetUserResponse struct {
-	UserID       int64  `json:"user_id"`
-	Email        string `json:"email"`
-	PasswordHash string `json:"password_hash"`
-	FullName     string `json:"full_name"`
-	PhoneNumber  string `json:"phone_number"`
-	Address      string `json:"address"`
-	Role         string `json:"role"`
-	ImageURL     string `json:"image_url"`
-	Provider     string `json:"provider"`
-	CreatedAt    string `json:"created_at"`
-	UpdatedAt    string `json:"updated_at"`
-	Token        string `json:"token"`
-	TokenExpires string `json:"token_expires"`
-	IsVerified   bool   `json:"is_verified"`
-	IsDeleted    bool   `json:"is_deleted"`
-}
@@ -1,6 +1,7 @@
 package delivery
 
 import (
+	"th3y3m/e-commerce-microservices/pkg/authz"
 	"th3y3m/e-commerce-microservices/service/order/dependency_injection"
 	"th3y3m/e-commerce-microservices/service/order/model"
 
@@ -10,14 +11,14 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
-func GetOrderByID(c *gin.Context) {
+func (h *handler) GetOrderByID(c *gin.Context) {
 	id := c.Param("order_id")
 
-	module := dependency_injection.NewOrderUsecaseProvider()
+	module := dependency_injection.NewOrderUsecaseProvider(h.cfg, h.services)
 
 	orderID, err := strconv.ParseInt(id, 10, 64)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(400, gin.H{
 			"error": "Invalid Order ID",
 		})
@@ -27,24 +28,33 @@ func GetOrderByID(c *gin.Context) {
 	var req model.GetOrderRequest
 	req.OrderID = orderID
 
+	caller, ok := authz.Authorize(c)
+	if !ok {
+		return
+	}
+
 	order, err := module.GetOrder(c, &req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(500, gin.H{
 			"error": "Internal Server Error",
 		})
 		return
 	}
 
+	if !authz.RequireOwner(c, caller, order.CustomerID) {
+		return
+	}
+
 	c.JSON(200, order)
 }
 
-func GetAllOrders(c *gin.Context) {
-	module := dependency_injection.NewOrderUsecaseProvider()
+func (h *handler) GetAllOrders(c *gin.Context) {
+	module := dependency_injection.NewOrderUsecaseProvider(h.cfg, h.services)
 
 	orders, err := module.GetAllOrders(c)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(500, gin.H{
 			"error": "Internal Server Error",
 		})
@@ -54,22 +64,22 @@ func GetAllOrders(c *gin.Context) {
 	c.JSON(200, orders)
 }
 
-func CreateOrder(c *gin.Context) {
+func (h *handler) CreateOrder(c *gin.Context) {
 	var req model.CreateOrderRequest
 	err := c.BindJSON(&req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(400, gin.H{
 			"error": "Bad Request",
 		})
 		return
 	}
 
-	module := dependency_injection.NewOrderUsecaseProvider()
+	module := dependency_injection.NewOrderUsecaseProvider(h.cfg, h.services)
 
 	order, err := module.CreateOrder(c, &req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(500, gin.H{
 			"error": "Internal Server Error",
 		})
@@ -79,22 +89,22 @@ func CreateOrder(c *gin.Context) {
 	c.JSON(200, order)
 }
 
-func UpdateOrder(c *gin.Context) {
+func (h *handler) UpdateOrder(c *gin.Context) {
 	var req model.UpdateOrderRequest
 	err := c.BindJSON(&req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(400, gin.H{
 			"error": "Bad Request",
 		})
 		return
 	}
 
-	module := dependency_injection.NewOrderUsecaseProvider()
+	module := dependency_injection.NewOrderUsecaseProvider(h.cfg, h.services)
 
 	order, err := module.UpdateOrder(c, &req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(500, gin.H{
 			"error": "Internal Server Error",
 		})
@@ -104,22 +114,27 @@ func UpdateOrder(c *gin.Context) {
 	c.JSON(200, order)
 }
 
-func DeleteOrder(c *gin.Context) {
-	module := dependency_injection.NewOrderUsecaseProvider()
+func (h *handler) DeleteOrder(c *gin.Context) {
+	module := dependency_injection.NewOrderUsecaseProvider(h.cfg, h.services)
 
 	var req model.DeleteOrderRequest
 	err := c.BindJSON(&req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(400, gin.H{
 			"error": "Bad Request",
 		})
 		return
 	}
 
+	caller, ok := authz.Authorize(c)
+	if !ok || !authz.RequireRole(c, caller, authz.RoleAdmin) {
+		return
+	}
+
 	err = module.DeleteOrder(c, &req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(500, gin.H{
 			"error": "Internal Server Error",
 		})
@@ -131,19 +146,28 @@ func DeleteOrder(c *gin.Context) {
 	})
 }
 
-func GetPaginatedOrder(c *gin.Context) {
-	module := dependency_injection.NewOrderUsecaseProvider()
+func (h *handler) GetPaginatedOrder(c *gin.Context) {
+	module := dependency_injection.NewOrderUsecaseProvider(h.cfg, h.services)
 
 	var req model.GetOrdersRequest
 	err := c.BindJSON(&req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(400, gin.H{
 			"error": "Bad Request",
 		})
 		return
 	}
 
+	caller, ok := authz.Authorize(c)
+	if !ok || !authz.RequireUser(c, caller) {
+		return
+	}
+	// Everyone but admins and the system only sees their own orders
+	if !caller.Privileged() {
+		req.CustomerID = &caller.UserID
+	}
+
 	if req.Paging.PageIndex == 0 {
 		req.Paging.PageIndex = 1
 	}
@@ -153,7 +177,7 @@ func GetPaginatedOrder(c *gin.Context) {
 
 	orders, err := module.GetOrderList(c, &req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(500, gin.H{
 			"error": "Internal Server Error",
 		})
@@ -163,22 +187,27 @@ func GetPaginatedOrder(c *gin.Context) {
 	c.JSON(200, orders)
 }
 
-func PlaceOrder(c *gin.Context) {
-	module := dependency_injection.NewOrderUsecaseProvider()
+func (h *handler) PlaceOrder(c *gin.Context) {
+	module := dependency_injection.NewOrderUsecaseProvider(h.cfg, h.services)
 
 	var req model.PlaceOrderRequest
 	err := c.BindJSON(&req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(400, gin.H{
 			"error": "Bad Request",
 		})
 		return
 	}
 
+	caller, ok := authz.Authorize(c)
+	if !ok || !authz.RequireOwner(c, caller, req.UserId) {
+		return
+	}
+
 	url, err := module.PlaceOrder(c, req.UserId, req.CartId, req.CourierID, req.VoucherID, req.PaymentMethod, req.ShipAddress, req.Freight)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(500, gin.H{
 			"error": "Internal Server Error",
 		})
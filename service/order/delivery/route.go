@@ -1,20 +1,53 @@
 package delivery
 
 import (
+	"context"
+	"net/http"
+	"th3y3m/e-commerce-microservices/pkg/authz"
+	"th3y3m/e-commerce-microservices/pkg/discovery"
+	"th3y3m/e-commerce-microservices/pkg/logging"
+	"th3y3m/e-commerce-microservices/pkg/metrics"
+	"th3y3m/e-commerce-microservices/pkg/outbox"
+	"th3y3m/e-commerce-microservices/pkg/tracing"
+	"th3y3m/e-commerce-microservices/service/order/config"
+	"th3y3m/e-commerce-microservices/service/order/dependency_injection"
+
 	"github.com/gin-gonic/gin"
 )
 
-func RegisterHandlers() *gin.Engine {
-	r := gin.Default()
+// handler serves the routes of the service, with the usecases built from cfg
+type handler struct {
+	cfg config.Config
+	// Calls the other services for the usecases
+	services *http.Client
+}
+
+func RegisterHandlers(cfg config.Config, resolver discovery.Resolver) *gin.Engine {
+	h := &handler{cfg: cfg, services: dependency_injection.NewServiceClientProvider(cfg, resolver)}
+	keys := authz.NewKeys(cfg.Auth)
+
+	r := gin.New()
+	// Lets the request context, and the trace in it, reach the usecases through *gin.Context
+	r.ContextWithFallback = true
+	r.Use(gin.Recovery(), tracing.Middleware("order"), logging.Middleware(), metrics.Middleware())
+	r.GET(metrics.Path, metrics.Handler())
+	r.Use(keys.Middleware("user_id", "customer_id"))
 
 	order := r.Group("/api/orders")
 	{
-		order.GET("/:order_id", GetOrderByID)
-		order.GET("", GetPaginatedOrder)
-		order.POST("", PlaceOrder)
-		order.PUT("", UpdateOrder)
-		order.DELETE("", DeleteOrder)
+		order.GET("/:order_id", h.GetOrderByID)
+		order.GET("", h.GetPaginatedOrder)
+		order.POST("", h.PlaceOrder)
+		order.PUT("", keys.InternalOnly("momo", "vnpay"), h.UpdateOrder)
+		order.DELETE("", h.DeleteOrder)
 	}
 
 	return r
 }
+
+// RelayOutbox publishes the events written to the outbox until ctx is canceled
+func RelayOutbox(cfg config.Config) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		return outbox.NewRelay(cfg.Postgres, outbox.NewRelayConfig(cfg.Outbox), dependency_injection.NewBrokerProvider(cfg)).Run(ctx)
+	}
+}
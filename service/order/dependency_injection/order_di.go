@@ -1,21 +1,27 @@
 package dependency_injection
 
 import (
+	"net/http"
+	"th3y3m/e-commerce-microservices/pkg/authz"
+	"th3y3m/e-commerce-microservices/pkg/broker"
+	"th3y3m/e-commerce-microservices/pkg/clients"
+	"th3y3m/e-commerce-microservices/pkg/discovery"
+	"th3y3m/e-commerce-microservices/pkg/logging"
 	"th3y3m/e-commerce-microservices/pkg/postgresql"
+	"th3y3m/e-commerce-microservices/pkg/rabbitmq"
 	redis_client "th3y3m/e-commerce-microservices/pkg/redis"
+	"th3y3m/e-commerce-microservices/service/order/config"
 	"th3y3m/e-commerce-microservices/service/order/repository"
 	"th3y3m/e-commerce-microservices/service/order/usecase"
-
-	"github.com/sirupsen/logrus"
 )
 
-func NewOrderRepositoryProvider() repository.IOrderRepository {
-	log := logrus.New()
-	db, err := postgresql.NewGormDB()
+func NewOrderRepositoryProvider(cfg config.Config) repository.IOrderRepository {
+	log := logging.Logger()
+	db, err := postgresql.NewGormDB(cfg.Postgres)
 	if err != nil {
 		log.Error(err)
 	}
-	redis, err := redis_client.ConnectToRedis()
+	redis, err := redis_client.ConnectToRedis(cfg.Redis)
 	if err != nil {
 		log.Error(err)
 	}
@@ -23,8 +29,28 @@ func NewOrderRepositoryProvider() repository.IOrderRepository {
 	return repository.NewOrderRepository(db, redis, log)
 }
 
-func NewOrderUsecaseProvider() usecase.IOrderUsecase {
-	log := logrus.New()
-	orderRepository := NewOrderRepositoryProvider()
-	return usecase.NewOrderUsecase(orderRepository, log)
+func NewOrderUsecaseProvider(cfg config.Config, httpClient *http.Client) usecase.IOrderUsecase {
+	log := logging.Logger()
+	orderRepository := NewOrderRepositoryProvider(cfg)
+	opts := clients.NewOptions(cfg.Clients)
+	return usecase.NewOrderUsecase(orderRepository, usecase.Clients{
+		CartItem:    clients.NewCartItemClient(httpClient, opts),
+		Product:     clients.NewProductClient(httpClient, opts),
+		Voucher:     clients.NewVoucherClient(httpClient, opts),
+		OrderDetail: clients.NewOrderDetailClient(httpClient, opts),
+		Payment:     clients.NewPaymentClient(httpClient, opts),
+		MoMo:        clients.NewMoMoClient(httpClient, opts),
+		VnPay:       clients.NewVnPayClient(httpClient, opts),
+	}, log)
+}
+
+// NewBrokerProvider returns the RabbitMQ broker, or the in-process one when BROKER is memory
+func NewBrokerProvider(cfg config.Config) broker.Broker {
+	return broker.FromConfig(cfg.RabbitMQ, rabbitmq.DefaultBroker)
+}
+
+// NewServiceClientProvider returns the HTTP client the order service calls other
+// services with, signed with its service token and sent to the instances resolver finds
+func NewServiceClientProvider(cfg config.Config, resolver discovery.Resolver) *http.Client {
+	return authz.NewKeys(cfg.Auth).NewServiceClient("order", resolver)
 }
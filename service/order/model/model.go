@@ -5,10 +5,6 @@ import (
 	"time"
 )
 
-type MoMoResponse struct {
-	PaymentURL string `json:"payment_url"`
-}
-
 type PlaceOrderRequest struct {
 	UserId        int64   `json:"user_id"`
 	CartId        int64   `json:"cart_id"`
@@ -59,53 +55,15 @@ type UpdateProductRequest struct {
 	CategoryID  int64   `json:"category_id"`
 	ImageURL    string  `json:"image_url"`
 }
-type CreatePaymentRequest struct {
-	OrderID          int64   `json:"order_id"`
-	PaymentAmount    float64 `json:"payment_amount"`
-	PaymentMethod    string  `json:"payment_method"`
-	PaymentStatus    string  `json:"payment_status"`
-	PaymentSignature string  `json:"payment_signature"`
-}
 type GetOrderDetailResponse struct {
 	OrderID   int64   `json:"order_id"`
 	ProductID int64   `json:"product_id"`
 	Quantity  int     `json:"quantity"`
 	UnitPrice float64 `json:"unit_price"`
 }
-type CreateOrderDetailRequest struct {
-	OrderID   int64   `json:"order_id"`
-	ProductID int64   `json:"product_id"`
-	Quantity  int     `json:"quantity"`
-	UnitPrice float64 `json:"unit_price"`
-}
-
-type GetProductResponse struct {
-	ProductID   int64   `json:"product_id"`
-	SellerID    int64   `json:"seller_id"`
-	ProductName string  `json:"product_name"`
-	Description string  `json:"description"`
-	Price       float64 `json:"price"`
-	Quantity    int     `json:"quantity"`
-	CategoryID  int64   `json:"category_id"`
-	ImageURL    string  `json:"image_url"`
-	CreatedAt   string  `json:"created_at"`
-	UpdatedAt   string  `json:"updated_at"`
-	IsDeleted   bool    `json:"is_deleted"`
-}
 type GetProductRequest struct {
 	ProductID int64 `json:"product_id"`
 }
-type GetCartItemsRequest struct {
-	CartID    *int64 `json:"cart_id"`
-	ProductID *int64 `json:"product_id"`
-}
-
-type GetCartItemResponse struct {
-	CartID    int64 `json:"cart_id"`
-	ProductID int64 `json:"product_id"`
-	Quantity  int   `json:"quantity"`
-}
-
 type GetOrderRequest struct {
 	OrderID int64 `json:"order_id"`
 }
@@ -131,30 +89,6 @@ type GetOrdersRequest struct {
 type DeleteOrderRequest struct {
 	OrderID int64 `json:"order_id"`
 }
-type CheckVoucherUsageRequest struct {
-	VoucherID int64 `json:"voucher_id"`
-	Order     Order `json:"order"`
-}
-
-type CheckVoucherUsageResponse struct {
-	Valid bool `json:"valid"`
-}
-
-type GetVoucherResponse struct {
-	VoucherID          int64   `json:"voucher_id"`
-	VoucherCode        string  `json:"voucher_code"`
-	DiscountType       string  `json:"discount_type"`
-	DiscountValue      float64 `json:"discount_value"`
-	MinimumOrderAmount float64 `json:"minimum_order_amount"`
-	MaxDiscountAmount  float64 `json:"max_discount_amount"`
-	StartDate          string  `json:"start_date"`
-	EndDate            string  `json:"end_date"`
-	UsageLimit         int     `json:"usage_limit"`
-	UsageCount         int     `json:"usage_count"`
-	IsDeleted          bool    `json:"is_deleted"`
-	CreatedAt          string  `json:"created_at"`
-	UpdatedAt          string  `json:"updated_at"`
-}
 type GetOrderResponse struct {
 	OrderID               int64   `json:"order_id"`
 	CustomerID            int64   `json:"customer_id"`
@@ -3,7 +3,11 @@ package repository
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"th3y3m/e-commerce-microservices/pkg/events"
+	"th3y3m/e-commerce-microservices/pkg/metrics"
+	"th3y3m/e-commerce-microservices/pkg/outbox"
 	"th3y3m/e-commerce-microservices/service/order/model"
 
 	"github.com/redis/go-redis/v9"
@@ -22,6 +26,7 @@ type IOrderRepository interface {
 	GetAll(ctx context.Context) ([]*Order, error)
 	Create(ctx context.Context, order *Order) (*Order, error)
 	Update(ctx context.Context, order *Order) (*Order, error)
+	ChangeStatusWithEvents(ctx context.Context, orderID int64, from, to string, evs ...events.Event) (*Order, error)
 	Delete(ctx context.Context, orderID int64) error
 	getQuerySearch(db *gorm.DB, req *model.GetOrdersRequest) *gorm.DB
 	GetList(ctx context.Context, req *model.GetOrdersRequest) ([]*Order, error)
@@ -36,7 +41,7 @@ func NewOrderRepository(db *gorm.DB, redis *redis.Client, log *logrus.Logger) IO
 }
 
 func (pr *orderRepository) Get(ctx context.Context, orderID int64) (*Order, error) {
-	pr.log.Infof("Fetching order with ID: %d", orderID)
+	pr.log.WithContext(ctx).Infof("Fetching order with ID: %d", orderID)
 	var order Order
 	cacheKey := fmt.Sprintf("order:%d", orderID)
 
@@ -45,19 +50,21 @@ func (pr *orderRepository) Get(ctx context.Context, orderID int64) (*Order, erro
 		cachedOrder, err := pr.redis.Get(ctx, cacheKey).Result()
 		if err == nil {
 			if err := json.Unmarshal([]byte(cachedOrder), &order); err == nil {
-				pr.log.Infof("Order found in cache: %d", orderID)
+				pr.log.WithContext(ctx).Infof("Order found in cache: %d", orderID)
+				metrics.CacheHit("orderRepository.Get")
 				return &order, nil
 			}
 		} else if err != redis.Nil {
-			pr.log.Warnf("Failed to get order from Redis: %v", err)
+			pr.log.WithContext(ctx).Warnf("Failed to get order from Redis: %v", err)
 		}
+		metrics.CacheMiss("orderRepository.Get")
 	} else {
-		pr.log.Warn("Redis client is not initialized")
+		pr.log.WithContext(ctx).Warn("Redis client is not initialized")
 	}
 
 	// If not found in cache, get from database
 	if err := pr.db.WithContext(ctx).First(&order, orderID).Error; err != nil {
-		pr.log.Errorf("Error fetching order from database: %v", err)
+		pr.log.WithContext(ctx).Errorf("Error fetching order from database: %v", err)
 		return nil, err
 	}
 
@@ -65,9 +72,9 @@ func (pr *orderRepository) Get(ctx context.Context, orderID int64) (*Order, erro
 	if pr.redis != nil {
 		orderJSON, _ := json.Marshal(order)
 		if err := pr.redis.Set(ctx, cacheKey, orderJSON, 0).Err(); err != nil {
-			pr.log.Warnf("Failed to save order to Redis: %v", err)
+			pr.log.WithContext(ctx).Warnf("Failed to save order to Redis: %v", err)
 		} else {
-			pr.log.Infof("Order saved to cache: %d", orderID)
+			pr.log.WithContext(ctx).Infof("Order saved to cache: %d", orderID)
 		}
 	}
 
@@ -75,7 +82,7 @@ func (pr *orderRepository) Get(ctx context.Context, orderID int64) (*Order, erro
 }
 
 func (pr *orderRepository) GetAll(ctx context.Context) ([]*Order, error) {
-	pr.log.Info("Fetching all orders")
+	pr.log.WithContext(ctx).Info("Fetching all orders")
 	var orders []*Order
 	cacheKey := "all_orders"
 
@@ -84,19 +91,21 @@ func (pr *orderRepository) GetAll(ctx context.Context) ([]*Order, error) {
 		cachedOrders, err := pr.redis.Get(ctx, cacheKey).Result()
 		if err == nil {
 			if err := json.Unmarshal([]byte(cachedOrders), &orders); err == nil {
-				pr.log.Info("Orders found in cache")
+				pr.log.WithContext(ctx).Info("Orders found in cache")
+				metrics.CacheHit("orderRepository.GetAll")
 				return orders, nil
 			}
 		} else if err != redis.Nil {
-			pr.log.Warnf("Failed to get orders from Redis: %v", err)
+			pr.log.WithContext(ctx).Warnf("Failed to get orders from Redis: %v", err)
 		}
+		metrics.CacheMiss("orderRepository.GetAll")
 	} else {
-		pr.log.Warn("Redis client is not initialized")
+		pr.log.WithContext(ctx).Warn("Redis client is not initialized")
 	}
 
 	// If not found in cache, get from database
 	if err := pr.db.WithContext(ctx).Find(&orders).Error; err != nil {
-		pr.log.Errorf("Error fetching orders from database: %v", err)
+		pr.log.WithContext(ctx).Errorf("Error fetching orders from database: %v", err)
 		return nil, err
 	}
 
@@ -104,9 +113,9 @@ func (pr *orderRepository) GetAll(ctx context.Context) ([]*Order, error) {
 	if pr.redis != nil {
 		ordersJSON, _ := json.Marshal(orders)
 		if err := pr.redis.Set(ctx, cacheKey, ordersJSON, 0).Err(); err != nil {
-			pr.log.Warnf("Failed to save orders to Redis: %v", err)
+			pr.log.WithContext(ctx).Warnf("Failed to save orders to Redis: %v", err)
 		} else {
-			pr.log.Info("Orders saved to cache")
+			pr.log.WithContext(ctx).Info("Orders saved to cache")
 		}
 	}
 
@@ -114,9 +123,9 @@ func (pr *orderRepository) GetAll(ctx context.Context) ([]*Order, error) {
 }
 
 func (pr *orderRepository) Create(ctx context.Context, order *Order) (*Order, error) {
-	pr.log.Infof("Creating order: %+v", order)
+	pr.log.WithContext(ctx).Infof("Creating order: %+v", order)
 	if err := pr.db.WithContext(ctx).Create(order).Error; err != nil {
-		pr.log.Errorf("Error creating order: %v", err)
+		pr.log.WithContext(ctx).Errorf("Error creating order: %v", err)
 		return nil, err
 	}
 	cacheKey := fmt.Sprintf("order:%d", order.OrderID)
@@ -125,16 +134,16 @@ func (pr *orderRepository) Create(ctx context.Context, order *Order) (*Order, er
 	if pr.redis != nil {
 		orderJSON, _ := json.Marshal(order)
 		if err := pr.redis.Set(ctx, cacheKey, orderJSON, 0).Err(); err != nil {
-			pr.log.Warnf("Failed to save order to Redis: %v", err)
+			pr.log.WithContext(ctx).Warnf("Failed to save order to Redis: %v", err)
 		} else {
-			pr.log.Infof("Order saved to cache: %d", order.OrderID)
+			pr.log.WithContext(ctx).Infof("Order saved to cache: %d", order.OrderID)
 		}
 
 		// Invalidate the cache for all records
 		if err := pr.redis.Del(ctx, "all_orders").Err(); err != nil {
-			pr.log.Warnf("Failed to invalidate all orders cache: %v", err)
+			pr.log.WithContext(ctx).Warnf("Failed to invalidate all orders cache: %v", err)
 		} else {
-			pr.log.Info("Invalidated cache for all orders")
+			pr.log.WithContext(ctx).Info("Invalidated cache for all orders")
 		}
 	}
 
@@ -143,38 +152,70 @@ func (pr *orderRepository) Create(ctx context.Context, order *Order) (*Order, er
 }
 
 func (pr *orderRepository) Update(ctx context.Context, order *Order) (*Order, error) {
-	pr.log.Infof("Updating order: %+v", order)
+	pr.log.WithContext(ctx).Infof("Updating order: %+v", order)
 	if err := pr.db.WithContext(ctx).Save(order).Error; err != nil {
-		pr.log.Errorf("Error updating order: %v", err)
+		pr.log.WithContext(ctx).Errorf("Error updating order: %v", err)
 		return nil, err
 	}
+	return pr.cache(ctx, order), nil
+}
+
+// ErrStatusChanged is returned when the order is no longer in the status it was expected in
+var ErrStatusChanged = errors.New("order status has changed")
+
+// ChangeStatusWithEvents moves the order from one status to another and adds the events to the
+// outbox in one transaction. It fails with ErrStatusChanged unless the order was in from.
+func (pr *orderRepository) ChangeStatusWithEvents(ctx context.Context, orderID int64, from, to string, evs ...events.Event) (*Order, error) {
+	pr.log.WithContext(ctx).Infof("Changing status of order %d from %s to %s", orderID, from, to)
+	var order Order
+	err := pr.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&Order{}).Where("order_id = ? AND order_status = ?", orderID, from).Update("order_status", to)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return ErrStatusChanged
+		}
+		if err := tx.First(&order, orderID).Error; err != nil {
+			return err
+		}
+		return outbox.Add(ctx, tx, evs...)
+	})
+	if err != nil {
+		pr.log.WithContext(ctx).Errorf("Error changing order status: %v", err)
+		return nil, err
+	}
+	return pr.cache(ctx, &order), nil
+}
+
+// cache stores the saved order and drops the cached list of all orders
+func (pr *orderRepository) cache(ctx context.Context, order *Order) *Order {
 	cacheKey := fmt.Sprintf("order:%d", order.OrderID)
 
 	// Save to cache if Redis is available
 	if pr.redis != nil {
 		orderJSON, _ := json.Marshal(order)
 		if err := pr.redis.Set(ctx, cacheKey, orderJSON, 0).Err(); err != nil {
-			pr.log.Warnf("Failed to save order to Redis: %v", err)
+			pr.log.WithContext(ctx).Warnf("Failed to save order to Redis: %v", err)
 		} else {
-			pr.log.Infof("Order saved to cache: %d", order.OrderID)
+			pr.log.WithContext(ctx).Infof("Order saved to cache: %d", order.OrderID)
 		}
 
 		// Invalidate the cache for all records
 		if err := pr.redis.Del(ctx, "all_orders").Err(); err != nil {
-			pr.log.Warnf("Failed to invalidate all orders cache: %v", err)
+			pr.log.WithContext(ctx).Warnf("Failed to invalidate all orders cache: %v", err)
 		} else {
-			pr.log.Info("Invalidated cache for all orders")
+			pr.log.WithContext(ctx).Info("Invalidated cache for all orders")
 		}
 	}
 
-	// Return the updated order
-	return order, nil
+	return order
 }
 
 func (pr *orderRepository) Delete(ctx context.Context, orderID int64) error {
-	pr.log.Infof("Deleting order with ID: %d", orderID)
+	pr.log.WithContext(ctx).Infof("Deleting order with ID: %d", orderID)
 	if err := pr.db.WithContext(ctx).Delete(&Order{}, orderID).Error; err != nil {
-		pr.log.Errorf("Error deleting order: %v", err)
+		pr.log.WithContext(ctx).Errorf("Error deleting order: %v", err)
 		return err
 	}
 
@@ -183,16 +224,16 @@ func (pr *orderRepository) Delete(ctx context.Context, orderID int64) error {
 	// Delete from cache if Redis is available
 	if pr.redis != nil {
 		if err := pr.redis.Del(ctx, cacheKey).Err(); err != nil {
-			pr.log.Warnf("Failed to delete order from Redis: %v", err)
+			pr.log.WithContext(ctx).Warnf("Failed to delete order from Redis: %v", err)
 		} else {
-			pr.log.Infof("Order deleted from cache: %d", orderID)
+			pr.log.WithContext(ctx).Infof("Order deleted from cache: %d", orderID)
 		}
 
 		// Invalidate the cache for all records
 		if err := pr.redis.Del(ctx, "all_orders").Err(); err != nil {
-			pr.log.Warnf("Failed to invalidate all orders cache: %v", err)
+			pr.log.WithContext(ctx).Warnf("Failed to invalidate all orders cache: %v", err)
 		} else {
-			pr.log.Info("Invalidated cache for all orders")
+			pr.log.WithContext(ctx).Info("Invalidated cache for all orders")
 		}
 	}
 
@@ -246,7 +287,7 @@ func (pr *orderRepository) getQuerySearch(db *gorm.DB, req *model.GetOrdersReque
 }
 
 func (pr *orderRepository) GetList(ctx context.Context, req *model.GetOrdersRequest) ([]*Order, error) {
-	pr.log.Infof("Fetching order list with request: %+v", req)
+	pr.log.WithContext(ctx).Infof("Fetching order list with request: %+v", req)
 	var orders []*Order
 
 	db := pr.db.WithContext(ctx)
@@ -271,10 +312,10 @@ func (pr *orderRepository) GetList(ctx context.Context, req *model.GetOrdersRequ
 
 	result := db.Table("orders").Offset(int(req.Paging.PageIndex-1) * int(req.Paging.PageSize)).Limit(int(req.Paging.PageSize)).Find(&orders)
 	if result.Error != nil {
-		pr.log.Errorf("Error fetching order list: %v", result.Error)
+		pr.log.WithContext(ctx).Errorf("Error fetching order list: %v", result.Error)
 		return nil, result.Error
 	}
 
-	pr.log.Infof("Fetched %d orders", len(orders))
+	pr.log.WithContext(ctx).Infof("Fetched %d orders", len(orders))
 	return orders, nil
 }
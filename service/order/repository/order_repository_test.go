@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"context"
+	"io"
+	"testing"
+	"th3y3m/e-commerce-microservices/pkg/constant"
+	"th3y3m/e-commerce-microservices/pkg/events"
+	"th3y3m/e-commerce-microservices/pkg/outbox"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestChangeStatusWithEventsPlacesThePendingOrderOnce(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{Logger: logger.Discard})
+	require.NoError(t, err)
+	// SQLite only reads back the timestamps of datetime columns
+	require.NoError(t, db.Exec(`CREATE TABLE orders (
+		order_id INTEGER PRIMARY KEY AUTOINCREMENT, customer_id INTEGER, order_date DATETIME,
+		total_amount REAL, order_status TEXT, shipping_address TEXT, courier_id INTEGER,
+		freight_price REAL, estimated_delivery_date DATETIME, actual_delivery_date DATETIME,
+		voucher_id INTEGER, is_deleted BOOLEAN DEFAULT false, created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	)`).Error)
+	require.NoError(t, outbox.Migrate(db))
+
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+	repo := NewOrderRepository(db, nil, log)
+
+	ctx := context.Background()
+	order, err := repo.Create(ctx, &Order{CustomerID: 3, TotalAmount: 120, OrderStatus: constant.ORDER_STATUS_PENDING})
+	require.NoError(t, err)
+
+	placed := events.OrderPlaced{OrderID: order.OrderID, CustomerID: 3, TotalAmount: 120}
+	placedOrder, err := repo.ChangeStatusWithEvents(ctx, order.OrderID, constant.ORDER_STATUS_PENDING, constant.ORDER_STATUS_PLACED, placed)
+	require.NoError(t, err)
+	assert.Equal(t, constant.ORDER_STATUS_PLACED, placedOrder.OrderStatus)
+
+	// The order is no longer pending, neither the status nor the outbox change
+	_, err = repo.ChangeStatusWithEvents(ctx, order.OrderID, constant.ORDER_STATUS_PENDING, constant.ORDER_STATUS_PLACED, placed)
+	assert.ErrorIs(t, err, ErrStatusChanged)
+
+	var messages []outbox.Message
+	require.NoError(t, db.Find(&messages).Error)
+	require.Len(t, messages, 1)
+	assert.Equal(t, events.TypeOrderPlaced, messages[0].EventType)
+}
@@ -1,27 +1,38 @@
 package usecase
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
 	"fmt"
-	"io"
-	"net/http"
+	"th3y3m/e-commerce-microservices/pkg/authz"
+	"th3y3m/e-commerce-microservices/pkg/clients"
 	"th3y3m/e-commerce-microservices/pkg/constant"
+	"th3y3m/e-commerce-microservices/pkg/events"
+	"th3y3m/e-commerce-microservices/pkg/metrics"
 	"th3y3m/e-commerce-microservices/pkg/util"
 	"th3y3m/e-commerce-microservices/service/order/model"
-	"th3y3m/e-commerce-microservices/service/order/rabbitmq"
 	"th3y3m/e-commerce-microservices/service/order/repository"
 	"time"
 
 	"github.com/sirupsen/logrus"
 )
 
+// Clients of the services an order is placed with
+type Clients struct {
+	CartItem    *clients.CartItemClient
+	Product     *clients.ProductClient
+	Voucher     *clients.VoucherClient
+	OrderDetail *clients.OrderDetailClient
+	Payment     *clients.PaymentClient
+	MoMo        *clients.GatewayClient
+	VnPay       *clients.GatewayClient
+}
+
 const tsCreateTimeLayout = "2006-01-02 15:04:05 +0700"
 
 type orderUsecase struct {
 	log       *logrus.Logger
 	orderRepo repository.IOrderRepository
+	clients   Clients
 }
 
 type IOrderUsecase interface {
@@ -37,18 +48,19 @@ type IOrderUsecase interface {
 	CancelOrder(ctx context.Context, orderID int64) error
 }
 
-func NewOrderUsecase(orderRepo repository.IOrderRepository, log *logrus.Logger) IOrderUsecase {
+func NewOrderUsecase(orderRepo repository.IOrderRepository, clients Clients, log *logrus.Logger) IOrderUsecase {
 	return &orderUsecase{
 		orderRepo: orderRepo,
+		clients:   clients,
 		log:       log,
 	}
 }
 
 func (pu *orderUsecase) CancelOrder(ctx context.Context, orderID int64) error {
-	pu.log.Infof("Cancelling order with ID: %d", orderID)
+	pu.log.WithContext(ctx).Infof("Cancelling order with ID: %d", orderID)
 	order, err := pu.orderRepo.Get(ctx, orderID)
 	if err != nil {
-		pu.log.Errorf("Error fetching order for cancellation: %v", err)
+		pu.log.WithContext(ctx).Errorf("Error fetching order for cancellation: %v", err)
 		return err
 	}
 
@@ -56,23 +68,23 @@ func (pu *orderUsecase) CancelOrder(ctx context.Context, orderID int64) error {
 
 	_, err = pu.orderRepo.Update(ctx, order)
 	if err != nil {
-		pu.log.Errorf("Error updating order for cancellation: %v", err)
+		pu.log.WithContext(ctx).Errorf("Error updating order for cancellation: %v", err)
 		return err
 	}
 
-	pu.log.Infof("Cancelled order with ID: %d", orderID)
+	pu.log.WithContext(ctx).Infof("Cancelled order with ID: %d", orderID)
 	return nil
 }
 
 func (pu *orderUsecase) GetOrder(ctx context.Context, req *model.GetOrderRequest) (*model.GetOrderResponse, error) {
-	pu.log.Infof("Fetching order with ID: %d", req.OrderID)
+	pu.log.WithContext(ctx).Infof("Fetching order with ID: %d", req.OrderID)
 	order, err := pu.orderRepo.Get(ctx, req.OrderID)
 	if err != nil {
-		pu.log.Errorf("Error fetching order: %v", err)
+		pu.log.WithContext(ctx).Errorf("Error fetching order: %v", err)
 		return nil, err
 	}
 
-	pu.log.Infof("Fetched order: %+v", order)
+	pu.log.WithContext(ctx).Infof("Fetched order: %+v", order)
 	return &model.GetOrderResponse{
 		OrderID:               order.OrderID,
 		CustomerID:            order.CustomerID,
@@ -92,10 +104,10 @@ func (pu *orderUsecase) GetOrder(ctx context.Context, req *model.GetOrderRequest
 }
 
 func (pu *orderUsecase) GetAllOrders(ctx context.Context) ([]*model.GetOrderResponse, error) {
-	pu.log.Infof("Fetching all orders")
+	pu.log.WithContext(ctx).Infof("Fetching all orders")
 	orders, err := pu.orderRepo.GetAll(ctx)
 	if err != nil {
-		pu.log.Errorf("Error fetching orders: %v", err)
+		pu.log.WithContext(ctx).Errorf("Error fetching orders: %v", err)
 		return nil, err
 	}
 
@@ -119,12 +131,12 @@ func (pu *orderUsecase) GetAllOrders(ctx context.Context) ([]*model.GetOrderResp
 		})
 	}
 
-	pu.log.Infof("Fetched %d orders", len(orderResponses))
+	pu.log.WithContext(ctx).Infof("Fetched %d orders", len(orderResponses))
 	return orderResponses, nil
 }
 
 func (pu *orderUsecase) CreateOrder(ctx context.Context, order *model.CreateOrderRequest) (*model.GetOrderResponse, error) {
-	pu.log.Infof("Creating order: %+v", order)
+	pu.log.WithContext(ctx).Infof("Creating order: %+v", order)
 	orderData := repository.Order{
 		CustomerID:            order.CustomerID,
 		OrderDate:             time.Now(),
@@ -140,11 +152,11 @@ func (pu *orderUsecase) CreateOrder(ctx context.Context, order *model.CreateOrde
 
 	createdOrder, err := pu.orderRepo.Create(ctx, &orderData)
 	if err != nil {
-		pu.log.Errorf("Error creating order: %v", err)
+		pu.log.WithContext(ctx).Errorf("Error creating order: %v", err)
 		return nil, err
 	}
 
-	pu.log.Infof("Created order: %+v", createdOrder)
+	pu.log.WithContext(ctx).Infof("Created order: %+v", createdOrder)
 	return &model.GetOrderResponse{
 		OrderID:               createdOrder.OrderID,
 		CustomerID:            createdOrder.CustomerID,
@@ -164,10 +176,10 @@ func (pu *orderUsecase) CreateOrder(ctx context.Context, order *model.CreateOrde
 }
 
 func (pu *orderUsecase) DeleteOrder(ctx context.Context, req *model.DeleteOrderRequest) error {
-	pu.log.Infof("Deleting order with ID: %d", req.OrderID)
+	pu.log.WithContext(ctx).Infof("Deleting order with ID: %d", req.OrderID)
 	order, err := pu.orderRepo.Get(ctx, req.OrderID)
 	if err != nil {
-		pu.log.Errorf("Error fetching order for deletion: %v", err)
+		pu.log.WithContext(ctx).Errorf("Error fetching order for deletion: %v", err)
 		return err
 	}
 
@@ -175,19 +187,19 @@ func (pu *orderUsecase) DeleteOrder(ctx context.Context, req *model.DeleteOrderR
 
 	_, err = pu.orderRepo.Update(ctx, order)
 	if err != nil {
-		pu.log.Errorf("Error updating order for deletion: %v", err)
+		pu.log.WithContext(ctx).Errorf("Error updating order for deletion: %v", err)
 		return err
 	}
 
-	pu.log.Infof("Deleted order with ID: %d", req.OrderID)
+	pu.log.WithContext(ctx).Infof("Deleted order with ID: %d", req.OrderID)
 	return nil
 }
 
 func (pu *orderUsecase) UpdateOrder(ctx context.Context, rep *model.UpdateOrderRequest) (*model.GetOrderResponse, error) {
-	pu.log.Infof("Updating order with ID: %d", rep.OrderID)
+	pu.log.WithContext(ctx).Infof("Updating order with ID: %d", rep.OrderID)
 	order, err := pu.orderRepo.Get(ctx, rep.OrderID)
 	if err != nil {
-		pu.log.Errorf("Error fetching order for update: %v", err)
+		pu.log.WithContext(ctx).Errorf("Error fetching order for update: %v", err)
 		return nil, err
 	}
 
@@ -206,11 +218,11 @@ func (pu *orderUsecase) UpdateOrder(ctx context.Context, rep *model.UpdateOrderR
 
 	updatedOrder, err := pu.orderRepo.Update(ctx, order)
 	if err != nil {
-		pu.log.Errorf("Error updating order: %v", err)
+		pu.log.WithContext(ctx).Errorf("Error updating order: %v", err)
 		return nil, err
 	}
 
-	pu.log.Infof("Updated order: %+v", updatedOrder)
+	pu.log.WithContext(ctx).Infof("Updated order: %+v", updatedOrder)
 	return &model.GetOrderResponse{
 		OrderID:               updatedOrder.OrderID,
 		CustomerID:            updatedOrder.CustomerID,
@@ -230,10 +242,10 @@ func (pu *orderUsecase) UpdateOrder(ctx context.Context, rep *model.UpdateOrderR
 }
 
 func (pu *orderUsecase) GetOrderList(ctx context.Context, req *model.GetOrdersRequest) (*util.PaginatedList[model.GetOrderResponse], error) {
-	pu.log.Infof("Fetching order list with request: %+v", req)
+	pu.log.WithContext(ctx).Infof("Fetching order list with request: %+v", req)
 	orders, err := pu.orderRepo.GetList(ctx, req)
 	if err != nil {
-		pu.log.Errorf("Error fetching order list: %v", err)
+		pu.log.WithContext(ctx).Errorf("Error fetching order list: %v", err)
 		return nil, err
 	}
 
@@ -267,7 +279,7 @@ func (pu *orderUsecase) GetOrderList(ctx context.Context, req *model.GetOrdersRe
 
 	list.GetTotalPages()
 
-	pu.log.Infof("Fetched %d orders", len(orderResponses))
+	pu.log.WithContext(ctx).Infof("Fetched %d orders", len(orderResponses))
 	return list, nil
 }
 
@@ -281,26 +293,28 @@ func (o *orderUsecase) PlaceOrder(ctx context.Context, userId, cartId, CourierID
 		return "", err
 	}
 
-	// Use a channel to capture errors from goroutines
-	errChan := make(chan error, 2)
-
-	// Publish Inventory Update Event in a goroutine
-	go func() {
-		errChan <- rabbitmq.PublishInventoryUpdateEvent(userId, cartId)
-	}()
-
-	// Publish Order Notification Event in a goroutine
-	go func() {
-		errChan <- rabbitmq.PublishOrderNotificationEvent(order.OrderID, paymentURL)
-	}()
+	event := events.OrderPlaced{
+		OrderID:       order.OrderID,
+		CustomerID:    userId,
+		CartID:        cartId,
+		VoucherID:     VoucherID,
+		TotalAmount:   order.TotalAmount,
+		PaymentMethod: paymentMethod,
+		PaymentURL:    paymentURL,
+	}
 
-	// Wait for both goroutines to finish and check for errors
-	for i := 0; i < 2; i++ {
-		if err := <-errChan; err != nil {
-			return "", err
-		}
+	// The order is placed once its details and payment exist, together with the event the
+	// outbox relay publishes to the product and mail services
+	_, err = o.orderRepo.ChangeStatusWithEvents(ctx, order.OrderID, constant.ORDER_STATUS_PENDING, constant.ORDER_STATUS_PLACED, event)
+	if err != nil {
+		o.log.WithContext(ctx).Errorf("Failed to place order: %v", err)
+		return "", err
 	}
 
+	metrics.OrderPlaced(paymentMethod)
+	if VoucherID != 0 {
+		metrics.VoucherRedeemed()
+	}
 	return paymentURL, nil
 }
 
@@ -325,100 +339,27 @@ func (o *orderUsecase) PlaceOrder(ctx context.Context, userId, cartId, CourierID
 // }
 
 func (o *orderUsecase) ProcessOrder(ctx context.Context, userId, cartId, CourierID, VoucherID int64, shipAddress, paymentMethod string, freight float64) (*model.GetOrderResponse, error) {
-	client := &http.Client{}
-
 	// Fetch cart items
-	cartItemReq := model.GetCartItemsRequest{
-		CartID: &cartId,
-	}
-	cartItemData, err := json.Marshal(cartItemReq)
-	if err != nil {
-		o.log.Errorf("Failed to marshal cart item request: %v", err)
-		return &model.GetOrderResponse{}, err
-	}
-
-	url := constant.CART_ITEM_SERVICE
-	req, err := http.NewRequestWithContext(ctx, "GET", url, bytes.NewBuffer(cartItemData))
+	productsList, err := o.clients.CartItem.List(ctx, clients.CartItemFilter{CartID: &cartId})
 	if err != nil {
-		o.log.Errorf("Failed to create cart item request: %v", err)
-		return &model.GetOrderResponse{}, err
-	}
-
-	resp, err := client.Do(req)
-	if err != nil {
-		o.log.Errorf("Failed to execute cart item request: %v", err)
-		return &model.GetOrderResponse{}, err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		o.log.Errorf("Cart item service returned non-OK status: %d", resp.StatusCode)
-		return &model.GetOrderResponse{}, fmt.Errorf("cart item service returned non-OK status: %d", resp.StatusCode)
-	}
-
-	var productsList []model.GetCartItemResponse
-	err = json.NewDecoder(resp.Body).Decode(&productsList)
-	if err != nil {
-		o.log.Errorf("Failed to decode cart item response: %v", err)
+		o.log.WithContext(ctx).Errorf("Failed to fetch cart items: %v", err)
 		return &model.GetOrderResponse{}, err
 	}
 
 	// Fetch product details and calculate total amount
 	totalAmount := 0.0
-	productDetails := make(map[int64]model.GetProductResponse)
+	productDetails := make(map[int64]*clients.Product)
 	for _, product := range productsList {
 		if _, exists := productDetails[product.ProductID]; !exists {
-			url := constant.PRODUCT_SERVICE + fmt.Sprintf("/%d", product.ProductID)
-			req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-			if err != nil {
-				o.log.Errorf("Failed to create product request: %v", err)
-				return &model.GetOrderResponse{}, err
-			}
-
-			resp, err := client.Do(req)
+			p, err := o.clients.Product.Get(ctx, product.ProductID)
 			if err != nil {
-				o.log.Errorf("Failed to execute product request: %v", err)
+				o.log.WithContext(ctx).Errorf("Failed to fetch product %d: %v", product.ProductID, err)
 				return &model.GetOrderResponse{}, err
 			}
-			defer resp.Body.Close()
-
-			if resp.StatusCode != http.StatusOK {
-				o.log.Errorf("Product service returned non-OK status: %d", resp.StatusCode)
-				return &model.GetOrderResponse{}, fmt.Errorf("product service returned non-OK status: %d", resp.StatusCode)
-			}
 
-			var p model.GetProductResponse
-			err = json.NewDecoder(resp.Body).Decode(&p)
+			discountPrice, err := o.clients.Product.PriceAfterDiscount(ctx, product.ProductID)
 			if err != nil {
-				o.log.Errorf("Failed to decode product response: %v", err)
-				return &model.GetOrderResponse{}, err
-			}
-
-			url = constant.PRODUCT_SERVICE + "/discount-price" + fmt.Sprintf("/%d", product.ProductID)
-
-			req, err = http.NewRequestWithContext(ctx, "GET", url, nil)
-
-			if err != nil {
-				o.log.Errorf("Failed to create product discount request: %v", err)
-				return &model.GetOrderResponse{}, err
-			}
-
-			resp, err = client.Do(req)
-			if err != nil {
-				o.log.Errorf("Failed to execute product discount request: %v", err)
-				return &model.GetOrderResponse{}, err
-			}
-			defer resp.Body.Close()
-
-			if resp.StatusCode != http.StatusOK {
-				o.log.Errorf("Product service returned non-OK status: %d", resp.StatusCode)
-				return &model.GetOrderResponse{}, fmt.Errorf("product service returned non-OK status: %d", resp.StatusCode)
-			}
-
-			var discountPrice float64
-			err = json.NewDecoder(resp.Body).Decode(&discountPrice)
-			if err != nil {
-				o.log.Errorf("Failed to decode product discount response: %v", err)
+				o.log.WithContext(ctx).Errorf("Failed to fetch the discounted price of product %d: %v", product.ProductID, err)
 				return &model.GetOrderResponse{}, err
 			}
 
@@ -431,87 +372,28 @@ func (o *orderUsecase) ProcessOrder(ctx context.Context, userId, cartId, Courier
 	}
 
 	// Fetch voucher details
-	url = constant.VOUCHER_SERVICE + fmt.Sprintf("/%d", VoucherID)
-	req, err = http.NewRequestWithContext(ctx, "GET", url, nil)
+	voucher, err := o.clients.Voucher.Get(ctx, VoucherID)
 	if err != nil {
-		o.log.Errorf("Failed to create voucher request: %v", err)
-		return &model.GetOrderResponse{}, err
-	}
-
-	resp, err = client.Do(req)
-	if err != nil {
-		o.log.Errorf("Failed to execute voucher request: %v", err)
-		return &model.GetOrderResponse{}, err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		o.log.Errorf("Voucher service returned non-OK status: %d", resp.StatusCode)
-		return &model.GetOrderResponse{}, fmt.Errorf("voucher service returned non-OK status: %d", resp.StatusCode)
-	}
-
-	var voucher model.GetVoucherResponse
-	err = json.NewDecoder(resp.Body).Decode(&voucher)
-	if err != nil {
-		o.log.Errorf("Failed to decode voucher response: %v", err)
+		o.log.WithContext(ctx).Errorf("Failed to fetch voucher: %v", err)
 		return &model.GetOrderResponse{}, err
 	}
 
 	// Check voucher usage
-	checkVoucherUsageRequest := model.CheckVoucherUsageRequest{
-		VoucherID: VoucherID,
-		Order: model.Order{
-			OrderID:               0,
-			CustomerID:            userId,
-			OrderDate:             time.Now(),
-			ShippingAddress:       shipAddress,
-			CourierID:             CourierID,
-			TotalAmount:           totalAmount,
-			OrderStatus:           "Pending",
-			FreightPrice:          freight,
-			EstimatedDeliveryDate: time.Now(),
-			ActualDeliveryDate:    time.Now(),
-			VoucherID:             VoucherID,
-			IsDeleted:             false,
-			CreatedAt:             time.Now(),
-			UpdatedAt:             time.Now(),
-		},
-	}
-
-	checkVoucherUsageRequestData, err := json.Marshal(checkVoucherUsageRequest)
-	if err != nil {
-		o.log.Errorf("Failed to marshal check voucher usage request: %v", err)
-		return &model.GetOrderResponse{}, err
-	}
-
-	url = constant.VOUCHER_SERVICE + "/check-usage"
-	req, err = http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(checkVoucherUsageRequestData))
-	if err != nil {
-		o.log.Errorf("Failed to create check voucher usage request: %v", err)
-		return &model.GetOrderResponse{}, err
-	}
-
-	resp, err = client.Do(req)
-	if err != nil {
-		o.log.Errorf("Failed to execute check voucher usage request: %v", err)
+	valid, err := o.clients.Voucher.CheckUsage(ctx, VoucherID, clients.VoucherOrder{
+		CustomerID:      userId,
+		TotalAmount:     totalAmount,
+		ShippingAddress: shipAddress,
+		CourierID:       CourierID,
+		FreightPrice:    freight,
+		VoucherID:       VoucherID,
+	})
+	if err != nil {
+		o.log.WithContext(ctx).Errorf("Failed to check voucher usage: %v", err)
 		return &model.GetOrderResponse{}, err
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		o.log.Errorf("Voucher service returned non-OK status: %d", resp.StatusCode)
-		return &model.GetOrderResponse{}, fmt.Errorf("voucher service returned non-OK status: %d", resp.StatusCode)
-	}
 
-	var checkVoucherResponse model.CheckVoucherUsageResponse
-	err = json.NewDecoder(resp.Body).Decode(&checkVoucherResponse)
-	if err != nil {
-		o.log.Errorf("Failed to decode check voucher usage response: %v", err)
-		return &model.GetOrderResponse{}, err
-	}
-
-	o.log.Infof("Voucher validity: %v", checkVoucherResponse.Valid)
-	if !checkVoucherResponse.Valid {
+	o.log.WithContext(ctx).Infof("Voucher validity: %v", valid)
+	if !valid {
 		return &model.GetOrderResponse{}, fmt.Errorf("Voucher is not valid")
 	}
 
@@ -536,166 +418,64 @@ func (o *orderUsecase) ProcessOrder(ctx context.Context, userId, cartId, Courier
 		FreightPrice:          freight,
 		EstimatedDeliveryDate: time.Now(),
 		ActualDeliveryDate:    time.Now(),
-		OrderStatus:           "Pending",
+		OrderStatus:           constant.ORDER_STATUS_PENDING,
 	}
 
 	createdOrder, err := o.CreateOrder(ctx, &newOrder)
 	if err != nil {
-		o.log.Errorf("Failed to create order: %v", err)
+		o.log.WithContext(ctx).Errorf("Failed to create order: %v", err)
 		return &model.GetOrderResponse{}, err
 	}
 
 	// Create order details
 	for _, item := range productsList {
-		product := productDetails[item.ProductID]
-
-		createOrderDetailRequest := model.CreateOrderDetailRequest{
+		err := o.clients.OrderDetail.Create(ctx, clients.OrderDetail{
 			OrderID:   createdOrder.OrderID,
 			ProductID: item.ProductID,
 			Quantity:  item.Quantity,
-			UnitPrice: product.Price,
-		}
-
-		orderDetailsData, err := json.Marshal(createOrderDetailRequest)
-		if err != nil {
-			o.log.Errorf("Failed to marshal order detail request: %v", err)
-			return &model.GetOrderResponse{}, err
-		}
-
-		url = constant.ORDER_DETAILS_SERVICE
-		req, err = http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(orderDetailsData))
-		if err != nil {
-			o.log.Errorf("Failed to create order detail request: %v", err)
-			return &model.GetOrderResponse{}, err
-		}
-
-		resp, err = client.Do(req)
+			UnitPrice: productDetails[item.ProductID].Price,
+		})
 		if err != nil {
-			o.log.Errorf("Failed to execute order detail request: %v", err)
+			o.log.WithContext(ctx).Errorf("Failed to create order detail: %v", err)
 			return &model.GetOrderResponse{}, err
 		}
-		defer resp.Body.Close()
-
-		if resp.StatusCode != http.StatusOK {
-			o.log.Errorf("Order detail service returned non-OK status: %d", resp.StatusCode)
-			return &model.GetOrderResponse{}, fmt.Errorf("order detail service returned non-OK status: %d", resp.StatusCode)
-		}
 	}
 
 	return createdOrder, nil
 }
 
 func (o *orderUsecase) ProcessPayment(ctx context.Context, order *model.GetOrderResponse, paymentMethod string) (string, error) {
-	payment := model.CreatePaymentRequest{
+	// Payments are recorded by the services, never by the customer directly
+	ctx = authz.AsSystem(ctx)
+
+	err := o.clients.Payment.Create(ctx, clients.CreatePayment{
 		OrderID:       order.OrderID,
 		PaymentAmount: order.TotalAmount,
 		PaymentMethod: paymentMethod,
 		PaymentStatus: constant.PAYMENT_STATUS_PENDING,
-	}
-
-	paymentRequest, err := json.Marshal(payment)
+	})
 	if err != nil {
-		o.log.Errorf("Failed to marshal payment data: %v", err)
+		o.log.WithContext(ctx).Errorf("Failed to create payment: %v", err)
 		return "", err
 	}
 
-	url := constant.PAYMENT_SERVICE
-	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(paymentRequest))
-	if err != nil {
-		o.log.Errorf("Failed to create request: %v", err)
-		return "", err
+	var gateway *clients.GatewayClient
+	switch paymentMethod {
+	case constant.PAYMENT_METHOD_MOMO:
+		gateway = o.clients.MoMo
+	case constant.PAYMENT_METHOD_VNPAY:
+		gateway = o.clients.VnPay
+	default:
+		return "", nil
 	}
 
-	// Set the context and execute the request
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	paymentURL, err := gateway.PaymentURL(ctx, order.OrderID, order.TotalAmount)
 	if err != nil {
-		o.log.Errorf("Failed to execute request: %v", err)
+		o.log.WithContext(ctx).Errorf("Failed to get the %s payment URL: %v", paymentMethod, err)
 		return "", err
 	}
-	defer resp.Body.Close()
-
-	// Check if the request was successful
-	if resp.StatusCode != http.StatusOK {
-		o.log.Errorf("Payment service returned non-OK status: %d", resp.StatusCode)
-		return "", fmt.Errorf("Payment service returned non-OK status: %d", resp.StatusCode)
-	}
-
-	if paymentMethod == constant.PAYMENT_METHOD_MOMO {
-		return o.processMomoPayment(ctx, order)
-	}
-
-	if paymentMethod == constant.PAYMENT_METHOD_VNPAY {
-		return o.processVnPayPayment(ctx, order)
-	}
-
-	return "", nil
-}
 
-func (o *orderUsecase) processMomoPayment(ctx context.Context, order *model.GetOrderResponse) (string, error) {
-	url := constant.MOMO_SERVICE + "?amount=" + fmt.Sprintf("%.2f", order.TotalAmount) + "&orderID=" + fmt.Sprintf("%d", order.OrderID)
+	o.log.WithContext(ctx).Infof("Received %s URL: %s", paymentMethod, paymentURL)
 
-	req, err := http.NewRequestWithContext(ctx, "POST", url, nil)
-	if err != nil {
-		o.log.Errorf("Failed to create request: %v", err)
-		return "", err
-	}
-
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		o.log.Errorf("Failed to execute request: %v", err)
-		return "", err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		o.log.Errorf("Momo service returned non-OK status: %d", resp.StatusCode)
-		return "", fmt.Errorf("Momo service returned non-OK status: %d", resp.StatusCode)
-	}
-
-	var paymentUrl model.MoMoResponse
-	err = json.NewDecoder(resp.Body).Decode(&paymentUrl)
-	if err != nil {
-		o.log.Errorf("Failed to decode cart item response: %v", err)
-		return paymentUrl.PaymentURL, err
-	}
-
-	o.log.Infof("Received MoMo URL: %s", paymentUrl.PaymentURL)
-
-	return paymentUrl.PaymentURL, nil
-}
-
-func (o *orderUsecase) processVnPayPayment(ctx context.Context, order *model.GetOrderResponse) (string, error) {
-	url := constant.VNPAY_SERVICE + "?amount=" + fmt.Sprintf("%.2f", order.TotalAmount) + "&orderID=" + fmt.Sprintf("%d", order.OrderID)
-
-	req, err := http.NewRequestWithContext(ctx, "POST", url, nil)
-	if err != nil {
-		o.log.Errorf("Failed to create request: %v", err)
-		return "", err
-	}
-
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		o.log.Errorf("Failed to execute request: %v", err)
-		return "", err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		o.log.Errorf("VnPay service returned non-OK status: %d", resp.StatusCode)
-		return "", fmt.Errorf("VnPay service returned non-OK status: %d", resp.StatusCode)
-	}
-
-	bodyBytes, err := io.ReadAll(resp.Body)
-	if err != nil {
-		o.log.Errorf("Failed to read response body: %v", err)
-		return "", err
-	}
-	returnUrl := string(bodyBytes)
-
-	o.log.Infof("Received VnPay URL: %s", returnUrl)
-
-	return returnUrl, nil
+	return paymentURL, nil
 }
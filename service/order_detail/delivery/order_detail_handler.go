@@ -8,13 +8,13 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
-func GetOrderDetailByID(c *gin.Context) {
-	module := dependency_injection.NewOrderDetailUsecaseProvider()
+func (h *handler) GetOrderDetailByID(c *gin.Context) {
+	module := dependency_injection.NewOrderDetailUsecaseProvider(h.cfg)
 
 	var req model.GetOrderDetailRequest
-	err := c.BindJSON(&req)
+	err := c.ShouldBindQuery(&req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(400, gin.H{
 			"error": "Bad Request",
 		})
@@ -23,7 +23,7 @@ func GetOrderDetailByID(c *gin.Context) {
 
 	orderDetail, err := module.GetOrderDetail(c, &req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(500, gin.H{
 			"error": "Internal Server Error",
 		})
@@ -33,22 +33,22 @@ func GetOrderDetailByID(c *gin.Context) {
 	c.JSON(200, orderDetail)
 }
 
-func CreateOrderDetail(c *gin.Context) {
+func (h *handler) CreateOrderDetail(c *gin.Context) {
 	var req model.CreateOrderDetailRequest
 	err := c.BindJSON(&req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(400, gin.H{
 			"error": "Bad Request",
 		})
 		return
 	}
 
-	module := dependency_injection.NewOrderDetailUsecaseProvider()
+	module := dependency_injection.NewOrderDetailUsecaseProvider(h.cfg)
 
 	orderDetail, err := module.CreateOrderDetail(c, &req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(500, gin.H{
 			"error": "Internal Server Error",
 		})
@@ -58,22 +58,22 @@ func CreateOrderDetail(c *gin.Context) {
 	c.JSON(200, orderDetail)
 }
 
-func UpdateOrderDetail(c *gin.Context) {
+func (h *handler) UpdateOrderDetail(c *gin.Context) {
 	var req model.UpdateOrderDetailRequest
 	err := c.BindJSON(&req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(400, gin.H{
 			"error": "Bad Request",
 		})
 		return
 	}
 
-	module := dependency_injection.NewOrderDetailUsecaseProvider()
+	module := dependency_injection.NewOrderDetailUsecaseProvider(h.cfg)
 
 	orderDetail, err := module.UpdateOrderDetail(c, &req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(500, gin.H{
 			"error": "Internal Server Error",
 		})
@@ -83,13 +83,13 @@ func UpdateOrderDetail(c *gin.Context) {
 	c.JSON(200, orderDetail)
 }
 
-func DeleteOrderDetail(c *gin.Context) {
-	module := dependency_injection.NewOrderDetailUsecaseProvider()
+func (h *handler) DeleteOrderDetail(c *gin.Context) {
+	module := dependency_injection.NewOrderDetailUsecaseProvider(h.cfg)
 
 	var req model.DeleteOrderDetailRequest
 	err := c.BindJSON(&req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(400, gin.H{
 			"error": "Bad Request",
 		})
@@ -98,7 +98,7 @@ func DeleteOrderDetail(c *gin.Context) {
 
 	err = module.DeleteOrderDetail(c, &req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(500, gin.H{
 			"error": "Internal Server Error",
 		})
@@ -110,13 +110,13 @@ func DeleteOrderDetail(c *gin.Context) {
 	})
 }
 
-func GetOrderDetails(c *gin.Context) {
-	module := dependency_injection.NewOrderDetailUsecaseProvider()
+func (h *handler) GetOrderDetails(c *gin.Context) {
+	module := dependency_injection.NewOrderDetailUsecaseProvider(h.cfg)
 
 	var req model.GetOrderDetailsRequest
-	err := c.BindJSON(&req)
+	err := c.ShouldBindQuery(&req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(400, gin.H{
 			"error": "Bad Request",
 		})
@@ -125,7 +125,7 @@ func GetOrderDetails(c *gin.Context) {
 
 	orderDetails, err := module.GetOrderDetailList(c, &req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(500, gin.H{
 			"error": "Internal Server Error",
 		})
@@ -1,19 +1,38 @@
 package delivery
 
 import (
+	"th3y3m/e-commerce-microservices/pkg/authz"
+	"th3y3m/e-commerce-microservices/pkg/logging"
+	"th3y3m/e-commerce-microservices/pkg/metrics"
+	"th3y3m/e-commerce-microservices/pkg/tracing"
+	"th3y3m/e-commerce-microservices/service/order_detail/config"
+
 	"github.com/gin-gonic/gin"
 )
 
-func RegisterHandlers() *gin.Engine {
-	r := gin.Default()
+// handler serves the routes of the service, with the usecases built from cfg
+type handler struct {
+	cfg config.Config
+}
+
+func RegisterHandlers(cfg config.Config) *gin.Engine {
+	h := &handler{cfg: cfg}
+	keys := authz.NewKeys(cfg.Auth)
+
+	r := gin.New()
+	// Lets the request context, and the trace in it, reach the usecases through *gin.Context
+	r.ContextWithFallback = true
+	r.Use(gin.Recovery(), tracing.Middleware("order_detail"), logging.Middleware(), metrics.Middleware())
+	r.GET(metrics.Path, metrics.Handler())
+	r.Use(keys.Middleware())
 
 	orderDetail := r.Group("/api/orderDetails")
 	{
-		orderDetail.GET("/GetOrderDetailByID", GetOrderDetailByID)
-		orderDetail.GET("", GetOrderDetails)
-		orderDetail.POST("", CreateOrderDetail)
-		orderDetail.PUT("", UpdateOrderDetail)
-		orderDetail.DELETE("", DeleteOrderDetail)
+		orderDetail.GET("/GetOrderDetailByID", h.GetOrderDetailByID)
+		orderDetail.GET("", h.GetOrderDetails)
+		orderDetail.POST("", h.CreateOrderDetail)
+		orderDetail.PUT("", h.UpdateOrderDetail)
+		orderDetail.DELETE("", h.DeleteOrderDetail)
 	}
 
 	return r
@@ -1,21 +1,21 @@
 package dependency_injection
 
 import (
+	"th3y3m/e-commerce-microservices/pkg/logging"
 	"th3y3m/e-commerce-microservices/pkg/postgresql"
 	redis_client "th3y3m/e-commerce-microservices/pkg/redis"
+	"th3y3m/e-commerce-microservices/service/order_detail/config"
 	"th3y3m/e-commerce-microservices/service/order_detail/repository"
 	"th3y3m/e-commerce-microservices/service/order_detail/usecase"
-
-	"github.com/sirupsen/logrus"
 )
 
-func NewOrderDetailRepositoryProvider() repository.IOrderDetailRepository {
-	log := logrus.New()
-	db, err := postgresql.NewGormDB()
+func NewOrderDetailRepositoryProvider(cfg config.Config) repository.IOrderDetailRepository {
+	log := logging.Logger()
+	db, err := postgresql.NewGormDB(cfg.Postgres)
 	if err != nil {
 		log.Error(err)
 	}
-	redis, err := redis_client.ConnectToRedis()
+	redis, err := redis_client.ConnectToRedis(cfg.Redis)
 	if err != nil {
 		log.Error(err)
 	}
@@ -23,8 +23,8 @@ func NewOrderDetailRepositoryProvider() repository.IOrderDetailRepository {
 	return repository.NewOrderDetailRepository(db, redis, log)
 }
 
-func NewOrderDetailUsecaseProvider() usecase.IOrderDetailUsecase {
-	log := logrus.New()
-	orderDetailRepository := NewOrderDetailRepositoryProvider()
+func NewOrderDetailUsecaseProvider(cfg config.Config) usecase.IOrderDetailUsecase {
+	log := logging.Logger()
+	orderDetailRepository := NewOrderDetailRepositoryProvider(cfg)
 	return usecase.NewOrderDetailUsecase(orderDetailRepository, log)
 }
@@ -1,13 +1,13 @@
 package model
 
 type GetOrderDetailRequest struct {
-	OrderID   int64 `json:"order_id"`
-	ProductID int64 `json:"product_id"`
+	OrderID   int64 `json:"order_id" form:"order_id"`
+	ProductID int64 `json:"product_id" form:"product_id"`
 }
 
 type GetOrderDetailsRequest struct {
-	OrderID   *int64 `json:"order_id"`
-	ProductID *int64 `json:"product_id"`
+	OrderID   *int64 `json:"order_id" form:"order_id"`
+	ProductID *int64 `json:"product_id" form:"product_id"`
 }
 
 type DeleteOrderDetailRequest struct {
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"th3y3m/e-commerce-microservices/pkg/metrics"
 	"th3y3m/e-commerce-microservices/service/order_detail/model"
 
 	"github.com/redis/go-redis/v9"
@@ -34,7 +35,7 @@ func NewOrderDetailRepository(db *gorm.DB, redis *redis.Client, log *logrus.Logg
 }
 
 func (pr *orderDetailRepository) Get(ctx context.Context, orderID, productID int64) (*OrderDetail, error) {
-	pr.log.Infof("Fetching orderDetail with ID: %d and productID: %d", orderID, productID)
+	pr.log.WithContext(ctx).Infof("Fetching orderDetail with ID: %d and productID: %d", orderID, productID)
 	var orderDetail OrderDetail
 	cacheKey := fmt.Sprintf("orderDetail:%d - %d", orderID, productID)
 
@@ -43,19 +44,21 @@ func (pr *orderDetailRepository) Get(ctx context.Context, orderID, productID int
 		cachedOrderDetail, err := pr.redis.Get(ctx, cacheKey).Result()
 		if err == nil {
 			if err := json.Unmarshal([]byte(cachedOrderDetail), &orderDetail); err == nil {
-				pr.log.Infof("OrderDetail found in cache: %d:%d", orderID, productID)
+				pr.log.WithContext(ctx).Infof("OrderDetail found in cache: %d:%d", orderID, productID)
+				metrics.CacheHit("orderDetailRepository.Get")
 				return &orderDetail, nil
 			}
 		} else if err != redis.Nil {
-			pr.log.Warnf("Failed to get orderDetail from Redis: %v", err)
+			pr.log.WithContext(ctx).Warnf("Failed to get orderDetail from Redis: %v", err)
 		}
+		metrics.CacheMiss("orderDetailRepository.Get")
 	} else {
-		pr.log.Warn("Redis client is not initialized")
+		pr.log.WithContext(ctx).Warn("Redis client is not initialized")
 	}
 
 	// If not found in cache, get from database
 	if err := pr.db.WithContext(ctx).Where("order_id = ? AND product_id = ?", orderID, productID).First(&orderDetail).Error; err != nil {
-		pr.log.Errorf("Error fetching orderDetail from database: %v", err)
+		pr.log.WithContext(ctx).Errorf("Error fetching orderDetail from database: %v", err)
 		return nil, err
 	}
 
@@ -63,9 +66,9 @@ func (pr *orderDetailRepository) Get(ctx context.Context, orderID, productID int
 	if pr.redis != nil {
 		orderDetailJSON, _ := json.Marshal(orderDetail)
 		if err := pr.redis.Set(ctx, cacheKey, orderDetailJSON, 0).Err(); err != nil {
-			pr.log.Warnf("Failed to save orderDetail to Redis: %v", err)
+			pr.log.WithContext(ctx).Warnf("Failed to save orderDetail to Redis: %v", err)
 		} else {
-			pr.log.Infof("OrderDetail saved to cache: %d", orderID)
+			pr.log.WithContext(ctx).Infof("OrderDetail saved to cache: %d", orderID)
 		}
 	}
 
@@ -73,9 +76,9 @@ func (pr *orderDetailRepository) Get(ctx context.Context, orderID, productID int
 }
 
 func (pr *orderDetailRepository) Create(ctx context.Context, orderDetail *OrderDetail) (*OrderDetail, error) {
-	pr.log.Infof("Creating orderDetail: %+v", orderDetail)
+	pr.log.WithContext(ctx).Infof("Creating orderDetail: %+v", orderDetail)
 	if err := pr.db.WithContext(ctx).Create(orderDetail).Error; err != nil {
-		pr.log.Errorf("Error creating orderDetail: %v", err)
+		pr.log.WithContext(ctx).Errorf("Error creating orderDetail: %v", err)
 		return nil, err
 	}
 	cacheKey := fmt.Sprintf("orderDetail:%d - %d", orderDetail.OrderID, orderDetail.ProductID)
@@ -84,16 +87,16 @@ func (pr *orderDetailRepository) Create(ctx context.Context, orderDetail *OrderD
 	if pr.redis != nil {
 		orderDetailJSON, _ := json.Marshal(orderDetail)
 		if err := pr.redis.Set(ctx, cacheKey, orderDetailJSON, 0).Err(); err != nil {
-			pr.log.Warnf("Failed to save orderDetail to Redis: %v", err)
+			pr.log.WithContext(ctx).Warnf("Failed to save orderDetail to Redis: %v", err)
 		} else {
-			pr.log.Infof("OrderDetail saved to cache: %d - %d", orderDetail.OrderID, orderDetail.ProductID)
+			pr.log.WithContext(ctx).Infof("OrderDetail saved to cache: %d - %d", orderDetail.OrderID, orderDetail.ProductID)
 		}
 
 		// Invalidate the cache for all records
 		if err := pr.redis.Del(ctx, "all_orderDetails").Err(); err != nil {
-			pr.log.Warnf("Failed to invalidate all orderDetails cache: %v", err)
+			pr.log.WithContext(ctx).Warnf("Failed to invalidate all orderDetails cache: %v", err)
 		} else {
-			pr.log.Info("Invalidated cache for all orderDetails")
+			pr.log.WithContext(ctx).Info("Invalidated cache for all orderDetails")
 		}
 	}
 
@@ -101,9 +104,9 @@ func (pr *orderDetailRepository) Create(ctx context.Context, orderDetail *OrderD
 }
 
 func (pr *orderDetailRepository) Update(ctx context.Context, orderDetail *OrderDetail) (*OrderDetail, error) {
-	pr.log.Infof("Updating orderDetail: %+v", orderDetail)
+	pr.log.WithContext(ctx).Infof("Updating orderDetail: %+v", orderDetail)
 	if err := pr.db.WithContext(ctx).Save(orderDetail).Error; err != nil {
-		pr.log.Errorf("Error updating orderDetail: %v", err)
+		pr.log.WithContext(ctx).Errorf("Error updating orderDetail: %v", err)
 		return nil, err
 	}
 	cacheKey := fmt.Sprintf("orderDetail:%d - %d", orderDetail.OrderID, orderDetail.ProductID)
@@ -112,16 +115,16 @@ func (pr *orderDetailRepository) Update(ctx context.Context, orderDetail *OrderD
 	if pr.redis != nil {
 		orderDetailJSON, _ := json.Marshal(orderDetail)
 		if err := pr.redis.Set(ctx, cacheKey, orderDetailJSON, 0).Err(); err != nil {
-			pr.log.Warnf("Failed to save orderDetail to Redis: %v", err)
+			pr.log.WithContext(ctx).Warnf("Failed to save orderDetail to Redis: %v", err)
 		} else {
-			pr.log.Infof("OrderDetail saved to cache: %d - %d", orderDetail.OrderID, orderDetail.ProductID)
+			pr.log.WithContext(ctx).Infof("OrderDetail saved to cache: %d - %d", orderDetail.OrderID, orderDetail.ProductID)
 		}
 
 		// Invalidate the cache for all records
 		if err := pr.redis.Del(ctx, "all_orderDetails").Err(); err != nil {
-			pr.log.Warnf("Failed to invalidate all orderDetails cache: %v", err)
+			pr.log.WithContext(ctx).Warnf("Failed to invalidate all orderDetails cache: %v", err)
 		} else {
-			pr.log.Info("Invalidated cache for all orderDetails")
+			pr.log.WithContext(ctx).Info("Invalidated cache for all orderDetails")
 		}
 	}
 
@@ -130,15 +133,15 @@ func (pr *orderDetailRepository) Update(ctx context.Context, orderDetail *OrderD
 }
 
 func (pr *orderDetailRepository) Delete(ctx context.Context, orderID, productID int64) error {
-	pr.log.Infof("Deleting orderDetail with ID: %d and productID: %d", orderID, productID)
+	pr.log.WithContext(ctx).Infof("Deleting orderDetail with ID: %d and productID: %d", orderID, productID)
 	orderDetail, err := pr.Get(ctx, orderID, productID)
 	if err != nil {
-		pr.log.Errorf("Error fetching orderDetail for deletion: %v", err)
+		pr.log.WithContext(ctx).Errorf("Error fetching orderDetail for deletion: %v", err)
 		return err
 	}
 
 	if err := pr.db.WithContext(ctx).Delete(orderDetail).Error; err != nil {
-		pr.log.Errorf("Error deleting orderDetail: %v", err)
+		pr.log.WithContext(ctx).Errorf("Error deleting orderDetail: %v", err)
 		return err
 	}
 
@@ -147,16 +150,16 @@ func (pr *orderDetailRepository) Delete(ctx context.Context, orderID, productID
 	// Delete from cache if Redis is available
 	if pr.redis != nil {
 		if err := pr.redis.Del(ctx, cacheKey).Err(); err != nil {
-			pr.log.Warnf("Failed to delete orderDetail from Redis: %v", err)
+			pr.log.WithContext(ctx).Warnf("Failed to delete orderDetail from Redis: %v", err)
 		} else {
-			pr.log.Infof("OrderDetail deleted from cache: %d", orderID)
+			pr.log.WithContext(ctx).Infof("OrderDetail deleted from cache: %d", orderID)
 		}
 
 		// Invalidate the cache for all records
 		if err := pr.redis.Del(ctx, "all_orderDetails").Err(); err != nil {
-			pr.log.Warnf("Failed to invalidate all orderDetails cache: %v", err)
+			pr.log.WithContext(ctx).Warnf("Failed to invalidate all orderDetails cache: %v", err)
 		} else {
-			pr.log.Info("Invalidated cache for all orderDetails")
+			pr.log.WithContext(ctx).Info("Invalidated cache for all orderDetails")
 		}
 	}
 
@@ -164,7 +167,7 @@ func (pr *orderDetailRepository) Delete(ctx context.Context, orderID, productID
 }
 
 func (pr *orderDetailRepository) GetList(ctx context.Context, req *model.GetOrderDetailsRequest) ([]*OrderDetail, error) {
-	pr.log.Infof("Fetching orderDetails with request: %+v", req)
+	pr.log.WithContext(ctx).Infof("Fetching orderDetails with request: %+v", req)
 	var orderDetails []*OrderDetail
 	cacheKey := "all_orderDetails"
 
@@ -173,31 +176,33 @@ func (pr *orderDetailRepository) GetList(ctx context.Context, req *model.GetOrde
 		cachedOrderDetails, err := pr.redis.Get(ctx, cacheKey).Result()
 		if err == nil {
 			if err := json.Unmarshal([]byte(cachedOrderDetails), &orderDetails); err == nil {
-				pr.log.Infof("OrderDetails found in cache: %d", req.OrderID)
+				pr.log.WithContext(ctx).Infof("OrderDetails found in cache: %d", req.OrderID)
+				metrics.CacheHit("orderDetailRepository.GetList")
 				return orderDetails, nil
 			}
 		} else if err != redis.Nil {
-			pr.log.Warnf("Failed to get orderDetails from Redis: %v", err)
+			pr.log.WithContext(ctx).Warnf("Failed to get orderDetails from Redis: %v", err)
 		}
+		metrics.CacheMiss("orderDetailRepository.GetList")
 	} else {
-		pr.log.Warn("Redis client is not initialized")
+		pr.log.WithContext(ctx).Warn("Redis client is not initialized")
 	}
 
 	// If not found in cache, get from database
 	if req.OrderID != nil && req.ProductID == nil {
 		if err := pr.db.WithContext(ctx).Where("order_id = ?", req.OrderID).Find(&orderDetails).Error; err != nil {
-			pr.log.Errorf("Error fetching orderDetails from database: %v", err)
+			pr.log.WithContext(ctx).Errorf("Error fetching orderDetails from database: %v", err)
 			return nil, err
 		}
 
 	} else if req.OrderID == nil && req.ProductID != nil {
 		if err := pr.db.WithContext(ctx).Where("product_id = ?", req.ProductID).Find(&orderDetails).Error; err != nil {
-			pr.log.Errorf("Error fetching orderDetails from database: %v", err)
+			pr.log.WithContext(ctx).Errorf("Error fetching orderDetails from database: %v", err)
 			return nil, err
 		}
 	} else {
 		if err := pr.db.WithContext(ctx).Find(&orderDetails).Error; err != nil {
-			pr.log.Errorf("Error fetching orderDetails from database: %v", err)
+			pr.log.WithContext(ctx).Errorf("Error fetching orderDetails from database: %v", err)
 			return nil, err
 		}
 	}
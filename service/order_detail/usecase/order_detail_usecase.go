@@ -29,14 +29,14 @@ func NewOrderDetailUsecase(orderDetailRepo repository.IOrderDetailRepository, lo
 }
 
 func (pu *orderDetailUsecase) GetOrderDetail(ctx context.Context, req *model.GetOrderDetailRequest) (*model.GetOrderDetailResponse, error) {
-	pu.log.Infof("Fetching orderDetail with OrderID: %d and ProductID: %d", req.OrderID, req.ProductID)
+	pu.log.WithContext(ctx).Infof("Fetching orderDetail with OrderID: %d and ProductID: %d", req.OrderID, req.ProductID)
 	orderDetail, err := pu.orderDetailRepo.Get(ctx, req.OrderID, req.ProductID)
 	if err != nil {
-		pu.log.Errorf("Error fetching orderDetail: %v", err)
+		pu.log.WithContext(ctx).Errorf("Error fetching orderDetail: %v", err)
 		return nil, err
 	}
 
-	pu.log.Infof("Fetched orderDetail: %+v", orderDetail)
+	pu.log.WithContext(ctx).Infof("Fetched orderDetail: %+v", orderDetail)
 	return &model.GetOrderDetailResponse{
 		OrderID:   orderDetail.OrderID,
 		ProductID: orderDetail.ProductID,
@@ -46,7 +46,7 @@ func (pu *orderDetailUsecase) GetOrderDetail(ctx context.Context, req *model.Get
 }
 
 func (pu *orderDetailUsecase) CreateOrderDetail(ctx context.Context, orderDetail *model.CreateOrderDetailRequest) (*model.GetOrderDetailResponse, error) {
-	pu.log.Infof("Creating orderDetail: %+v", orderDetail)
+	pu.log.WithContext(ctx).Infof("Creating orderDetail: %+v", orderDetail)
 	orderDetailData := repository.OrderDetail{
 		OrderID:   orderDetail.OrderID,
 		ProductID: orderDetail.ProductID,
@@ -56,11 +56,11 @@ func (pu *orderDetailUsecase) CreateOrderDetail(ctx context.Context, orderDetail
 
 	createdOrderDetail, err := pu.orderDetailRepo.Create(ctx, &orderDetailData)
 	if err != nil {
-		pu.log.Errorf("Error creating orderDetail: %v", err)
+		pu.log.WithContext(ctx).Errorf("Error creating orderDetail: %v", err)
 		return nil, err
 	}
 
-	pu.log.Infof("Created orderDetail: %+v", createdOrderDetail)
+	pu.log.WithContext(ctx).Infof("Created orderDetail: %+v", createdOrderDetail)
 	return &model.GetOrderDetailResponse{
 		OrderID:   createdOrderDetail.OrderID,
 		ProductID: createdOrderDetail.ProductID,
@@ -70,28 +70,28 @@ func (pu *orderDetailUsecase) CreateOrderDetail(ctx context.Context, orderDetail
 }
 
 func (pu *orderDetailUsecase) DeleteOrderDetail(ctx context.Context, req *model.DeleteOrderDetailRequest) error {
-	pu.log.Infof("Deleting orderDetail with OrderID: %d and ProductID: %d", req.OrderID, req.ProductID)
+	pu.log.WithContext(ctx).Infof("Deleting orderDetail with OrderID: %d and ProductID: %d", req.OrderID, req.ProductID)
 	orderDetail, err := pu.orderDetailRepo.Get(ctx, req.OrderID, req.ProductID)
 	if err != nil {
-		pu.log.Errorf("Error fetching orderDetail for deletion: %v", err)
+		pu.log.WithContext(ctx).Errorf("Error fetching orderDetail for deletion: %v", err)
 		return err
 	}
 
 	err = pu.orderDetailRepo.Delete(ctx, orderDetail.OrderID, orderDetail.ProductID)
 	if err != nil {
-		pu.log.Errorf("Error deleting orderDetail: %v", err)
+		pu.log.WithContext(ctx).Errorf("Error deleting orderDetail: %v", err)
 		return err
 	}
 
-	pu.log.Infof("Deleted orderDetail with OrderID: %d and ProductID: %d", req.OrderID, req.ProductID)
+	pu.log.WithContext(ctx).Infof("Deleted orderDetail with OrderID: %d and ProductID: %d", req.OrderID, req.ProductID)
 	return nil
 }
 
 func (pu *orderDetailUsecase) UpdateOrderDetail(ctx context.Context, rep *model.UpdateOrderDetailRequest) (*model.GetOrderDetailResponse, error) {
-	pu.log.Infof("Updating orderDetail: %+v", rep)
+	pu.log.WithContext(ctx).Infof("Updating orderDetail: %+v", rep)
 	orderDetail, err := pu.orderDetailRepo.Get(ctx, rep.OrderID, rep.ProductID)
 	if err != nil {
-		pu.log.Errorf("Error fetching orderDetail for update: %v", err)
+		pu.log.WithContext(ctx).Errorf("Error fetching orderDetail for update: %v", err)
 		return nil, err
 	}
 
@@ -100,11 +100,11 @@ func (pu *orderDetailUsecase) UpdateOrderDetail(ctx context.Context, rep *model.
 
 	updatedOrderDetail, err := pu.orderDetailRepo.Update(ctx, orderDetail)
 	if err != nil {
-		pu.log.Errorf("Error updating orderDetail: %v", err)
+		pu.log.WithContext(ctx).Errorf("Error updating orderDetail: %v", err)
 		return nil, err
 	}
 
-	pu.log.Infof("Updated orderDetail: %+v", updatedOrderDetail)
+	pu.log.WithContext(ctx).Infof("Updated orderDetail: %+v", updatedOrderDetail)
 	return &model.GetOrderDetailResponse{
 		OrderID:   updatedOrderDetail.OrderID,
 		ProductID: updatedOrderDetail.ProductID,
@@ -114,10 +114,10 @@ func (pu *orderDetailUsecase) UpdateOrderDetail(ctx context.Context, rep *model.
 }
 
 func (pu *orderDetailUsecase) GetOrderDetailList(ctx context.Context, req *model.GetOrderDetailsRequest) ([]*model.GetOrderDetailResponse, error) {
-	pu.log.Infof("Fetching orderDetails with OrderID: %d", req.OrderID)
+	pu.log.WithContext(ctx).Infof("Fetching orderDetails with OrderID: %d", req.OrderID)
 	orderDetails, err := pu.orderDetailRepo.GetList(ctx, req)
 	if err != nil {
-		pu.log.Errorf("Error fetching orderDetails: %v", err)
+		pu.log.WithContext(ctx).Errorf("Error fetching orderDetails: %v", err)
 		return nil, err
 	}
 
@@ -131,6 +131,6 @@ func (pu *orderDetailUsecase) GetOrderDetailList(ctx context.Context, req *model
 		})
 	}
 
-	pu.log.Infof("Fetched orderDetails: %+v", orderDetailResponses)
+	pu.log.WithContext(ctx).Infof("Fetched orderDetails: %+v", orderDetailResponses)
 	return orderDetailResponses, nil
 }
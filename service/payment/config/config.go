@@ -0,0 +1,10 @@
+package config
+
+import pkgconfig "th3y3m/e-commerce-microservices/pkg/config"
+
+// Config of the payment service
+type Config struct {
+	pkgconfig.Service
+	pkgconfig.RabbitMQ
+	pkgconfig.Outbox
+}
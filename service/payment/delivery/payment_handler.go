@@ -1,7 +1,11 @@
 package delivery
 
 import (
+	"errors"
+	"net/http"
 	"strconv"
+	"th3y3m/e-commerce-microservices/pkg/authz"
+	"th3y3m/e-commerce-microservices/pkg/clients"
 	"th3y3m/e-commerce-microservices/service/payment/dependency_injection"
 	"th3y3m/e-commerce-microservices/service/payment/model"
 
@@ -9,15 +13,15 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
-func GetPaymentByID(c *gin.Context) {
-	module := dependency_injection.NewPaymentUsecaseProvider()
+func (h *handler) GetPaymentByID(c *gin.Context) {
+	module := dependency_injection.NewPaymentUsecaseProvider(h.cfg)
 
 	var req model.GetPaymentRequest
 
 	id := c.Param("payment_id")
 	paymentID, err := strconv.ParseInt(id, 10, 64)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(400, gin.H{
 			"error": "Invalid Payment ID",
 		})
@@ -25,24 +29,33 @@ func GetPaymentByID(c *gin.Context) {
 	}
 	req.PaymentID = paymentID
 
+	caller, ok := authz.Authorize(c)
+	if !ok {
+		return
+	}
+
 	payment, err := module.GetPayment(c, &req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(500, gin.H{
 			"error": "Internal Server Error",
 		})
 		return
 	}
 
+	if !h.requireOrderOwner(c, caller, payment.OrderID) {
+		return
+	}
+
 	c.JSON(200, payment)
 }
 
-func GetAllPayments(c *gin.Context) {
-	module := dependency_injection.NewPaymentUsecaseProvider()
+func (h *handler) GetAllPayments(c *gin.Context) {
+	module := dependency_injection.NewPaymentUsecaseProvider(h.cfg)
 
 	payments, err := module.GetAllPayments(c)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(500, gin.H{
 			"error": "Internal Server Error",
 		})
@@ -52,22 +65,28 @@ func GetAllPayments(c *gin.Context) {
 	c.JSON(200, payments)
 }
 
-func CreatePayment(c *gin.Context) {
+func (h *handler) CreatePayment(c *gin.Context) {
 	var req model.CreatePaymentRequest
 	err := c.BindJSON(&req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(400, gin.H{
 			"error": "Bad Request",
 		})
 		return
 	}
 
-	module := dependency_injection.NewPaymentUsecaseProvider()
+	// Payments are recorded by the order and payment gateway services
+	caller, ok := authz.Authorize(c)
+	if !ok || !authz.RequireRole(c, caller, authz.RoleAdmin) {
+		return
+	}
+
+	module := dependency_injection.NewPaymentUsecaseProvider(h.cfg)
 
 	payment, err := module.CreatePayment(c, &req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(500, gin.H{
 			"error": "Internal Server Error",
 		})
@@ -77,22 +96,28 @@ func CreatePayment(c *gin.Context) {
 	c.JSON(200, payment)
 }
 
-func UpdatePayment(c *gin.Context) {
+func (h *handler) UpdatePayment(c *gin.Context) {
 	var req model.UpdatePaymentRequest
 	err := c.BindJSON(&req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(400, gin.H{
 			"error": "Bad Request",
 		})
 		return
 	}
 
-	module := dependency_injection.NewPaymentUsecaseProvider()
+	// Payments are recorded by the order and payment gateway services
+	caller, ok := authz.Authorize(c)
+	if !ok || !authz.RequireRole(c, caller, authz.RoleAdmin) {
+		return
+	}
+
+	module := dependency_injection.NewPaymentUsecaseProvider(h.cfg)
 
 	payment, err := module.UpdatePayment(c, &req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(500, gin.H{
 			"error": "Internal Server Error",
 		})
@@ -102,18 +127,36 @@ func UpdatePayment(c *gin.Context) {
 	c.JSON(200, payment)
 }
 
-func GetPaginatedPayment(c *gin.Context) {
-	module := dependency_injection.NewPaymentUsecaseProvider()
+func (h *handler) GetPaginatedPayment(c *gin.Context) {
+	module := dependency_injection.NewPaymentUsecaseProvider(h.cfg)
 
 	var req model.GetPaymentsRequest
 	err := c.BindJSON(&req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(400, gin.H{
 			"error": "Bad Request",
 		})
 		return
 	}
+
+	// Customers list the payments of one of their orders at a time
+	caller, ok := authz.Authorize(c)
+	if !ok || !authz.RequireUser(c, caller) {
+		return
+	}
+	if !caller.Privileged() {
+		if req.OrderID == nil {
+			c.JSON(403, gin.H{
+				"error": "Access denied",
+			})
+			return
+		}
+		if !h.requireOrderOwner(c, caller, *req.OrderID) {
+			return
+		}
+	}
+
 	if req.Paging.PageIndex == 0 {
 		req.Paging.PageIndex = 1
 	}
@@ -122,7 +165,7 @@ func GetPaginatedPayment(c *gin.Context) {
 	}
 	payments, err := module.GetPaymentList(c, &req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(500, gin.H{
 			"error": "Internal Server Error",
 		})
@@ -131,3 +174,33 @@ func GetPaginatedPayment(c *gin.Context) {
 
 	c.JSON(200, payments)
 }
+
+// requireOrderOwner asks the order service, on behalf of the caller, whether the caller may
+// read the order. The order service applies its own ownership rules.
+func (h *handler) requireOrderOwner(c *gin.Context, caller *authz.Caller, orderID int64) bool {
+	if caller.Privileged() {
+		return true
+	}
+	// Anonymous callers have no orders, the order service would only answer 401
+	if !authz.RequireUser(c, caller) {
+		return false
+	}
+
+	_, err := dependency_injection.NewOrderClientProvider(h.cfg, h.services).Get(c, orderID, clients.OnBehalfOf(c.Request.Header))
+	if err == nil {
+		return true
+	}
+
+	var apiErr *clients.Error
+	if errors.As(err, &apiErr) && (apiErr.StatusCode == http.StatusUnauthorized || apiErr.StatusCode == http.StatusForbidden) {
+		c.JSON(403, gin.H{
+			"error": "Access denied",
+		})
+		return false
+	}
+	logrus.WithContext(c).Errorf("Checking the owner of order %d: %v", orderID, err)
+	c.JSON(500, gin.H{
+		"error": "Internal Server Error",
+	})
+	return false
+}
@@ -1,19 +1,52 @@
 package delivery
 
 import (
+	"context"
+	"net/http"
+	"th3y3m/e-commerce-microservices/pkg/authz"
+	"th3y3m/e-commerce-microservices/pkg/discovery"
+	"th3y3m/e-commerce-microservices/pkg/logging"
+	"th3y3m/e-commerce-microservices/pkg/metrics"
+	"th3y3m/e-commerce-microservices/pkg/outbox"
+	"th3y3m/e-commerce-microservices/pkg/tracing"
+	"th3y3m/e-commerce-microservices/service/payment/config"
+	"th3y3m/e-commerce-microservices/service/payment/dependency_injection"
+
 	"github.com/gin-gonic/gin"
 )
 
-func RegisterHandlers() *gin.Engine {
-	r := gin.Default()
+// handler serves the routes of the service, with the usecases built from cfg
+type handler struct {
+	cfg config.Config
+	// Calls the other services for the usecases
+	services *http.Client
+}
+
+func RegisterHandlers(cfg config.Config, resolver discovery.Resolver) *gin.Engine {
+	h := &handler{cfg: cfg, services: dependency_injection.NewServiceClientProvider(cfg, resolver)}
+	keys := authz.NewKeys(cfg.Auth)
+
+	r := gin.New()
+	// Lets the request context, and the trace in it, reach the usecases through *gin.Context
+	r.ContextWithFallback = true
+	r.Use(gin.Recovery(), tracing.Middleware("payment"), logging.Middleware(), metrics.Middleware())
+	r.GET(metrics.Path, metrics.Handler())
+	r.Use(keys.Middleware())
 
 	payment := r.Group("/api/payments")
 	{
-		payment.GET("/:payment_id", GetPaymentByID)
-		payment.GET("", GetPaginatedPayment)
-		payment.POST("", CreatePayment)
-		payment.PUT("", UpdatePayment)
+		payment.GET("/:payment_id", h.GetPaymentByID)
+		payment.GET("", h.GetPaginatedPayment)
+		payment.POST("", h.CreatePayment)
+		payment.PUT("", h.UpdatePayment)
 	}
 
 	return r
 }
+
+// RelayOutbox publishes the events written to the outbox until ctx is canceled
+func RelayOutbox(cfg config.Config) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		return outbox.NewRelay(cfg.Postgres, outbox.NewRelayConfig(cfg.Outbox), dependency_injection.NewBrokerProvider(cfg)).Run(ctx)
+	}
+}
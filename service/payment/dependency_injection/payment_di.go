@@ -1,21 +1,27 @@
 package dependency_injection
 
 import (
+	"net/http"
+	"th3y3m/e-commerce-microservices/pkg/authz"
+	"th3y3m/e-commerce-microservices/pkg/broker"
+	"th3y3m/e-commerce-microservices/pkg/clients"
+	"th3y3m/e-commerce-microservices/pkg/discovery"
+	"th3y3m/e-commerce-microservices/pkg/logging"
 	"th3y3m/e-commerce-microservices/pkg/postgresql"
+	"th3y3m/e-commerce-microservices/pkg/rabbitmq"
 	redis_client "th3y3m/e-commerce-microservices/pkg/redis"
+	"th3y3m/e-commerce-microservices/service/payment/config"
 	"th3y3m/e-commerce-microservices/service/payment/repository"
 	"th3y3m/e-commerce-microservices/service/payment/usecase"
-
-	"github.com/sirupsen/logrus"
 )
 
-func NewPaymentRepositoryProvider() repository.IPaymentRepository {
-	log := logrus.New()
-	db, err := postgresql.NewGormDB()
+func NewPaymentRepositoryProvider(cfg config.Config) repository.IPaymentRepository {
+	log := logging.Logger()
+	db, err := postgresql.NewGormDB(cfg.Postgres)
 	if err != nil {
 		log.Error(err)
 	}
-	redis, err := redis_client.ConnectToRedis()
+	redis, err := redis_client.ConnectToRedis(cfg.Redis)
 	if err != nil {
 		log.Error(err)
 	}
@@ -23,8 +29,25 @@ func NewPaymentRepositoryProvider() repository.IPaymentRepository {
 	return repository.NewPaymentRepository(db, redis, log)
 }
 
-func NewPaymentUsecaseProvider() usecase.IPaymentUsecase {
-	log := logrus.New()
-	paymentRepository := NewPaymentRepositoryProvider()
+func NewPaymentUsecaseProvider(cfg config.Config) usecase.IPaymentUsecase {
+	log := logging.Logger()
+	paymentRepository := NewPaymentRepositoryProvider(cfg)
 	return usecase.NewPaymentUsecase(paymentRepository, log)
 }
+
+// NewBrokerProvider returns the RabbitMQ broker, or the in-process one when BROKER is memory
+func NewBrokerProvider(cfg config.Config) broker.Broker {
+	return broker.FromConfig(cfg.RabbitMQ, rabbitmq.DefaultBroker)
+}
+
+// NewServiceClientProvider returns the HTTP client the payment service calls other
+// services with, signed with its service token and sent to the instances resolver finds
+func NewServiceClientProvider(cfg config.Config, resolver discovery.Resolver) *http.Client {
+	return authz.NewKeys(cfg.Auth).NewServiceClient("payment", resolver)
+}
+
+// NewOrderClientProvider returns the client of the order service the payment routes check the
+// owner of an order with
+func NewOrderClientProvider(cfg config.Config, httpClient *http.Client) *clients.OrderClient {
+	return clients.NewOrderClient(httpClient, clients.NewOptions(cfg.Clients))
+}
@@ -1,17 +1,15 @@
-// Code generated by mockery v2.46.3. DO NOT EDIT.
+// Code generated by mockery v2.53.5. DO NOT EDIT.
 
 package mocks
 
 import (
 	context "context"
-
-	gorm "gorm.io/gorm"
-
-	mock "github.com/stretchr/testify/mock"
-
+	events "th3y3m/e-commerce-microservices/pkg/events"
 	model "th3y3m/e-commerce-microservices/service/payment/model"
-
 	repository "th3y3m/e-commerce-microservices/service/payment/repository"
+
+	mock "github.com/stretchr/testify/mock"
+	gorm "gorm.io/gorm"
 )
 
 // IPaymentRepository is an autogenerated mock type for the IPaymentRepository type
@@ -49,6 +47,43 @@ func (_m *IPaymentRepository) Create(ctx context.Context, payment *repository.Pa
 	return r0, r1
 }
 
+// CreateWithEvents provides a mock function with given fields: ctx, payment, evs
+func (_m *IPaymentRepository) CreateWithEvents(ctx context.Context, payment *repository.Payment, evs ...events.Event) (*repository.Payment, error) {
+	_va := make([]interface{}, len(evs))
+	for _i := range evs {
+		_va[_i] = evs[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, payment)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateWithEvents")
+	}
+
+	var r0 *repository.Payment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *repository.Payment, ...events.Event) (*repository.Payment, error)); ok {
+		return rf(ctx, payment, evs...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *repository.Payment, ...events.Event) *repository.Payment); ok {
+		r0 = rf(ctx, payment, evs...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*repository.Payment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *repository.Payment, ...events.Event) error); ok {
+		r1 = rf(ctx, payment, evs...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Delete provides a mock function with given fields: ctx, paymentID
 func (_m *IPaymentRepository) Delete(ctx context.Context, paymentID int64) error {
 	ret := _m.Called(ctx, paymentID)
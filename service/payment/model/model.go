@@ -40,6 +40,8 @@ type CreatePaymentRequest struct {
 	PaymentMethod    string  `json:"payment_method"`
 	PaymentStatus    string  `json:"payment_status"`
 	PaymentSignature string  `json:"payment_signature"`
+	// Transaction ID of the payment provider, only carried by the PaymentCompleted event
+	TransactionID string `json:"transaction_id"`
 }
 
 type UpdatePaymentRequest struct {
@@ -4,6 +4,9 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"th3y3m/e-commerce-microservices/pkg/events"
+	"th3y3m/e-commerce-microservices/pkg/metrics"
+	"th3y3m/e-commerce-microservices/pkg/outbox"
 	"th3y3m/e-commerce-microservices/service/payment/model"
 
 	"github.com/redis/go-redis/v9"
@@ -21,6 +24,7 @@ type IPaymentRepository interface {
 	Get(ctx context.Context, paymentID int64) (*Payment, error)
 	GetAll(ctx context.Context) ([]*Payment, error)
 	Create(ctx context.Context, payment *Payment) (*Payment, error)
+	CreateWithEvents(ctx context.Context, payment *Payment, evs ...events.Event) (*Payment, error)
 	Update(ctx context.Context, payment *Payment) (*Payment, error)
 	Delete(ctx context.Context, paymentID int64) error
 	getQuerySearch(db *gorm.DB, req *model.GetPaymentsRequest) *gorm.DB
@@ -36,7 +40,7 @@ func NewPaymentRepository(db *gorm.DB, redis *redis.Client, log *logrus.Logger)
 }
 
 func (pr *paymentRepository) Get(ctx context.Context, paymentID int64) (*Payment, error) {
-	pr.log.Infof("Fetching payment with ID: %d", paymentID)
+	pr.log.WithContext(ctx).Infof("Fetching payment with ID: %d", paymentID)
 	var payment Payment
 	cacheKey := fmt.Sprintf("payment:%d", paymentID)
 
@@ -45,19 +49,21 @@ func (pr *paymentRepository) Get(ctx context.Context, paymentID int64) (*Payment
 		cachedPayment, err := pr.redis.Get(ctx, cacheKey).Result()
 		if err == nil {
 			if err := json.Unmarshal([]byte(cachedPayment), &payment); err == nil {
-				pr.log.Infof("Payment found in cache: %d", paymentID)
+				pr.log.WithContext(ctx).Infof("Payment found in cache: %d", paymentID)
+				metrics.CacheHit("paymentRepository.Get")
 				return &payment, nil
 			}
 		} else if err != redis.Nil {
-			pr.log.Warnf("Failed to get payment from Redis: %v", err)
+			pr.log.WithContext(ctx).Warnf("Failed to get payment from Redis: %v", err)
 		}
+		metrics.CacheMiss("paymentRepository.Get")
 	} else {
-		pr.log.Warn("Redis client is not initialized")
+		pr.log.WithContext(ctx).Warn("Redis client is not initialized")
 	}
 
 	// If not found in cache, get from database
 	if err := pr.db.WithContext(ctx).First(&payment, paymentID).Error; err != nil {
-		pr.log.Errorf("Error fetching payment from database: %v", err)
+		pr.log.WithContext(ctx).Errorf("Error fetching payment from database: %v", err)
 		return nil, err
 	}
 
@@ -65,9 +71,9 @@ func (pr *paymentRepository) Get(ctx context.Context, paymentID int64) (*Payment
 	if pr.redis != nil {
 		paymentJSON, _ := json.Marshal(payment)
 		if err := pr.redis.Set(ctx, cacheKey, paymentJSON, 0).Err(); err != nil {
-			pr.log.Warnf("Failed to save payment to Redis: %v", err)
+			pr.log.WithContext(ctx).Warnf("Failed to save payment to Redis: %v", err)
 		} else {
-			pr.log.Infof("Payment saved to cache: %d", paymentID)
+			pr.log.WithContext(ctx).Infof("Payment saved to cache: %d", paymentID)
 		}
 	}
 
@@ -75,7 +81,7 @@ func (pr *paymentRepository) Get(ctx context.Context, paymentID int64) (*Payment
 }
 
 func (pr *paymentRepository) GetAll(ctx context.Context) ([]*Payment, error) {
-	pr.log.Info("Fetching all payments")
+	pr.log.WithContext(ctx).Info("Fetching all payments")
 	var payments []*Payment
 	cacheKey := "all_payments"
 
@@ -84,19 +90,21 @@ func (pr *paymentRepository) GetAll(ctx context.Context) ([]*Payment, error) {
 		cachedPayments, err := pr.redis.Get(ctx, cacheKey).Result()
 		if err == nil {
 			if err := json.Unmarshal([]byte(cachedPayments), &payments); err == nil {
-				pr.log.Info("Payments found in cache")
+				pr.log.WithContext(ctx).Info("Payments found in cache")
+				metrics.CacheHit("paymentRepository.GetAll")
 				return payments, nil
 			}
 		} else if err != redis.Nil {
-			pr.log.Warnf("Failed to get payments from Redis: %v", err)
+			pr.log.WithContext(ctx).Warnf("Failed to get payments from Redis: %v", err)
 		}
+		metrics.CacheMiss("paymentRepository.GetAll")
 	} else {
-		pr.log.Warn("Redis client is not initialized")
+		pr.log.WithContext(ctx).Warn("Redis client is not initialized")
 	}
 
 	// If not found in cache, get from database
 	if err := pr.db.WithContext(ctx).Find(&payments).Error; err != nil {
-		pr.log.Errorf("Error fetching payments from database: %v", err)
+		pr.log.WithContext(ctx).Errorf("Error fetching payments from database: %v", err)
 		return nil, err
 	}
 
@@ -104,9 +112,9 @@ func (pr *paymentRepository) GetAll(ctx context.Context) ([]*Payment, error) {
 	if pr.redis != nil {
 		paymentsJSON, _ := json.Marshal(payments)
 		if err := pr.redis.Set(ctx, cacheKey, paymentsJSON, 0).Err(); err != nil {
-			pr.log.Warnf("Failed to save payments to Redis: %v", err)
+			pr.log.WithContext(ctx).Warnf("Failed to save payments to Redis: %v", err)
 		} else {
-			pr.log.Info("Payments saved to cache")
+			pr.log.WithContext(ctx).Info("Payments saved to cache")
 		}
 	}
 
@@ -114,9 +122,20 @@ func (pr *paymentRepository) GetAll(ctx context.Context) ([]*Payment, error) {
 }
 
 func (pr *paymentRepository) Create(ctx context.Context, payment *Payment) (*Payment, error) {
-	pr.log.Infof("Creating payment: %+v", payment)
-	if err := pr.db.WithContext(ctx).Create(payment).Error; err != nil {
-		pr.log.Errorf("Error creating payment: %v", err)
+	return pr.CreateWithEvents(ctx, payment)
+}
+
+// CreateWithEvents saves the payment and adds the events to the outbox in one transaction
+func (pr *paymentRepository) CreateWithEvents(ctx context.Context, payment *Payment, evs ...events.Event) (*Payment, error) {
+	pr.log.WithContext(ctx).Infof("Creating payment: %+v", payment)
+	err := pr.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(payment).Error; err != nil {
+			return err
+		}
+		return outbox.Add(ctx, tx, evs...)
+	})
+	if err != nil {
+		pr.log.WithContext(ctx).Errorf("Error creating payment: %v", err)
 		return nil, err
 	}
 	cacheKey := fmt.Sprintf("payment:%d", payment.PaymentID)
@@ -125,16 +144,16 @@ func (pr *paymentRepository) Create(ctx context.Context, payment *Payment) (*Pay
 	if pr.redis != nil {
 		paymentJSON, _ := json.Marshal(payment)
 		if err := pr.redis.Set(ctx, cacheKey, paymentJSON, 0).Err(); err != nil {
-			pr.log.Warnf("Failed to save payment to Redis: %v", err)
+			pr.log.WithContext(ctx).Warnf("Failed to save payment to Redis: %v", err)
 		} else {
-			pr.log.Infof("Payment saved to cache: %d", payment.PaymentID)
+			pr.log.WithContext(ctx).Infof("Payment saved to cache: %d", payment.PaymentID)
 		}
 
 		// Invalidate the cache for all records
 		if err := pr.redis.Del(ctx, "all_payments").Err(); err != nil {
-			pr.log.Warnf("Failed to invalidate all payments cache: %v", err)
+			pr.log.WithContext(ctx).Warnf("Failed to invalidate all payments cache: %v", err)
 		} else {
-			pr.log.Info("Invalidated cache for all payments")
+			pr.log.WithContext(ctx).Info("Invalidated cache for all payments")
 		}
 	}
 
@@ -143,9 +162,9 @@ func (pr *paymentRepository) Create(ctx context.Context, payment *Payment) (*Pay
 }
 
 func (pr *paymentRepository) Update(ctx context.Context, payment *Payment) (*Payment, error) {
-	pr.log.Infof("Updating payment: %+v", payment)
+	pr.log.WithContext(ctx).Infof("Updating payment: %+v", payment)
 	if err := pr.db.WithContext(ctx).Save(payment).Error; err != nil {
-		pr.log.Errorf("Error updating payment: %v", err)
+		pr.log.WithContext(ctx).Errorf("Error updating payment: %v", err)
 		return nil, err
 	}
 	cacheKey := fmt.Sprintf("payment:%d", payment.PaymentID)
@@ -154,16 +173,16 @@ func (pr *paymentRepository) Update(ctx context.Context, payment *Payment) (*Pay
 	if pr.redis != nil {
 		paymentJSON, _ := json.Marshal(payment)
 		if err := pr.redis.Set(ctx, cacheKey, paymentJSON, 0).Err(); err != nil {
-			pr.log.Warnf("Failed to save payment to Redis: %v", err)
+			pr.log.WithContext(ctx).Warnf("Failed to save payment to Redis: %v", err)
 		} else {
-			pr.log.Infof("Payment saved to cache: %d", payment.PaymentID)
+			pr.log.WithContext(ctx).Infof("Payment saved to cache: %d", payment.PaymentID)
 		}
 
 		// Invalidate the cache for all records
 		if err := pr.redis.Del(ctx, "all_payments").Err(); err != nil {
-			pr.log.Warnf("Failed to invalidate all payments cache: %v", err)
+			pr.log.WithContext(ctx).Warnf("Failed to invalidate all payments cache: %v", err)
 		} else {
-			pr.log.Info("Invalidated cache for all payments")
+			pr.log.WithContext(ctx).Info("Invalidated cache for all payments")
 		}
 	}
 
@@ -172,9 +191,9 @@ func (pr *paymentRepository) Update(ctx context.Context, payment *Payment) (*Pay
 }
 
 func (pr *paymentRepository) Delete(ctx context.Context, paymentID int64) error {
-	pr.log.Infof("Deleting payment with ID: %d", paymentID)
+	pr.log.WithContext(ctx).Infof("Deleting payment with ID: %d", paymentID)
 	if err := pr.db.WithContext(ctx).Delete(&Payment{}, paymentID).Error; err != nil {
-		pr.log.Errorf("Error deleting payment: %v", err)
+		pr.log.WithContext(ctx).Errorf("Error deleting payment: %v", err)
 		return err
 	}
 
@@ -183,16 +202,16 @@ func (pr *paymentRepository) Delete(ctx context.Context, paymentID int64) error
 	// Delete from cache if Redis is available
 	if pr.redis != nil {
 		if err := pr.redis.Del(ctx, cacheKey).Err(); err != nil {
-			pr.log.Warnf("Failed to delete payment from Redis: %v", err)
+			pr.log.WithContext(ctx).Warnf("Failed to delete payment from Redis: %v", err)
 		} else {
-			pr.log.Infof("Payment deleted from cache: %d", paymentID)
+			pr.log.WithContext(ctx).Infof("Payment deleted from cache: %d", paymentID)
 		}
 
 		// Invalidate the cache for all records
 		if err := pr.redis.Del(ctx, "all_payments").Err(); err != nil {
-			pr.log.Warnf("Failed to invalidate all payments cache: %v", err)
+			pr.log.WithContext(ctx).Warnf("Failed to invalidate all payments cache: %v", err)
 		} else {
-			pr.log.Info("Invalidated cache for all payments")
+			pr.log.WithContext(ctx).Info("Invalidated cache for all payments")
 		}
 	}
 
@@ -234,7 +253,7 @@ func (pr *paymentRepository) getQuerySearch(db *gorm.DB, req *model.GetPaymentsR
 }
 
 func (pr *paymentRepository) GetList(ctx context.Context, req *model.GetPaymentsRequest) ([]*Payment, error) {
-	pr.log.Infof("Fetching payment list with request: %+v", req)
+	pr.log.WithContext(ctx).Infof("Fetching payment list with request: %+v", req)
 	var payments []*Payment
 
 	db := pr.db.WithContext(ctx)
@@ -259,10 +278,10 @@ func (pr *paymentRepository) GetList(ctx context.Context, req *model.GetPayments
 
 	result := db.Table("payments").Offset(int(req.Paging.PageIndex-1) * int(req.Paging.PageSize)).Limit(int(req.Paging.PageSize)).Find(&payments)
 	if result.Error != nil {
-		pr.log.Errorf("Error fetching payment list: %v", result.Error)
+		pr.log.WithContext(ctx).Errorf("Error fetching payment list: %v", result.Error)
 		return nil, result.Error
 	}
 
-	pr.log.Infof("Fetched %d payments", len(payments))
+	pr.log.WithContext(ctx).Infof("Fetched %d payments", len(payments))
 	return payments, nil
 }
@@ -3,6 +3,8 @@ package usecase
 import (
 	"context"
 	"errors"
+	"th3y3m/e-commerce-microservices/pkg/constant"
+	"th3y3m/e-commerce-microservices/pkg/events"
 	"th3y3m/e-commerce-microservices/pkg/util"
 	"th3y3m/e-commerce-microservices/service/payment/model"
 	"th3y3m/e-commerce-microservices/service/payment/repository"
@@ -35,14 +37,14 @@ func NewPaymentUsecase(paymentRepo repository.IPaymentRepository, log *logrus.Lo
 }
 
 func (pu *paymentUsecase) GetPayment(ctx context.Context, req *model.GetPaymentRequest) (*model.GetPaymentResponse, error) {
-	pu.log.Infof("Fetching payment with ID: %d", req.PaymentID)
+	pu.log.WithContext(ctx).Infof("Fetching payment with ID: %d", req.PaymentID)
 	payment, err := pu.paymentRepo.Get(ctx, req.PaymentID)
 	if err != nil {
-		pu.log.Errorf("Error fetching payment: %v", err)
+		pu.log.WithContext(ctx).Errorf("Error fetching payment: %v", err)
 		return nil, err
 	}
 
-	pu.log.Infof("Fetched payment: %+v", payment)
+	pu.log.WithContext(ctx).Infof("Fetched payment: %+v", payment)
 	return &model.GetPaymentResponse{
 		PaymentID:        payment.PaymentID,
 		OrderID:          payment.OrderID,
@@ -55,10 +57,10 @@ func (pu *paymentUsecase) GetPayment(ctx context.Context, req *model.GetPaymentR
 }
 
 func (pu *paymentUsecase) GetAllPayments(ctx context.Context) ([]*model.GetPaymentResponse, error) {
-	pu.log.Info("Fetching all payments")
+	pu.log.WithContext(ctx).Info("Fetching all payments")
 	payments, err := pu.paymentRepo.GetAll(ctx)
 	if err != nil {
-		pu.log.Errorf("Error fetching all payments: %v", err)
+		pu.log.WithContext(ctx).Errorf("Error fetching all payments: %v", err)
 		return nil, err
 	}
 
@@ -75,12 +77,12 @@ func (pu *paymentUsecase) GetAllPayments(ctx context.Context) ([]*model.GetPayme
 		})
 	}
 
-	pu.log.Infof("Fetched %d payments", len(paymentResponses))
+	pu.log.WithContext(ctx).Infof("Fetched %d payments", len(paymentResponses))
 	return paymentResponses, nil
 }
 
 func (pu *paymentUsecase) CreatePayment(ctx context.Context, payment *model.CreatePaymentRequest) (*model.GetPaymentResponse, error) {
-	pu.log.Infof("Creating payment: %+v", payment)
+	pu.log.WithContext(ctx).Infof("Creating payment: %+v", payment)
 	paymentData := repository.Payment{
 		OrderID:          payment.OrderID,
 		PaymentAmount:    payment.PaymentAmount,
@@ -90,13 +92,24 @@ func (pu *paymentUsecase) CreatePayment(ctx context.Context, payment *model.Crea
 		PaymentSignature: payment.PaymentSignature,
 	}
 
-	createdPayment, err := pu.paymentRepo.Create(ctx, &paymentData)
+	// Subscribers learn about completed payments through the outbox
+	var evs []events.Event
+	if payment.PaymentStatus == constant.PAYMENT_STATUS_COMPLETED {
+		evs = append(evs, events.PaymentCompleted{
+			OrderID:       payment.OrderID,
+			Amount:        payment.PaymentAmount,
+			PaymentMethod: payment.PaymentMethod,
+			TransactionID: payment.TransactionID,
+		})
+	}
+
+	createdPayment, err := pu.paymentRepo.CreateWithEvents(ctx, &paymentData, evs...)
 	if err != nil {
-		pu.log.Errorf("Error creating payment: %v", err)
+		pu.log.WithContext(ctx).Errorf("Error creating payment: %v", err)
 		return nil, err
 	}
 
-	pu.log.Infof("Created payment: %+v", createdPayment)
+	pu.log.WithContext(ctx).Infof("Created payment: %+v", createdPayment)
 	return &model.GetPaymentResponse{
 		PaymentID:        createdPayment.PaymentID,
 		OrderID:          createdPayment.OrderID,
@@ -109,10 +122,10 @@ func (pu *paymentUsecase) CreatePayment(ctx context.Context, payment *model.Crea
 }
 
 func (pu *paymentUsecase) UpdatePayment(ctx context.Context, rep *model.UpdatePaymentRequest) (*model.GetPaymentResponse, error) {
-	pu.log.Infof("Updating payment with ID: %d", rep.PaymentID)
+	pu.log.WithContext(ctx).Infof("Updating payment with ID: %d", rep.PaymentID)
 	payment, err := pu.paymentRepo.Get(ctx, rep.PaymentID)
 	if err != nil {
-		pu.log.Errorf("Error fetching payment for update: %v", err)
+		pu.log.WithContext(ctx).Errorf("Error fetching payment for update: %v", err)
 		return nil, err
 	}
 
@@ -123,11 +136,11 @@ func (pu *paymentUsecase) UpdatePayment(ctx context.Context, rep *model.UpdatePa
 
 	updatedPayment, err := pu.paymentRepo.Update(ctx, payment)
 	if err != nil {
-		pu.log.Errorf("Error updating payment: %v", err)
+		pu.log.WithContext(ctx).Errorf("Error updating payment: %v", err)
 		return nil, err
 	}
 
-	pu.log.Infof("Updated payment: %+v", updatedPayment)
+	pu.log.WithContext(ctx).Infof("Updated payment: %+v", updatedPayment)
 	return &model.GetPaymentResponse{
 		PaymentID:        updatedPayment.PaymentID,
 		OrderID:          updatedPayment.OrderID,
@@ -140,10 +153,10 @@ func (pu *paymentUsecase) UpdatePayment(ctx context.Context, rep *model.UpdatePa
 }
 
 func (pu *paymentUsecase) GetPaymentList(ctx context.Context, req *model.GetPaymentsRequest) (*util.PaginatedList[model.GetPaymentResponse], error) {
-	pu.log.Infof("Fetching payment list with request: %+v", req)
+	pu.log.WithContext(ctx).Infof("Fetching payment list with request: %+v", req)
 	payments, err := pu.paymentRepo.GetList(ctx, req)
 	if err != nil {
-		pu.log.Errorf("Error fetching payment list: %v", err)
+		pu.log.WithContext(ctx).Errorf("Error fetching payment list: %v", err)
 		return nil, err
 	}
 
@@ -170,12 +183,12 @@ func (pu *paymentUsecase) GetPaymentList(ctx context.Context, req *model.GetPaym
 
 	list.GetTotalPages()
 
-	pu.log.Infof("Fetched %d payments", len(paymentResponses))
+	pu.log.WithContext(ctx).Infof("Fetched %d payments", len(paymentResponses))
 	return list, nil
 }
 
 func (pu *paymentUsecase) GetRevenue(ctx context.Context, day, month, year *int) ([]float64, error) {
-	pu.log.Infof("Fetching revenue for day: %d, month: %d, year: %d", *day, *month, *year)
+	pu.log.WithContext(ctx).Infof("Fetching revenue for day: %d, month: %d, year: %d", *day, *month, *year)
 	currentTime := time.Now()
 	var revenue []float64
 
@@ -187,7 +200,7 @@ func (pu *paymentUsecase) GetRevenue(ctx context.Context, day, month, year *int)
 			}
 			yearsRevenue, err := pu.paymentRepo.GetList(ctx, model)
 			if err != nil {
-				pu.log.Errorf("Error fetching revenue for year %d: %v", i, err)
+				pu.log.WithContext(ctx).Errorf("Error fetching revenue for year %d: %v", i, err)
 				return nil, err
 			}
 			yearRevenue := yearsRevenue[0].PaymentAmount
@@ -205,7 +218,7 @@ func (pu *paymentUsecase) GetRevenue(ctx context.Context, day, month, year *int)
 				}
 				monthsRevenue, err := pu.paymentRepo.GetList(ctx, model)
 				if err != nil {
-					pu.log.Errorf("Error fetching revenue for month %d-%d: %v", m, y, err)
+					pu.log.WithContext(ctx).Errorf("Error fetching revenue for month %d-%d: %v", m, y, err)
 					return nil, err
 				}
 				monthRevenue := monthsRevenue[0].PaymentAmount
@@ -232,7 +245,7 @@ func (pu *paymentUsecase) GetRevenue(ctx context.Context, day, month, year *int)
 					}
 					daysRevenue, err := pu.paymentRepo.GetList(ctx, model)
 					if err != nil {
-						pu.log.Errorf("Error fetching revenue for day %d-%d-%d: %v", d, m, y, err)
+						pu.log.WithContext(ctx).Errorf("Error fetching revenue for day %d-%d-%d: %v", d, m, y, err)
 						return nil, err
 					}
 					dayRevenue := daysRevenue[0].PaymentAmount
@@ -243,7 +256,7 @@ func (pu *paymentUsecase) GetRevenue(ctx context.Context, day, month, year *int)
 			startMonth = 12 // Reset to December for the previous year
 		}
 	} else {
-		pu.log.Errorf("No valid date parameter provided")
+		pu.log.WithContext(ctx).Errorf("No valid date parameter provided")
 		return nil, errors.New("no valid date parameter provided")
 	}
 
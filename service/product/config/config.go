@@ -0,0 +1,12 @@
+package config
+
+import pkgconfig "th3y3m/e-commerce-microservices/pkg/config"
+
+// Config of the product service
+type Config struct {
+	pkgconfig.Service
+	pkgconfig.RabbitMQ
+	pkgconfig.Elasticsearch
+	pkgconfig.Outbox
+	pkgconfig.Inbox
+}
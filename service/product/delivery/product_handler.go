@@ -1,21 +1,25 @@
 package delivery
 
 import (
+	"context"
 	"strconv"
+	"th3y3m/e-commerce-microservices/pkg/authz"
 	"th3y3m/e-commerce-microservices/service/product/dependency_injection"
 	"th3y3m/e-commerce-microservices/service/product/model"
+	"th3y3m/e-commerce-microservices/service/product/rabbitmq"
+	"th3y3m/e-commerce-microservices/service/product/usecase"
 
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
 )
 
-func GetProductByID(c *gin.Context) {
-	module := dependency_injection.NewProductUsecaseProvider()
+func (h *handler) GetProductByID(c *gin.Context) {
+	module := dependency_injection.NewProductUsecaseProvider(h.cfg, h.services)
 
 	productIDStr := c.Param("product_id")
 	productID, err := strconv.ParseInt(productIDStr, 10, 64)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(400, gin.H{
 			"error": "Invalid product ID",
 		})
@@ -28,7 +32,7 @@ func GetProductByID(c *gin.Context) {
 
 	product, err := module.GetProduct(c, &req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(500, gin.H{
 			"error": "Internal Server Error",
 		})
@@ -38,12 +42,12 @@ func GetProductByID(c *gin.Context) {
 	c.JSON(200, product)
 }
 
-func GetAllProducts(c *gin.Context) {
-	module := dependency_injection.NewProductUsecaseProvider()
+func (h *handler) GetAllProducts(c *gin.Context) {
+	module := dependency_injection.NewProductUsecaseProvider(h.cfg, h.services)
 
 	products, err := module.GetAllProducts(c)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(500, gin.H{
 			"error": "Internal Server Error",
 		})
@@ -53,89 +57,116 @@ func GetAllProducts(c *gin.Context) {
 	c.JSON(200, products)
 }
 
-func CreateProduct(c *gin.Context) {
+func (h *handler) CreateProduct(c *gin.Context) {
 	var req model.CreateProductRequest
 	err := c.BindJSON(&req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(400, gin.H{
 			"error": "Bad Request",
 		})
 		return
 	}
 
-	module := dependency_injection.NewProductUsecaseProvider()
+	// Sellers may only list products under their own account
+	caller, ok := authz.Authorize(c)
+	if !ok || !authz.RequireRole(c, caller, authz.RoleSeller) || !authz.RequireOwner(c, caller, req.SellerID) {
+		return
+	}
+
+	module := dependency_injection.NewProductUsecaseProvider(h.cfg, h.services)
 
 	product, err := module.CreateProduct(c, &req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(500, gin.H{
 			"error": "Internal Server Error",
 		})
 		return
 	}
 
+	h.publishProductChanged(c, "product_created", product.ProductID)
+
 	c.JSON(200, product)
 }
 
-func UpdateProduct(c *gin.Context) {
+func (h *handler) UpdateProduct(c *gin.Context) {
 	var req model.UpdateProductRequest
 	err := c.BindJSON(&req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(400, gin.H{
 			"error": "Bad Request",
 		})
 		return
 	}
 
-	module := dependency_injection.NewProductUsecaseProvider()
+	caller, ok := authz.Authorize(c)
+	if !ok || !authz.RequireRole(c, caller, authz.RoleSeller) || !authz.RequireOwner(c, caller, req.SellerID) {
+		return
+	}
+
+	module := dependency_injection.NewProductUsecaseProvider(h.cfg, h.services)
+
+	if !requireProductOwner(c, module, caller, req.ProductID) {
+		return
+	}
 
 	product, err := module.UpdateProduct(c, &req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(500, gin.H{
 			"error": "Internal Server Error",
 		})
 		return
 	}
 
+	h.publishProductChanged(c, "product_updated", product.ProductID)
+
 	c.JSON(200, product)
 }
 
-func DeleteProduct(c *gin.Context) {
-	module := dependency_injection.NewProductUsecaseProvider()
+func (h *handler) DeleteProduct(c *gin.Context) {
+	module := dependency_injection.NewProductUsecaseProvider(h.cfg, h.services)
 
 	var req model.DeleteProductRequest
 	err := c.BindJSON(&req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(400, gin.H{
 			"error": "Bad Request",
 		})
 		return
 	}
+
+	caller, ok := authz.Authorize(c)
+	if !ok || !authz.RequireRole(c, caller, authz.RoleSeller) || !requireProductOwner(c, module, caller, req.ProductID) {
+		return
+	}
+
 	err = module.DeleteProduct(c, &req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(500, gin.H{
 			"error": "Internal Server Error",
 		})
 		return
 	}
 
+	h.publishProductChanged(c, "product_deleted", req.ProductID)
+
 	c.JSON(200, gin.H{
 		"message": "Product deleted successfully",
 	})
 }
 
-func GetPaginatedProduct(c *gin.Context) {
-	module := dependency_injection.NewProductUsecaseProvider()
+func (h *handler) GetPaginatedProduct(c *gin.Context) {
+	module := dependency_injection.NewProductUsecaseProvider(h.cfg, h.services)
 
 	var req model.GetProductsRequest
 	err := c.BindJSON(&req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(400, gin.H{
 			"error": "Bad Request",
 		})
@@ -151,7 +182,7 @@ func GetPaginatedProduct(c *gin.Context) {
 
 	products, err := module.GetProductList(c, &req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(500, gin.H{
 			"error": "Internal Server Error",
 		})
@@ -161,13 +192,13 @@ func GetPaginatedProduct(c *gin.Context) {
 	c.JSON(200, products)
 }
 
-func GetProductPriceAfterDiscount(c *gin.Context) {
-	module := dependency_injection.NewProductUsecaseProvider()
+func (h *handler) GetProductPriceAfterDiscount(c *gin.Context) {
+	module := dependency_injection.NewProductUsecaseProvider(h.cfg, h.services)
 
 	productIDStr := c.Param("product_id")
 	productID, err := strconv.ParseInt(productIDStr, 10, 64)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(400, gin.H{
 			"error": "Invalid product ID",
 		})
@@ -180,7 +211,7 @@ func GetProductPriceAfterDiscount(c *gin.Context) {
 
 	price, err := module.GetProductPriceAfterDiscount(c, &req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(500, gin.H{
 			"error": "Internal Server Error",
 		})
@@ -189,3 +220,32 @@ func GetProductPriceAfterDiscount(c *gin.Context) {
 
 	c.JSON(200, price)
 }
+
+// publishProductChanged lets the gateway drop cached product responses without delaying the reply
+func (h *handler) publishProductChanged(c *gin.Context, event string, productId int64) {
+	// The gin context is reused once the handler returns, keep only the request values
+	ctx := context.WithoutCancel(c.Request.Context())
+	go func() {
+		if err := rabbitmq.PublishProductChangedEvent(ctx, dependency_injection.NewBrokerProvider(h.cfg), event, productId); err != nil {
+			logrus.WithContext(ctx).Errorf("Failed to publish %s event: %v", event, err)
+		}
+	}()
+}
+
+// requireProductOwner aborts with 403 unless the caller is the seller of the product
+func requireProductOwner(c *gin.Context, module usecase.IProductUsecase, caller *authz.Caller, productID int64) bool {
+	if caller.Privileged() {
+		return true
+	}
+
+	product, err := module.GetProduct(c, &model.GetProductRequest{ProductID: productID})
+	if err != nil {
+		logrus.WithContext(c).Error(err)
+		c.JSON(500, gin.H{
+			"error": "Internal Server Error",
+		})
+		return false
+	}
+
+	return authz.RequireOwner(c, caller, product.SellerID)
+}
@@ -2,33 +2,61 @@ package delivery
 
 import (
 	"context"
-	"log"
+	"net/http"
+	"th3y3m/e-commerce-microservices/pkg/authz"
+	"th3y3m/e-commerce-microservices/pkg/discovery"
+	"th3y3m/e-commerce-microservices/pkg/logging"
+	"th3y3m/e-commerce-microservices/pkg/metrics"
+	"th3y3m/e-commerce-microservices/pkg/outbox"
+	"th3y3m/e-commerce-microservices/pkg/tracing"
+	"th3y3m/e-commerce-microservices/service/product/config"
 	"th3y3m/e-commerce-microservices/service/product/dependency_injection"
 	"th3y3m/e-commerce-microservices/service/product/rabbitmq"
 
 	"github.com/gin-gonic/gin"
 )
 
-func RegisterHandlers() *gin.Engine {
-	r := gin.Default()
-	module := dependency_injection.NewProductUsecaseProvider()
-	ctx := context.Background()
+// handler serves the routes of the service, with the usecases built from cfg
+type handler struct {
+	cfg config.Config
+	// Calls the other services for the usecases
+	services *http.Client
+}
 
-	go func() {
-		if err := rabbitmq.ConsumeInventoryUpdates(ctx, module); err != nil {
-			log.Fatalf("Failed to start RabbitMQ consumer: %v", err)
-		}
-	}()
+func RegisterHandlers(cfg config.Config, resolver discovery.Resolver) *gin.Engine {
+	h := &handler{cfg: cfg, services: dependency_injection.NewServiceClientProvider(cfg, resolver)}
+	keys := authz.NewKeys(cfg.Auth)
 
+	r := gin.New()
+	// Lets the request context, and the trace in it, reach the usecases through *gin.Context
+	r.ContextWithFallback = true
+	r.Use(gin.Recovery(), tracing.Middleware("product"), logging.Middleware(), metrics.Middleware())
+	r.GET(metrics.Path, metrics.Handler())
+	r.Use(keys.Middleware("seller_id"))
 	product := r.Group("/api/products")
 	{
-		product.GET("/:product_id", GetProductByID)
-		product.GET("", GetPaginatedProduct)
-		product.POST("", CreateProduct)
-		product.PUT("", UpdateProduct)
-		product.DELETE("", DeleteProduct)
-		product.GET("/discount-price/:product_id", GetProductPriceAfterDiscount)
+		product.GET("/:product_id", h.GetProductByID)
+		product.GET("", h.GetPaginatedProduct)
+		product.POST("", h.CreateProduct)
+		product.PUT("", h.UpdateProduct)
+		product.DELETE("", h.DeleteProduct)
+		product.GET("/discount-price/:product_id", h.GetProductPriceAfterDiscount)
 	}
 
 	return r
 }
+
+// ConsumeInventoryUpdates applies the stock changes of placed orders until ctx is canceled
+func ConsumeInventoryUpdates(cfg config.Config, resolver discovery.Resolver) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		services := dependency_injection.NewServiceClientProvider(cfg, resolver)
+		return rabbitmq.ConsumeInventoryUpdates(ctx, dependency_injection.NewBrokerProvider(cfg), dependency_injection.NewProductUsecaseProvider(cfg, services), dependency_injection.NewInboxProvider(cfg))
+	}
+}
+
+// RelayOutbox publishes the events written to the outbox until ctx is canceled
+func RelayOutbox(cfg config.Config) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		return outbox.NewRelay(cfg.Postgres, outbox.NewRelayConfig(cfg.Outbox), dependency_injection.NewBrokerProvider(cfg)).Run(ctx)
+	}
+}
@@ -1,29 +1,37 @@
 package dependency_injection
 
 import (
+	"net/http"
+	"th3y3m/e-commerce-microservices/pkg/authz"
+	"th3y3m/e-commerce-microservices/pkg/broker"
+	"th3y3m/e-commerce-microservices/pkg/cache"
+	"th3y3m/e-commerce-microservices/pkg/clients"
+	"th3y3m/e-commerce-microservices/pkg/discovery"
 	"th3y3m/e-commerce-microservices/pkg/elasticsearch_server"
+	"th3y3m/e-commerce-microservices/pkg/inbox"
+	"th3y3m/e-commerce-microservices/pkg/logging"
 	"th3y3m/e-commerce-microservices/pkg/postgresql"
+	"th3y3m/e-commerce-microservices/pkg/rabbitmq"
 	redis_client "th3y3m/e-commerce-microservices/pkg/redis"
+	"th3y3m/e-commerce-microservices/service/product/config"
 	"th3y3m/e-commerce-microservices/service/product/repository"
 	"th3y3m/e-commerce-microservices/service/product/usecase"
-
-	"github.com/sirupsen/logrus"
 )
 
-func NewProductRepositoryProvider() repository.IProductRepository {
-	log := logrus.New()
+func NewProductRepositoryProvider(cfg config.Config) repository.IProductRepository {
+	log := logging.Logger()
 
-	db, err := postgresql.NewGormDB()
+	db, err := postgresql.NewGormDB(cfg.Postgres)
 	if err != nil {
 		log.Error(err)
 	}
 
-	redis, err := redis_client.ConnectToRedis()
+	redis, err := redis_client.ConnectToRedis(cfg.Redis)
 	if err != nil {
 		log.Error(err)
 	}
 
-	es, err := elasticsearch_server.ConnectToElasticsearch()
+	es, err := elasticsearch_server.ConnectToElasticsearch(cfg.Elasticsearch)
 	if err != nil {
 		log.Error(err)
 	}
@@ -33,11 +41,33 @@ func NewProductRepositoryProvider() repository.IProductRepository {
 	// 	log.Fatalf("Error creating the Elasticsearch client: %s", err)
 	// }
 
-	return repository.NewProductRepository(db, redis, log, es)
+	return repository.NewProductRepository(db, cache.NewStore(redis), cache.NewConfig(cfg.Cache), log, es)
+}
+
+func NewProductUsecaseProvider(cfg config.Config, httpClient *http.Client) usecase.IProductUsecase {
+	log := logging.Logger()
+	productRepository := NewProductRepositoryProvider(cfg)
+	opts := clients.NewOptions(cfg.Clients)
+	return usecase.NewProductUsecase(productRepository, usecase.Clients{
+		ProductDiscount: clients.NewProductDiscountClient(httpClient, opts),
+		Discount:        clients.NewDiscountClient(httpClient, opts),
+		CartItem:        clients.NewCartItemClient(httpClient, opts),
+	}, log)
+}
+
+// NewInboxProvider remembers the events the product service processed in its database, so
+// stock changes commit together with the event
+func NewInboxProvider(cfg config.Config) inbox.Store {
+	return inbox.NewPostgresStore(cfg.Postgres, cfg.Inbox)
+}
+
+// NewBrokerProvider returns the RabbitMQ broker, or the in-process one when BROKER is memory
+func NewBrokerProvider(cfg config.Config) broker.Broker {
+	return broker.FromConfig(cfg.RabbitMQ, rabbitmq.DefaultBroker)
 }
 
-func NewProductUsecaseProvider() usecase.IProductUsecase {
-	log := logrus.New()
-	productRepository := NewProductRepositoryProvider()
-	return usecase.NewProductUsecase(productRepository, log)
+// NewServiceClientProvider returns the HTTP client the product service calls other
+// services with, signed with its service token and sent to the instances resolver finds
+func NewServiceClientProvider(cfg config.Config, resolver discovery.Resolver) *http.Client {
+	return authz.NewKeys(cfg.Auth).NewServiceClient("product", resolver)
 }
@@ -1,34 +1,38 @@
 package main
 
 import (
+	"context"
 	"log"
-	"os"
-	"strings"
+	pkgconfig "th3y3m/e-commerce-microservices/pkg/config"
+	"th3y3m/e-commerce-microservices/pkg/discovery"
+	"th3y3m/e-commerce-microservices/pkg/health"
+	"th3y3m/e-commerce-microservices/pkg/logging"
+	"th3y3m/e-commerce-microservices/pkg/server"
+	"th3y3m/e-commerce-microservices/pkg/tracing"
+	"th3y3m/e-commerce-microservices/service/product/config"
 	"th3y3m/e-commerce-microservices/service/product/delivery"
-
-	"github.com/spf13/viper"
 )
 
 func main() {
-	viper.SetConfigFile("../../.env")
-	viper.AutomaticEnv()
-	if err := viper.ReadInConfig(); err != nil {
-		log.Printf("error while reading config file: %s", err.Error())
-		return
-	}
-	log.Println("Config file loaded successfully")
+	logging.Setup("product")
+
+	var cfg config.Config
+	pkgconfig.MustLoad("product", &cfg)
+
+	shutdownTracing := tracing.Setup("product", cfg.Tracing)
+	defer shutdownTracing(context.Background())
 
-	for _, env := range viper.AllKeys() {
-		if viper.GetString(env) != "" {
-			_ = os.Setenv(env, viper.GetString(env))
-			_ = os.Setenv(strings.ToUpper(env), viper.GetString(env))
-		}
+	resolver, err := discovery.New(cfg.Discovery, cfg.Redis)
+	if err != nil {
+		log.Fatalf("Failed to set up service discovery: %v", err)
 	}
 
-	r := delivery.RegisterHandlers()
+	checker := health.NewChecker(health.Postgres(cfg.Postgres), health.Redis(cfg.Redis), health.RabbitMQ(cfg.RabbitMQ), health.Elasticsearch(cfg.Elasticsearch))
+	r := delivery.RegisterHandlers(cfg, resolver)
+	checker.Register(r)
 
 	log.Println("Starting server on port 8081")
-	if err := r.Run(":8081"); err != nil {
-		log.Fatalf("Error starting server: %v", err)
+	if err := server.Run(":8081", cfg.Server, r, checker, discovery.Heartbeat(discovery.Product, resolver, cfg.Discovery), delivery.ConsumeInventoryUpdates(cfg, resolver), delivery.RelayOutbox(cfg)); err != nil {
+		log.Fatalf("Error running server: %v", err)
 	}
 }
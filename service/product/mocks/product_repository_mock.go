@@ -1,17 +1,15 @@
-// Code generated by mockery v2.46.3. DO NOT EDIT.
+// Code generated by mockery v2.53.5. DO NOT EDIT.
 
 package mocks
 
 import (
 	context "context"
-
-	gorm "gorm.io/gorm"
-
-	mock "github.com/stretchr/testify/mock"
-
+	events "th3y3m/e-commerce-microservices/pkg/events"
 	model "th3y3m/e-commerce-microservices/service/product/model"
-
 	repository "th3y3m/e-commerce-microservices/service/product/repository"
+
+	mock "github.com/stretchr/testify/mock"
+	gorm "gorm.io/gorm"
 )
 
 // IProductRepository is an autogenerated mock type for the IProductRepository type
@@ -157,6 +155,51 @@ func (_m *IProductRepository) GetList(ctx context.Context, req *model.GetProduct
 	return r0, r1
 }
 
+// GetQuerySearch provides a mock function with given fields: db, req
+func (_m *IProductRepository) GetQuerySearch(db *gorm.DB, req *model.GetProductsRequest) *gorm.DB {
+	ret := _m.Called(db, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetQuerySearch")
+	}
+
+	var r0 *gorm.DB
+	if rf, ok := ret.Get(0).(func(*gorm.DB, *model.GetProductsRequest) *gorm.DB); ok {
+		r0 = rf(db, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*gorm.DB)
+		}
+	}
+
+	return r0
+}
+
+// ReserveInventory provides a mock function with given fields: ctx, quantities, evs
+func (_m *IProductRepository) ReserveInventory(ctx context.Context, quantities map[int64]int, evs ...events.Event) error {
+	_va := make([]interface{}, len(evs))
+	for _i := range evs {
+		_va[_i] = evs[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, quantities)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReserveInventory")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, map[int64]int, ...events.Event) error); ok {
+		r0 = rf(ctx, quantities, evs...)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Update provides a mock function with given fields: ctx, product
 func (_m *IProductRepository) Update(ctx context.Context, product *repository.Product) (*repository.Product, error) {
 	ret := _m.Called(ctx, product)
@@ -187,26 +230,6 @@ func (_m *IProductRepository) Update(ctx context.Context, product *repository.Pr
 	return r0, r1
 }
 
-// GetQuerySearch provides a mock function with given fields: db, req
-func (_m *IProductRepository) GetQuerySearch(db *gorm.DB, req *model.GetProductsRequest) *gorm.DB {
-	ret := _m.Called(db, req)
-
-	if len(ret) == 0 {
-		panic("no return value specified for GetQuerySearch")
-	}
-
-	var r0 *gorm.DB
-	if rf, ok := ret.Get(0).(func(*gorm.DB, *model.GetProductsRequest) *gorm.DB); ok {
-		r0 = rf(db, req)
-	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*gorm.DB)
-		}
-	}
-
-	return r0
-}
-
 // NewIProductRepository creates a new instance of IProductRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewIProductRepository(t interface {
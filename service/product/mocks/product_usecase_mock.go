@@ -1,9 +1,10 @@
-// Code generated by mockery v2.46.3. DO NOT EDIT.
+// Code generated by mockery v2.53.5. DO NOT EDIT.
 
 package mocks
 
 import (
 	context "context"
+	events "th3y3m/e-commerce-microservices/pkg/events"
 	model "th3y3m/e-commerce-microservices/service/product/model"
 
 	mock "github.com/stretchr/testify/mock"
@@ -182,17 +183,17 @@ func (_m *IProductUsecase) GetProductPriceAfterDiscount(ctx context.Context, req
 	return r0, r1
 }
 
-// UpdateInventory provides a mock function with given fields: ctx, userId, cartId
-func (_m *IProductUsecase) UpdateInventory(ctx context.Context, userId int64, cartId int64) error {
-	ret := _m.Called(ctx, userId, cartId)
+// UpdateInventory provides a mock function with given fields: ctx, order
+func (_m *IProductUsecase) UpdateInventory(ctx context.Context, order events.OrderPlaced) error {
+	ret := _m.Called(ctx, order)
 
 	if len(ret) == 0 {
 		panic("no return value specified for UpdateInventory")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) error); ok {
-		r0 = rf(ctx, userId, cartId)
+	if rf, ok := ret.Get(0).(func(context.Context, events.OrderPlaced) error); ok {
+		r0 = rf(ctx, order)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -5,34 +5,6 @@ import (
 	"time"
 )
 
-type GetCartItemResponse struct {
-	CartID    int64 `json:"cart_id"`
-	ProductID int64 `json:"product_id"`
-	Quantity  int   `json:"quantity"`
-}
-type GetCartItemsRequest struct {
-	CartID    *int64 `json:"cart_id"`
-	ProductID *int64 `json:"product_id"`
-}
-
-type GetDiscountResponse struct {
-	DiscountID    int64   `json:"discount_id"`
-	DiscountType  string  `json:"discount_type"`
-	DiscountValue float64 `json:"discount_value"`
-	StartDate     string  `json:"start_date"`
-	EndDate       string  `json:"end_date"`
-	IsDeleted     bool    `json:"is_deleted"`
-	CreatedAt     string  `json:"created_at"`
-	UpdatedAt     string  `json:"updated_at"`
-}
-type ProductDiscount struct {
-	ProductID  int64 `json:"product_id"`
-	DiscountID int64 `json:"discount_id"`
-}
-type GetProductDiscountsRequest struct {
-	ProductID  *int64 `json:"product_id"`
-	DiscountID *int64 `json:"discount_id"`
-}
 type GetProductPriceAfterDiscount struct {
 	ProductID int64 `json:"product_id"`
 }
@@ -2,34 +2,22 @@ package rabbitmq
 
 import (
 	"context"
-	"log"
-	"strconv"
-	"th3y3m/e-commerce-microservices/pkg/rabbitmq"
+	"th3y3m/e-commerce-microservices/pkg/broker"
+	"th3y3m/e-commerce-microservices/pkg/events"
+	"th3y3m/e-commerce-microservices/pkg/inbox"
+	"th3y3m/e-commerce-microservices/pkg/logging"
 	"th3y3m/e-commerce-microservices/service/product/usecase"
 )
 
-func ConsumeInventoryUpdates(ctx context.Context, productUsecase usecase.IProductUsecase) error {
-	return rabbitmq.ConsumeMessages(ctx, "inventory_update_queue", func(message map[string]string) error {
-		userIdStr := message["userId"]
-		cartIdStr := message["cartId"]
-
-		userId, err := strconv.ParseInt(userIdStr, 10, 64)
-		if err != nil {
-			log.Printf("Failed to convert userId to int64: %v", err)
-			return err
-		}
-
-		cartId, err := strconv.ParseInt(cartIdStr, 10, 64)
-		if err != nil {
-			log.Printf("Failed to convert cartId to int64: %v", err)
-			return err
-		}
-
-		if err := productUsecase.UpdateInventory(ctx, userId, cartId); err != nil {
-			log.Printf("Failed to update inventory: %v", err)
+// ConsumeInventoryUpdates handles every placed order once, a redelivered order does not take its
+// stock again
+func ConsumeInventoryUpdates(ctx context.Context, b broker.Broker, productUsecase usecase.IProductUsecase, store inbox.Store) error {
+	return broker.Consume(ctx, b, broker.InventoryUpdates, inbox.Once(store, broker.InventoryUpdates.Queue, func(ctx context.Context, _ *events.Envelope, event events.OrderPlaced) error {
+		if err := productUsecase.UpdateInventory(ctx, event); err != nil {
+			logging.Logger().WithContext(ctx).Errorf("Failed to update inventory: %v", err)
 			return err
 		}
 
 		return nil
-	})
+	}))
 }
@@ -0,0 +1,21 @@
+package rabbitmq
+
+import (
+	"context"
+	"strconv"
+	"th3y3m/e-commerce-microservices/pkg/broker"
+	"th3y3m/e-commerce-microservices/pkg/events"
+)
+
+// PublishProductChangedEvent tells the gateway to drop cached product responses
+func PublishProductChangedEvent(ctx context.Context, b broker.Broker, event string, productId int64) error {
+	path := "/api/products"
+	if productId != 0 {
+		path += "/" + strconv.FormatInt(productId, 10)
+	}
+
+	return broker.PublishEvent(ctx, b, events.CacheInvalidated{
+		Path:   path,
+		Reason: event,
+	})
+}
@@ -7,11 +7,15 @@ import (
 	"fmt"
 	"io"
 	"log"
+	"strconv"
+	"th3y3m/e-commerce-microservices/pkg/cache"
 	"th3y3m/e-commerce-microservices/pkg/elasticsearch_server"
+	"th3y3m/e-commerce-microservices/pkg/events"
+	"th3y3m/e-commerce-microservices/pkg/outbox"
+	"th3y3m/e-commerce-microservices/pkg/postgresql"
 	"th3y3m/e-commerce-microservices/service/product/model"
 
 	"github.com/elastic/go-elasticsearch/v7"
-	"github.com/redis/go-redis/v9"
 	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
 )
@@ -19,7 +23,8 @@ import (
 type productRepository struct {
 	log           *logrus.Logger
 	db            *gorm.DB
-	redis         *redis.Client
+	products      *cache.Cache[*Product]
+	lists         *cache.Cache[[]*Product]
 	elasticClient *elasticsearch.Client // Add Elasticsearch client
 }
 
@@ -28,181 +33,123 @@ type IProductRepository interface {
 	GetAll(ctx context.Context) ([]*Product, error)
 	Create(ctx context.Context, product *Product) (*Product, error)
 	Update(ctx context.Context, product *Product) (*Product, error)
+	ReserveInventory(ctx context.Context, quantities map[int64]int, evs ...events.Event) error
 	Delete(ctx context.Context, productID int64) error
 	GetQuerySearch(db *gorm.DB, req *model.GetProductsRequest) *gorm.DB
 	GetList(ctx context.Context, req *model.GetProductsRequest) ([]*Product, error)
 }
 
-func NewProductRepository(db *gorm.DB, redis *redis.Client, log *logrus.Logger, elasticClient *elasticsearch.Client) IProductRepository {
+func NewProductRepository(db *gorm.DB, store cache.Store, cfg cache.Config, log *logrus.Logger, elasticClient *elasticsearch.Client) IProductRepository {
 	return &productRepository{
 		db:            db,
-		redis:         redis,
+		products:      cache.New[*Product](store, "product", cfg).CacheNotFound(gorm.ErrRecordNotFound),
+		lists:         cache.New[[]*Product](store, "products", cfg),
 		log:           log,
 		elasticClient: elasticClient,
 	}
 }
 
 func (pr *productRepository) Get(ctx context.Context, productID int64) (*Product, error) {
-	pr.log.Infof("Fetching product with ID: %d", productID)
-	var product Product
-	cacheKey := fmt.Sprintf("product:%d", productID)
-
-	// Try to get the product from Redis cache
-	if pr.redis != nil {
-		cachedProduct, err := pr.redis.Get(ctx, cacheKey).Result()
-		if err == nil {
-			if err := json.Unmarshal([]byte(cachedProduct), &product); err == nil {
-				pr.log.Infof("Product found in cache: %d", productID)
-				return &product, nil
-			}
-		} else if err != redis.Nil {
-			pr.log.Warnf("Failed to get product from Redis: %v", err)
+	pr.log.WithContext(ctx).Infof("Fetching product with ID: %d", productID)
+	product, err := pr.products.GetOrLoad(ctx, strconv.FormatInt(productID, 10), func(ctx context.Context) (*Product, error) {
+		var product Product
+		if err := pr.db.WithContext(ctx).First(&product, productID).Error; err != nil {
+			return nil, err
 		}
-	} else {
-		pr.log.Warn("Redis client is not initialized")
-	}
-
-	// If not found in cache, get from database
-	if err := pr.db.WithContext(ctx).First(&product, productID).Error; err != nil {
-		pr.log.Errorf("Error fetching product from database: %v", err)
+		return &product, nil
+	})
+	if err != nil {
+		pr.log.WithContext(ctx).Errorf("Error fetching product: %v", err)
 		return nil, err
 	}
-
-	// Save to cache if Redis is available
-	if pr.redis != nil {
-		productJSON, _ := json.Marshal(product)
-		if err := pr.redis.Set(ctx, cacheKey, productJSON, 0).Err(); err != nil {
-			pr.log.Warnf("Failed to save product to Redis: %v", err)
-		} else {
-			pr.log.Infof("Product saved to cache: %d", productID)
-		}
-	}
-
-	return &product, nil
+	return product, nil
 }
 
 func (pr *productRepository) GetAll(ctx context.Context) ([]*Product, error) {
-	pr.log.Info("Fetching all products")
-	var products []*Product
-	cacheKey := "all_products"
-
-	// Try to get the products from Redis cache
-	if pr.redis != nil {
-		cachedProducts, err := pr.redis.Get(ctx, cacheKey).Result()
-		if err == nil {
-			if err := json.Unmarshal([]byte(cachedProducts), &products); err == nil {
-				pr.log.Info("Products found in cache")
-				return products, nil
-			}
-		} else if err != redis.Nil {
-			pr.log.Warnf("Failed to get products from Redis: %v", err)
+	pr.log.WithContext(ctx).Info("Fetching all products")
+	products, err := pr.lists.GetOrLoad(ctx, "all", func(ctx context.Context) ([]*Product, error) {
+		var products []*Product
+		if err := pr.db.WithContext(ctx).Find(&products).Error; err != nil {
+			return nil, err
 		}
-	} else {
-		pr.log.Warn("Redis client is not initialized")
-	}
-
-	// If not found in cache, get from database
-	if err := pr.db.WithContext(ctx).Find(&products).Error; err != nil {
-		pr.log.Errorf("Error fetching products from database: %v", err)
+		return products, nil
+	})
+	if err != nil {
+		pr.log.WithContext(ctx).Errorf("Error fetching products: %v", err)
 		return nil, err
 	}
-
-	// Save to cache if Redis is available
-	if pr.redis != nil {
-		productsJSON, _ := json.Marshal(products)
-		if err := pr.redis.Set(ctx, cacheKey, productsJSON, 0).Err(); err != nil {
-			pr.log.Warnf("Failed to save products to Redis: %v", err)
-		} else {
-			pr.log.Info("Products saved to cache")
-		}
-	}
-
 	return products, nil
 }
 
 func (pr *productRepository) Create(ctx context.Context, product *Product) (*Product, error) {
-	pr.log.Infof("Creating product: %+v", product)
+	pr.log.WithContext(ctx).Infof("Creating product: %+v", product)
 	if err := pr.db.WithContext(ctx).Create(product).Error; err != nil {
-		pr.log.Errorf("Error creating product: %v", err)
+		pr.log.WithContext(ctx).Errorf("Error creating product: %v", err)
 		return nil, err
 	}
-	cacheKey := fmt.Sprintf("product:%d", product.ProductID)
 
-	// Save to cache if Redis is available
-	if pr.redis != nil {
-		productJSON, _ := json.Marshal(product)
-		if err := pr.redis.Set(ctx, cacheKey, productJSON, 0).Err(); err != nil {
-			pr.log.Warnf("Failed to save product to Redis: %v", err)
-		} else {
-			pr.log.Infof("Product saved to cache: %d", product.ProductID)
-		}
-
-		// Invalidate the cache for all records
-		if err := pr.redis.Del(ctx, "all_products").Err(); err != nil {
-			pr.log.Warnf("Failed to invalidate all products cache: %v", err)
-		} else {
-			pr.log.Info("Invalidated cache for all products")
-		}
-	}
+	pr.products.Set(ctx, strconv.FormatInt(product.ProductID, 10), product)
+	pr.lists.InvalidateAll(ctx)
 
 	// Return the newly created product (with any updated fields)
 	return product, nil
 }
 
 func (pr *productRepository) Update(ctx context.Context, product *Product) (*Product, error) {
-	pr.log.Infof("Updating product: %+v", product)
+	pr.log.WithContext(ctx).Infof("Updating product: %+v", product)
 	if err := pr.db.WithContext(ctx).Save(product).Error; err != nil {
-		pr.log.Errorf("Error updating product: %v", err)
+		pr.log.WithContext(ctx).Errorf("Error updating product: %v", err)
 		return nil, err
 	}
-	cacheKey := fmt.Sprintf("product:%d", product.ProductID)
 
-	// Save to cache if Redis is available
-	if pr.redis != nil {
-		productJSON, _ := json.Marshal(product)
-		if err := pr.redis.Set(ctx, cacheKey, productJSON, 0).Err(); err != nil {
-			pr.log.Warnf("Failed to save product to Redis: %v", err)
-		} else {
-			pr.log.Infof("Product saved to cache: %d", product.ProductID)
-		}
-
-		// Invalidate the cache for all records
-		if err := pr.redis.Del(ctx, "all_products").Err(); err != nil {
-			pr.log.Warnf("Failed to invalidate all products cache: %v", err)
-		} else {
-			pr.log.Info("Invalidated cache for all products")
-		}
-	}
+	pr.products.Set(ctx, strconv.FormatInt(product.ProductID, 10), product)
+	pr.lists.InvalidateAll(ctx)
 
 	// Return the updated product
 	return product, nil
 }
 
-func (pr *productRepository) Delete(ctx context.Context, productID int64) error {
-	pr.log.Infof("Deleting product with ID: %d", productID)
-	if err := pr.db.WithContext(ctx).Delete(&Product{}, productID).Error; err != nil {
-		pr.log.Errorf("Error deleting product: %v", err)
+// ReserveInventory takes the quantities from the stock of the products, by product ID, and adds
+// the events to the outbox in one transaction
+func (pr *productRepository) ReserveInventory(ctx context.Context, quantities map[int64]int, evs ...events.Event) error {
+	pr.log.WithContext(ctx).Infof("Reserving inventory: %v", quantities)
+	// Joins the transaction of the inbox when called from an event handler
+	err := postgresql.DB(ctx, pr.db).WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for productID, quantity := range quantities {
+			err := tx.Model(&Product{}).
+				Where("product_id = ?", productID).
+				Update("quantity", gorm.Expr("quantity - ?", quantity)).Error
+			if err != nil {
+				return err
+			}
+		}
+		return outbox.Add(ctx, tx, evs...)
+	})
+	if err != nil {
+		pr.log.WithContext(ctx).Errorf("Error reserving inventory: %v", err)
 		return err
 	}
 
-	cacheKey := fmt.Sprintf("product:%d", productID)
+	keys := make([]string, 0, len(quantities))
+	for productID := range quantities {
+		keys = append(keys, strconv.FormatInt(productID, 10))
+	}
+	pr.products.Delete(ctx, keys...)
+	pr.lists.InvalidateAll(ctx)
 
-	// Delete from cache if Redis is available
-	if pr.redis != nil {
-		if err := pr.redis.Del(ctx, cacheKey).Err(); err != nil {
-			pr.log.Warnf("Failed to delete product from Redis: %v", err)
-		} else {
-			pr.log.Infof("Product deleted from cache: %d", productID)
-		}
+	return nil
+}
 
-		// Invalidate the cache for all records
-		if err := pr.redis.Del(ctx, "all_products").Err(); err != nil {
-			pr.log.Warnf("Failed to invalidate all products cache: %v", err)
-		} else {
-			pr.log.Info("Invalidated cache for all products")
-		}
+func (pr *productRepository) Delete(ctx context.Context, productID int64) error {
+	pr.log.WithContext(ctx).Infof("Deleting product with ID: %d", productID)
+	if err := pr.db.WithContext(ctx).Delete(&Product{}, productID).Error; err != nil {
+		pr.log.WithContext(ctx).Errorf("Error deleting product: %v", err)
+		return err
 	}
 
+	pr.products.Delete(ctx, strconv.FormatInt(productID, 10))
+	pr.lists.InvalidateAll(ctx)
+
 	return nil
 }
 
@@ -257,7 +204,7 @@ func (pr *productRepository) GetQuerySearch(db *gorm.DB, req *model.GetProductsR
 }
 
 func (pr *productRepository) GetList(ctx context.Context, req *model.GetProductsRequest) ([]*Product, error) {
-	pr.log.Infof("Fetching product list with request: %+v", req)
+	pr.log.WithContext(ctx).Infof("Fetching product list with request: %+v", req)
 	var products []*Product
 
 	// Handle the database query and filtering first
@@ -288,11 +235,11 @@ func (pr *productRepository) GetList(ctx context.Context, req *model.GetProducts
 		Find(&products)
 
 	if result.Error != nil {
-		pr.log.Errorf("Error fetching product list: %v", result.Error)
+		pr.log.WithContext(ctx).Errorf("Error fetching product list: %v", result.Error)
 		return nil, result.Error
 	}
 
-	pr.log.Infof("Fetched %d products from the database", len(products))
+	pr.log.WithContext(ctx).Infof("Fetched %d products from the database", len(products))
 
 	// If Elasticsearch is available, index the products and perform a search
 	if pr.elasticClient == nil {
@@ -301,18 +248,18 @@ func (pr *productRepository) GetList(ctx context.Context, req *model.GetProducts
 
 	// Ensure the index exists
 	if err := elasticsearch_server.CreateIndex(pr.elasticClient, "products"); err != nil {
-		pr.log.Errorf("Error creating index or index already exists: %v", err)
+		pr.log.WithContext(ctx).Errorf("Error creating index or index already exists: %v", err)
 	}
 
 	err := pr.indexProducts(products)
 	if err != nil {
-		pr.log.Errorf("Error indexing products to Elasticsearch: %v", err)
+		pr.log.WithContext(ctx).Errorf("Error indexing products to Elasticsearch: %v", err)
 	}
 
 	if req.ProductName != "" {
 		products, err = pr.searchProducts(ctx, req.ProductName)
 		if err != nil {
-			pr.log.Errorf("Error searching products in Elasticsearch: %v", err)
+			pr.log.WithContext(ctx).Errorf("Error searching products in Elasticsearch: %v", err)
 		}
 	}
 
@@ -361,7 +308,7 @@ func (pr *productRepository) indexProducts(products []*Product) error {
 }
 
 func (pr *productRepository) searchProducts(ctx context.Context, name string) ([]*Product, error) {
-	pr.log.Infof("Searching for products with name: %s", name)
+	pr.log.WithContext(ctx).Infof("Searching for products with name: %s", name)
 
 	searchQuery := map[string]interface{}{
 		"query": map[string]interface{}{
@@ -408,12 +355,12 @@ func (pr *productRepository) searchProducts(ctx context.Context, name string) ([
 	// Serialize the search query to JSON
 	queryJSON, err := json.Marshal(searchQuery)
 	if err != nil {
-		pr.log.Errorf("Error marshaling search query: %v", err)
+		pr.log.WithContext(ctx).Errorf("Error marshaling search query: %v", err)
 		return nil, err
 	}
 
 	// Log the search query
-	pr.log.Infof("Elasticsearch search query: %s", string(queryJSON))
+	pr.log.WithContext(ctx).Infof("Elasticsearch search query: %s", string(queryJSON))
 
 	// Execute the search request to Elasticsearch
 	res, err := pr.elasticClient.Search(
@@ -423,23 +370,23 @@ func (pr *productRepository) searchProducts(ctx context.Context, name string) ([
 		pr.elasticClient.Search.WithTrackTotalHits(true),
 	)
 	if err != nil {
-		pr.log.Errorf("Error executing Elasticsearch search: %v", err)
+		pr.log.WithContext(ctx).Errorf("Error executing Elasticsearch search: %v", err)
 		return nil, err
 	}
 	defer res.Body.Close()
 
 	// Log the Elasticsearch response status
-	pr.log.Infof("Elasticsearch response status: %s", res.Status())
+	pr.log.WithContext(ctx).Infof("Elasticsearch response status: %s", res.Status())
 
 	// Read the full response body
 	bodyBytes, err := io.ReadAll(res.Body)
 	if err != nil {
-		pr.log.Errorf("Failed to read Elasticsearch response body: %v", err)
+		pr.log.WithContext(ctx).Errorf("Failed to read Elasticsearch response body: %v", err)
 		return nil, err
 	}
 
 	// Log the full response body
-	pr.log.Infof("Elasticsearch response body: %s", string(bodyBytes))
+	pr.log.WithContext(ctx).Infof("Elasticsearch response body: %s", string(bodyBytes))
 
 	// Parse the Elasticsearch response
 	var esResponse struct {
@@ -450,7 +397,7 @@ func (pr *productRepository) searchProducts(ctx context.Context, name string) ([
 		} `json:"hits"`
 	}
 	if err := json.Unmarshal(bodyBytes, &esResponse); err != nil {
-		pr.log.Errorf("Error decoding Elasticsearch response: %v", err)
+		pr.log.WithContext(ctx).Errorf("Error decoding Elasticsearch response: %v", err)
 		return nil, err
 	}
 
@@ -460,6 +407,6 @@ func (pr *productRepository) searchProducts(ctx context.Context, name string) ([
 		products = append(products, &hit.Source)
 	}
 
-	pr.log.Infof("Found %d products from Elasticsearch", len(products))
+	pr.log.WithContext(ctx).Infof("Found %d products from Elasticsearch", len(products))
 	return products, nil
 }
@@ -1,12 +1,9 @@
 package usecase
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
-	"fmt"
-	"net/http"
-	"th3y3m/e-commerce-microservices/pkg/constant"
+	"th3y3m/e-commerce-microservices/pkg/clients"
+	"th3y3m/e-commerce-microservices/pkg/events"
 	"th3y3m/e-commerce-microservices/pkg/util"
 	"th3y3m/e-commerce-microservices/service/product/model"
 	"th3y3m/e-commerce-microservices/service/product/repository"
@@ -15,12 +12,20 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
+// Clients of the services prices and stock depend on
+type Clients struct {
+	ProductDiscount *clients.ProductDiscountClient
+	Discount        *clients.DiscountClient
+	CartItem        *clients.CartItemClient
+}
+
 const tsCreateTimeLayout = "2006-01-02 15:04:05 +0700"
 const fallbackPrice = 1000.0 // Fallback price if the calculated price is less than 0
 
 type ProductUsecase struct {
 	log         *logrus.Logger
 	productRepo repository.IProductRepository
+	clients     Clients
 }
 
 type IProductUsecase interface {
@@ -31,25 +36,26 @@ type IProductUsecase interface {
 	DeleteProduct(ctx context.Context, req *model.DeleteProductRequest) error
 	GetProductList(ctx context.Context, req *model.GetProductsRequest) (*util.PaginatedList[model.GetProductListResponse], error)
 	GetProductPriceAfterDiscount(ctx context.Context, req *model.GetProductPriceAfterDiscount) (float64, error)
-	UpdateInventory(ctx context.Context, userId, cartId int64) error
+	UpdateInventory(ctx context.Context, order events.OrderPlaced) error
 }
 
-func NewProductUsecase(productRepo repository.IProductRepository, log *logrus.Logger) IProductUsecase {
+func NewProductUsecase(productRepo repository.IProductRepository, clients Clients, log *logrus.Logger) IProductUsecase {
 	return &ProductUsecase{
 		productRepo: productRepo,
+		clients:     clients,
 		log:         log,
 	}
 }
 
 func (pu *ProductUsecase) GetProduct(ctx context.Context, req *model.GetProductRequest) (*model.GetProductResponse, error) {
-	pu.log.Infof("Fetching product with ID: %d", req.ProductID)
+	pu.log.WithContext(ctx).Infof("Fetching product with ID: %d", req.ProductID)
 	product, err := pu.productRepo.Get(ctx, req.ProductID)
 	if err != nil {
-		pu.log.Errorf("Error fetching product: %v", err)
+		pu.log.WithContext(ctx).Errorf("Error fetching product: %v", err)
 		return nil, err
 	}
 
-	pu.log.Infof("Fetched product: %+v", product)
+	pu.log.WithContext(ctx).Infof("Fetched product: %+v", product)
 	return &model.GetProductResponse{
 		ProductID:   product.ProductID,
 		SellerID:    product.SellerID,
@@ -66,10 +72,10 @@ func (pu *ProductUsecase) GetProduct(ctx context.Context, req *model.GetProductR
 }
 
 func (pu *ProductUsecase) GetAllProducts(ctx context.Context) ([]*model.GetProductResponse, error) {
-	pu.log.Info("Fetching all products")
+	pu.log.WithContext(ctx).Info("Fetching all products")
 	products, err := pu.productRepo.GetAll(ctx)
 	if err != nil {
-		pu.log.Errorf("Error fetching all products: %v", err)
+		pu.log.WithContext(ctx).Errorf("Error fetching all products: %v", err)
 		return nil, err
 	}
 
@@ -90,12 +96,12 @@ func (pu *ProductUsecase) GetAllProducts(ctx context.Context) ([]*model.GetProdu
 		})
 	}
 
-	pu.log.Infof("Fetched %d products", len(productResponses))
+	pu.log.WithContext(ctx).Infof("Fetched %d products", len(productResponses))
 	return productResponses, nil
 }
 
 func (pu *ProductUsecase) CreateProduct(ctx context.Context, product *model.CreateProductRequest) (*model.GetProductResponse, error) {
-	pu.log.Infof("Creating product: %+v", product)
+	pu.log.WithContext(ctx).Infof("Creating product: %+v", product)
 	productData := repository.Product{
 		SellerID:    product.SellerID,
 		ProductName: product.ProductName,
@@ -108,11 +114,11 @@ func (pu *ProductUsecase) CreateProduct(ctx context.Context, product *model.Crea
 
 	createdProduct, err := pu.productRepo.Create(ctx, &productData)
 	if err != nil {
-		pu.log.Errorf("Error creating product: %v", err)
+		pu.log.WithContext(ctx).Errorf("Error creating product: %v", err)
 		return nil, err
 	}
 
-	pu.log.Infof("Created product: %+v", createdProduct)
+	pu.log.WithContext(ctx).Infof("Created product: %+v", createdProduct)
 	return &model.GetProductResponse{
 		ProductID:   createdProduct.ProductID,
 		SellerID:    createdProduct.SellerID,
@@ -129,10 +135,10 @@ func (pu *ProductUsecase) CreateProduct(ctx context.Context, product *model.Crea
 }
 
 func (pu *ProductUsecase) DeleteProduct(ctx context.Context, req *model.DeleteProductRequest) error {
-	pu.log.Infof("Deleting product with ID: %d", req.ProductID)
+	pu.log.WithContext(ctx).Infof("Deleting product with ID: %d", req.ProductID)
 	product, err := pu.productRepo.Get(ctx, req.ProductID)
 	if err != nil {
-		pu.log.Errorf("Error fetching product for deletion: %v", err)
+		pu.log.WithContext(ctx).Errorf("Error fetching product for deletion: %v", err)
 		return err
 	}
 
@@ -140,19 +146,19 @@ func (pu *ProductUsecase) DeleteProduct(ctx context.Context, req *model.DeletePr
 
 	_, err = pu.productRepo.Update(ctx, product)
 	if err != nil {
-		pu.log.Errorf("Error updating product for deletion: %v", err)
+		pu.log.WithContext(ctx).Errorf("Error updating product for deletion: %v", err)
 		return err
 	}
 
-	pu.log.Infof("Deleted product with ID: %d", req.ProductID)
+	pu.log.WithContext(ctx).Infof("Deleted product with ID: %d", req.ProductID)
 	return nil
 }
 
 func (pu *ProductUsecase) UpdateProduct(ctx context.Context, rep *model.UpdateProductRequest) (*model.GetProductResponse, error) {
-	pu.log.Infof("Updating product with ID: %d", rep.ProductID)
+	pu.log.WithContext(ctx).Infof("Updating product with ID: %d", rep.ProductID)
 	product, err := pu.productRepo.Get(ctx, rep.ProductID)
 	if err != nil {
-		pu.log.Errorf("Error fetching product for update: %v", err)
+		pu.log.WithContext(ctx).Errorf("Error fetching product for update: %v", err)
 		return nil, err
 	}
 
@@ -167,11 +173,11 @@ func (pu *ProductUsecase) UpdateProduct(ctx context.Context, rep *model.UpdatePr
 
 	updatedProduct, err := pu.productRepo.Update(ctx, product)
 	if err != nil {
-		pu.log.Errorf("Error updating product: %v", err)
+		pu.log.WithContext(ctx).Errorf("Error updating product: %v", err)
 		return nil, err
 	}
 
-	pu.log.Infof("Updated product: %+v", updatedProduct)
+	pu.log.WithContext(ctx).Infof("Updated product: %+v", updatedProduct)
 	return &model.GetProductResponse{
 		ProductID:   updatedProduct.ProductID,
 		SellerID:    updatedProduct.SellerID,
@@ -188,10 +194,10 @@ func (pu *ProductUsecase) UpdateProduct(ctx context.Context, rep *model.UpdatePr
 }
 
 func (pu *ProductUsecase) GetProductList(ctx context.Context, req *model.GetProductsRequest) (*util.PaginatedList[model.GetProductListResponse], error) {
-	pu.log.Infof("Fetching product list with request: %+v", req)
+	pu.log.WithContext(ctx).Infof("Fetching product list with request: %+v", req)
 	products, err := pu.productRepo.GetList(ctx, req)
 	if err != nil {
-		pu.log.Errorf("Error fetching product list: %v", err)
+		pu.log.WithContext(ctx).Errorf("Error fetching product list: %v", err)
 		return nil, err
 	}
 
@@ -201,7 +207,7 @@ func (pu *ProductUsecase) GetProductList(ctx context.Context, req *model.GetProd
 			ProductID: product.ProductID,
 		})
 		if err != nil {
-			pu.log.Errorf("Error fetching product price after discount: %v", err)
+			pu.log.WithContext(ctx).Errorf("Error fetching product price after discount: %v", err)
 			price = product.Price
 		}
 		productResponses = append(productResponses, model.GetProductListResponse{
@@ -230,94 +236,47 @@ func (pu *ProductUsecase) GetProductList(ctx context.Context, req *model.GetProd
 
 	list.GetTotalPages()
 
-	pu.log.Infof("Fetched %d products", len(productResponses))
+	pu.log.WithContext(ctx).Infof("Fetched %d products", len(productResponses))
 	return list, nil
 }
 
 func (pu *ProductUsecase) GetProductPriceAfterDiscount(ctx context.Context, req *model.GetProductPriceAfterDiscount) (float64, error) {
-	pu.log.Infof("Fetching product price after discount with product ID: %d", req.ProductID)
+	pu.log.WithContext(ctx).Infof("Fetching product price after discount with product ID: %d", req.ProductID)
 
 	product, err := pu.productRepo.Get(ctx, req.ProductID)
 	if err != nil {
-		pu.log.Errorf("Error fetching product: %v", err)
+		pu.log.WithContext(ctx).Errorf("Error fetching product: %v", err)
 		return 0, err
 	}
 
-	productDiscountsRequest := &model.GetProductDiscountsRequest{
-		ProductID: &req.ProductID,
-	}
-
-	data, err := json.Marshal(productDiscountsRequest)
-	if err != nil {
-		pu.log.Errorf("Failed to marshal product discounts request: %v", err)
-		return product.Price, err
-	}
-
 	// Fetch the product discounts
-	url := constant.PRODUCT_DISCOUNT_SERVICE
-	client := &http.Client{
-		Timeout: 10 * time.Second,
-	}
-
-	request, err := http.NewRequest("GET", url, bytes.NewBuffer(data))
-	if err != nil {
-		return product.Price, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	resp, err := client.Do(request)
+	productDiscounts, err := pu.clients.ProductDiscount.List(ctx, clients.ProductDiscountFilter{ProductID: &req.ProductID})
 	if err != nil {
-		return product.Price, fmt.Errorf("failed to execute request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		if resp.StatusCode == http.StatusNotFound {
-			pu.log.Infof("No discounts found for product with ID: %d", req.ProductID)
+		if clients.IsNotFound(err) {
+			pu.log.WithContext(ctx).Infof("No discounts found for product with ID: %d", req.ProductID)
 			return product.Price, nil
 		}
-		return product.Price, fmt.Errorf("product discount service returned non-OK status: %d", resp.StatusCode)
-	}
-
-	var productDiscounts []*model.ProductDiscount
-	if err := json.NewDecoder(resp.Body).Decode(&productDiscounts); err != nil {
-		return product.Price, fmt.Errorf("failed to decode product discounts response: %w", err)
+		return product.Price, err
 	}
 
 	var percentageDiscounts []float64
 	var fixedDiscounts []float64
 
 	for _, discount := range productDiscounts {
-		url := fmt.Sprintf("%s/%d", constant.DISCOUNT_SERVICE, discount.DiscountID)
-
-		request, err := http.NewRequest("GET", url, nil)
+		discountEvent, err := pu.clients.Discount.Get(ctx, discount.DiscountID)
 		if err != nil {
-			return product.Price, fmt.Errorf("failed to create discount request: %w", err)
-		}
-
-		resp, err := client.Do(request)
-		if err != nil {
-			return product.Price, fmt.Errorf("failed to execute discount request: %w", err)
-		}
-		defer resp.Body.Close()
-
-		if resp.StatusCode != http.StatusOK {
-			return product.Price, fmt.Errorf("discount service returned non-OK status: %d", resp.StatusCode)
-		}
-
-		var discountEvent model.GetDiscountResponse
-		if err := json.NewDecoder(resp.Body).Decode(&discountEvent); err != nil {
-			return product.Price, fmt.Errorf("failed to decode discount response: %w", err)
+			return product.Price, err
 		}
 
 		startDate, err := time.Parse(tsCreateTimeLayout, discountEvent.StartDate)
 		if err != nil {
-			pu.log.Errorf("Error parsing start date: %v", err)
+			pu.log.WithContext(ctx).Errorf("Error parsing start date: %v", err)
 			return product.Price, err
 		}
 
 		endDate, err := time.Parse(tsCreateTimeLayout, discountEvent.EndDate)
 		if err != nil {
-			pu.log.Errorf("Error parsing end date: %v", err)
+			pu.log.WithContext(ctx).Errorf("Error parsing end date: %v", err)
 			return product.Price, err
 		}
 
@@ -345,62 +304,31 @@ func (pu *ProductUsecase) GetProductPriceAfterDiscount(ctx context.Context, req
 		product.Price = fallbackPrice
 	}
 
-	pu.log.Infof("Fetched product price after discount: %f", product.Price)
+	pu.log.WithContext(ctx).Infof("Fetched product price after discount: %f", product.Price)
 	return product.Price, nil
 }
 
-func (o *ProductUsecase) UpdateInventory(ctx context.Context, userId, cartId int64) error {
-	cartItemReq := model.GetCartItemsRequest{
-		CartID: &cartId,
-	}
-	cartItemData, err := json.Marshal(cartItemReq)
-	if err != nil {
-		o.log.Errorf("Failed to marshal order data: %v", err)
-		return err
-	}
-
-	url := constant.CART_ITEM_SERVICE
-	req, err := http.NewRequest("GET", url, bytes.NewBuffer(cartItemData))
+// UpdateInventory takes the stock of the cart of a placed order. The gateway is told to drop the
+// cached products, and subscribers that the stock was reserved, once the stock changed.
+func (o *ProductUsecase) UpdateInventory(ctx context.Context, order events.OrderPlaced) error {
+	productsList, err := o.clients.CartItem.List(ctx, clients.CartItemFilter{CartID: &order.CartID})
 	if err != nil {
-		o.log.Errorf("Failed to create request: %v", err)
-		return err
-	}
-
-	// Set the context and execute the request
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		o.log.Errorf("Failed to execute request: %v", err)
-		return err
-	}
-	defer resp.Body.Close()
-
-	// Check if the request was successful
-	if resp.StatusCode != http.StatusOK {
-		o.log.Errorf("cart item service returned non-OK status: %d", resp.StatusCode)
-		return fmt.Errorf("cart item service returned non-OK status: %d", resp.StatusCode)
-	}
-
-	// Decode the response into cart items
-	var productsList []model.GetCartItemResponse
-	err = json.NewDecoder(resp.Body).Decode(&productsList)
-	if err != nil {
-		o.log.Errorf("Failed to decode response: %v", err)
+		o.log.WithContext(ctx).Errorf("Failed to fetch the cart items of order %d: %v", order.OrderID, err)
 		return err
 	}
 
+	quantities := make(map[int64]int, len(productsList))
 	for _, product := range productsList {
-		p, err := o.productRepo.Get(ctx, product.ProductID)
-		if err != nil {
-			return err
-		}
-
-		p.Quantity -= product.Quantity
-		_, err = o.productRepo.Update(ctx, p)
-		if err != nil {
-			return err
-		}
-	}
-
-	return nil
+		quantities[product.ProductID] += product.Quantity
+	}
+
+	return o.productRepo.ReserveInventory(ctx, quantities,
+		events.InventoryReserved{
+			OrderID:    order.OrderID,
+			CustomerID: order.CustomerID,
+			CartID:     order.CartID,
+		},
+		// Stock levels changed for every product of the cart
+		events.CacheInvalidated{Path: "/api/products", Reason: "inventory_updated"},
+	)
 }
@@ -18,6 +18,7 @@ func TestCreateProduct(t *testing.T) {
 	mockRepo := mocks.NewIProductRepository(t)
 	productUsecase := NewProductUsecase(
 		mockRepo,
+		Clients{},
 		log,
 	)
 
@@ -46,6 +47,7 @@ func TestGetProduct(t *testing.T) {
 	mockRepo := mocks.NewIProductRepository(t)
 	productUsecase := NewProductUsecase(
 		mockRepo,
+		Clients{},
 		log,
 	)
 
@@ -74,6 +76,7 @@ func TestGetAllProducts(t *testing.T) {
 	mockRepo := mocks.NewIProductRepository(t)
 	productUsecase := NewProductUsecase(
 		mockRepo,
+		Clients{},
 		log,
 	)
 
@@ -108,6 +111,7 @@ func TestUpdateProduct(t *testing.T) {
 	mockRepo := mocks.NewIProductRepository(t)
 	productUsecase := NewProductUsecase(
 		mockRepo,
+		Clients{},
 		log,
 	)
 
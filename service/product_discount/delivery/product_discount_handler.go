@@ -9,22 +9,22 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
-func CreateProductDiscount(c *gin.Context) {
+func (h *handler) CreateProductDiscount(c *gin.Context) {
 	var req model.CreateProductDiscountRequest
 	err := c.BindJSON(&req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(400, gin.H{
 			"error": "Bad Request",
 		})
 		return
 	}
 
-	module := dependency_injection.NewProductDiscountUsecaseProvider()
+	module := dependency_injection.NewProductDiscountUsecaseProvider(h.cfg)
 
 	productDiscount, err := module.CreateProductDiscount(c, &req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(500, gin.H{
 			"error": "Internal Server Error",
 		})
@@ -34,13 +34,13 @@ func CreateProductDiscount(c *gin.Context) {
 	c.JSON(200, productDiscount)
 }
 
-func DeleteProductDiscount(c *gin.Context) {
-	module := dependency_injection.NewProductDiscountUsecaseProvider()
+func (h *handler) DeleteProductDiscount(c *gin.Context) {
+	module := dependency_injection.NewProductDiscountUsecaseProvider(h.cfg)
 
 	var req model.DeleteProductDiscountRequest
 	err := c.BindJSON(&req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(400, gin.H{
 			"error": "Bad Request",
 		})
@@ -49,7 +49,7 @@ func DeleteProductDiscount(c *gin.Context) {
 
 	err = module.DeleteProductDiscount(c, &req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(500, gin.H{
 			"error": "Internal Server Error",
 		})
@@ -61,13 +61,13 @@ func DeleteProductDiscount(c *gin.Context) {
 	})
 }
 
-func GetProductDiscountList(c *gin.Context) {
-	module := dependency_injection.NewProductDiscountUsecaseProvider()
+func (h *handler) GetProductDiscountList(c *gin.Context) {
+	module := dependency_injection.NewProductDiscountUsecaseProvider(h.cfg)
 
 	var req model.GetProductDiscountsRequest
-	err := c.BindJSON(&req)
+	err := c.ShouldBindQuery(&req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(400, gin.H{
 			"error": "Bad Request",
 		})
@@ -77,13 +77,13 @@ func GetProductDiscountList(c *gin.Context) {
 	productDiscounts, err := module.GetProductDiscountList(c, &req)
 	if err != nil {
 		if err == constant.ErrNoProductDiscountsFound {
-			logrus.Infof("No product discounts found for request: %+v", req)
+			logrus.WithContext(c).Infof("No product discounts found for request: %+v", req)
 			c.JSON(404, gin.H{
 				"error": "Not Found",
 			})
 			return
 		}
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(500, gin.H{
 			"error": "Internal Server Error",
 		})
@@ -1,17 +1,36 @@
 package delivery
 
 import (
+	"th3y3m/e-commerce-microservices/pkg/authz"
+	"th3y3m/e-commerce-microservices/pkg/logging"
+	"th3y3m/e-commerce-microservices/pkg/metrics"
+	"th3y3m/e-commerce-microservices/pkg/tracing"
+	"th3y3m/e-commerce-microservices/service/product_discount/config"
+
 	"github.com/gin-gonic/gin"
 )
 
-func RegisterHandlers() *gin.Engine {
-	r := gin.Default()
+// handler serves the routes of the service, with the usecases built from cfg
+type handler struct {
+	cfg config.Config
+}
+
+func RegisterHandlers(cfg config.Config) *gin.Engine {
+	h := &handler{cfg: cfg}
+	keys := authz.NewKeys(cfg.Auth)
+
+	r := gin.New()
+	// Lets the request context, and the trace in it, reach the usecases through *gin.Context
+	r.ContextWithFallback = true
+	r.Use(gin.Recovery(), tracing.Middleware("product_discount"), logging.Middleware(), metrics.Middleware())
+	r.GET(metrics.Path, metrics.Handler())
+	r.Use(keys.Middleware())
 
 	productDiscount := r.Group("/api/productDiscounts")
 	{
-		productDiscount.GET("", GetProductDiscountList)
-		productDiscount.POST("", CreateProductDiscount)
-		productDiscount.DELETE("", DeleteProductDiscount)
+		productDiscount.GET("", h.GetProductDiscountList)
+		productDiscount.POST("", h.CreateProductDiscount)
+		productDiscount.DELETE("", h.DeleteProductDiscount)
 	}
 
 	return r
@@ -1,21 +1,21 @@
 package dependency_injection
 
 import (
+	"th3y3m/e-commerce-microservices/pkg/logging"
 	"th3y3m/e-commerce-microservices/pkg/postgresql"
 	redis_client "th3y3m/e-commerce-microservices/pkg/redis"
+	"th3y3m/e-commerce-microservices/service/product_discount/config"
 	"th3y3m/e-commerce-microservices/service/product_discount/repository"
 	"th3y3m/e-commerce-microservices/service/product_discount/usecase"
-
-	"github.com/sirupsen/logrus"
 )
 
-func NewProductDiscountRepositoryProvider() repository.IProductDiscountRepository {
-	log := logrus.New()
-	db, err := postgresql.NewGormDB()
+func NewProductDiscountRepositoryProvider(cfg config.Config) repository.IProductDiscountRepository {
+	log := logging.Logger()
+	db, err := postgresql.NewGormDB(cfg.Postgres)
 	if err != nil {
 		log.Error(err)
 	}
-	redis, err := redis_client.ConnectToRedis()
+	redis, err := redis_client.ConnectToRedis(cfg.Redis)
 	if err != nil {
 		log.Error(err)
 	}
@@ -23,8 +23,8 @@ func NewProductDiscountRepositoryProvider() repository.IProductDiscountRepositor
 	return repository.NewProductDiscountRepository(db, redis, log)
 }
 
-func NewProductDiscountUsecaseProvider() usecase.IProductDiscountUsecase {
-	log := logrus.New()
-	productDiscountRepository := NewProductDiscountRepositoryProvider()
+func NewProductDiscountUsecaseProvider(cfg config.Config) usecase.IProductDiscountUsecase {
+	log := logging.Logger()
+	productDiscountRepository := NewProductDiscountRepositoryProvider(cfg)
 	return usecase.NewProductDiscountUsecase(productDiscountRepository, log)
 }
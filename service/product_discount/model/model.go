@@ -6,8 +6,8 @@ type GetProductDiscountRequest struct {
 }
 
 type GetProductDiscountsRequest struct {
-	ProductID  *int64 `json:"product_id"`
-	DiscountID *int64 `json:"discount_id"`
+	ProductID  *int64 `json:"product_id" form:"product_id"`
+	DiscountID *int64 `json:"discount_id" form:"discount_id"`
 }
 
 type DeleteProductDiscountRequest struct {
@@ -28,7 +28,7 @@ func NewProductDiscountUsecase(productDiscountRepo repository.IProductDiscountRe
 }
 
 func (pu *productDiscountUsecase) CreateProductDiscount(ctx context.Context, productDiscount *model.CreateProductDiscountRequest) (*model.GetProductDiscountResponse, error) {
-	pu.log.Infof("Creating productDiscount: %+v", productDiscount)
+	pu.log.WithContext(ctx).Infof("Creating productDiscount: %+v", productDiscount)
 	productDiscountData := repository.ProductDiscount{
 		ProductID:  productDiscount.ProductID,
 		DiscountID: productDiscount.DiscountID,
@@ -36,11 +36,11 @@ func (pu *productDiscountUsecase) CreateProductDiscount(ctx context.Context, pro
 
 	createdProductDiscount, err := pu.productDiscountRepo.Create(ctx, &productDiscountData)
 	if err != nil {
-		pu.log.Errorf("Error creating productDiscount: %v", err)
+		pu.log.WithContext(ctx).Errorf("Error creating productDiscount: %v", err)
 		return nil, err
 	}
 
-	pu.log.Infof("Created productDiscount: %+v", createdProductDiscount)
+	pu.log.WithContext(ctx).Infof("Created productDiscount: %+v", createdProductDiscount)
 	return &model.GetProductDiscountResponse{
 		ProductID:  createdProductDiscount.ProductID,
 		DiscountID: createdProductDiscount.DiscountID,
@@ -48,7 +48,7 @@ func (pu *productDiscountUsecase) CreateProductDiscount(ctx context.Context, pro
 }
 
 func (pu *productDiscountUsecase) DeleteProductDiscount(ctx context.Context, req *model.DeleteProductDiscountRequest) error {
-	pu.log.Infof("Deleting productDiscount with ProductID: %d and DiscountID: %d", req.ProductID, req.DiscountID)
+	pu.log.WithContext(ctx).Infof("Deleting productDiscount with ProductID: %d and DiscountID: %d", req.ProductID, req.DiscountID)
 
 	request := &model.GetProductDiscountsRequest{
 		ProductID:  &req.ProductID,
@@ -56,30 +56,30 @@ func (pu *productDiscountUsecase) DeleteProductDiscount(ctx context.Context, req
 	}
 	productDiscounts, err := pu.productDiscountRepo.Get(ctx, request)
 	if err != nil {
-		pu.log.Errorf("Error fetching productDiscount for deletion: %v", err)
+		pu.log.WithContext(ctx).Errorf("Error fetching productDiscount for deletion: %v", err)
 		return err
 	}
 
 	err = pu.productDiscountRepo.Delete(ctx, productDiscounts[0].ProductID, productDiscounts[0].DiscountID)
 	if err != nil {
-		pu.log.Errorf("Error deleting productDiscount: %v", err)
+		pu.log.WithContext(ctx).Errorf("Error deleting productDiscount: %v", err)
 		return err
 	}
 
-	pu.log.Infof("Deleted productDiscount with ProductID: %d and DisctountID: %d", req.ProductID, req.DiscountID)
+	pu.log.WithContext(ctx).Infof("Deleted productDiscount with ProductID: %d and DisctountID: %d", req.ProductID, req.DiscountID)
 	return nil
 }
 
 func (pu *productDiscountUsecase) GetProductDiscountList(ctx context.Context, req *model.GetProductDiscountsRequest) ([]*model.GetProductDiscountResponse, error) {
-	pu.log.Infof("Fetching productDiscount list with request: %+v", req)
+	pu.log.WithContext(ctx).Infof("Fetching productDiscount list with request: %+v", req)
 
 	productDiscounts, err := pu.productDiscountRepo.Get(ctx, req)
 	if err != nil {
 		if err == constant.ErrNoProductDiscountsFound {
-			pu.log.Infof("No product discounts found for request: %+v", req)
+			pu.log.WithContext(ctx).Infof("No product discounts found for request: %+v", req)
 			return nil, err
 		}
-		pu.log.Errorf("Error fetching productDiscount list: %v", err)
+		pu.log.WithContext(ctx).Errorf("Error fetching productDiscount list: %v", err)
 		return nil, err
 	}
 
@@ -91,6 +91,6 @@ func (pu *productDiscountUsecase) GetProductDiscountList(ctx context.Context, re
 		})
 	}
 
-	pu.log.Infof("Fetched productDiscount list: %+v", productDiscountResponses)
+	pu.log.WithContext(ctx).Infof("Fetched productDiscount list: %+v", productDiscountResponses)
 	return productDiscountResponses, nil
 }
@@ -8,13 +8,13 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
-func GetReviewByID(c *gin.Context) {
-	module := dependency_injection.NewReviewUsecaseProvider()
+func (h *handler) GetReviewByID(c *gin.Context) {
+	module := dependency_injection.NewReviewUsecaseProvider(h.cfg)
 
 	var req model.GetReviewRequest
 	err := c.BindJSON(&req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(400, gin.H{
 			"error": "Bad Request",
 		})
@@ -23,7 +23,7 @@ func GetReviewByID(c *gin.Context) {
 
 	review, err := module.GetReview(c, &req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(500, gin.H{
 			"error": "Internal Server Error",
 		})
@@ -33,12 +33,12 @@ func GetReviewByID(c *gin.Context) {
 	c.JSON(200, review)
 }
 
-func GetAllReviews(c *gin.Context) {
-	module := dependency_injection.NewReviewUsecaseProvider()
+func (h *handler) GetAllReviews(c *gin.Context) {
+	module := dependency_injection.NewReviewUsecaseProvider(h.cfg)
 
 	reviews, err := module.GetAllReviews(c)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(500, gin.H{
 			"error": "Internal Server Error",
 		})
@@ -48,22 +48,22 @@ func GetAllReviews(c *gin.Context) {
 	c.JSON(200, reviews)
 }
 
-func CreateReview(c *gin.Context) {
+func (h *handler) CreateReview(c *gin.Context) {
 	var req model.CreateReviewRequest
 	err := c.BindJSON(&req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(400, gin.H{
 			"error": "Bad Request",
 		})
 		return
 	}
 
-	module := dependency_injection.NewReviewUsecaseProvider()
+	module := dependency_injection.NewReviewUsecaseProvider(h.cfg)
 
 	review, err := module.CreateReview(c, &req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(500, gin.H{
 			"error": "Internal Server Error",
 		})
@@ -73,22 +73,22 @@ func CreateReview(c *gin.Context) {
 	c.JSON(200, review)
 }
 
-func UpdateReview(c *gin.Context) {
+func (h *handler) UpdateReview(c *gin.Context) {
 	var req model.UpdateReviewRequest
 	err := c.BindJSON(&req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(400, gin.H{
 			"error": "Bad Request",
 		})
 		return
 	}
 
-	module := dependency_injection.NewReviewUsecaseProvider()
+	module := dependency_injection.NewReviewUsecaseProvider(h.cfg)
 
 	review, err := module.UpdateReview(c, &req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(500, gin.H{
 			"error": "Internal Server Error",
 		})
@@ -98,13 +98,13 @@ func UpdateReview(c *gin.Context) {
 	c.JSON(200, review)
 }
 
-func DeleteReview(c *gin.Context) {
-	module := dependency_injection.NewReviewUsecaseProvider()
+func (h *handler) DeleteReview(c *gin.Context) {
+	module := dependency_injection.NewReviewUsecaseProvider(h.cfg)
 
 	var req model.DeleteReviewRequest
 	err := c.BindJSON(&req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(400, gin.H{
 			"error": "Bad Request",
 		})
@@ -113,7 +113,7 @@ func DeleteReview(c *gin.Context) {
 
 	err = module.DeleteReview(c, &req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(500, gin.H{
 			"error": "Internal Server Error",
 		})
@@ -125,13 +125,13 @@ func DeleteReview(c *gin.Context) {
 	})
 }
 
-func GetPaginatedReview(c *gin.Context) {
-	module := dependency_injection.NewReviewUsecaseProvider()
+func (h *handler) GetPaginatedReview(c *gin.Context) {
+	module := dependency_injection.NewReviewUsecaseProvider(h.cfg)
 
 	var req model.GetReviewsRequest
 	err := c.BindJSON(&req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(400, gin.H{
 			"error": "Bad Request",
 		})
@@ -145,7 +145,7 @@ func GetPaginatedReview(c *gin.Context) {
 	}
 	reviews, err := module.GetReviewList(c, &req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(500, gin.H{
 			"error": "Internal Server Error",
 		})
@@ -1,19 +1,38 @@
 package delivery
 
 import (
+	"th3y3m/e-commerce-microservices/pkg/authz"
+	"th3y3m/e-commerce-microservices/pkg/logging"
+	"th3y3m/e-commerce-microservices/pkg/metrics"
+	"th3y3m/e-commerce-microservices/pkg/tracing"
+	"th3y3m/e-commerce-microservices/service/review/config"
+
 	"github.com/gin-gonic/gin"
 )
 
-func RegisterHandlers() *gin.Engine {
-	r := gin.Default()
+// handler serves the routes of the service, with the usecases built from cfg
+type handler struct {
+	cfg config.Config
+}
+
+func RegisterHandlers(cfg config.Config) *gin.Engine {
+	h := &handler{cfg: cfg}
+	keys := authz.NewKeys(cfg.Auth)
+
+	r := gin.New()
+	// Lets the request context, and the trace in it, reach the usecases through *gin.Context
+	r.ContextWithFallback = true
+	r.Use(gin.Recovery(), tracing.Middleware("review"), logging.Middleware(), metrics.Middleware())
+	r.GET(metrics.Path, metrics.Handler())
+	r.Use(keys.Middleware("user_id"))
 
 	review := r.Group("/api/reviews")
 	{
-		review.GET("/:review_id", GetReviewByID)
-		review.GET("", GetPaginatedReview)
-		review.POST("", CreateReview)
-		review.PUT("", UpdateReview)
-		review.DELETE("", DeleteReview)
+		review.GET("/:review_id", h.GetReviewByID)
+		review.GET("", h.GetPaginatedReview)
+		review.POST("", h.CreateReview)
+		review.PUT("", h.UpdateReview)
+		review.DELETE("", h.DeleteReview)
 	}
 
 	return r
@@ -1,21 +1,21 @@
 package dependency_injection
 
 import (
+	"th3y3m/e-commerce-microservices/pkg/logging"
 	"th3y3m/e-commerce-microservices/pkg/postgresql"
 	redis_client "th3y3m/e-commerce-microservices/pkg/redis"
+	"th3y3m/e-commerce-microservices/service/review/config"
 	"th3y3m/e-commerce-microservices/service/review/repository"
 	"th3y3m/e-commerce-microservices/service/review/usecase"
-
-	"github.com/sirupsen/logrus"
 )
 
-func NewReviewRepositoryProvider() repository.IReviewRepository {
-	log := logrus.New()
-	db, err := postgresql.NewGormDB()
+func NewReviewRepositoryProvider(cfg config.Config) repository.IReviewRepository {
+	log := logging.Logger()
+	db, err := postgresql.NewGormDB(cfg.Postgres)
 	if err != nil {
 		log.Error(err)
 	}
-	redis, err := redis_client.ConnectToRedis()
+	redis, err := redis_client.ConnectToRedis(cfg.Redis)
 	if err != nil {
 		log.Error(err)
 	}
@@ -23,8 +23,8 @@ func NewReviewRepositoryProvider() repository.IReviewRepository {
 	return repository.NewReviewRepository(db, redis, log)
 }
 
-func NewReviewUsecaseProvider() usecase.IReviewUsecase {
-	log := logrus.New()
-	reviewRepository := NewReviewRepositoryProvider()
+func NewReviewUsecaseProvider(cfg config.Config) usecase.IReviewUsecase {
+	log := logging.Logger()
+	reviewRepository := NewReviewRepositoryProvider(cfg)
 	return usecase.NewReviewUsecase(reviewRepository, log)
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"th3y3m/e-commerce-microservices/pkg/metrics"
 	"th3y3m/e-commerce-microservices/service/review/model"
 
 	"github.com/redis/go-redis/v9"
@@ -36,7 +37,7 @@ func NewReviewRepository(db *gorm.DB, redis *redis.Client, log *logrus.Logger) I
 }
 
 func (pr *reviewRepository) Get(ctx context.Context, reviewID int64) (*Review, error) {
-	pr.log.Infof("Fetching review with ID: %d", reviewID)
+	pr.log.WithContext(ctx).Infof("Fetching review with ID: %d", reviewID)
 	var review Review
 	cacheKey := fmt.Sprintf("review:%d", reviewID)
 
@@ -45,19 +46,21 @@ func (pr *reviewRepository) Get(ctx context.Context, reviewID int64) (*Review, e
 		cachedReview, err := pr.redis.Get(ctx, cacheKey).Result()
 		if err == nil {
 			if err := json.Unmarshal([]byte(cachedReview), &review); err == nil {
-				pr.log.Infof("Review found in cache: %d", reviewID)
+				pr.log.WithContext(ctx).Infof("Review found in cache: %d", reviewID)
+				metrics.CacheHit("reviewRepository.Get")
 				return &review, nil
 			}
 		} else if err != redis.Nil {
-			pr.log.Warnf("Failed to get review from Redis: %v", err)
+			pr.log.WithContext(ctx).Warnf("Failed to get review from Redis: %v", err)
 		}
+		metrics.CacheMiss("reviewRepository.Get")
 	} else {
-		pr.log.Warn("Redis client is not initialized")
+		pr.log.WithContext(ctx).Warn("Redis client is not initialized")
 	}
 
 	// If not found in cache, get from database
 	if err := pr.db.WithContext(ctx).First(&review, reviewID).Error; err != nil {
-		pr.log.Errorf("Error fetching review from database: %v", err)
+		pr.log.WithContext(ctx).Errorf("Error fetching review from database: %v", err)
 		return nil, err
 	}
 
@@ -65,9 +68,9 @@ func (pr *reviewRepository) Get(ctx context.Context, reviewID int64) (*Review, e
 	if pr.redis != nil {
 		reviewJSON, _ := json.Marshal(review)
 		if err := pr.redis.Set(ctx, cacheKey, reviewJSON, 0).Err(); err != nil {
-			pr.log.Warnf("Failed to save review to Redis: %v", err)
+			pr.log.WithContext(ctx).Warnf("Failed to save review to Redis: %v", err)
 		} else {
-			pr.log.Infof("Review saved to cache: %d", reviewID)
+			pr.log.WithContext(ctx).Infof("Review saved to cache: %d", reviewID)
 		}
 	}
 
@@ -75,7 +78,7 @@ func (pr *reviewRepository) Get(ctx context.Context, reviewID int64) (*Review, e
 }
 
 func (pr *reviewRepository) GetAll(ctx context.Context) ([]*Review, error) {
-	pr.log.Info("Fetching all reviews")
+	pr.log.WithContext(ctx).Info("Fetching all reviews")
 	var reviews []*Review
 	cacheKey := "all_reviews"
 
@@ -84,19 +87,21 @@ func (pr *reviewRepository) GetAll(ctx context.Context) ([]*Review, error) {
 		cachedReviews, err := pr.redis.Get(ctx, cacheKey).Result()
 		if err == nil {
 			if err := json.Unmarshal([]byte(cachedReviews), &reviews); err == nil {
-				pr.log.Info("Reviews found in cache")
+				pr.log.WithContext(ctx).Info("Reviews found in cache")
+				metrics.CacheHit("reviewRepository.GetAll")
 				return reviews, nil
 			}
 		} else if err != redis.Nil {
-			pr.log.Warnf("Failed to get reviews from Redis: %v", err)
+			pr.log.WithContext(ctx).Warnf("Failed to get reviews from Redis: %v", err)
 		}
+		metrics.CacheMiss("reviewRepository.GetAll")
 	} else {
-		pr.log.Warn("Redis client is not initialized")
+		pr.log.WithContext(ctx).Warn("Redis client is not initialized")
 	}
 
 	// If not found in cache, get from database
 	if err := pr.db.WithContext(ctx).Find(&reviews).Error; err != nil {
-		pr.log.Errorf("Error fetching reviews from database: %v", err)
+		pr.log.WithContext(ctx).Errorf("Error fetching reviews from database: %v", err)
 		return nil, err
 	}
 
@@ -104,9 +109,9 @@ func (pr *reviewRepository) GetAll(ctx context.Context) ([]*Review, error) {
 	if pr.redis != nil {
 		reviewsJSON, _ := json.Marshal(reviews)
 		if err := pr.redis.Set(ctx, cacheKey, reviewsJSON, 0).Err(); err != nil {
-			pr.log.Warnf("Failed to save reviews to Redis: %v", err)
+			pr.log.WithContext(ctx).Warnf("Failed to save reviews to Redis: %v", err)
 		} else {
-			pr.log.Info("Reviews saved to cache")
+			pr.log.WithContext(ctx).Info("Reviews saved to cache")
 		}
 	}
 
@@ -114,9 +119,9 @@ func (pr *reviewRepository) GetAll(ctx context.Context) ([]*Review, error) {
 }
 
 func (pr *reviewRepository) Create(ctx context.Context, review *Review) (*Review, error) {
-	pr.log.Infof("Creating review: %+v", review)
+	pr.log.WithContext(ctx).Infof("Creating review: %+v", review)
 	if err := pr.db.WithContext(ctx).Create(review).Error; err != nil {
-		pr.log.Errorf("Error creating review: %v", err)
+		pr.log.WithContext(ctx).Errorf("Error creating review: %v", err)
 		return nil, err
 	}
 	cacheKey := fmt.Sprintf("review:%d", review.ReviewID)
@@ -125,16 +130,16 @@ func (pr *reviewRepository) Create(ctx context.Context, review *Review) (*Review
 	if pr.redis != nil {
 		reviewJSON, _ := json.Marshal(review)
 		if err := pr.redis.Set(ctx, cacheKey, reviewJSON, 0).Err(); err != nil {
-			pr.log.Warnf("Failed to save review to Redis: %v", err)
+			pr.log.WithContext(ctx).Warnf("Failed to save review to Redis: %v", err)
 		} else {
-			pr.log.Infof("Review saved to cache: %d", review.ReviewID)
+			pr.log.WithContext(ctx).Infof("Review saved to cache: %d", review.ReviewID)
 		}
 
 		// Invalidate the cache for all records
 		if err := pr.redis.Del(ctx, "all_reviews").Err(); err != nil {
-			pr.log.Warnf("Failed to invalidate all reviews cache: %v", err)
+			pr.log.WithContext(ctx).Warnf("Failed to invalidate all reviews cache: %v", err)
 		} else {
-			pr.log.Info("Invalidated cache for all reviews")
+			pr.log.WithContext(ctx).Info("Invalidated cache for all reviews")
 		}
 	}
 
@@ -143,9 +148,9 @@ func (pr *reviewRepository) Create(ctx context.Context, review *Review) (*Review
 }
 
 func (pr *reviewRepository) Update(ctx context.Context, review *Review) (*Review, error) {
-	pr.log.Infof("Updating review: %+v", review)
+	pr.log.WithContext(ctx).Infof("Updating review: %+v", review)
 	if err := pr.db.WithContext(ctx).Save(review).Error; err != nil {
-		pr.log.Errorf("Error updating review: %v", err)
+		pr.log.WithContext(ctx).Errorf("Error updating review: %v", err)
 		return nil, err
 	}
 	cacheKey := fmt.Sprintf("review:%d", review.ReviewID)
@@ -154,16 +159,16 @@ func (pr *reviewRepository) Update(ctx context.Context, review *Review) (*Review
 	if pr.redis != nil {
 		reviewJSON, _ := json.Marshal(review)
 		if err := pr.redis.Set(ctx, cacheKey, reviewJSON, 0).Err(); err != nil {
-			pr.log.Warnf("Failed to save review to Redis: %v", err)
+			pr.log.WithContext(ctx).Warnf("Failed to save review to Redis: %v", err)
 		} else {
-			pr.log.Infof("Review saved to cache: %d", review.ReviewID)
+			pr.log.WithContext(ctx).Infof("Review saved to cache: %d", review.ReviewID)
 		}
 
 		// Invalidate the cache for all records
 		if err := pr.redis.Del(ctx, "all_reviews").Err(); err != nil {
-			pr.log.Warnf("Failed to invalidate all reviews cache: %v", err)
+			pr.log.WithContext(ctx).Warnf("Failed to invalidate all reviews cache: %v", err)
 		} else {
-			pr.log.Info("Invalidated cache for all reviews")
+			pr.log.WithContext(ctx).Info("Invalidated cache for all reviews")
 		}
 	}
 
@@ -172,9 +177,9 @@ func (pr *reviewRepository) Update(ctx context.Context, review *Review) (*Review
 }
 
 func (pr *reviewRepository) Delete(ctx context.Context, reviewID int64) error {
-	pr.log.Infof("Deleting review with ID: %d", reviewID)
+	pr.log.WithContext(ctx).Infof("Deleting review with ID: %d", reviewID)
 	if err := pr.db.WithContext(ctx).Delete(&Review{}, reviewID).Error; err != nil {
-		pr.log.Errorf("Error deleting review: %v", err)
+		pr.log.WithContext(ctx).Errorf("Error deleting review: %v", err)
 		return err
 	}
 
@@ -183,16 +188,16 @@ func (pr *reviewRepository) Delete(ctx context.Context, reviewID int64) error {
 	// Delete from cache if Redis is available
 	if pr.redis != nil {
 		if err := pr.redis.Del(ctx, cacheKey).Err(); err != nil {
-			pr.log.Warnf("Failed to delete review from Redis: %v", err)
+			pr.log.WithContext(ctx).Warnf("Failed to delete review from Redis: %v", err)
 		} else {
-			pr.log.Infof("Review deleted from cache: %d", reviewID)
+			pr.log.WithContext(ctx).Infof("Review deleted from cache: %d", reviewID)
 		}
 
 		// Invalidate the cache for all records
 		if err := pr.redis.Del(ctx, "all_reviews").Err(); err != nil {
-			pr.log.Warnf("Failed to invalidate all reviews cache: %v", err)
+			pr.log.WithContext(ctx).Warnf("Failed to invalidate all reviews cache: %v", err)
 		} else {
-			pr.log.Info("Invalidated cache for all reviews")
+			pr.log.WithContext(ctx).Info("Invalidated cache for all reviews")
 		}
 	}
 
@@ -230,7 +235,7 @@ func (pr *reviewRepository) getQuerySearch(db *gorm.DB, req *model.GetReviewsReq
 }
 
 func (pr *reviewRepository) GetList(ctx context.Context, req *model.GetReviewsRequest) ([]*Review, error) {
-	pr.log.Infof("Fetching review list with request: %+v", req)
+	pr.log.WithContext(ctx).Infof("Fetching review list with request: %+v", req)
 	var reviews []*Review
 
 	db := pr.db.WithContext(ctx)
@@ -255,10 +260,10 @@ func (pr *reviewRepository) GetList(ctx context.Context, req *model.GetReviewsRe
 
 	result := db.Table("reviews").Offset(int(req.Paging.PageIndex-1) * int(req.Paging.PageSize)).Limit(int(req.Paging.PageSize)).Find(&reviews)
 	if result.Error != nil {
-		pr.log.Errorf("Error fetching review list: %v", result.Error)
+		pr.log.WithContext(ctx).Errorf("Error fetching review list: %v", result.Error)
 		return nil, result.Error
 	}
 
-	pr.log.Infof("Fetched %d reviews", len(reviews))
+	pr.log.WithContext(ctx).Infof("Fetched %d reviews", len(reviews))
 	return reviews, nil
 }
@@ -34,24 +34,24 @@ func NewReviewUsecase(reviewRepo repository.IReviewRepository, log *logrus.Logge
 }
 
 func (pu *reviewUsecase) GetReview(ctx context.Context, req *model.GetReviewRequest) (*model.GetReviewResponse, error) {
-	pu.log.Infof("Fetching review with ID: %d", req.ReviewID)
+	pu.log.WithContext(ctx).Infof("Fetching review with ID: %d", req.ReviewID)
 	review, err := pu.reviewRepo.Get(ctx, req.ReviewID)
 	if err != nil {
-		pu.log.Errorf("Error fetching review: %v", err)
+		pu.log.WithContext(ctx).Errorf("Error fetching review: %v", err)
 		return nil, err
 	}
 
-	pu.log.Infof("Fetched review: %+v", review)
+	pu.log.WithContext(ctx).Infof("Fetched review: %+v", review)
 	return &model.GetReviewResponse{
 		ReviewID: review.ReviewID,
 	}, nil
 }
 
 func (pu *reviewUsecase) GetAllReviews(ctx context.Context) ([]*model.GetReviewResponse, error) {
-	pu.log.Info("Fetching all reviews")
+	pu.log.WithContext(ctx).Info("Fetching all reviews")
 	reviews, err := pu.reviewRepo.GetAll(ctx)
 	if err != nil {
-		pu.log.Errorf("Error fetching all reviews: %v", err)
+		pu.log.WithContext(ctx).Errorf("Error fetching all reviews: %v", err)
 		return nil, err
 	}
 
@@ -69,12 +69,12 @@ func (pu *reviewUsecase) GetAllReviews(ctx context.Context) ([]*model.GetReviewR
 		})
 	}
 
-	pu.log.Infof("Fetched %d reviews", len(reviewResponses))
+	pu.log.WithContext(ctx).Infof("Fetched %d reviews", len(reviewResponses))
 	return reviewResponses, nil
 }
 
 func (pu *reviewUsecase) CreateReview(ctx context.Context, review *model.CreateReviewRequest) (*model.GetReviewResponse, error) {
-	pu.log.Infof("Creating review: %+v", review)
+	pu.log.WithContext(ctx).Infof("Creating review: %+v", review)
 	reviewData := repository.Review{
 		ProductID: review.ProductID,
 		UserID:    review.UserID,
@@ -84,11 +84,11 @@ func (pu *reviewUsecase) CreateReview(ctx context.Context, review *model.CreateR
 
 	createdReview, err := pu.reviewRepo.Create(ctx, &reviewData)
 	if err != nil {
-		pu.log.Errorf("Error creating review: %v", err)
+		pu.log.WithContext(ctx).Errorf("Error creating review: %v", err)
 		return nil, err
 	}
 
-	pu.log.Infof("Created review: %+v", createdReview)
+	pu.log.WithContext(ctx).Infof("Created review: %+v", createdReview)
 	return &model.GetReviewResponse{
 		ReviewID:  createdReview.ReviewID,
 		ProductID: createdReview.ProductID,
@@ -102,10 +102,10 @@ func (pu *reviewUsecase) CreateReview(ctx context.Context, review *model.CreateR
 }
 
 func (pu *reviewUsecase) DeleteReview(ctx context.Context, req *model.DeleteReviewRequest) error {
-	pu.log.Infof("Deleting review with ID: %d", req.ReviewID)
+	pu.log.WithContext(ctx).Infof("Deleting review with ID: %d", req.ReviewID)
 	review, err := pu.reviewRepo.Get(ctx, req.ReviewID)
 	if err != nil {
-		pu.log.Errorf("Error fetching review for deletion: %v", err)
+		pu.log.WithContext(ctx).Errorf("Error fetching review for deletion: %v", err)
 		return err
 	}
 
@@ -113,19 +113,19 @@ func (pu *reviewUsecase) DeleteReview(ctx context.Context, req *model.DeleteRevi
 
 	_, err = pu.reviewRepo.Update(ctx, review)
 	if err != nil {
-		pu.log.Errorf("Error updating review for deletion: %v", err)
+		pu.log.WithContext(ctx).Errorf("Error updating review for deletion: %v", err)
 		return err
 	}
 
-	pu.log.Infof("Deleted review with ID: %d", req.ReviewID)
+	pu.log.WithContext(ctx).Infof("Deleted review with ID: %d", req.ReviewID)
 	return nil
 }
 
 func (pu *reviewUsecase) UpdateReview(ctx context.Context, rep *model.UpdateReviewRequest) (*model.GetReviewResponse, error) {
-	pu.log.Infof("Updating review with ID: %d", rep.ReviewID)
+	pu.log.WithContext(ctx).Infof("Updating review with ID: %d", rep.ReviewID)
 	review, err := pu.reviewRepo.Get(ctx, rep.ReviewID)
 	if err != nil {
-		pu.log.Errorf("Error fetching review for update: %v", err)
+		pu.log.WithContext(ctx).Errorf("Error fetching review for update: %v", err)
 		return nil, err
 	}
 
@@ -136,11 +136,11 @@ func (pu *reviewUsecase) UpdateReview(ctx context.Context, rep *model.UpdateRevi
 
 	updatedReview, err := pu.reviewRepo.Update(ctx, review)
 	if err != nil {
-		pu.log.Errorf("Error updating review: %v", err)
+		pu.log.WithContext(ctx).Errorf("Error updating review: %v", err)
 		return nil, err
 	}
 
-	pu.log.Infof("Updated review: %+v", updatedReview)
+	pu.log.WithContext(ctx).Infof("Updated review: %+v", updatedReview)
 	return &model.GetReviewResponse{
 		ReviewID:  updatedReview.ReviewID,
 		ProductID: updatedReview.ProductID,
@@ -154,10 +154,10 @@ func (pu *reviewUsecase) UpdateReview(ctx context.Context, rep *model.UpdateRevi
 }
 
 func (pu *reviewUsecase) GetReviewList(ctx context.Context, req *model.GetReviewsRequest) (*util.PaginatedList[model.GetReviewResponse], error) {
-	pu.log.Infof("Fetching review list with request: %+v", req)
+	pu.log.WithContext(ctx).Infof("Fetching review list with request: %+v", req)
 	reviews, err := pu.reviewRepo.GetList(ctx, req)
 	if err != nil {
-		pu.log.Errorf("Error fetching review list: %v", err)
+		pu.log.WithContext(ctx).Errorf("Error fetching review list: %v", err)
 		return nil, err
 	}
 
@@ -185,6 +185,6 @@ func (pu *reviewUsecase) GetReviewList(ctx context.Context, req *model.GetReview
 
 	list.GetTotalPages()
 
-	pu.log.Infof("Fetched %d reviews", len(reviewResponses))
+	pu.log.WithContext(ctx).Infof("Fetched %d reviews", len(reviewResponses))
 	return list, nil
 }
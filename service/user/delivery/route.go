@@ -1,20 +1,39 @@
 package delivery
 
 import (
+	"th3y3m/e-commerce-microservices/pkg/authz"
+	"th3y3m/e-commerce-microservices/pkg/logging"
+	"th3y3m/e-commerce-microservices/pkg/metrics"
+	"th3y3m/e-commerce-microservices/pkg/tracing"
+	"th3y3m/e-commerce-microservices/service/user/config"
+
 	"github.com/gin-gonic/gin"
 )
 
-func RegisterHandlers() *gin.Engine {
-	r := gin.Default()
+// handler serves the routes of the service, with the usecases built from cfg
+type handler struct {
+	cfg config.Config
+}
+
+func RegisterHandlers(cfg config.Config) *gin.Engine {
+	h := &handler{cfg: cfg}
+	keys := authz.NewKeys(cfg.Auth)
+
+	r := gin.New()
+	// Lets the request context, and the trace in it, reach the usecases through *gin.Context
+	r.ContextWithFallback = true
+	r.Use(gin.Recovery(), tracing.Middleware("user"), logging.Middleware(), metrics.Middleware())
+	r.GET(metrics.Path, metrics.Handler())
+	r.Use(keys.Middleware())
 
 	user := r.Group("/api/users")
 	{
-		user.GET("/get-user", GetUser)
-		user.GET("", GetPaginatedUser)
-		user.POST("", CreateUser)
-		user.PUT("", UpdateUser)
-		user.DELETE("", DeleteUser)
-		user.POST("/verify", VerifyToken)
+		user.GET("/get-user", h.GetUser)
+		user.GET("", h.GetPaginatedUser)
+		user.POST("", h.CreateUser)
+		user.PUT("", h.UpdateUser)
+		user.DELETE("", h.DeleteUser)
+		user.POST("/verify", h.VerifyToken)
 	}
 
 	return r
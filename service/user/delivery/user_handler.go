@@ -2,6 +2,7 @@ package delivery
 
 import (
 	"net/http"
+	"th3y3m/e-commerce-microservices/pkg/authz"
 	"th3y3m/e-commerce-microservices/service/user/dependency_injection"
 	"th3y3m/e-commerce-microservices/service/user/model"
 
@@ -11,37 +12,47 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
-func GetUser(c *gin.Context) {
-	module := dependency_injection.NewUserUsecaseProvider()
+func (h *handler) GetUser(c *gin.Context) {
+	module := dependency_injection.NewUserUsecaseProvider(h.cfg)
 
 	var req model.GetUserRequest
-	err := c.BindJSON(&req)
+	err := c.ShouldBindQuery(&req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(400, gin.H{
 			"error": "Bad Request",
 		})
 		return
 	}
 
+	caller, ok := authz.Authorize(c)
+	if !ok {
+		return
+	}
+
 	user, err := module.GetUser(c, &req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(500, gin.H{
 			"error": "Internal Server Error",
 		})
 		return
 	}
 
+	// Customers may only read their own profile
+	if (caller.Role == authz.RoleCustomer || caller.IsAnonymous()) && !authz.RequireOwner(c, caller, user.UserID) {
+		return
+	}
+
 	c.JSON(200, user)
 }
 
-func GetAllUsers(c *gin.Context) {
-	module := dependency_injection.NewUserUsecaseProvider()
+func (h *handler) GetAllUsers(c *gin.Context) {
+	module := dependency_injection.NewUserUsecaseProvider(h.cfg)
 
 	users, err := module.GetAllUsers(c)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(500, gin.H{
 			"error": "Internal Server Error",
 		})
@@ -51,21 +62,21 @@ func GetAllUsers(c *gin.Context) {
 	c.JSON(200, users)
 }
 
-func CreateUser(c *gin.Context) {
+func (h *handler) CreateUser(c *gin.Context) {
 	var req model.CreateUserRequest
 	if err := c.BindJSON(&req); err != nil {
-		logrus.Error("Failed to bind JSON: ", err)
+		logrus.WithContext(c).Error("Failed to bind JSON: ", err)
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Bad Request", "details": err.Error()})
 		return
 	}
 
 	// Inject the UserUsecase (through dependency injection)
-	module := dependency_injection.NewUserUsecaseProvider()
+	module := dependency_injection.NewUserUsecaseProvider(h.cfg)
 
 	// Call CreateUser usecase function
 	user, err := module.CreateUser(c, &req)
 	if err != nil {
-		logrus.Error("Failed to create user: ", err)
+		logrus.WithContext(c).Error("Failed to create user: ", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal Server Error", "details": err.Error()})
 		return
 	}
@@ -74,22 +85,34 @@ func CreateUser(c *gin.Context) {
 	c.JSON(http.StatusOK, user)
 }
 
-func UpdateUser(c *gin.Context) {
+func (h *handler) UpdateUser(c *gin.Context) {
 	var req model.UpdateUserRequest
 	err := c.BindJSON(&req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(400, gin.H{
 			"error": "Bad Request",
 		})
 		return
 	}
 
-	module := dependency_injection.NewUserUsecaseProvider()
+	caller, ok := authz.Authorize(c)
+	if !ok || !authz.RequireOwner(c, caller, req.UserID) {
+		return
+	}
+	// Only admins may change roles
+	if !caller.Privileged() && req.Role != "" && req.Role != caller.Role {
+		c.JSON(403, gin.H{
+			"error": "Access denied",
+		})
+		return
+	}
+
+	module := dependency_injection.NewUserUsecaseProvider(h.cfg)
 
 	user, err := module.UpdateUser(c, &req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(500, gin.H{
 			"error": "Internal Server Error",
 		})
@@ -99,22 +122,27 @@ func UpdateUser(c *gin.Context) {
 	c.JSON(200, user)
 }
 
-func DeleteUser(c *gin.Context) {
-	module := dependency_injection.NewUserUsecaseProvider()
+func (h *handler) DeleteUser(c *gin.Context) {
+	module := dependency_injection.NewUserUsecaseProvider(h.cfg)
 
 	var req model.DeleteUserRequest
 	err := c.BindJSON(&req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(400, gin.H{
 			"error": "Bad Request",
 		})
 		return
 	}
 
+	caller, ok := authz.Authorize(c)
+	if !ok || !authz.RequireOwner(c, caller, req.UserID) {
+		return
+	}
+
 	err = module.DeleteUser(c, &req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(500, gin.H{
 			"error": "Internal Server Error",
 		})
@@ -126,18 +154,25 @@ func DeleteUser(c *gin.Context) {
 	})
 }
 
-func GetPaginatedUser(c *gin.Context) {
-	module := dependency_injection.NewUserUsecaseProvider()
+func (h *handler) GetPaginatedUser(c *gin.Context) {
+	module := dependency_injection.NewUserUsecaseProvider(h.cfg)
 
 	var req model.GetUsersRequest
 	err := c.BindJSON(&req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(400, gin.H{
 			"error": "Bad Request",
 		})
 		return
 	}
+
+	// Listing users is for the back office
+	caller, ok := authz.Authorize(c)
+	if !ok || !authz.RequireRole(c, caller, authz.RoleAdmin) {
+		return
+	}
+
 	if req.Paging.PageIndex == 0 {
 		req.Paging.PageIndex = 1
 	}
@@ -146,7 +181,7 @@ func GetPaginatedUser(c *gin.Context) {
 	}
 	users, err := module.GetUserList(c, &req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(500, gin.H{
 			"error": "Internal Server Error",
 		})
@@ -156,8 +191,8 @@ func GetPaginatedUser(c *gin.Context) {
 	c.JSON(200, users)
 }
 
-func VerifyToken(c *gin.Context) {
-	module := dependency_injection.NewUserUsecaseProvider()
+func (h *handler) VerifyToken(c *gin.Context) {
+	module := dependency_injection.NewUserUsecaseProvider(h.cfg)
 
 	token := c.Query("token")
 	userID := c.Query("user_id")
@@ -170,7 +205,7 @@ func VerifyToken(c *gin.Context) {
 	}
 	userIDInt, err := strconv.ParseInt(userID, 10, 64)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(400, gin.H{
 			"error": "Bad Request",
 		})
@@ -178,7 +213,7 @@ func VerifyToken(c *gin.Context) {
 	}
 	isValid, err := module.VerifyToken(c, token, userIDInt)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(500, gin.H{
 			"error": "Internal Server Error",
 		})
@@ -1,30 +1,31 @@
 package dependency_injection
 
 import (
+	"th3y3m/e-commerce-microservices/pkg/cache"
+	"th3y3m/e-commerce-microservices/pkg/logging"
 	"th3y3m/e-commerce-microservices/pkg/postgresql"
 	redis_client "th3y3m/e-commerce-microservices/pkg/redis"
+	"th3y3m/e-commerce-microservices/service/user/config"
 	"th3y3m/e-commerce-microservices/service/user/repository"
 	"th3y3m/e-commerce-microservices/service/user/usecase"
-
-	"github.com/sirupsen/logrus"
 )
 
-func NewUserRepositoryProvider() repository.IUserRepository {
-	log := logrus.New()
-	db, err := postgresql.NewGormDB()
+func NewUserRepositoryProvider(cfg config.Config) repository.IUserRepository {
+	log := logging.Logger()
+	db, err := postgresql.NewGormDB(cfg.Postgres)
 	if err != nil {
 		log.Error(err)
 	}
-	redis, err := redis_client.ConnectToRedis()
+	redis, err := redis_client.ConnectToRedis(cfg.Redis)
 	if err != nil {
 		log.Error(err)
 	}
 
-	return repository.NewUserRepository(db, redis, log)
+	return repository.NewUserRepository(db, cache.NewStore(redis), cache.NewConfig(cfg.Cache), log)
 }
 
-func NewUserUsecaseProvider() usecase.IUserUsecase {
-	log := logrus.New()
-	userRepository := NewUserRepositoryProvider()
+func NewUserUsecaseProvider(cfg config.Config) usecase.IUserUsecase {
+	log := logging.Logger()
+	userRepository := NewUserRepositoryProvider(cfg)
 	return usecase.NewUserUsecase(userRepository, log)
 }
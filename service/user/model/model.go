@@ -6,8 +6,8 @@ import (
 )
 
 type GetUserRequest struct {
-	UserID *int64 `json:"user_id"`
-	Email  string `json:"email"`
+	UserID *int64 `json:"user_id" form:"user_id"`
+	Email  string `json:"email" form:"email"`
 }
 
 type GetUsersRequest struct {
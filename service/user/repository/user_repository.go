@@ -2,13 +2,13 @@ package repository
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
+	"strconv"
 	"time"
 
+	"th3y3m/e-commerce-microservices/pkg/cache"
 	"th3y3m/e-commerce-microservices/service/user/model"
 
-	"github.com/redis/go-redis/v9"
 	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
 )
@@ -16,7 +16,8 @@ import (
 type userRepository struct {
 	log   *logrus.Logger
 	db    *gorm.DB
-	redis *redis.Client
+	users *cache.Cache[*User]
+	lists *cache.Cache[[]*User]
 }
 
 type IUserRepository interface {
@@ -30,54 +31,50 @@ type IUserRepository interface {
 	VerifyToken(ctx context.Context, token string, userID int64) (bool, error)
 }
 
-func NewUserRepository(db *gorm.DB, redis *redis.Client, log *logrus.Logger) IUserRepository {
+func NewUserRepository(db *gorm.DB, store cache.Store, cfg cache.Config, log *logrus.Logger) IUserRepository {
 	return &userRepository{
-		db:    db,
-		redis: redis,
+		db: db,
+		// Users are cached by ID and by email, both keys are invalidated through the tag of the user
+		users: cache.New[*User](store, "user", cfg).
+			CacheNotFound(gorm.ErrRecordNotFound).
+			Tagged(func(user *User) []string { return []string{userTag(user.UserID)} }),
+		lists: cache.New[[]*User](store, "users", cfg),
 		log:   log,
 	}
 }
 
+func userTag(userID int64) string {
+	return cache.Tag("user", userID)
+}
+
 func (pr *userRepository) Get(ctx context.Context, userID *int64, email string) (*User, error) {
-	var user User
 	var cacheKey string
 
 	if userID != nil {
-		pr.log.Infof("Fetching user with ID: %d", *userID)
-		cacheKey = fmt.Sprintf("user:%d", *userID)
+		pr.log.WithContext(ctx).Infof("Fetching user with ID: %d", *userID)
+		cacheKey = strconv.FormatInt(*userID, 10)
 	} else if email != "" {
-		pr.log.Infof("Fetching user with email: %s", email)
-		cacheKey = fmt.Sprintf("user:email:%s", email)
+		pr.log.WithContext(ctx).Infof("Fetching user with email: %s", email)
+		cacheKey = "email:" + email
 	} else {
 		return nil, fmt.Errorf("either userID or email must be provided")
 	}
 
-	// Check if Redis client is initialized
-	if pr.redis != nil {
-		// Try to get the user from Redis cache
-		cachedUser, err := pr.redis.Get(ctx, cacheKey).Result()
-		if err == nil {
-			if err := json.Unmarshal([]byte(cachedUser), &user); err == nil {
-				pr.log.Infof("User found in cache: %s", cacheKey)
-				return &user, nil
-			}
-		} else if err != redis.Nil {
-			pr.log.Warnf("Failed to get user from Redis: %v", err)
+	user, err := pr.users.GetOrLoad(ctx, cacheKey, func(ctx context.Context) (*User, error) {
+		var user User
+		query := pr.db.WithContext(ctx)
+		if userID != nil {
+			query = query.First(&user, *userID)
+		} else {
+			query = query.Where("email = ?", email).First(&user)
 		}
-	} else {
-		pr.log.Warn("Redis client is not initialized")
-	}
-
-	// If not found in cache or Redis is unavailable, get from database
-	query := pr.db.WithContext(ctx)
-	if userID != nil {
-		query = query.First(&user, *userID)
-	} else {
-		query = query.Where("email = ?", email).First(&user)
-	}
-
-	if err := query.Error; err != nil {
-		pr.log.Errorf("Error fetching user from database: %v", err)
+		if err := query.Error; err != nil {
+			return nil, err
+		}
+		return &user, nil
+	})
+	if err != nil {
+		pr.log.WithContext(ctx).Errorf("Error fetching user: %v", err)
 
 		// Return nil if the user is not found
 		if err == gorm.ErrRecordNotFound {
@@ -86,132 +83,67 @@ func (pr *userRepository) Get(ctx context.Context, userID *int64, email string)
 		return nil, err
 	}
 
-	// Save to cache if Redis is available
-	if pr.redis != nil {
-		userJSON, _ := json.Marshal(user)
-		if err := pr.redis.Set(ctx, cacheKey, userJSON, 0).Err(); err != nil {
-			pr.log.Warnf("Failed to save user to Redis: %v", err)
-		} else {
-			pr.log.Infof("User saved to cache: %s", cacheKey)
-		}
-	}
-
-	return &user, nil
+	return user, nil
 }
 
 func (pr *userRepository) GetAll(ctx context.Context) ([]*User, error) {
-	pr.log.Info("Fetching all users")
-	var users []*User
-	cacheKey := "all_users"
-
-	// Try to get the users from Redis cache
-	if pr.redis != nil {
-		cachedUsers, err := pr.redis.Get(ctx, cacheKey).Result()
-		if err == nil {
-			if err := json.Unmarshal([]byte(cachedUsers), &users); err == nil {
-				pr.log.Info("Users found in cache")
-				return users, nil
-			}
-		} else if err != redis.Nil {
-			pr.log.Warnf("Failed to get users from Redis: %v", err)
+	pr.log.WithContext(ctx).Info("Fetching all users")
+	users, err := pr.lists.GetOrLoad(ctx, "all", func(ctx context.Context) ([]*User, error) {
+		var users []*User
+		if err := pr.db.WithContext(ctx).Find(&users).Error; err != nil {
+			return nil, err
 		}
-	} else {
-		pr.log.Warn("Redis client is not initialized")
-	}
-
-	// If not found in cache, get from database
-	if err := pr.db.WithContext(ctx).Find(&users).Error; err != nil {
-		pr.log.Errorf("Error fetching users from database: %v", err)
+		return users, nil
+	})
+	if err != nil {
+		pr.log.WithContext(ctx).Errorf("Error fetching users from database: %v", err)
 		return nil, err
 	}
-
-	// Save to cache
-	if pr.redis != nil {
-		userJSON, _ := json.Marshal(users)
-		if err := pr.redis.Set(ctx, cacheKey, userJSON, 0).Err(); err != nil {
-			pr.log.Warnf("Failed to save user to Redis: %v", err)
-		} else {
-			pr.log.Infof("User saved to cache: %s", cacheKey)
-		}
-	}
-
 	return users, nil
 }
 
 func (pr *userRepository) Create(ctx context.Context, user *User) (*User, error) {
-	pr.log.Infof("Creating user: %+v", user)
+	pr.log.WithContext(ctx).Infof("Creating user: %+v", user)
 	if err := pr.db.WithContext(ctx).Create(user).Error; err != nil {
-		pr.log.Errorf("Error creating user: %v", err)
+		pr.log.WithContext(ctx).Errorf("Error creating user: %v", err)
 		return nil, err
 	}
 
-	if pr.redis != nil {
-		cacheKey := fmt.Sprintf("user:%d", user.UserID)
-		userJSON, _ := json.Marshal(user)
-		if err := pr.redis.Set(ctx, cacheKey, userJSON, 0).Err(); err != nil {
-			pr.log.Warnf("Failed to save user to Redis: %v", err)
-		} else {
-			pr.log.Infof("User saved to cache: %s", cacheKey)
-		}
-
-		// Invalidate the cache for all records
-		if err := pr.redis.Del(ctx, "all_users").Err(); err != nil {
-			pr.log.Warnf("Failed to invalidate all users cache: %v", err)
-		}
-		pr.log.Info("Invalidated cache for all users")
-	}
+	// The email may be cached as missing
+	pr.users.Delete(ctx, "email:"+user.Email)
+	pr.users.Set(ctx, strconv.FormatInt(user.UserID, 10), user)
+	pr.lists.InvalidateAll(ctx)
 
 	// Return the newly created user (with any updated fields)
 	return user, nil
 }
 
 func (pr *userRepository) Update(ctx context.Context, user *User) (*User, error) {
-	pr.log.Infof("Updating user: %+v", user)
+	pr.log.WithContext(ctx).Infof("Updating user: %+v", user)
 	if err := pr.db.WithContext(ctx).Save(user).Error; err != nil {
-		pr.log.Errorf("Error updating user: %v", err)
+		pr.log.WithContext(ctx).Errorf("Error updating user: %v", err)
 		return nil, err
 	}
 
-	if pr.redis != nil {
-		cacheKey := fmt.Sprintf("user:%d", user.UserID)
-		userJSON, _ := json.Marshal(user)
-		if err := pr.redis.Set(ctx, cacheKey, userJSON, 0).Err(); err != nil {
-			pr.log.Warnf("Failed to save user to Redis: %v", err)
-		} else {
-			pr.log.Infof("User saved to cache: %s", cacheKey)
-		}
-
-		// Invalidate the cache for all records
-		if err := pr.redis.Del(ctx, "all_users").Err(); err != nil {
-			pr.log.Warnf("Failed to invalidate all users cache: %v", err)
-		}
-		pr.log.Info("Invalidated cache for all users")
-	}
+	// Drops the user cached under its previous email as well
+	pr.users.Invalidate(ctx, userTag(user.UserID))
+	pr.users.Delete(ctx, "email:"+user.Email)
+	pr.users.Set(ctx, strconv.FormatInt(user.UserID, 10), user)
+	pr.lists.InvalidateAll(ctx)
 
 	// Return the updated user
 	return user, nil
 }
 
 func (pr *userRepository) Delete(ctx context.Context, userID int64) error {
-	pr.log.Infof("Deleting user with ID: %d", userID)
+	pr.log.WithContext(ctx).Infof("Deleting user with ID: %d", userID)
 	if err := pr.db.WithContext(ctx).Delete(&User{}, userID).Error; err != nil {
-		pr.log.Errorf("Error deleting user: %v", err)
+		pr.log.WithContext(ctx).Errorf("Error deleting user: %v", err)
 		return err
 	}
 
-	if pr.redis != nil {
-		cacheKey := fmt.Sprintf("user:%d", userID)
-		if err := pr.redis.Del(ctx, cacheKey).Err(); err != nil {
-			pr.log.Warnf("Failed to delete user from Redis: %v", err)
-		}
-		pr.log.Infof("User deleted from cache: %d", userID)
-
-		// Invalidate the cache for all records
-		if err := pr.redis.Del(ctx, "all_users").Err(); err != nil {
-			pr.log.Warnf("Failed to invalidate all users cache: %v", err)
-		}
-		pr.log.Info("Invalidated cache for all users")
-	}
+	pr.users.Invalidate(ctx, userTag(userID))
+	pr.lists.InvalidateAll(ctx)
 
 	return nil
 }
@@ -259,7 +191,7 @@ func (pr *userRepository) getQuerySearch(db *gorm.DB, req *model.GetUsersRequest
 }
 
 func (pr *userRepository) GetList(ctx context.Context, req *model.GetUsersRequest) ([]*User, error) {
-	pr.log.Infof("Fetching user list with request: %+v", req)
+	pr.log.WithContext(ctx).Infof("Fetching user list with request: %+v", req)
 	var users []*User
 
 	db := pr.db.WithContext(ctx)
@@ -284,29 +216,29 @@ func (pr *userRepository) GetList(ctx context.Context, req *model.GetUsersReques
 
 	result := db.Table("users").Offset(int(req.Paging.PageIndex-1) * int(req.Paging.PageSize)).Limit(int(req.Paging.PageSize)).Find(&users)
 	if result.Error != nil {
-		pr.log.Errorf("Error fetching user list: %v", result.Error)
+		pr.log.WithContext(ctx).Errorf("Error fetching user list: %v", result.Error)
 		return nil, result.Error
 	}
 
-	pr.log.Infof("Fetched %d users", len(users))
+	pr.log.WithContext(ctx).Infof("Fetched %d users", len(users))
 	return users, nil
 }
 
 func (pr *userRepository) VerifyToken(ctx context.Context, token string, userID int64) (bool, error) {
-	pr.log.Infof("Verifying token for user: %d", userID)
+	pr.log.WithContext(ctx).Infof("Verifying token for user: %d", userID)
 
 	var user User
 	if err := pr.db.WithContext(ctx).Where("user_id = ? AND token = ?", userID, token).First(&user).Error; err != nil {
-		pr.log.Errorf("Error fetching user with matching token from database: %v", err)
+		pr.log.WithContext(ctx).Errorf("Error fetching user with matching token from database: %v", err)
 		return false, fmt.Errorf("invalid user or token")
 	}
 
 	// Check if the token has expired
 	if user.TokenExpires.Before(time.Now()) {
-		pr.log.Errorf("Token has expired for user: %d", userID)
+		pr.log.WithContext(ctx).Errorf("Token has expired for user: %d", userID)
 		return false, fmt.Errorf("token has expired")
 	}
 
-	pr.log.Infof("Token successfully verified for user: %d", userID)
+	pr.log.WithContext(ctx).Infof("Token successfully verified for user: %d", userID)
 	return true, nil
 }
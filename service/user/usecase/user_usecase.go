@@ -36,19 +36,19 @@ func NewUserUsecase(userRepo repository.IUserRepository, log *logrus.Logger) IUs
 }
 
 func (pu *userUsecase) GetUser(ctx context.Context, req *model.GetUserRequest) (*model.GetUserResponse, error) {
-	pu.log.Infof("Fetching user with ID: %d or email: %s", req.UserID, req.Email)
+	pu.log.WithContext(ctx).Infof("Fetching user with ID: %d or email: %s", req.UserID, req.Email)
 	user, err := pu.userRepo.Get(ctx, req.UserID, req.Email)
 	if err != nil {
-		pu.log.Errorf("Error fetching user: %v", err)
+		pu.log.WithContext(ctx).Errorf("Error fetching user: %v", err)
 		return nil, err
 	}
 
 	if user == nil {
-		pu.log.Errorf("User not found")
+		pu.log.WithContext(ctx).Errorf("User not found")
 		return &model.GetUserResponse{}, nil
 	}
 
-	pu.log.Infof("Fetched user: %+v", user)
+	pu.log.WithContext(ctx).Infof("Fetched user: %+v", user)
 	return &model.GetUserResponse{
 		UserID:       user.UserID,
 		Email:        user.Email,
@@ -69,10 +69,10 @@ func (pu *userUsecase) GetUser(ctx context.Context, req *model.GetUserRequest) (
 }
 
 func (pu *userUsecase) GetAllUsers(ctx context.Context) ([]*model.GetUserResponse, error) {
-	pu.log.Info("Fetching all users")
+	pu.log.WithContext(ctx).Info("Fetching all users")
 	users, err := pu.userRepo.GetAll(ctx)
 	if err != nil {
-		pu.log.Errorf("Error fetching all users: %v", err)
+		pu.log.WithContext(ctx).Errorf("Error fetching all users: %v", err)
 		return nil, err
 	}
 
@@ -97,12 +97,12 @@ func (pu *userUsecase) GetAllUsers(ctx context.Context) ([]*model.GetUserRespons
 		})
 	}
 
-	pu.log.Infof("Fetched %d users", len(userResponses))
+	pu.log.WithContext(ctx).Infof("Fetched %d users", len(userResponses))
 	return userResponses, nil
 }
 
 func (pu *userUsecase) CreateUser(ctx context.Context, user *model.CreateUserRequest) (*model.GetUserResponse, error) {
-	pu.log.Infof("Creating user: %+v", user)
+	pu.log.WithContext(ctx).Infof("Creating user: %+v", user)
 	userData := repository.User{
 		Email:        user.Email,
 		PasswordHash: user.Password,
@@ -130,11 +130,11 @@ func (pu *userUsecase) CreateUser(ctx context.Context, user *model.CreateUserReq
 
 	createdUser, err := pu.userRepo.Create(ctx, &userData)
 	if err != nil {
-		pu.log.Errorf("Error creating user: %v", err)
+		pu.log.WithContext(ctx).Errorf("Error creating user: %v", err)
 		return nil, err
 	}
 
-	pu.log.Infof("Created user: %+v", createdUser)
+	pu.log.WithContext(ctx).Infof("Created user: %+v", createdUser)
 	return &model.GetUserResponse{
 		UserID:       createdUser.UserID,
 		Email:        createdUser.Email,
@@ -155,10 +155,10 @@ func (pu *userUsecase) CreateUser(ctx context.Context, user *model.CreateUserReq
 }
 
 func (pu *userUsecase) DeleteUser(ctx context.Context, req *model.DeleteUserRequest) error {
-	pu.log.Infof("Deleting user with ID: %d", req.UserID)
+	pu.log.WithContext(ctx).Infof("Deleting user with ID: %d", req.UserID)
 	user, err := pu.userRepo.Get(ctx, &req.UserID, "")
 	if err != nil {
-		pu.log.Errorf("Error fetching user for deletion: %v", err)
+		pu.log.WithContext(ctx).Errorf("Error fetching user for deletion: %v", err)
 		return err
 	}
 
@@ -166,19 +166,19 @@ func (pu *userUsecase) DeleteUser(ctx context.Context, req *model.DeleteUserRequ
 
 	_, err = pu.userRepo.Update(ctx, user)
 	if err != nil {
-		pu.log.Errorf("Error updating user for deletion: %v", err)
+		pu.log.WithContext(ctx).Errorf("Error updating user for deletion: %v", err)
 		return err
 	}
 
-	pu.log.Infof("Deleted user with ID: %d", req.UserID)
+	pu.log.WithContext(ctx).Infof("Deleted user with ID: %d", req.UserID)
 	return nil
 }
 
 func (pu *userUsecase) UpdateUser(ctx context.Context, rep *model.UpdateUserRequest) (*model.GetUserResponse, error) {
-	pu.log.Infof("Updating user with ID: %d", rep.UserID)
+	pu.log.WithContext(ctx).Infof("Updating user with ID: %d", rep.UserID)
 	user, err := pu.userRepo.Get(ctx, &rep.UserID, rep.Email)
 	if err != nil {
-		pu.log.Errorf("Error fetching user for update: %v", err)
+		pu.log.WithContext(ctx).Errorf("Error fetching user for update: %v", err)
 		return nil, err
 	}
 
@@ -197,11 +197,11 @@ func (pu *userUsecase) UpdateUser(ctx context.Context, rep *model.UpdateUserRequ
 
 	updatedUser, err := pu.userRepo.Update(ctx, user)
 	if err != nil {
-		pu.log.Errorf("Error updating user: %v", err)
+		pu.log.WithContext(ctx).Errorf("Error updating user: %v", err)
 		return nil, err
 	}
 
-	pu.log.Infof("Updated user: %+v", updatedUser)
+	pu.log.WithContext(ctx).Infof("Updated user: %+v", updatedUser)
 	return &model.GetUserResponse{
 		UserID:       updatedUser.UserID,
 		Email:        updatedUser.Email,
@@ -222,10 +222,10 @@ func (pu *userUsecase) UpdateUser(ctx context.Context, rep *model.UpdateUserRequ
 }
 
 func (pu *userUsecase) GetUserList(ctx context.Context, req *model.GetUsersRequest) (*util.PaginatedList[model.GetUserResponse], error) {
-	pu.log.Infof("Fetching user list with request: %+v", req)
+	pu.log.WithContext(ctx).Infof("Fetching user list with request: %+v", req)
 	users, err := pu.userRepo.GetList(ctx, req)
 	if err != nil {
-		pu.log.Errorf("Error fetching user list: %v", err)
+		pu.log.WithContext(ctx).Errorf("Error fetching user list: %v", err)
 		return nil, err
 	}
 
@@ -260,21 +260,21 @@ func (pu *userUsecase) GetUserList(ctx context.Context, req *model.GetUsersReque
 
 	list.GetTotalPages()
 
-	pu.log.Infof("Fetched %d users", len(userResponses))
+	pu.log.WithContext(ctx).Infof("Fetched %d users", len(userResponses))
 	return list, nil
 }
 
 func (pu *userUsecase) VerifyToken(ctx context.Context, token string, userID int64) (bool, error) {
-	pu.log.Infof("Verifying token: %s for user: %d", token, userID)
+	pu.log.WithContext(ctx).Infof("Verifying token: %s for user: %d", token, userID)
 	isValid, err := pu.userRepo.VerifyToken(ctx, token, userID)
 	if err != nil {
-		pu.log.Errorf("Error verifying token: %v", err)
+		pu.log.WithContext(ctx).Errorf("Error verifying token: %v", err)
 		return false, err
 	}
 
 	user, err := pu.userRepo.Get(ctx, &userID, "")
 	if err != nil {
-		pu.log.Errorf("Error fetching user for token verification: %v", err)
+		pu.log.WithContext(ctx).Errorf("Error fetching user for token verification: %v", err)
 		return false, err
 	}
 
@@ -285,7 +285,7 @@ func (pu *userUsecase) VerifyToken(ctx context.Context, token string, userID int
 
 		_, err = pu.userRepo.Update(ctx, user)
 		if err != nil {
-			pu.log.Errorf("Error updating user after token verification: %v", err)
+			pu.log.WithContext(ctx).Errorf("Error updating user after token verification: %v", err)
 			return false, err
 		}
 	} else {
@@ -293,11 +293,11 @@ func (pu *userUsecase) VerifyToken(ctx context.Context, token string, userID int
 
 		_, err = pu.userRepo.Update(ctx, user)
 		if err != nil {
-			pu.log.Errorf("Error updating user after token verification: %v", err)
+			pu.log.WithContext(ctx).Errorf("Error updating user after token verification: %v", err)
 			return false, err
 		}
 	}
 
-	pu.log.Infof("Token is valid: %t", isValid)
+	pu.log.WithContext(ctx).Infof("Token is valid: %t", isValid)
 	return isValid, nil
 }
@@ -0,0 +1,17 @@
+package config
+
+import pkgconfig "th3y3m/e-commerce-microservices/pkg/config"
+
+// Config of the VNPay service
+type Config struct {
+	pkgconfig.Base
+	VNPay VNPay
+}
+
+// VNPay is the merchant account payments are created with
+type VNPay struct {
+	URL        string `env:"VNPAY_URL" required:"true"`
+	ReturnURL  string `env:"VNPAY_RETURN_URL" required:"true"`
+	TmnCode    string `env:"VNPAY_TMNCODE" required:"true"`
+	HashSecret string `env:"VNPAY_HASH_SECRET" required:"true" secret:"true"`
+}
@@ -8,8 +8,8 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-func CreateVnPayUrl(c *gin.Context) {
-	VnPayConfig := dependency_injection.NewVnpayUsecaseProvider()
+func (h *handler) CreateVnPayUrl(c *gin.Context) {
+	VnPayConfig := dependency_injection.NewVnpayUsecaseProvider(h.cfg, h.services)
 
 	amountStr := c.Query("amount")
 	amount, err := strconv.ParseFloat(amountStr, 64)
@@ -32,11 +32,11 @@ func CreateVnPayUrl(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"payment_url": paymentUrl})
 }
 
-func ValidateVnPayResponse(c *gin.Context) {
-	VnPayConfig := dependency_injection.NewVnpayUsecaseProvider()
+func (h *handler) ValidateVnPayResponse(c *gin.Context) {
+	VnPayConfig := dependency_injection.NewVnpayUsecaseProvider(h.cfg, h.services)
 
 	queryParams := c.Request.URL.Query()
-	res, err := VnPayConfig.ValidateVNPayResponse(queryParams)
+	res, err := VnPayConfig.ValidateVNPayResponse(c, queryParams)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -1,12 +1,26 @@
 package dependency_injection
 
 import (
+	"net/http"
+	"th3y3m/e-commerce-microservices/pkg/authz"
+	"th3y3m/e-commerce-microservices/pkg/clients"
+	"th3y3m/e-commerce-microservices/pkg/discovery"
+	"th3y3m/e-commerce-microservices/pkg/logging"
+	"th3y3m/e-commerce-microservices/service/vnpay/config"
 	"th3y3m/e-commerce-microservices/service/vnpay/usecase"
-
-	"github.com/sirupsen/logrus"
 )
 
-func NewVnpayUsecaseProvider() usecase.IVnpayUsecase {
-	log := logrus.New()
-	return usecase.NewVnpayUsecase(log)
+func NewVnpayUsecaseProvider(cfg config.Config, httpClient *http.Client) usecase.IVnpayUsecase {
+	log := logging.Logger()
+	opts := clients.NewOptions(cfg.Clients)
+	return usecase.NewVnpayUsecase(log, cfg.VNPay, usecase.Clients{
+		Order:   clients.NewOrderClient(httpClient, opts),
+		Payment: clients.NewPaymentClient(httpClient, opts),
+	})
+}
+
+// NewServiceClientProvider returns the HTTP client the VNPay service calls other
+// services with, signed with its service token and sent to the instances resolver finds
+func NewServiceClientProvider(cfg config.Config, resolver discovery.Resolver) *http.Client {
+	return authz.NewKeys(cfg.Auth).NewServiceClient("vnpay", resolver)
 }
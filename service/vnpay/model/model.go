@@ -2,44 +2,6 @@ package model
 
 import "time"
 
-type GetOrderResponse struct {
-	OrderID               int64   `json:"order_id"`
-	CustomerID            int64   `json:"customer_id"`
-	OrderDate             string  `json:"order_date"`
-	TotalAmount           float64 `json:"total_amount"`
-	OrderStatus           string  `json:"order_status"`
-	ShippingAddress       string  `json:"shipping_address"`
-	CourierID             int64   `json:"courier_id"`
-	FreightPrice          float64 `json:"freight_price"`
-	EstimatedDeliveryDate string  `json:"estimated_delivery_date"`
-	ActualDeliveryDate    string  `json:"actual_delivery_date"`
-	VoucherID             int64   `json:"voucher_id"`
-	IsDeleted             bool    `json:"is_deleted"`
-	CreatedAt             string  `json:"created_at"`
-	UpdatedAt             string  `json:"updated_at"`
-}
-
-type CreatePaymentRequest struct {
-	OrderID          int64   `json:"order_id"`
-	PaymentAmount    float64 `json:"payment_amount"`
-	PaymentMethod    string  `json:"payment_method"`
-	PaymentStatus    string  `json:"payment_status"`
-	PaymentSignature string  `json:"payment_signature"`
-}
-type UpdateOrderRequest struct {
-	OrderID               int64     `json:"order_id"`
-	CustomerID            int64     `json:"customer_id"`
-	OrderDate             time.Time `json:"order_date"`
-	TotalAmount           float64   `json:"total_amount"`
-	OrderStatus           string    `json:"order_status"`
-	ShippingAddress       string    `json:"shipping_address"`
-	CourierID             int64     `json:"courier_id"`
-	FreightPrice          float64   `json:"freight_price"`
-	EstimatedDeliveryDate time.Time `json:"estimated_delivery_date"`
-	ActualDeliveryDate    time.Time `json:"actual_delivery_date"`
-	VoucherID             int64     `json:"voucher_id"`
-	IsDeleted             bool      `json:"is_deleted"`
-}
 type Payment struct {
 	PaymentID        int64     `gorm:"primaryKey;column:payment_id;autoIncrement"`
 	OrderID          int64     `gorm:"column:order_id"`
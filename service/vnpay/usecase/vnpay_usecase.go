@@ -1,41 +1,48 @@
 package usecase
 
 import (
-	"bytes"
-	"encoding/json"
-	"errors"
+	"context"
 	"fmt"
 	"net"
-	"net/http"
 	"net/url"
 	"os"
 	"sort"
 	"strconv"
 	"strings"
+	"th3y3m/e-commerce-microservices/pkg/authz"
+	"th3y3m/e-commerce-microservices/pkg/clients"
 	"th3y3m/e-commerce-microservices/pkg/constant"
+	"th3y3m/e-commerce-microservices/pkg/metrics"
 	"th3y3m/e-commerce-microservices/pkg/util"
+	"th3y3m/e-commerce-microservices/service/vnpay/config"
 	"th3y3m/e-commerce-microservices/service/vnpay/model"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
-	"github.com/spf13/viper"
 )
 
-func NewVnpayUsecase(log *logrus.Logger) IVnpayUsecase {
+// Clients of the services a payment is recorded in
+type Clients struct {
+	Order   *clients.OrderClient
+	Payment *clients.PaymentClient
+}
+
+func NewVnpayUsecase(log *logrus.Logger, vnpay config.VNPay, clients Clients) IVnpayUsecase {
 
 	return &VnpayUsecase{
-		url:        viper.GetString("VNPAY_URL"),
-		returnUrl:  viper.GetString("VNPAY_RETURN_URL"),
-		tmnCode:    viper.GetString("VNPAY_TMNCODE"),
-		hashSecret: viper.GetString("VNPAY_HASH_SECRET"),
+		url:        vnpay.URL,
+		returnUrl:  vnpay.ReturnURL,
+		tmnCode:    vnpay.TmnCode,
+		hashSecret: vnpay.HashSecret,
+		clients:    clients,
 		log:        log,
 	}
 }
 
 type IVnpayUsecase interface {
 	CreateVNPayUrl(amount float64, orderinfor string) (string, error)
-	ValidateVNPayResponse(queryString url.Values) (*model.PaymentResponse, error)
+	ValidateVNPayResponse(ctx context.Context, queryString url.Values) (*model.PaymentResponse, error)
 }
 
 type VnpayUsecase struct {
@@ -43,6 +50,7 @@ type VnpayUsecase struct {
 	returnUrl  string
 	tmnCode    string
 	hashSecret string
+	clients    Clients
 	log        *logrus.Logger
 }
 
@@ -79,7 +87,9 @@ func (s *VnpayUsecase) CreateVNPayUrl(amount float64, orderID string) (string, e
 	return TransactionUrl, nil
 }
 
-func (s *VnpayUsecase) ValidateVNPayResponse(queryString url.Values) (*model.PaymentResponse, error) {
+func (s *VnpayUsecase) ValidateVNPayResponse(ctx context.Context, queryString url.Values) (*model.PaymentResponse, error) {
+	// VNPay calls back without a user, the order and payment are updated by the service itself
+	ctx = authz.AsSystem(ctx)
 
 	vnpSecureHash := queryString.Get("vnp_SecureHash")
 	vnpAmount := queryString.Get("vnp_Amount")
@@ -103,19 +113,14 @@ func (s *VnpayUsecase) ValidateVNPayResponse(queryString url.Values) (*model.Pay
 		return &model.PaymentResponse{IsSuccessful: false, RedirectUrl: "LINK_INVALID"}, nil
 	}
 
-	res, err := http.Get(fmt.Sprintf("%s/%s", constant.ORDER_SERVICE, orderId))
+	id, err := strconv.ParseInt(orderId, 10, 64)
 	if err != nil {
-		return nil, err
-	}
-	defer res.Body.Close()
-
-	if res.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("error: received status code %d from order service", res.StatusCode)
+		return nil, fmt.Errorf("invalid orderId format")
 	}
 
-	var order model.GetOrderResponse
-	if err := json.NewDecoder(res.Body).Decode(&order); err != nil {
-		return nil, fmt.Errorf("error decoding order response: %v", err)
+	order, err := s.clients.Order.Get(ctx, id)
+	if err != nil {
+		return nil, err
 	}
 
 	if order.OrderStatus == constant.ORDER_STATUS_COMPLETED {
@@ -127,89 +132,31 @@ func (s *VnpayUsecase) ValidateVNPayResponse(queryString url.Values) (*model.Pay
 
 	vnpResponseCode := queryString.Get("vnp_ResponseCode")
 	if vnpResponseCode == "00" && queryString.Get("vnp_TransactionStatus") == "00" {
-		order.OrderStatus = constant.ORDER_STATUS_COMPLETED
-		updateModel := model.UpdateOrderRequest{
-			OrderID:               order.OrderID,
-			CustomerID:            order.CustomerID,
-			OrderDate:             util.ParseTime(order.OrderDate),
-			OrderStatus:           order.OrderStatus,
-			ActualDeliveryDate:    util.ParseTime(order.ActualDeliveryDate),
-			EstimatedDeliveryDate: util.ParseTime(order.EstimatedDeliveryDate),
-			ShippingAddress:       order.ShippingAddress,
-			CourierID:             order.CourierID,
-			FreightPrice:          order.FreightPrice,
-			TotalAmount:           order.TotalAmount,
-			VoucherID:             order.VoucherID,
-			IsDeleted:             order.IsDeleted,
-		}
-
 		// Update the order status
-		url := constant.ORDER_SERVICE
-		orderData, err := json.Marshal(updateModel)
-		if err != nil {
-			s.log.Errorf("Failed to marshal order data: %v", err)
+		if err := s.clients.Order.UpdateStatus(ctx, order, constant.ORDER_STATUS_COMPLETED); err != nil {
+			s.log.WithContext(ctx).Errorf("Failed to update order in order service: %v", err)
 			return nil, err
 		}
 
-		req, err := http.NewRequest("PUT", url, bytes.NewBuffer(orderData))
-		if err != nil {
-			s.log.Errorf("Failed to create request: %v", err)
-			return nil, err
-		}
-		req.Header.Set("Content-Type", "application/json")
-
-		client := &http.Client{}
-		res, err := client.Do(req)
-		if err != nil {
-			s.log.Errorf("Failed to update order in order service: %v", err)
-			return nil, err
-		}
-		defer res.Body.Close()
-
-		if res.StatusCode != http.StatusOK {
-			s.log.Errorf("Error updating order: received status %v", res.StatusCode)
-			return nil, errors.New("error updating order")
-		}
-
 		paymentAmount, err := strconv.ParseFloat(vnpAmount, 64)
 		if err != nil {
 			return nil, fmt.Errorf("invalid payment amount: %v", err)
 		}
 		paymentAmount = paymentAmount / 100
 
-		paymentCreateModel := &model.CreatePaymentRequest{
+		err = s.clients.Payment.Create(ctx, clients.CreatePayment{
 			OrderID:          order.OrderID,
 			PaymentAmount:    paymentAmount,
 			PaymentStatus:    constant.ORDER_STATUS_COMPLETED,
 			PaymentSignature: queryString.Get("vnp_BankTranNo"),
 			PaymentMethod:    constant.PAYMENT_METHOD_VNPAY,
-		}
-
-		url = constant.PAYMENT_SERVICE
-		paymentData, err := json.Marshal(paymentCreateModel)
+			TransactionID:    queryString.Get("vnp_TransactionNo"),
+		})
 		if err != nil {
-			s.log.Errorf("Failed to marshal payment data: %v", err)
+			s.log.WithContext(ctx).Errorf("Failed to create payment in payment service: %v", err)
 			return nil, err
 		}
-
-		req, err = http.NewRequest("POST", url, bytes.NewBuffer(paymentData))
-		if err != nil {
-			s.log.Errorf("Failed to create request: %v", err)
-			return nil, err
-		}
-		req.Header.Set("Content-Type", "application/json")
-
-		res, err = client.Do(req)
-		if err != nil {
-			s.log.Errorf("Failed to create payment in payment service: %v", err)
-			return nil, err
-		}
-		defer res.Body.Close()
-
-		if res.StatusCode != http.StatusOK {
-			s.log.Errorf("Error creating payment: received status %v", res.StatusCode)
-			return nil, errors.New("error creating payment")
-		}
+		metrics.PaymentCompleted(constant.PAYMENT_METHOD_VNPAY)
 
 		// cart, err := s.shoppingCartService.GetUserShoppingCart(order.CustomerID)
 		// if err != nil {
@@ -226,48 +173,10 @@ func (s *VnpayUsecase) ValidateVNPayResponse(queryString url.Values) (*model.Pay
 		}, nil
 	}
 
-	order.OrderStatus = constant.ORDER_STATUS_FAILED
-	updateModel := model.UpdateOrderRequest{
-		OrderID:               order.OrderID,
-		CustomerID:            order.CustomerID,
-		OrderDate:             util.ParseTime(order.OrderDate),
-		OrderStatus:           order.OrderStatus,
-		ActualDeliveryDate:    util.ParseTime(order.ActualDeliveryDate),
-		EstimatedDeliveryDate: util.ParseTime(order.EstimatedDeliveryDate),
-		ShippingAddress:       order.ShippingAddress,
-		CourierID:             order.CourierID,
-		FreightPrice:          order.FreightPrice,
-		TotalAmount:           order.TotalAmount,
-		VoucherID:             order.VoucherID,
-		IsDeleted:             order.IsDeleted,
-	}
-
-	url := constant.ORDER_SERVICE
-	orderData, err := json.Marshal(updateModel)
-	if err != nil {
-		s.log.Errorf("Failed to marshal order data: %v", err)
-		return nil, err
-	}
-
-	req, err := http.NewRequest("PUT", url, bytes.NewBuffer(orderData))
-	if err != nil {
-		s.log.Errorf("Failed to create request: %v", err)
+	if err := s.clients.Order.UpdateStatus(ctx, order, constant.ORDER_STATUS_FAILED); err != nil {
+		s.log.WithContext(ctx).Errorf("Failed to update order in order service: %v", err)
 		return nil, err
 	}
-	req.Header.Set("Content-Type", "application/json")
-
-	client := &http.Client{}
-	res, err = client.Do(req)
-	if err != nil {
-		s.log.Errorf("Failed to update order in order service: %v", err)
-		return nil, err
-	}
-	defer res.Body.Close()
-
-	if res.StatusCode != http.StatusOK {
-		s.log.Errorf("Error updating order: received status %v", res.StatusCode)
-		return nil, errors.New("error updating order")
-	}
 
 	return &model.PaymentResponse{
 		IsSuccessful: false,
@@ -0,0 +1,8 @@
+package config
+
+import pkgconfig "th3y3m/e-commerce-microservices/pkg/config"
+
+// Config of the voucher service
+type Config struct {
+	pkgconfig.Service
+}
@@ -1,20 +1,39 @@
 package delivery
 
 import (
+	"th3y3m/e-commerce-microservices/pkg/authz"
+	"th3y3m/e-commerce-microservices/pkg/logging"
+	"th3y3m/e-commerce-microservices/pkg/metrics"
+	"th3y3m/e-commerce-microservices/pkg/tracing"
+	"th3y3m/e-commerce-microservices/service/voucher/config"
+
 	"github.com/gin-gonic/gin"
 )
 
-func RegisterHandlers() *gin.Engine {
-	r := gin.Default()
+// handler serves the routes of the service, with the usecases built from cfg
+type handler struct {
+	cfg config.Config
+}
+
+func RegisterHandlers(cfg config.Config) *gin.Engine {
+	h := &handler{cfg: cfg}
+	keys := authz.NewKeys(cfg.Auth)
+
+	r := gin.New()
+	// Lets the request context, and the trace in it, reach the usecases through *gin.Context
+	r.ContextWithFallback = true
+	r.Use(gin.Recovery(), tracing.Middleware("voucher"), logging.Middleware(), metrics.Middleware())
+	r.GET(metrics.Path, metrics.Handler())
+	r.Use(keys.Middleware())
 
 	voucher := r.Group("/api/vouchers")
 	{
-		voucher.GET("/:voucher_id", GetVoucherByID)
-		voucher.GET("", GetPaginatedVoucher)
-		voucher.POST("", CreateVoucher)
-		voucher.PUT("", UpdateVoucher)
-		voucher.DELETE("", DeleteVoucher)
-		voucher.POST("/check-usage", CheckVoucherUsage)
+		voucher.GET("/:voucher_id", h.GetVoucherByID)
+		voucher.GET("", h.GetPaginatedVoucher)
+		voucher.POST("", h.CreateVoucher)
+		voucher.PUT("", h.UpdateVoucher)
+		voucher.DELETE("", h.DeleteVoucher)
+		voucher.POST("/check-usage", h.CheckVoucherUsage)
 	}
 
 	return r
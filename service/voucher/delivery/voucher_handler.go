@@ -9,15 +9,15 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
-func GetVoucherByID(c *gin.Context) {
-	module := dependency_injection.NewVoucherUsecaseProvider()
+func (h *handler) GetVoucherByID(c *gin.Context) {
+	module := dependency_injection.NewVoucherUsecaseProvider(h.cfg)
 
 	var req model.GetVoucherRequest
 
 	id := c.Param("voucher_id")
 	voucherID, err := strconv.ParseInt(id, 10, 64)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(400, gin.H{
 			"error": "Invalid voucher ID",
 		})
@@ -27,7 +27,7 @@ func GetVoucherByID(c *gin.Context) {
 
 	voucher, err := module.GetVoucher(c, &req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(500, gin.H{
 			"error": "Internal Server Error",
 		})
@@ -37,12 +37,12 @@ func GetVoucherByID(c *gin.Context) {
 	c.JSON(200, voucher)
 }
 
-func GetAllVouchers(c *gin.Context) {
-	module := dependency_injection.NewVoucherUsecaseProvider()
+func (h *handler) GetAllVouchers(c *gin.Context) {
+	module := dependency_injection.NewVoucherUsecaseProvider(h.cfg)
 
 	vouchers, err := module.GetAllVouchers(c)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(500, gin.H{
 			"error": "Internal Server Error",
 		})
@@ -52,22 +52,22 @@ func GetAllVouchers(c *gin.Context) {
 	c.JSON(200, vouchers)
 }
 
-func CreateVoucher(c *gin.Context) {
+func (h *handler) CreateVoucher(c *gin.Context) {
 	var req model.CreateVoucherRequest
 	err := c.BindJSON(&req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(400, gin.H{
 			"error": "Bad Request",
 		})
 		return
 	}
 
-	module := dependency_injection.NewVoucherUsecaseProvider()
+	module := dependency_injection.NewVoucherUsecaseProvider(h.cfg)
 
 	voucher, err := module.CreateVoucher(c, &req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(500, gin.H{
 			"error": "Internal Server Error",
 		})
@@ -77,22 +77,22 @@ func CreateVoucher(c *gin.Context) {
 	c.JSON(200, voucher)
 }
 
-func UpdateVoucher(c *gin.Context) {
+func (h *handler) UpdateVoucher(c *gin.Context) {
 	var req model.UpdateVoucherRequest
 	err := c.BindJSON(&req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(400, gin.H{
 			"error": "Bad Request",
 		})
 		return
 	}
 
-	module := dependency_injection.NewVoucherUsecaseProvider()
+	module := dependency_injection.NewVoucherUsecaseProvider(h.cfg)
 
 	voucher, err := module.UpdateVoucher(c, &req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(500, gin.H{
 			"error": "Internal Server Error",
 		})
@@ -102,13 +102,13 @@ func UpdateVoucher(c *gin.Context) {
 	c.JSON(200, voucher)
 }
 
-func DeleteVoucher(c *gin.Context) {
-	module := dependency_injection.NewVoucherUsecaseProvider()
+func (h *handler) DeleteVoucher(c *gin.Context) {
+	module := dependency_injection.NewVoucherUsecaseProvider(h.cfg)
 
 	var req model.DeleteVoucherRequest
 	err := c.BindJSON(&req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(400, gin.H{
 			"error": "Bad Request",
 		})
@@ -117,7 +117,7 @@ func DeleteVoucher(c *gin.Context) {
 
 	err = module.DeleteVoucher(c, &req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(500, gin.H{
 			"error": "Internal Server Error",
 		})
@@ -129,13 +129,13 @@ func DeleteVoucher(c *gin.Context) {
 	})
 }
 
-func GetPaginatedVoucher(c *gin.Context) {
-	module := dependency_injection.NewVoucherUsecaseProvider()
+func (h *handler) GetPaginatedVoucher(c *gin.Context) {
+	module := dependency_injection.NewVoucherUsecaseProvider(h.cfg)
 
 	var req model.GetVouchersRequest
 	err := c.BindJSON(&req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(400, gin.H{
 			"error": "Bad Request",
 		})
@@ -149,7 +149,7 @@ func GetPaginatedVoucher(c *gin.Context) {
 	}
 	vouchers, err := module.GetVoucherList(c, &req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(500, gin.H{
 			"error": "Internal Server Error",
 		})
@@ -159,13 +159,13 @@ func GetPaginatedVoucher(c *gin.Context) {
 	c.JSON(200, vouchers)
 }
 
-func CheckVoucherUsage(c *gin.Context) {
-	module := dependency_injection.NewVoucherUsecaseProvider()
+func (h *handler) CheckVoucherUsage(c *gin.Context) {
+	module := dependency_injection.NewVoucherUsecaseProvider(h.cfg)
 
 	var req model.CheckVoucherUsageRequest
 	err := c.BindJSON(&req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(400, gin.H{
 			"error": "Bad Request",
 		})
@@ -174,7 +174,7 @@ func CheckVoucherUsage(c *gin.Context) {
 
 	isValid, err := module.CheckVoucherUsage(c, &req)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(c).Error(err)
 		c.JSON(500, gin.H{
 			"error": "Internal Server Error",
 		})
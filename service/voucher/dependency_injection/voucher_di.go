@@ -1,30 +1,31 @@
 package dependency_injection
 
 import (
+	"th3y3m/e-commerce-microservices/pkg/cache"
+	"th3y3m/e-commerce-microservices/pkg/logging"
 	"th3y3m/e-commerce-microservices/pkg/postgresql"
 	redis_client "th3y3m/e-commerce-microservices/pkg/redis"
+	"th3y3m/e-commerce-microservices/service/voucher/config"
 	"th3y3m/e-commerce-microservices/service/voucher/repository"
 	"th3y3m/e-commerce-microservices/service/voucher/usecase"
-
-	"github.com/sirupsen/logrus"
 )
 
-func NewVoucherRepositoryProvider() repository.IVoucherRepository {
-	log := logrus.New()
-	db, err := postgresql.NewGormDB()
+func NewVoucherRepositoryProvider(cfg config.Config) repository.IVoucherRepository {
+	log := logging.Logger()
+	db, err := postgresql.NewGormDB(cfg.Postgres)
 	if err != nil {
 		log.Error(err)
 	}
-	redis, err := redis_client.ConnectToRedis()
+	redis, err := redis_client.ConnectToRedis(cfg.Redis)
 	if err != nil {
 		log.Error(err)
 	}
 
-	return repository.NewVoucherRepository(db, redis, log)
+	return repository.NewVoucherRepository(db, cache.NewStore(redis), cache.NewConfig(cfg.Cache), log)
 }
 
-func NewVoucherUsecaseProvider() usecase.IVoucherUsecase {
-	log := logrus.New()
-	voucherRepository := NewVoucherRepositoryProvider()
+func NewVoucherUsecaseProvider(cfg config.Config) usecase.IVoucherUsecase {
+	log := logging.Logger()
+	voucherRepository := NewVoucherRepositoryProvider(cfg)
 	return usecase.NewVoucherUsecase(voucherRepository, log)
 }
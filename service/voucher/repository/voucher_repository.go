@@ -2,20 +2,21 @@ package repository
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
+	"strconv"
+	"th3y3m/e-commerce-microservices/pkg/cache"
 	"th3y3m/e-commerce-microservices/service/voucher/model"
 	"time"
 
-	"github.com/redis/go-redis/v9"
 	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
 )
 
 type voucherRepository struct {
-	log   *logrus.Logger
-	db    *gorm.DB
-	redis *redis.Client
+	log      *logrus.Logger
+	db       *gorm.DB
+	vouchers *cache.Cache[*Voucher]
+	lists    *cache.Cache[[]*Voucher]
 }
 
 type IVoucherRepository interface {
@@ -28,174 +29,84 @@ type IVoucherRepository interface {
 	GetList(ctx context.Context, req *model.GetVouchersRequest) ([]*Voucher, error)
 }
 
-func NewVoucherRepository(db *gorm.DB, redis *redis.Client, log *logrus.Logger) IVoucherRepository {
+func NewVoucherRepository(db *gorm.DB, store cache.Store, cfg cache.Config, log *logrus.Logger) IVoucherRepository {
 	return &voucherRepository{
-		db:    db,
-		redis: redis,
-		log:   log,
+		db:       db,
+		vouchers: cache.New[*Voucher](store, "voucher", cfg).CacheNotFound(gorm.ErrRecordNotFound),
+		lists:    cache.New[[]*Voucher](store, "vouchers", cfg),
+		log:      log,
 	}
 }
 
 func (pr *voucherRepository) Get(ctx context.Context, voucherID int64) (*Voucher, error) {
-	pr.log.Infof("Fetching voucher with ID: %d", voucherID)
-	var voucher Voucher
-	cacheKey := fmt.Sprintf("voucher:%d", voucherID)
-
-	// Try to get the voucher from Redis cache
-	if pr.redis != nil {
-		cachedVoucher, err := pr.redis.Get(ctx, cacheKey).Result()
-		if err == nil {
-			if err := json.Unmarshal([]byte(cachedVoucher), &voucher); err == nil {
-				pr.log.Infof("Voucher found in cache: %d", voucherID)
-				return &voucher, nil
-			}
-		} else if err != redis.Nil {
-			pr.log.Warnf("Failed to get voucher from Redis: %v", err)
+	pr.log.WithContext(ctx).Infof("Fetching voucher with ID: %d", voucherID)
+	voucher, err := pr.vouchers.GetOrLoad(ctx, strconv.FormatInt(voucherID, 10), func(ctx context.Context) (*Voucher, error) {
+		var voucher Voucher
+		if err := pr.db.WithContext(ctx).First(&voucher, voucherID).Error; err != nil {
+			return nil, err
 		}
-	} else {
-		pr.log.Warn("Redis client is not initialized")
-	}
-
-	// If not found in cache, get from database
-	if err := pr.db.WithContext(ctx).First(&voucher, voucherID).Error; err != nil {
-		pr.log.Errorf("Error fetching voucher from database: %v", err)
+		return &voucher, nil
+	})
+	if err != nil {
+		pr.log.WithContext(ctx).Errorf("Error fetching voucher: %v", err)
 		return nil, err
 	}
-
-	// Save to cache if Redis is available
-	if pr.redis != nil {
-		voucherJSON, _ := json.Marshal(voucher)
-		if err := pr.redis.Set(ctx, cacheKey, voucherJSON, 0).Err(); err != nil {
-			pr.log.Warnf("Failed to save voucher to Redis: %v", err)
-		} else {
-			pr.log.Infof("Voucher saved to cache: %d", voucherID)
-		}
-	}
-
-	return &voucher, nil
+	return voucher, nil
 }
 
 func (pr *voucherRepository) GetAll(ctx context.Context) ([]*Voucher, error) {
-	pr.log.Info("Fetching all vouchers")
-	var vouchers []*Voucher
-	cacheKey := "all_vouchers"
-
-	// Try to get the vouchers from Redis cache
-	if pr.redis != nil {
-		cachedVouchers, err := pr.redis.Get(ctx, cacheKey).Result()
-		if err == nil {
-			if err := json.Unmarshal([]byte(cachedVouchers), &vouchers); err == nil {
-				pr.log.Info("Vouchers found in cache")
-				return vouchers, nil
-			}
-		} else if err != redis.Nil {
-			pr.log.Warnf("Failed to get vouchers from Redis: %v", err)
+	pr.log.WithContext(ctx).Info("Fetching all vouchers")
+	vouchers, err := pr.lists.GetOrLoad(ctx, "all", func(ctx context.Context) ([]*Voucher, error) {
+		var vouchers []*Voucher
+		if err := pr.db.WithContext(ctx).Find(&vouchers).Error; err != nil {
+			return nil, err
 		}
-	} else {
-		pr.log.Warn("Redis client is not initialized")
-	}
-
-	// If not found in cache, get from database
-	if err := pr.db.WithContext(ctx).Find(&vouchers).Error; err != nil {
-		pr.log.Errorf("Error fetching vouchers from database: %v", err)
+		return vouchers, nil
+	})
+	if err != nil {
+		pr.log.WithContext(ctx).Errorf("Error fetching vouchers: %v", err)
 		return nil, err
 	}
-
-	// Save to cache if Redis is available
-	if pr.redis != nil {
-		vouchersJSON, _ := json.Marshal(vouchers)
-		if err := pr.redis.Set(ctx, cacheKey, vouchersJSON, 0).Err(); err != nil {
-			pr.log.Warnf("Failed to save vouchers to Redis: %v", err)
-		} else {
-			pr.log.Info("Vouchers saved to cache")
-		}
-	}
-
 	return vouchers, nil
 }
 
 func (pr *voucherRepository) Create(ctx context.Context, voucher *Voucher) (*Voucher, error) {
-	pr.log.Infof("Creating voucher: %+v", voucher)
+	pr.log.WithContext(ctx).Infof("Creating voucher: %+v", voucher)
 	if err := pr.db.WithContext(ctx).Create(voucher).Error; err != nil {
-		pr.log.Errorf("Error creating voucher: %v", err)
+		pr.log.WithContext(ctx).Errorf("Error creating voucher: %v", err)
 		return nil, err
 	}
-	cacheKey := fmt.Sprintf("voucher:%d", voucher.VoucherID)
-
-	// Save to cache if Redis is available
-	if pr.redis != nil {
-		voucherJSON, _ := json.Marshal(voucher)
-		if err := pr.redis.Set(ctx, cacheKey, voucherJSON, 0).Err(); err != nil {
-			pr.log.Warnf("Failed to save voucher to Redis: %v", err)
-		} else {
-			pr.log.Infof("Voucher saved to cache: %d", voucher.VoucherID)
-		}
 
-		// Invalidate the cache for all records
-		if err := pr.redis.Del(ctx, "all_vouchers").Err(); err != nil {
-			pr.log.Warnf("Failed to invalidate all vouchers cache: %v", err)
-		} else {
-			pr.log.Info("Invalidated cache for all vouchers")
-		}
-	}
+	pr.vouchers.Set(ctx, strconv.FormatInt(voucher.VoucherID, 10), voucher)
+	pr.lists.InvalidateAll(ctx)
 
 	// Return the newly created voucher (with any updated fields)
 	return voucher, nil
 }
 
 func (pr *voucherRepository) Update(ctx context.Context, voucher *Voucher) (*Voucher, error) {
-	pr.log.Infof("Updating voucher: %+v", voucher)
+	pr.log.WithContext(ctx).Infof("Updating voucher: %+v", voucher)
 	if err := pr.db.WithContext(ctx).Save(voucher).Error; err != nil {
-		pr.log.Errorf("Error updating voucher: %v", err)
+		pr.log.WithContext(ctx).Errorf("Error updating voucher: %v", err)
 		return nil, err
 	}
-	cacheKey := fmt.Sprintf("voucher:%d", voucher.VoucherID)
-
-	// Save to cache if Redis is available
-	if pr.redis != nil {
-		voucherJSON, _ := json.Marshal(voucher)
-		if err := pr.redis.Set(ctx, cacheKey, voucherJSON, 0).Err(); err != nil {
-			pr.log.Warnf("Failed to save voucher to Redis: %v", err)
-		} else {
-			pr.log.Infof("Voucher saved to cache: %d", voucher.VoucherID)
-		}
 
-		// Invalidate the cache for all records
-		if err := pr.redis.Del(ctx, "all_vouchers").Err(); err != nil {
-			pr.log.Warnf("Failed to invalidate all vouchers cache: %v", err)
-		} else {
-			pr.log.Info("Invalidated cache for all vouchers")
-		}
-	}
+	pr.vouchers.Set(ctx, strconv.FormatInt(voucher.VoucherID, 10), voucher)
+	pr.lists.InvalidateAll(ctx)
 
 	// Return the updated voucher
 	return voucher, nil
 }
 
 func (pr *voucherRepository) Delete(ctx context.Context, voucherID int64) error {
-	pr.log.Infof("Deleting voucher with ID: %d", voucherID)
+	pr.log.WithContext(ctx).Infof("Deleting voucher with ID: %d", voucherID)
 	if err := pr.db.WithContext(ctx).Delete(&Voucher{}, voucherID).Error; err != nil {
-		pr.log.Errorf("Error deleting voucher: %v", err)
+		pr.log.WithContext(ctx).Errorf("Error deleting voucher: %v", err)
 		return err
 	}
 
-	cacheKey := fmt.Sprintf("voucher:%d", voucherID)
-
-	// Delete from cache if Redis is available
-	if pr.redis != nil {
-		if err := pr.redis.Del(ctx, cacheKey).Err(); err != nil {
-			pr.log.Warnf("Failed to delete voucher from Redis: %v", err)
-		} else {
-			pr.log.Infof("Voucher deleted from cache: %d", voucherID)
-		}
-
-		// Invalidate the cache for all records
-		if err := pr.redis.Del(ctx, "all_vouchers").Err(); err != nil {
-			pr.log.Warnf("Failed to invalidate all vouchers cache: %v", err)
-		} else {
-			pr.log.Info("Invalidated cache for all vouchers")
-		}
-	}
+	pr.vouchers.Delete(ctx, strconv.FormatInt(voucherID, 10))
+	pr.lists.InvalidateAll(ctx)
 
 	return nil
 }
@@ -219,7 +130,7 @@ func (pr *voucherRepository) getQuerySearch(db *gorm.DB, req *model.GetVouchersR
 }
 
 func (pr *voucherRepository) GetList(ctx context.Context, req *model.GetVouchersRequest) ([]*Voucher, error) {
-	pr.log.Infof("Fetching voucher list with request: %+v", req)
+	pr.log.WithContext(ctx).Infof("Fetching voucher list with request: %+v", req)
 	var vouchers []*Voucher
 
 	db := pr.db.WithContext(ctx)
@@ -244,10 +155,10 @@ func (pr *voucherRepository) GetList(ctx context.Context, req *model.GetVouchers
 
 	result := db.Table("vouchers").Offset(int(req.Paging.PageIndex-1) * int(req.Paging.PageSize)).Limit(int(req.Paging.PageSize)).Find(&vouchers)
 	if result.Error != nil {
-		pr.log.Errorf("Error fetching voucher list: %v", result.Error)
+		pr.log.WithContext(ctx).Errorf("Error fetching voucher list: %v", result.Error)
 		return nil, result.Error
 	}
 
-	pr.log.Infof("Fetched %d vouchers", len(vouchers))
+	pr.log.WithContext(ctx).Infof("Fetched %d vouchers", len(vouchers))
 	return vouchers, nil
 }
@@ -35,14 +35,14 @@ func NewVoucherUsecase(voucherRepo repository.IVoucherRepository, log *logrus.Lo
 }
 
 func (pu *voucherUsecase) GetVoucher(ctx context.Context, req *model.GetVoucherRequest) (*model.GetVoucherResponse, error) {
-	pu.log.Infof("Fetching voucher with ID: %d", req.VoucherID)
+	pu.log.WithContext(ctx).Infof("Fetching voucher with ID: %d", req.VoucherID)
 	voucher, err := pu.voucherRepo.Get(ctx, req.VoucherID)
 	if err != nil {
-		pu.log.Errorf("Error fetching voucher: %v", err)
+		pu.log.WithContext(ctx).Errorf("Error fetching voucher: %v", err)
 		return nil, err
 	}
 
-	pu.log.Infof("Fetched voucher: %+v", voucher)
+	pu.log.WithContext(ctx).Infof("Fetched voucher: %+v", voucher)
 	return &model.GetVoucherResponse{
 		VoucherID:          voucher.VoucherID,
 		VoucherCode:        voucher.VoucherCode,
@@ -61,10 +61,10 @@ func (pu *voucherUsecase) GetVoucher(ctx context.Context, req *model.GetVoucherR
 }
 
 func (pu *voucherUsecase) GetAllVouchers(ctx context.Context) ([]*model.GetVoucherResponse, error) {
-	pu.log.Info("Fetching all vouchers")
+	pu.log.WithContext(ctx).Info("Fetching all vouchers")
 	vouchers, err := pu.voucherRepo.GetAll(ctx)
 	if err != nil {
-		pu.log.Errorf("Error fetching all vouchers: %v", err)
+		pu.log.WithContext(ctx).Errorf("Error fetching all vouchers: %v", err)
 		return nil, err
 	}
 
@@ -87,12 +87,12 @@ func (pu *voucherUsecase) GetAllVouchers(ctx context.Context) ([]*model.GetVouch
 		})
 	}
 
-	pu.log.Infof("Fetched %d vouchers", len(voucherResponses))
+	pu.log.WithContext(ctx).Infof("Fetched %d vouchers", len(voucherResponses))
 	return voucherResponses, nil
 }
 
 func (pu *voucherUsecase) CreateVoucher(ctx context.Context, voucher *model.CreateVoucherRequest) (*model.GetVoucherResponse, error) {
-	pu.log.Infof("Creating voucher: %+v", voucher)
+	pu.log.WithContext(ctx).Infof("Creating voucher: %+v", voucher)
 	voucherData := repository.Voucher{
 		VoucherCode:        voucher.VoucherCode,
 		DiscountType:       voucher.DiscountType,
@@ -107,11 +107,11 @@ func (pu *voucherUsecase) CreateVoucher(ctx context.Context, voucher *model.Crea
 
 	createdVoucher, err := pu.voucherRepo.Create(ctx, &voucherData)
 	if err != nil {
-		pu.log.Errorf("Error creating voucher: %v", err)
+		pu.log.WithContext(ctx).Errorf("Error creating voucher: %v", err)
 		return nil, err
 	}
 
-	pu.log.Infof("Created voucher: %+v", createdVoucher)
+	pu.log.WithContext(ctx).Infof("Created voucher: %+v", createdVoucher)
 	return &model.GetVoucherResponse{
 		VoucherID:          createdVoucher.VoucherID,
 		VoucherCode:        createdVoucher.VoucherCode,
@@ -130,10 +130,10 @@ func (pu *voucherUsecase) CreateVoucher(ctx context.Context, voucher *model.Crea
 }
 
 func (pu *voucherUsecase) DeleteVoucher(ctx context.Context, req *model.DeleteVoucherRequest) error {
-	pu.log.Infof("Deleting voucher with ID: %d", req.VoucherID)
+	pu.log.WithContext(ctx).Infof("Deleting voucher with ID: %d", req.VoucherID)
 	voucher, err := pu.voucherRepo.Get(ctx, req.VoucherID)
 	if err != nil {
-		pu.log.Errorf("Error fetching voucher for deletion: %v", err)
+		pu.log.WithContext(ctx).Errorf("Error fetching voucher for deletion: %v", err)
 		return err
 	}
 
@@ -141,19 +141,19 @@ func (pu *voucherUsecase) DeleteVoucher(ctx context.Context, req *model.DeleteVo
 
 	_, err = pu.voucherRepo.Update(ctx, voucher)
 	if err != nil {
-		pu.log.Errorf("Error updating voucher for deletion: %v", err)
+		pu.log.WithContext(ctx).Errorf("Error updating voucher for deletion: %v", err)
 		return err
 	}
 
-	pu.log.Infof("Deleted voucher with ID: %d", req.VoucherID)
+	pu.log.WithContext(ctx).Infof("Deleted voucher with ID: %d", req.VoucherID)
 	return nil
 }
 
 func (pu *voucherUsecase) UpdateVoucher(ctx context.Context, rep *model.UpdateVoucherRequest) (*model.GetVoucherResponse, error) {
-	pu.log.Infof("Updating voucher with ID: %d", rep.VoucherID)
+	pu.log.WithContext(ctx).Infof("Updating voucher with ID: %d", rep.VoucherID)
 	voucher, err := pu.voucherRepo.Get(ctx, rep.VoucherID)
 	if err != nil {
-		pu.log.Errorf("Error fetching voucher for update: %v", err)
+		pu.log.WithContext(ctx).Errorf("Error fetching voucher for update: %v", err)
 		return nil, err
 	}
 
@@ -171,11 +171,11 @@ func (pu *voucherUsecase) UpdateVoucher(ctx context.Context, rep *model.UpdateVo
 
 	updatedVoucher, err := pu.voucherRepo.Update(ctx, voucher)
 	if err != nil {
-		pu.log.Errorf("Error updating voucher: %v", err)
+		pu.log.WithContext(ctx).Errorf("Error updating voucher: %v", err)
 		return nil, err
 	}
 
-	pu.log.Infof("Updated voucher: %+v", updatedVoucher)
+	pu.log.WithContext(ctx).Infof("Updated voucher: %+v", updatedVoucher)
 	return &model.GetVoucherResponse{
 		VoucherID:          updatedVoucher.VoucherID,
 		VoucherCode:        updatedVoucher.VoucherCode,
@@ -194,10 +194,10 @@ func (pu *voucherUsecase) UpdateVoucher(ctx context.Context, rep *model.UpdateVo
 }
 
 func (pu *voucherUsecase) GetVoucherList(ctx context.Context, req *model.GetVouchersRequest) (*util.PaginatedList[model.GetVoucherResponse], error) {
-	pu.log.Infof("Fetching voucher list with request: %+v", req)
+	pu.log.WithContext(ctx).Infof("Fetching voucher list with request: %+v", req)
 	vouchers, err := pu.voucherRepo.GetList(ctx, req)
 	if err != nil {
-		pu.log.Errorf("Error fetching voucher list: %v", err)
+		pu.log.WithContext(ctx).Errorf("Error fetching voucher list: %v", err)
 		return nil, err
 	}
 
@@ -230,47 +230,47 @@ func (pu *voucherUsecase) GetVoucherList(ctx context.Context, req *model.GetVouc
 
 	list.GetTotalPages()
 
-	pu.log.Infof("Fetched %d vouchers", len(voucherResponses))
+	pu.log.WithContext(ctx).Infof("Fetched %d vouchers", len(voucherResponses))
 	return list, nil
 }
 
 func (pu *voucherUsecase) CheckVoucherUsage(ctx context.Context, req *model.CheckVoucherUsageRequest) (bool, error) {
-	pu.log.Infof("Checking voucher usage with voucher ID: %d", req.VoucherID)
+	pu.log.WithContext(ctx).Infof("Checking voucher usage with voucher ID: %d", req.VoucherID)
 	voucher, err := pu.voucherRepo.Get(ctx, req.VoucherID)
 	if err != nil {
-		pu.log.Errorf("Error fetching voucher for usage check: %v", err)
+		pu.log.WithContext(ctx).Errorf("Error fetching voucher for usage check: %v", err)
 		return false, err
 	}
 
 	// Check if voucher is deleted
 	if voucher.IsDeleted {
-		pu.log.Infof("Voucher is deleted")
+		pu.log.WithContext(ctx).Infof("Voucher is deleted")
 		return false, nil
 	}
 
 	if voucher.UsageLimit == 0 {
-		pu.log.Infof("Voucher has unlimited usage")
+		pu.log.WithContext(ctx).Infof("Voucher has unlimited usage")
 		return true, nil
 	}
 
 	if voucher.UsageCount >= voucher.UsageLimit {
-		pu.log.Infof("Voucher has reached its usage limit")
+		pu.log.WithContext(ctx).Infof("Voucher has reached its usage limit")
 		return false, nil
 	}
 
 	// Check if voucher is expired
 	if time.Now().After(voucher.EndDate) || time.Now().Before(voucher.StartDate) {
-		pu.log.Infof("Voucher has expired")
+		pu.log.WithContext(ctx).Infof("Voucher has expired")
 		return false, nil
 	}
 
 	// Check if voucher is applicable
 	if req.Order.TotalAmount < voucher.MinimumOrderAmount {
-		pu.log.Infof("Voucher is not applicable")
+		pu.log.WithContext(ctx).Infof("Voucher is not applicable")
 		return false, nil
 	}
 
-	pu.log.Infof("Voucher has not reached its usage limit")
+	pu.log.WithContext(ctx).Infof("Voucher has not reached its usage limit")
 	return true, nil
 
 }